@@ -0,0 +1,249 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package abortcondition
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/DataDog/chaos-controller/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultHTTPProbeTimeout is the HTTP probe request timeout when no maximum latency is specified,
+// kept short since the probes are sent from the reconcile loop
+const defaultHTTPProbeTimeout = 2 * time.Second
+
+// Evaluator evaluates the abort conditions of a disruption
+type Evaluator interface {
+	// Evaluate returns a description of the first violated abort condition of the given disruption, or an empty string if none is violated
+	// it initializes the restart count of newly evaluated targets in the disruption status, which has to be updated afterward
+	Evaluate(c client.Client, instance *chaosv1beta1.Disruption) (string, error)
+}
+
+type evaluator struct {
+	httpClient *http.Client
+}
+
+// NewEvaluator creates an abort conditions evaluator
+func NewEvaluator() Evaluator {
+	return evaluator{
+		httpClient: &http.Client{},
+	}
+}
+
+func (e evaluator) Evaluate(c client.Client, instance *chaosv1beta1.Disruption) (string, error) {
+	conditions := instance.Spec.AbortConditions
+	if conditions == nil {
+		return "", nil
+	}
+
+	if violation := probeHTTPs(e.httpClient, conditions.HTTPProbes); violation != "" {
+		return violation, nil
+	}
+
+	if conditions.MaxTargetRestarts == nil && conditions.MaxNotReadyTargets == nil {
+		return "", nil
+	}
+
+	notReadyTargets := 0
+
+	if instance.Spec.Level == chaostypes.DisruptionLevelNode {
+		for _, target := range instance.Status.Targets {
+			node := corev1.Node{}
+
+			if err := c.Get(context.Background(), types.NamespacedName{Name: target}, &node); err != nil {
+				// a removed target is not considered since it is replaced by the dynamic targeting
+				if client.IgnoreNotFound(err) == nil {
+					continue
+				}
+
+				return "", fmt.Errorf("error getting target node %s: %w", target, err)
+			}
+
+			if !isNodeReady(node) {
+				notReadyTargets++
+			}
+		}
+	} else {
+		pods := []corev1.Pod{}
+
+		for _, target := range instance.Status.Targets {
+			pod := corev1.Pod{}
+
+			if err := c.Get(context.Background(), types.NamespacedName{Namespace: instance.Namespace, Name: target}, &pod); err != nil {
+				if client.IgnoreNotFound(err) == nil {
+					continue
+				}
+
+				return "", fmt.Errorf("error getting target pod %s: %w", target, err)
+			}
+
+			if !utils.IsPodReady(pod) {
+				notReadyTargets++
+			}
+
+			pods = append(pods, pod)
+		}
+
+		if conditions.MaxTargetRestarts != nil {
+			if instance.Status.TargetsInitialRestartCount == nil {
+				instance.Status.TargetsInitialRestartCount = map[string]int32{}
+			}
+
+			if violation := checkTargetRestarts(pods, instance.Status.TargetsInitialRestartCount, *conditions.MaxTargetRestarts); violation != "" {
+				return violation, nil
+			}
+		}
+	}
+
+	if conditions.MaxNotReadyTargets != nil {
+		return checkNotReadyTargets(notReadyTargets, len(instance.Status.Targets), conditions.MaxNotReadyTargets)
+	}
+
+	return "", nil
+}
+
+// probeHTTPs sends the given probes requests in parallel and returns a description of the first failed probe, if any
+// all the probes share the maximum probe latency as deadline so they hold the reconcile loop no longer than a single probe
+func probeHTTPs(httpClient *http.Client, probes []chaosv1beta1.HTTPProbeSpec) string {
+	ctx, cancel := context.WithTimeout(context.Background(), chaosv1beta1.MaxHTTPProbeLatency)
+	defer cancel()
+
+	violations := make([]string, len(probes))
+	wg := sync.WaitGroup{}
+
+	for i, probe := range probes {
+		wg.Add(1)
+
+		go func(i int, probe chaosv1beta1.HTTPProbeSpec) {
+			defer wg.Done()
+
+			violations[i] = probeHTTP(ctx, httpClient, probe)
+		}(i, probe)
+	}
+
+	wg.Wait()
+
+	for _, violation := range violations {
+		if violation != "" {
+			return violation
+		}
+	}
+
+	return ""
+}
+
+// probeHTTP sends a GET request to the given probe URL and returns a description of the failure, if any
+func probeHTTP(ctx context.Context, httpClient *http.Client, probe chaosv1beta1.HTTPProbeSpec) string {
+	timeout := defaultHTTPProbeTimeout
+	if probe.MaxLatency.Duration() > 0 {
+		timeout = probe.MaxLatency.Duration()
+	}
+
+	// the maximum latency is validated by the admission webhook but disruptions created before may exceed it
+	if timeout > chaosv1beta1.MaxHTTPProbeLatency {
+		timeout = chaosv1beta1.MaxHTTPProbeLatency
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return fmt.Sprintf("HTTP probe %s request could not be created: %s", probe.URL, err)
+	}
+
+	start := time.Now()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("HTTP probe %s failed: %s", probe.URL, err)
+	}
+
+	latency := time.Since(start)
+
+	resp.Body.Close()
+
+	if probe.ExpectedStatusCode != 0 && resp.StatusCode != probe.ExpectedStatusCode {
+		return fmt.Sprintf("HTTP probe %s returned status code %d instead of %d", probe.URL, resp.StatusCode, probe.ExpectedStatusCode)
+	}
+
+	if probe.ExpectedStatusCode == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return fmt.Sprintf("HTTP probe %s returned non-2xx status code %d", probe.URL, resp.StatusCode)
+	}
+
+	if probe.MaxLatency.Duration() > 0 && latency > probe.MaxLatency.Duration() {
+		return fmt.Sprintf("HTTP probe %s responded in %s, more than %s", probe.URL, latency, probe.MaxLatency.Duration())
+	}
+
+	return ""
+}
+
+// checkTargetRestarts compares the given pods containers restart count with their initial restart count
+// and returns a description of the first pod exceeding the given maximum number of restarts, if any
+// pods without an initial restart count get their current restart count as initial restart count
+func checkTargetRestarts(pods []corev1.Pod, initialRestartCount map[string]int32, maxRestarts int) string {
+	for _, pod := range pods {
+		restartCount := int32(0)
+
+		for _, status := range pod.Status.ContainerStatuses {
+			restartCount += status.RestartCount
+		}
+
+		initial, found := initialRestartCount[pod.Name]
+		if !found {
+			initialRestartCount[pod.Name] = restartCount
+
+			continue
+		}
+
+		if restarts := int(restartCount - initial); restarts > maxRestarts {
+			return fmt.Sprintf("target pod %s containers restarted %d times since the disruption started, more than %d", pod.Name, restarts, maxRestarts)
+		}
+	}
+
+	return ""
+}
+
+// checkNotReadyTargets returns a description of the violation if the given number of not ready targets
+// exceeds the given maximum number (or percentage of the total number of targets)
+func checkNotReadyTargets(notReadyTargets, totalTargets int, maxNotReadyTargets *intstr.IntOrString) (string, error) {
+	value, isPercent, err := chaosv1beta1.GetIntOrPercentValueSafely(maxNotReadyTargets)
+	if err != nil {
+		return "", fmt.Errorf("error parsing the maximum number of not ready targets: %w", err)
+	}
+
+	if isPercent {
+		value = int(math.Floor(float64(value) * float64(totalTargets) / 100))
+	}
+
+	if notReadyTargets > value {
+		return fmt.Sprintf("%d targets out of %d are not ready, more than %s", notReadyTargets, totalTargets, maxNotReadyTargets.String()), nil
+	}
+
+	return "", nil
+}
+
+// isNodeReady returns true if the given node has the ready condition
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
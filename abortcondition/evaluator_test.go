@@ -0,0 +1,239 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package abortcondition
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Evaluator", func() {
+	Describe("probeHTTP", func() {
+		var (
+			server     *httptest.Server
+			statusCode int
+			delay      time.Duration
+			probe      chaosv1beta1.HTTPProbeSpec
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			delay = 0
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(delay)
+				w.WriteHeader(statusCode)
+			}))
+			probe = chaosv1beta1.HTTPProbeSpec{
+				URL: server.URL,
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should succeed with a 2xx status code", func() {
+			Expect(probeHTTP(context.Background(), server.Client(), probe)).To(BeEmpty())
+		})
+
+		Context("with a non-2xx status code", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusServiceUnavailable
+			})
+
+			It("should fail", func() {
+				Expect(probeHTTP(context.Background(), server.Client(), probe)).To(ContainSubstring("non-2xx status code 503"))
+			})
+		})
+
+		Context("with an expected status code", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusNotFound
+				probe.ExpectedStatusCode = http.StatusNotFound
+			})
+
+			It("should succeed when the status code matches", func() {
+				Expect(probeHTTP(context.Background(), server.Client(), probe)).To(BeEmpty())
+			})
+
+			It("should fail when the status code differs", func() {
+				probe.ExpectedStatusCode = http.StatusOK
+
+				Expect(probeHTTP(context.Background(), server.Client(), probe)).To(ContainSubstring("status code 404 instead of 200"))
+			})
+		})
+
+		Context("with a response slower than the maximum latency", func() {
+			BeforeEach(func() {
+				delay = 200 * time.Millisecond
+				probe.MaxLatency = "50ms"
+			})
+
+			It("should fail", func() {
+				Expect(probeHTTP(context.Background(), server.Client(), probe)).ToNot(BeEmpty())
+			})
+		})
+
+		Context("with an unreachable URL", func() {
+			BeforeEach(func() {
+				server.Close()
+			})
+
+			It("should fail", func() {
+				Expect(probeHTTP(context.Background(), server.Client(), probe)).To(ContainSubstring("failed"))
+			})
+		})
+	})
+
+	Describe("probeHTTPs", func() {
+		var (
+			server *httptest.Server
+			probes []chaosv1beta1.HTTPProbeSpec
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+
+				if r.URL.Path == "/failing" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			probes = []chaosv1beta1.HTTPProbeSpec{
+				{URL: server.URL + "/a"},
+				{URL: server.URL + "/b"},
+				{URL: server.URL + "/c"},
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should send the probes in parallel", func() {
+			start := time.Now()
+
+			Expect(probeHTTPs(server.Client(), probes)).To(BeEmpty())
+			Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+		})
+
+		It("should succeed without any probe", func() {
+			Expect(probeHTTPs(server.Client(), nil)).To(BeEmpty())
+		})
+
+		Context("with a failing probe", func() {
+			BeforeEach(func() {
+				probes[1].URL = server.URL + "/failing"
+			})
+
+			It("should return its failure", func() {
+				Expect(probeHTTPs(server.Client(), probes)).To(ContainSubstring("/failing returned non-2xx status code 503"))
+			})
+		})
+	})
+
+	Describe("checkTargetRestarts", func() {
+		var (
+			pods    []corev1.Pod
+			initial map[string]int32
+		)
+
+		newPod := func(name string, restarts ...int32) corev1.Pod {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+				},
+			}
+
+			for _, count := range restarts {
+				pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{RestartCount: count})
+			}
+
+			return pod
+		}
+
+		BeforeEach(func() {
+			pods = []corev1.Pod{newPod("foo", 1, 2), newPod("bar", 0)}
+			initial = map[string]int32{}
+		})
+
+		It("should initialize the restart count of new targets", func() {
+			Expect(checkTargetRestarts(pods, initial, 0)).To(BeEmpty())
+			Expect(initial).To(Equal(map[string]int32{"foo": 3, "bar": 0}))
+		})
+
+		Context("with targets restarting", func() {
+			BeforeEach(func() {
+				initial = map[string]int32{"foo": 1, "bar": 0}
+			})
+
+			It("should not fail under the maximum number of restarts", func() {
+				Expect(checkTargetRestarts(pods, initial, 2)).To(BeEmpty())
+			})
+
+			It("should fail over the maximum number of restarts", func() {
+				Expect(checkTargetRestarts(pods, initial, 1)).To(ContainSubstring("target pod foo containers restarted 2 times"))
+			})
+		})
+	})
+
+	Describe("checkNotReadyTargets", func() {
+		It("should compare with an absolute maximum", func() {
+			max := intstr.FromInt(1)
+
+			Expect(checkNotReadyTargets(1, 4, &max)).To(BeEmpty())
+			Expect(checkNotReadyTargets(2, 4, &max)).ToNot(BeEmpty())
+		})
+
+		It("should compare with a percentage of the targets", func() {
+			max := intstr.FromString("50%")
+
+			Expect(checkNotReadyTargets(2, 4, &max)).To(BeEmpty())
+			Expect(checkNotReadyTargets(3, 4, &max)).ToNot(BeEmpty())
+		})
+
+		It("should return an error with an invalid maximum", func() {
+			max := intstr.FromString("foo")
+
+			_, err := checkNotReadyTargets(0, 4, &max)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("IsPodReady", func() {
+		It("should depend on the ready condition", func() {
+			pod := corev1.Pod{}
+			Expect(utils.IsPodReady(pod)).To(BeFalse())
+
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(utils.IsPodReady(pod)).To(BeTrue())
+		})
+	})
+
+	Describe("isNodeReady", func() {
+		It("should depend on the ready condition", func() {
+			node := corev1.Node{}
+			Expect(isNodeReady(node)).To(BeFalse())
+
+			node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+			Expect(isNodeReady(node)).To(BeTrue())
+		})
+	})
+})
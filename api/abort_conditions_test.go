@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package api_test
+
+import (
+	"time"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AbortConditions Validation", func() {
+	var (
+		spec  v1beta1.AbortConditionsSpec
+		level chaostypes.DisruptionLevel
+	)
+
+	BeforeEach(func() {
+		maxRestarts := 2
+		maxNotReady := intstr.FromString("50%")
+		level = chaostypes.DisruptionLevelPod
+		spec = v1beta1.AbortConditionsSpec{
+			Interval: "30s",
+			HTTPProbes: []v1beta1.HTTPProbeSpec{
+				{
+					URL:                "http://demo.chaos-demo.svc.cluster.local:8080/health",
+					ExpectedStatusCode: 200,
+					MaxLatency:         "1s",
+				},
+			},
+			MaxTargetRestarts:  &maxRestarts,
+			MaxNotReadyTargets: &maxNotReady,
+		}
+	})
+
+	It("should validate a valid spec", func() {
+		Expect(spec.Validate(level)).To(BeNil())
+	})
+
+	Context("with a relative probe URL", func() {
+		BeforeEach(func() {
+			spec.HTTPProbes[0].URL = "/health"
+		})
+
+		It("should not validate", func() {
+			Expect(spec.Validate(level)).ToNot(BeNil())
+		})
+	})
+
+	Context("with a probe maximum latency over 5s", func() {
+		BeforeEach(func() {
+			spec.HTTPProbes[0].MaxLatency = "10s"
+		})
+
+		It("should not validate", func() {
+			Expect(spec.Validate(level)).ToNot(BeNil())
+		})
+	})
+
+	Context("with an unknown expected status code", func() {
+		BeforeEach(func() {
+			spec.HTTPProbes[0].ExpectedStatusCode = 299
+		})
+
+		It("should not validate", func() {
+			Expect(spec.Validate(level)).ToNot(BeNil())
+		})
+	})
+
+	Context("with a percentage of not ready targets over 100%", func() {
+		BeforeEach(func() {
+			maxNotReady := intstr.FromString("150%")
+			spec.MaxNotReadyTargets = &maxNotReady
+		})
+
+		It("should not validate", func() {
+			Expect(spec.Validate(level)).ToNot(BeNil())
+		})
+	})
+
+	Context("with a maximum number of target restarts on a node level disruption", func() {
+		BeforeEach(func() {
+			level = chaostypes.DisruptionLevelNode
+		})
+
+		It("should not validate", func() {
+			Expect(spec.Validate(level)).ToNot(BeNil())
+		})
+	})
+
+	Describe("GetInterval", func() {
+		It("should return the given interval", func() {
+			Expect(spec.GetInterval()).To(Equal(30 * time.Second))
+		})
+
+		It("should default to 10s", func() {
+			spec.Interval = ""
+
+			Expect(spec.GetInterval()).To(Equal(10 * time.Second))
+		})
+	})
+})
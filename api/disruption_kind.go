@@ -36,6 +36,7 @@ type DisruptionArgs struct {
 	DNSServer            string
 	KubeDNS              string
 	ChaosNamespace       string
+	TrafficController    string
 	DryRun               bool
 	OnInit               bool
 	PulseActiveDuration  time.Duration
@@ -87,11 +88,15 @@ func AppendArgs(args []string, xargs DisruptionArgs) []string {
 		args = append(args, "--kube-dns", xargs.KubeDNS)
 	}
 
-	// append allowed hosts for network disruptions
+	// append allowed hosts and traffic controller for network disruptions
 	if xargs.Kind == chaostypes.DisruptionKindNetworkDisruption {
 		for _, host := range xargs.AllowedHosts {
 			args = append(args, "--allowed-hosts", host)
 		}
+
+		if xargs.TrafficController != "" {
+			args = append(args, "--traffic-controller", xargs.TrafficController)
+		}
 	}
 
 	return args
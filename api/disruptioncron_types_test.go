@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package api_test
+
+import (
+	"time"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DisruptionCronSpec", func() {
+	var spec v1beta1.DisruptionCronSpec
+
+	BeforeEach(func() {
+		count := intstr.FromInt(1)
+		spec = v1beta1.DisruptionCronSpec{
+			Schedule: "*/15 * * * *",
+			Template: v1beta1.DisruptionSpec{
+				Count:       &count,
+				Selector:    map[string]string{"app": "demo"},
+				NodeFailure: &v1beta1.NodeFailureSpec{},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("should validate a valid spec", func() {
+			Expect(spec.Validate()).To(BeNil())
+		})
+
+		Context("with an invalid schedule", func() {
+			BeforeEach(func() {
+				spec.Schedule = "every 15 minutes"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an invalid concurrency policy", func() {
+			BeforeEach(func() {
+				spec.ConcurrencyPolicy = "Allow"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a negative history limit", func() {
+			BeforeEach(func() {
+				historyLimit := -1
+				spec.HistoryLimit = &historyLimit
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an invalid template", func() {
+			BeforeEach(func() {
+				spec.Template.OnInit = true
+				spec.Template.Level = "node"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("defaults", func() {
+		It("should default to the forbid concurrency policy", func() {
+			Expect(spec.GetConcurrencyPolicy()).To(Equal(v1beta1.DisruptionCronConcurrencyPolicyForbid))
+		})
+
+		It("should default to the default history limit", func() {
+			Expect(spec.GetHistoryLimit()).To(Equal(v1beta1.DisruptionCronDefaultHistoryLimit))
+		})
+
+		Context("with a history limit of zero", func() {
+			BeforeEach(func() {
+				historyLimit := 0
+				spec.HistoryLimit = &historyLimit
+			})
+
+			It("should not keep any finished disruption", func() {
+				Expect(spec.GetHistoryLimit()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("GetScheduleTimes", func() {
+		var (
+			earliest, now, last, next time.Time
+			err                       error
+		)
+
+		BeforeEach(func() {
+			earliest = time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+		})
+
+		JustBeforeEach(func() {
+			last, next, err = spec.GetScheduleTimes(earliest, now)
+		})
+
+		Context("without any missed run", func() {
+			BeforeEach(func() {
+				now = earliest.Add(10 * time.Minute)
+			})
+
+			It("should return a zero last time and the next run", func() {
+				Expect(err).To(BeNil())
+				Expect(last.IsZero()).To(BeTrue())
+				Expect(next).To(Equal(earliest.Add(15 * time.Minute)))
+			})
+		})
+
+		Context("with several missed runs", func() {
+			BeforeEach(func() {
+				now = earliest.Add(40 * time.Minute)
+			})
+
+			It("should return the most recent missed run and the next run", func() {
+				Expect(err).To(BeNil())
+				Expect(last).To(Equal(earliest.Add(30 * time.Minute)))
+				Expect(next).To(Equal(earliest.Add(45 * time.Minute)))
+			})
+		})
+
+		Context("with a run scheduled exactly now", func() {
+			BeforeEach(func() {
+				now = earliest.Add(15 * time.Minute)
+			})
+
+			It("should return the current run", func() {
+				Expect(err).To(BeNil())
+				Expect(last).To(Equal(now))
+				Expect(next).To(Equal(earliest.Add(30 * time.Minute)))
+			})
+		})
+	})
+})
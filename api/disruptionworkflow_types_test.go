@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package api_test
+
+import (
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DisruptionWorkflowSpec", func() {
+	var spec v1beta1.DisruptionWorkflowSpec
+
+	newStep := func(name string, parallel bool) v1beta1.DisruptionWorkflowStep {
+		count := intstr.FromInt(1)
+
+		return v1beta1.DisruptionWorkflowStep{
+			Name:     name,
+			Parallel: parallel,
+			Template: v1beta1.DisruptionSpec{
+				Count:       &count,
+				Selector:    map[string]string{"app": "demo"},
+				NodeFailure: &v1beta1.NodeFailureSpec{},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		spec = v1beta1.DisruptionWorkflowSpec{
+			Steps: []v1beta1.DisruptionWorkflowStep{
+				newStep("first", false),
+				newStep("second", false),
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("should validate a valid spec", func() {
+			Expect(spec.Validate()).To(BeNil())
+		})
+
+		Context("without any step", func() {
+			BeforeEach(func() {
+				spec.Steps = nil
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with duplicated step names", func() {
+			BeforeEach(func() {
+				spec.Steps[1].Name = "first"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an invalid step name", func() {
+			BeforeEach(func() {
+				spec.Steps[1].Name = "Second_Step"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an invalid step template", func() {
+			BeforeEach(func() {
+				spec.Steps[1].Template.OnInit = true
+				spec.Steps[1].Template.Level = "node"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("GetStepGroups", func() {
+		It("should run steps serially by default", func() {
+			Expect(spec.GetStepGroups()).To(Equal([][]int{{0}, {1}}))
+		})
+
+		Context("with parallel steps", func() {
+			BeforeEach(func() {
+				spec.Steps = []v1beta1.DisruptionWorkflowStep{
+					newStep("first", true),
+					newStep("second", true),
+					newStep("third", false),
+					newStep("fourth", true),
+					newStep("fifth", false),
+				}
+			})
+
+			It("should group parallel steps with the step before them", func() {
+				Expect(spec.GetStepGroups()).To(Equal([][]int{{0, 1}, {2, 3}, {4}}))
+			})
+		})
+	})
+
+	Describe("GetDisruptionSpec", func() {
+		var step v1beta1.DisruptionWorkflowStep
+
+		BeforeEach(func() {
+			step = newStep("first", false)
+			step.Template.Duration = "10m"
+		})
+
+		It("should keep the template duration", func() {
+			Expect(step.GetDisruptionSpec().Duration).To(Equal(v1beta1.DisruptionDuration("10m")))
+		})
+
+		Context("with a step duration", func() {
+			BeforeEach(func() {
+				step.Duration = "5m"
+			})
+
+			It("should override the template duration without altering the template", func() {
+				Expect(step.GetDisruptionSpec().Duration).To(Equal(v1beta1.DisruptionDuration("5m")))
+				Expect(step.Template.Duration).To(Equal(v1beta1.DisruptionDuration("10m")))
+			})
+		})
+	})
+})
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package api_test
+
+import (
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DNSDisruptionSpec", func() {
+	var spec v1beta1.DNSDisruptionSpec
+
+	BeforeEach(func() {
+		spec = v1beta1.DNSDisruptionSpec{
+			{
+				Hostname: "foo.bar.svc.cluster.local",
+				Record: v1beta1.DNSRecord{
+					Type:  "A",
+					Value: "10.0.0.1,10.0.0.2",
+				},
+			},
+			{
+				Hostname:      "bar.svc.cluster.local",
+				HostnameMatch: "suffix",
+				Record: v1beta1.DNSRecord{
+					Type:        "SERVFAIL",
+					Delay:       500,
+					Probability: 50,
+				},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("should validate a valid spec", func() {
+			Expect(spec.Validate()).To(BeNil())
+		})
+
+		Context("with an unknown record type", func() {
+			BeforeEach(func() {
+				spec[0].Record.Type = "MX"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a record without any value", func() {
+			BeforeEach(func() {
+				spec[0].Record.Value = ""
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a failure with a value", func() {
+			BeforeEach(func() {
+				spec[1].Record.Value = "10.0.0.1"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with forwarded queries", func() {
+			BeforeEach(func() {
+				spec[1].Record.Type = "FORWARD"
+			})
+
+			It("should validate with a delay", func() {
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("should not validate without any delay", func() {
+				spec[1].Record.Delay = 0
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an invalid regular expression hostname", func() {
+			BeforeEach(func() {
+				spec[0].Hostname = "foo("
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+
+			It("should validate when matched as is", func() {
+				spec[0].HostnameMatch = "exact"
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+		})
+	})
+
+	Describe("GenerateArgs", func() {
+		It("should pass the hostname match, delay and probability when set only", func() {
+			Expect(spec.GenerateArgs()).To(Equal([]string{
+				"dns-disruption",
+				"--host-record-pairs",
+				"foo.bar.svc.cluster.local;A;10.0.0.1,10.0.0.2",
+				"--host-record-pairs",
+				"bar.svc.cluster.local;SERVFAIL;;suffix;500;50",
+			}))
+		})
+	})
+})
@@ -37,7 +37,7 @@ var _ = Describe("GRPCDisruption Validation", func() {
 			}
 			err := spec.Validate().(*multierror.Error)
 			Expect(err.Len()).To(Equal(1))
-			Expect(err.Errors[0].Error()).To(Equal("GRPC: the gRPC disruption must have either ErrorToReturn or OverrideToReturn specified for endpoint /chaosdogfood.ChaosDogfood/order"))
+			Expect(err.Errors[0].Error()).To(Equal("GRPC: the gRPC disruption must have either ErrorToReturn, OverrideToReturn or Latency specified for endpoint /chaosdogfood.ChaosDogfood/order"))
 		})
 	})
 
@@ -359,4 +359,218 @@ var _ = Describe("GRPCDisruption Validation", func() {
 			})
 		})
 	})
+
+	Describe("Alterations with Latency", func() {
+		Context("without error nor override", func() {
+			It("Passes validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						Latency:        "2s",
+						LatencyJitter:  10,
+						QueryPercent:   50,
+					},
+				}
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+		})
+
+		Context("combined with an error", func() {
+			It("Passes validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "UNAVAILABLE",
+						Latency:        "2s",
+					},
+				}
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("Generates injector arguments with the error and the latency", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "UNAVAILABLE",
+						Latency:        "2s",
+						LatencyJitter:  10,
+					},
+				}
+
+				Expect(spec.GenerateArgs()).To(Equal([]string{"grpc-disruption", "--port", "50051", "--endpoint-alterations", "/chaosdogfood.ChaosDogfood/order;error;UNAVAILABLE;0;2s;10;0;"}))
+			})
+		})
+
+		Context("with a jitter but no latency", func() {
+			It("Fails validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "UNAVAILABLE",
+						LatencyJitter:  10,
+					},
+				}
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("Alterations failing streams after a number of messages", func() {
+		Context("combined with an error", func() {
+			It("Passes validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint:    "/chaosdogfood.ChaosDogfood/streamCatalog",
+						ErrorToReturn:     "UNAVAILABLE",
+						FailAfterMessages: 2,
+					},
+				}
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("Generates injector arguments with the number of messages", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint:    "/chaosdogfood.ChaosDogfood/streamCatalog",
+						ErrorToReturn:     "UNAVAILABLE",
+						FailAfterMessages: 2,
+					},
+				}
+
+				Expect(spec.GenerateArgs()).To(Equal([]string{"grpc-disruption", "--port", "50051", "--endpoint-alterations", "/chaosdogfood.ChaosDogfood/streamCatalog;error;UNAVAILABLE;0;;0;2;"}))
+			})
+		})
+
+		Context("without an error", func() {
+			It("Fails validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint:    "/chaosdogfood.ChaosDogfood/streamCatalog",
+						Latency:           "1s",
+						FailAfterMessages: 2,
+					},
+				}
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("Alterations with an override", func() {
+		Context("with a JSON object", func() {
+			It("Passes validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint:   "/chaosdogfood.ChaosDogfood/order",
+						OverrideToReturn: `{"message": "overridden", "confirmationId": 42}`,
+					},
+				}
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("Generates injector arguments with the override", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint:   "/chaosdogfood.ChaosDogfood/order",
+						OverrideToReturn: `{"message": "overridden; twice"}`,
+					},
+				}
+
+				Expect(spec.GenerateArgs()).To(Equal([]string{"grpc-disruption", "--port", "50051", "--endpoint-alterations", `/chaosdogfood.ChaosDogfood/order;override;{"message": "overridden; twice"};0;;0;0;`}))
+			})
+		})
+
+		Context("with an invalid JSON object", func() {
+			It("Fails validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint:   "/chaosdogfood.ChaosDogfood/order",
+						OverrideToReturn: `"overridden"`,
+					},
+				}
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("Alterations with metadata matchers", func() {
+		Context("with alterations of different metadata adding up to more than 100 percent", func() {
+			It("Passes validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "UNAVAILABLE",
+						QueryPercent:   100,
+						Metadata:       map[string]string{"x-tenant": "test"},
+					},
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "NOT_FOUND",
+						QueryPercent:   100,
+					},
+				}
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("Generates injector arguments with the encoded metadata", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "UNAVAILABLE",
+						QueryPercent:   100,
+						Metadata:       map[string]string{"x-tenant": "test;prod", ":authority": "orders"},
+					},
+				}
+
+				Expect(spec.GenerateArgs()).To(Equal([]string{"grpc-disruption", "--port", "50051", "--endpoint-alterations", "/chaosdogfood.ChaosDogfood/order;error;UNAVAILABLE;100;;0;0;%3Aauthority=orders&x-tenant=test%3Bprod"}))
+			})
+		})
+
+		Context("with alterations of the same metadata adding up to more than 100 percent", func() {
+			It("Fails validation", func() {
+				spec.Endpoints = []v1beta1.EndpointAlteration{
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "UNAVAILABLE",
+						QueryPercent:   60,
+						Metadata:       map[string]string{"x-tenant": "test"},
+					},
+					{
+						TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+						ErrorToReturn:  "NOT_FOUND",
+						QueryPercent:   60,
+						Metadata:       map[string]string{"x-tenant": "test"},
+					},
+				}
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("Listener port", func() {
+		It("Defaults to the port", func() {
+			Expect(spec.GetListenerPort()).To(Equal(50051))
+		})
+
+		It("Generates injector arguments with the listener port", func() {
+			spec.ListenerPort = 50052
+			spec.Endpoints = []v1beta1.EndpointAlteration{
+				{
+					TargetEndpoint: "/chaosdogfood.ChaosDogfood/order",
+					ErrorToReturn:  "UNAVAILABLE",
+				},
+			}
+
+			Expect(spec.GetListenerPort()).To(Equal(50052))
+			Expect(spec.GenerateArgs()).To(Equal([]string{"grpc-disruption", "--port", "50051", "--listener-port", "50052", "--endpoint-alterations", "/chaosdogfood.ChaosDogfood/order;error;UNAVAILABLE;0;;0;0;"}))
+		})
+	})
 })
@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package api_test
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPDisruptionSpec", func() {
+	var spec v1beta1.HTTPDisruptionSpec
+
+	BeforeEach(func() {
+		spec = v1beta1.HTTPDisruptionSpec{
+			Ports: []int{80},
+			Rules: []v1beta1.HTTPRule{
+				{
+					Method:     "GET",
+					Path:       "/api/orders*",
+					Percent:    20,
+					StatusCode: 503,
+				},
+				{
+					Path:    "/api/catalog",
+					Headers: map[string]string{"X-Chaos": "true"},
+					Delay:   "2s",
+					Abort:   true,
+				},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("should validate a valid spec", func() {
+			Expect(spec.Validate()).To(BeNil())
+		})
+
+		Context("without any port", func() {
+			BeforeEach(func() {
+				spec.Ports = nil
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an invalid port", func() {
+			BeforeEach(func() {
+				spec.Ports = []int{70000}
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("without any rule", func() {
+			BeforeEach(func() {
+				spec.Rules = nil
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a wildcard in the middle of a rule path", func() {
+			BeforeEach(func() {
+				spec.Rules[0].Path = "/api/*/orders"
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with an unknown status code", func() {
+			BeforeEach(func() {
+				spec.Rules[0].StatusCode = 299
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a rule returning a status code and aborting the connection", func() {
+			BeforeEach(func() {
+				spec.Rules[0].Abort = true
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a rule without any action", func() {
+			BeforeEach(func() {
+				spec.Rules[0].StatusCode = 0
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("GenerateArgs", func() {
+		It("should pass each port and JSON encoded rule as a single argument", func() {
+			args := spec.GenerateArgs()
+
+			Expect(args[:5]).To(Equal([]string{"http-disruption", "--ports", "80", "--rules", `{"method":"GET","path":"/api/orders*","percent":20,"statusCode":503}`}))
+			Expect(args[5]).To(Equal("--rules"))
+
+			rule := v1beta1.HTTPRule{}
+			Expect(json.Unmarshal([]byte(args[6]), &rule)).To(BeNil())
+			Expect(rule).To(Equal(spec.Rules[1]))
+		})
+	})
+})
@@ -0,0 +1,304 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package api_test
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkDisruptionSpec", func() {
+	var spec v1beta1.NetworkDisruptionSpec
+
+	BeforeEach(func() {
+		spec = v1beta1.NetworkDisruptionSpec{
+			Drop: 10,
+			Profiles: []v1beta1.NetworkDisruptionProfileSpec{
+				{
+					Hosts: []v1beta1.NetworkDisruptionHostSpec{
+						{
+							Host: "10.128.0.0/9",
+							Port: 443,
+						},
+					},
+					Delay:          200,
+					BandwidthLimit: 1000000,
+				},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("should validate a valid spec", func() {
+			Expect(spec.Validate()).To(BeNil())
+		})
+
+		Context("with a profile without any host or service", func() {
+			BeforeEach(func() {
+				spec.Profiles[0].Hosts = nil
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a profile without any disruption", func() {
+			BeforeEach(func() {
+				spec.Profiles[0].Delay = 0
+				spec.Profiles[0].BandwidthLimit = 0
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with too many profiles", func() {
+			BeforeEach(func() {
+				for len(spec.Profiles) <= v1beta1.MaxNetworkDisruptionProfiles {
+					spec.Profiles = append(spec.Profiles, spec.Profiles[0])
+				}
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with hosts but without any disruption at the disruption level", func() {
+			BeforeEach(func() {
+				spec.Drop = 0
+				spec.Hosts = []v1beta1.NetworkDisruptionHostSpec{
+					{
+						Host: "10.0.0.1",
+					},
+				}
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a loss model", func() {
+			BeforeEach(func() {
+				spec.Drop = 0
+				spec.LossModel = &v1beta1.NetworkDisruptionLossModelSpec{
+					GilbertElliott: &v1beta1.NetworkDisruptionGilbertElliottSpec{
+						P:            1,
+						R:            25,
+						BadStateLoss: 100,
+					},
+				}
+			})
+
+			It("should validate", func() {
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			Context("along with a drop percentage", func() {
+				BeforeEach(func() {
+					spec.Drop = 10
+				})
+
+				It("should not validate", func() {
+					Expect(spec.Validate()).ToNot(BeNil())
+				})
+			})
+
+			Context("with both models", func() {
+				BeforeEach(func() {
+					spec.LossModel.FourState = &v1beta1.NetworkDisruptionFourStateSpec{P13: 5, P31: 80}
+				})
+
+				It("should not validate", func() {
+					Expect(spec.Validate()).ToNot(BeNil())
+				})
+			})
+
+			Context("with a 4-state model leaving a state more than always", func() {
+				BeforeEach(func() {
+					spec.LossModel.GilbertElliott = nil
+					spec.LossModel.FourState = &v1beta1.NetworkDisruptionFourStateSpec{P13: 5, P31: 80, P32: 30}
+				})
+
+				It("should not validate", func() {
+					Expect(spec.Validate()).ToNot(BeNil())
+				})
+			})
+		})
+
+		Context("with a correlation without the value it correlates", func() {
+			BeforeEach(func() {
+				spec.DuplicateCorrelation = 25
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with reordering", func() {
+			BeforeEach(func() {
+				spec.Reorder = 25
+				spec.ReorderGap = 5
+			})
+
+			It("should not validate without any delay", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+
+			It("should validate with a delay", func() {
+				spec.Delay = 10
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+		})
+
+		Context("with a slot maximum delay lower than its minimum delay", func() {
+			BeforeEach(func() {
+				spec.Slot = &v1beta1.NetworkDisruptionSlotSpec{
+					MinDelay: 1000,
+					MaxDelay: 500,
+				}
+			})
+
+			It("should not validate", func() {
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with a partition", func() {
+			BeforeEach(func() {
+				spec = v1beta1.NetworkDisruptionSpec{
+					Partition: &v1beta1.NetworkDisruptionPartitionSpec{
+						Selector:  map[string]string{"app": "foo"},
+						Namespace: "bar",
+					},
+				}
+			})
+
+			It("should validate", func() {
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("should not validate without any selector", func() {
+				spec.Partition.Selector = nil
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+
+			It("should not validate with an invalid selector", func() {
+				spec.Partition.Selector = map[string]string{"app": "foo bar"}
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+
+			It("should not validate along with another disruption", func() {
+				spec.Delay = 100
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+
+			It("should not validate along with hosts", func() {
+				spec.Hosts = []v1beta1.NetworkDisruptionHostSpec{{Host: "10.0.0.1"}}
+
+				Expect(spec.Validate()).ToNot(BeNil())
+			})
+		})
+
+		Context("with reject", func() {
+			BeforeEach(func() {
+				spec.Reject = v1beta1.RejectTCPReset
+				spec.Hosts = []v1beta1.NetworkDisruptionHostSpec{{Host: "10.0.0.1", Port: 443}}
+			})
+
+			It("should validate", func() {
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("should validate along with services", func() {
+				spec.Services = []v1beta1.NetworkDisruptionServiceSpec{{Name: "foo", Namespace: "bar"}}
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+
+			It("should not validate a TCP reset of UDP packets", func() {
+				spec.Hosts[0].Protocol = "udp"
+
+				Expect(spec.Validate()).ToNot(BeNil())
+
+				spec.Reject = v1beta1.RejectICMPPortUnreachable
+
+				Expect(spec.Validate()).To(BeNil())
+			})
+		})
+
+		Context("with profiles only", func() {
+			BeforeEach(func() {
+				spec.Drop = 0
+			})
+
+			It("should validate", func() {
+				Expect(spec.Validate()).To(BeNil())
+			})
+		})
+	})
+
+	Describe("GenerateArgs", func() {
+		It("should pass each JSON encoded profile as a single argument", func() {
+			args := spec.GenerateArgs()
+
+			Expect(args[len(args)-2:]).To(Equal([]string{"--profiles", `{"hosts":[{"host":"10.128.0.0/9","port":443}],"delay":200,"bandwidthLimit":1000000}`}))
+
+			profile := v1beta1.NetworkDisruptionProfileSpec{}
+			Expect(json.Unmarshal([]byte(args[len(args)-1]), &profile)).To(BeNil())
+			Expect(profile).To(Equal(spec.Profiles[0]))
+		})
+
+		It("should pass the netem parameters when set only", func() {
+			Expect(spec.GenerateArgs()).ToNot(ContainElement("--delay-correlation"))
+
+			spec.Delay = 100
+			spec.DelayCorrelation = 25
+			spec.Slot = &v1beta1.NetworkDisruptionSlotSpec{MinDelay: 800}
+
+			args := spec.GenerateArgs()
+			Expect(args).To(ContainElements("--delay-correlation", "25", "--slot", `{"minDelay":800}`))
+			Expect(args).ToNot(ContainElement("--drop-correlation"))
+		})
+
+		It("should pass the ingress shaping flag when enabled only", func() {
+			Expect(spec.GenerateArgs()).ToNot(ContainElement("--ingress-shaping"))
+
+			spec.IngressShaping = true
+
+			Expect(spec.GenerateArgs()).To(ContainElement("--ingress-shaping"))
+		})
+
+		It("should pass the reject type when set only", func() {
+			Expect(spec.GenerateArgs()).ToNot(ContainElement("--reject"))
+
+			spec.Reject = v1beta1.RejectTCPReset
+
+			Expect(spec.GenerateArgs()).To(ContainElements("--reject", "tcp-reset"))
+		})
+
+		It("should pass the JSON encoded partition", func() {
+			spec.Partition = &v1beta1.NetworkDisruptionPartitionSpec{
+				Selector:  map[string]string{"app": "foo"},
+				Namespace: "bar",
+			}
+
+			Expect(spec.GenerateArgs()).To(ContainElements("--partition", `{"selector":{"app":"foo"},"namespace":"bar"}`))
+		})
+	})
+})
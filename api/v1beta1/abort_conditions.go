@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// defaultAbortConditionsInterval is the default interval between two abort conditions evaluations
+	defaultAbortConditionsInterval = 10 * time.Second
+	// MaxHTTPProbeLatency is the highest maximum latency of an HTTP probe, bounding the time the probes, sent in parallel, hold the reconcile loop
+	MaxHTTPProbeLatency = 5 * time.Second
+)
+
+// AbortConditionsSpec represents the conditions under which a disruption is deleted before its duration expires,
+// meaning the system under test has left its steady state
+type AbortConditionsSpec struct {
+	Interval DisruptionDuration `json:"interval,omitempty"` // interval between two evaluations, defaults to 10s
+	// +nullable
+	HTTPProbes []HTTPProbeSpec `json:"httpProbes,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +nullable
+	MaxTargetRestarts *int `json:"maxTargetRestarts,omitempty"` // maximum number of containers restarts of a single target pod since the disruption started
+	// +nullable
+	MaxNotReadyTargets *intstr.IntOrString `json:"maxNotReadyTargets,omitempty"` // maximum number (or percentage) of not ready targets
+}
+
+// HTTPProbeSpec represents an HTTP GET request expected to succeed while the disruption is ongoing
+type HTTPProbeSpec struct {
+	// +kubebuilder:validation:Required
+	// +ddmark:validation:Required=true
+	URL string `json:"url"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=599
+	ExpectedStatusCode int                `json:"expectedStatusCode,omitempty"` // expected response status code, defaults to any 2xx code
+	MaxLatency         DisruptionDuration `json:"maxLatency,omitempty"`         // maximum response time (up to 5s), also used as the request timeout
+}
+
+// Validate validates args for the given abort conditions
+func (s *AbortConditionsSpec) Validate(level chaostypes.DisruptionLevel) (retErr error) {
+	if s.Interval.Duration() < 0 {
+		retErr = multierror.Append(retErr, errors.New("the abort conditions interval can't be negative"))
+	}
+
+	if s.MaxTargetRestarts != nil {
+		if *s.MaxTargetRestarts < 0 {
+			retErr = multierror.Append(retErr, errors.New("the maximum number of target restarts can't be negative"))
+		}
+
+		if level == chaostypes.DisruptionLevelNode {
+			retErr = multierror.Append(retErr, errors.New("the maximum number of target restarts can only be used with pod level disruptions"))
+		}
+	}
+
+	if s.MaxNotReadyTargets != nil {
+		value, isPercent, err := GetIntOrPercentValueSafely(s.MaxNotReadyTargets)
+		if err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("invalid maximum number of not ready targets: %w", err))
+		} else if value < 0 || (isPercent && value > 100) {
+			retErr = multierror.Append(retErr, fmt.Errorf("the maximum number of not ready targets must be a positive integer or a percentage between 0%% and 100%%"))
+		}
+	}
+
+	for _, probe := range s.HTTPProbes {
+		if err := probe.Validate(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	return multierror.Prefix(retErr, "AbortConditions:")
+}
+
+// GetInterval returns the interval between two evaluations, defaulting to 10s
+func (s *AbortConditionsSpec) GetInterval() time.Duration {
+	if s.Interval.Duration() <= 0 {
+		return defaultAbortConditionsInterval
+	}
+
+	return s.Interval.Duration()
+}
+
+// Validate validates args for the given HTTP probe
+func (s *HTTPProbeSpec) Validate() (retErr error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid probe URL %s: %w", s.URL, err))
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid probe URL %s: an absolute http or https URL is expected", s.URL))
+	}
+
+	if s.ExpectedStatusCode != 0 && http.StatusText(s.ExpectedStatusCode) == "" {
+		retErr = multierror.Append(retErr, fmt.Errorf("unknown probe expected status code %d", s.ExpectedStatusCode))
+	}
+
+	if s.MaxLatency.Duration() < 0 {
+		retErr = multierror.Append(retErr, errors.New("the probe maximum latency can't be negative"))
+	} else if s.MaxLatency.Duration() > MaxHTTPProbeLatency {
+		retErr = multierror.Append(retErr, fmt.Errorf("the probe maximum latency can't be more than %s", MaxHTTPProbeLatency))
+	}
+
+	return retErr
+}
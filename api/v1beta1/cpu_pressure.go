@@ -5,13 +5,42 @@
 
 package v1beta1
 
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
 // CPUPressureSpec represents a cpu pressure disruption
 type CPUPressureSpec struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=1
+	// +ddmark:validation:Maximum=100
+	// +nullable
+	Percentage *int `json:"percentage,omitempty"` // target load of each stressed core (between 1 and 100), defaults to 100
+	// +nullable
+	Count *intstr.IntOrString `json:"count,omitempty"` // number of cores to stress in either integer form or percent form appended with a %, defaults to all allocated cores
 }
 
 // Validate validates args for the given disruption
-func (s *CPUPressureSpec) Validate() error {
-	return nil
+func (s *CPUPressureSpec) Validate() (retErr error) {
+	if s.Percentage != nil && (*s.Percentage < 1 || *s.Percentage > 100) {
+		retErr = multierror.Append(retErr, fmt.Errorf("percentage must be between 1 and 100, found: %d", *s.Percentage))
+	}
+
+	if s.Count != nil {
+		value, isPercent, err := GetIntOrPercentValueSafely(s.Count)
+		if err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("error determining value of count: %w", err))
+		} else if value <= 0 || (isPercent && value > 100) {
+			retErr = multierror.Append(retErr, fmt.Errorf("count must be a positive integer or a valid percentage value, found: %s", s.Count.String()))
+		}
+	}
+
+	return multierror.Prefix(retErr, "CPUPressure:")
 }
 
 // GenerateArgs generates injection or cleanup pod arguments for the given spec
@@ -20,5 +49,15 @@ func (s *CPUPressureSpec) GenerateArgs() []string {
 		"cpu-pressure",
 	}
 
+	// add load percentage flag if specified
+	if s.Percentage != nil {
+		args = append(args, []string{"--percentage", strconv.Itoa(*s.Percentage)}...)
+	}
+
+	// add cores count flag if specified
+	if s.Count != nil {
+		args = append(args, []string{"--count", s.Count.String()}...)
+	}
+
 	return args
 }
@@ -29,9 +29,9 @@ import (
 )
 
 // DisruptionSpec defines the desired state of Disruption
-// +ddmark:validation:ExclusiveFields={ContainerFailure,CPUPressure,DiskPressure,NodeFailure,Network,DNS}
-// +ddmark:validation:ExclusiveFields={NodeFailure,CPUPressure,DiskPressure,ContainerFailure,Network,DNS}
-// +ddmark:validation:AtLeastOneOf={DNS,CPUPressure,Network,NodeFailure,ContainerFailure,DiskPressure,GRPC}
+// +ddmark:validation:ExclusiveFields={ContainerFailure,CPUPressure,MemoryPressure,DiskPressure,NodeFailure,Network,DNS}
+// +ddmark:validation:ExclusiveFields={NodeFailure,CPUPressure,MemoryPressure,DiskPressure,ContainerFailure,Network,DNS}
+// +ddmark:validation:AtLeastOneOf={DNS,CPUPressure,MemoryPressure,Network,NodeFailure,ContainerFailure,DiskPressure,GRPC,HTTP}
 // +ddmark:validation:AtLeastOneOf={Selector,AdvancedSelector}
 type DisruptionSpec struct {
 	// +kubebuilder:validation:Required
@@ -46,6 +46,8 @@ type DisruptionSpec struct {
 	Unsafemode       *UnsafemodeSpec                   `json:"unsafeMode,omitempty"`       // unsafemode spec used to turn off safemode safety nets
 	StaticTargeting  bool                              `json:"staticTargeting,omitempty"`  // enable dynamic targeting and cluster observation
 	// +nullable
+	RespectPodDisruptionBudgets *bool `json:"respectPodDisruptionBudgets,omitempty"` // cap pod targets to the disruptions allowed by matching pod disruption budgets, defaults to the controller configuration
+	// +nullable
 	Pulse    *DisruptionPulse   `json:"pulse,omitempty"`    // enable pulsing diruptions and specify the duration of the active state and the dormant state of the pulsing duration
 	Duration DisruptionDuration `json:"duration,omitempty"` // time from disruption creation until chaos pods are deleted and no more are created
 	// +kubebuilder:validation:Enum=pod;node;""
@@ -61,11 +63,17 @@ type DisruptionSpec struct {
 	// +nullable
 	CPUPressure *CPUPressureSpec `json:"cpuPressure,omitempty"`
 	// +nullable
+	MemoryPressure *MemoryPressureSpec `json:"memoryPressure,omitempty"`
+	// +nullable
 	DiskPressure *DiskPressureSpec `json:"diskPressure,omitempty"`
 	// +nullable
 	DNS DNSDisruptionSpec `json:"dns,omitempty"`
 	// +nullable
 	GRPC *GRPCDisruptionSpec `json:"grpc,omitempty"`
+	// +nullable
+	HTTP *HTTPDisruptionSpec `json:"http,omitempty"`
+	// +nullable
+	AbortConditions *AbortConditionsSpec `json:"abortConditions,omitempty"` // conditions under which the disruption is deleted before its duration expires
 }
 
 // EmbeddedChaosAPI includes the library so it can be statically exported to chaosli
@@ -138,6 +146,9 @@ type DisruptionStatus struct {
 	InjectedTargetsCount int `json:"injectedTargetsCount"`
 	// Number of targets we want to target (count)
 	DesiredTargetsCount int `json:"desiredTargetsCount"`
+	// Containers restart count of each target pod when first evaluated by the abort conditions
+	// +nullable
+	TargetsInitialRestartCount map[string]int32 `json:"targetsInitialRestartCount,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -231,10 +242,12 @@ func (s *DisruptionSpec) validateGlobalDisruptionScope() (retErr error) {
 	// Rule: on init compatibility
 	if s.OnInit {
 		if s.CPUPressure != nil ||
+			s.MemoryPressure != nil ||
 			s.NodeFailure != nil ||
 			s.ContainerFailure != nil ||
 			s.DiskPressure != nil ||
-			s.GRPC != nil {
+			s.GRPC != nil ||
+			s.HTTP != nil {
 			retErr = multierror.Append(retErr, errors.New("OnInit is only compatible with network and dns disruptions"))
 		}
 
@@ -259,7 +272,7 @@ func (s *DisruptionSpec) validateGlobalDisruptionScope() (retErr error) {
 	// Rule: pulse compatibility
 	if s.Pulse != nil {
 		if s.NodeFailure != nil || s.ContainerFailure != nil {
-			retErr = multierror.Append(retErr, errors.New("pulse is only compatible with network, cpu pressure, disk pressure, dns and grpc disruptions"))
+			retErr = multierror.Append(retErr, errors.New("pulse is only compatible with network, cpu pressure, memory pressure, disk pressure, dns, grpc and http disruptions"))
 		}
 
 		if s.Pulse.ActiveDuration.Duration() < chaostypes.PulsingDisruptionMinimumDuration {
@@ -275,11 +288,22 @@ func (s *DisruptionSpec) validateGlobalDisruptionScope() (retErr error) {
 		retErr = multierror.Append(retErr, errors.New("GRPC disruptions can only be applied at the pod level"))
 	}
 
+	if s.HTTP != nil && s.Level != chaostypes.DisruptionLevelPod && s.Level != chaostypes.DisruptionLevelUnspecified {
+		retErr = multierror.Append(retErr, errors.New("HTTP disruptions can only be applied at the pod level"))
+	}
+
 	// Rule: count must be valid
 	if err := ValidateCount(s.Count); err != nil {
 		retErr = multierror.Append(retErr, err)
 	}
 
+	// Rule: abort conditions must be valid
+	if s.AbortConditions != nil {
+		if err := s.AbortConditions.Validate(s.Level); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
 	return retErr
 }
 
@@ -296,12 +320,16 @@ func (s *DisruptionSpec) DisruptionKindPicker(kind chaostypes.DisruptionKindName
 		disruptionKind = s.Network
 	case chaostypes.DisruptionKindCPUPressure:
 		disruptionKind = s.CPUPressure
+	case chaostypes.DisruptionKindMemoryPressure:
+		disruptionKind = s.MemoryPressure
 	case chaostypes.DisruptionKindDiskPressure:
 		disruptionKind = s.DiskPressure
 	case chaostypes.DisruptionKindDNSDisruption:
 		disruptionKind = s.DNS
 	case chaostypes.DisruptionKindGRPCDisruption:
 		disruptionKind = s.GRPC
+	case chaostypes.DisruptionKindHTTPDisruption:
+		disruptionKind = s.HTTP
 	}
 
 	return disruptionKind
@@ -356,6 +384,10 @@ func (s *DisruptionSpec) GetDisruptionCount() int {
 		count++
 	}
 
+	if s.MemoryPressure != nil {
+		count++
+	}
+
 	if s.ContainerFailure != nil {
 		count++
 	}
@@ -372,6 +404,10 @@ func (s *DisruptionSpec) GetDisruptionCount() int {
 		count++
 	}
 
+	if s.HTTP != nil {
+		count++
+	}
+
 	if s.Network != nil {
 		count++
 	}
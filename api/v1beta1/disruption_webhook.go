@@ -388,11 +388,17 @@ func safetyNetNeitherHostNorPort(r Disruption) bool {
 	}
 
 	// if hosts are not defined, this also falls into the safety net
-	if r.Spec.Network.Hosts == nil || len(r.Spec.Network.Hosts) == 0 {
+	// unless the disruption only applies to the hosts and services of its profiles
+	if (r.Spec.Network.Hosts == nil || len(r.Spec.Network.Hosts) == 0) && (r.Spec.Network.HasDisruptions() || len(r.Spec.Network.Profiles) == 0) {
 		return true
 	}
 
-	for _, host := range r.Spec.Network.Hosts {
+	hosts := append([]NetworkDisruptionHostSpec{}, r.Spec.Network.Hosts...)
+	for _, profile := range r.Spec.Network.Profiles {
+		hosts = append(hosts, profile.Hosts...)
+	}
+
+	for _, host := range hosts {
 		if host.Port == 0 && host.Host == "" {
 			return true
 		}
@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DisruptionCronConcurrencyPolicy describes how a disruption cron handles a scheduled run while a previously created disruption is still ongoing
+type DisruptionCronConcurrencyPolicy string
+
+const (
+	// DisruptionCronConcurrencyPolicyForbid skips the scheduled run if the previously created disruption is still ongoing
+	DisruptionCronConcurrencyPolicyForbid DisruptionCronConcurrencyPolicy = "Forbid"
+	// DisruptionCronConcurrencyPolicyReplace deletes the ongoing disruption and creates a new one
+	DisruptionCronConcurrencyPolicyReplace DisruptionCronConcurrencyPolicy = "Replace"
+
+	// DisruptionCronDefaultHistoryLimit is the number of finished disruptions kept by default
+	DisruptionCronDefaultHistoryLimit = 3
+
+	// disruptionCronMaxNameLength is the maximum length of a disruption cron name so the name of
+	// created disruptions (<cron name>-<unix timestamp>) is still a valid label value
+	disruptionCronMaxNameLength = 52
+)
+
+// DisruptionCronSpec defines the desired state of DisruptionCron
+type DisruptionCronSpec struct {
+	// +kubebuilder:validation:Required
+	// +ddmark:validation:Required=true
+	Schedule string `json:"schedule"` // standard cron schedule (5 fields) at which disruptions are created
+	// +kubebuilder:validation:Enum=Forbid;Replace;""
+	// +ddmark:validation:Enum=Forbid;Replace;""
+	ConcurrencyPolicy DisruptionCronConcurrencyPolicy `json:"concurrencyPolicy,omitempty"` // defaults to Forbid
+	// +kubebuilder:validation:Minimum=0
+	// +nullable
+	HistoryLimit *int `json:"historyLimit,omitempty"` // number of finished disruptions to keep, defaults to 3
+	// +kubebuilder:validation:Required
+	Template DisruptionSpec `json:"template"` // spec of the created disruptions
+}
+
+// DisruptionCronStatus defines the observed state of DisruptionCron
+type DisruptionCronStatus struct {
+	// Last time a disruption was scheduled, even if its creation was skipped by the concurrency policy
+	// +nullable
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Name of the last created disruption
+	LastDisruptionName string `json:"lastDisruptionName,omitempty"`
+	// Injection status of the last created disruption
+	// +kubebuilder:validation:Enum=NotInjected;PartiallyInjected;Injected;PreviouslyInjected;""
+	LastInjectionStatus chaostypes.DisruptionInjectionStatus `json:"lastInjectionStatus,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DisruptionCron is the Schema for the disruptioncrons API
+// +kubebuilder:resource:shortName=discron
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="Last Injection Status",type=string,JSONPath=`.status.lastInjectionStatus`
+type DisruptionCron struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DisruptionCronSpec   `json:"spec,omitempty"`
+	Status DisruptionCronStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DisruptionCronList contains a list of DisruptionCron
+type DisruptionCronList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DisruptionCron `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DisruptionCron{}, &DisruptionCronList{})
+}
+
+// Validate validates the disruption cron spec and the templated disruption spec
+func (s *DisruptionCronSpec) Validate() (retErr error) {
+	if _, err := cron.ParseStandard(s.Schedule); err != nil {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid schedule %s: %w", s.Schedule, err))
+	}
+
+	switch s.ConcurrencyPolicy {
+	case "", DisruptionCronConcurrencyPolicyForbid, DisruptionCronConcurrencyPolicyReplace:
+	default:
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid concurrency policy %s, allowed values are %s and %s", s.ConcurrencyPolicy, DisruptionCronConcurrencyPolicyForbid, DisruptionCronConcurrencyPolicyReplace))
+	}
+
+	if s.HistoryLimit != nil && *s.HistoryLimit < 0 {
+		retErr = multierror.Append(retErr, errors.New("the history limit can't be negative"))
+	}
+
+	if err := s.Template.Validate(); err != nil {
+		retErr = multierror.Append(retErr, multierror.Prefix(err, "Template:"))
+	}
+
+	return multierror.Prefix(retErr, "DisruptionCron:")
+}
+
+// GetConcurrencyPolicy returns the concurrency policy, defaulting to Forbid
+func (s *DisruptionCronSpec) GetConcurrencyPolicy() DisruptionCronConcurrencyPolicy {
+	if s.ConcurrencyPolicy == "" {
+		return DisruptionCronConcurrencyPolicyForbid
+	}
+
+	return s.ConcurrencyPolicy
+}
+
+// GetHistoryLimit returns the number of finished disruptions to keep, defaulting to DisruptionCronDefaultHistoryLimit
+func (s *DisruptionCronSpec) GetHistoryLimit() int {
+	if s.HistoryLimit == nil {
+		return DisruptionCronDefaultHistoryLimit
+	}
+
+	return *s.HistoryLimit
+}
+
+// GetScheduleTimes returns the most recent schedule time between the given earliest time (excluded) and now (included),
+// or a zero time if no run was missed, as well as the next schedule time after now
+func (s *DisruptionCronSpec) GetScheduleTimes(earliest, now time.Time) (last time.Time, next time.Time, err error) {
+	schedule, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error parsing schedule %s: %w", s.Schedule, err)
+	}
+
+	// only the most recent missed run matters since at most one disruption is created per reconcile loop
+	for t := schedule.Next(earliest); !t.After(now); t = schedule.Next(t) {
+		last = t
+	}
+
+	return last, schedule.Next(now), nil
+}
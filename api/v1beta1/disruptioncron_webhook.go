@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/chaos-controller/ddmark"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// SetupWebhookWithManager registers the disruption cron validating webhook
+// it relies on the configuration initialized by the disruption webhook setup, so it must be called after it
+func (r *DisruptionCron) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:webhookVersions={v1},path=/validate-chaos-datadoghq-com-v1beta1-disruptioncron,mutating=false,failurePolicy=fail,sideEffects=None,groups=chaos.datadoghq.com,resources=disruptioncrons,verbs=create;update,versions=v1beta1,name=vdisruptioncron.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &DisruptionCron{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *DisruptionCron) ValidateCreate() error {
+	logger.Debugw("validating created disruption cron", "instance", r.Name, "namespace", r.Namespace)
+
+	// delete-only mode, reject everything trying to be created
+	if deleteOnly {
+		return errors.New("the controller is currently in delete-only mode, you can't create new disruption crons for now")
+	}
+
+	// created disruptions are named after the cron, their name must remain a valid label value
+	if len(r.Name) > disruptionCronMaxNameLength {
+		return fmt.Errorf("invalid disruption cron name: must be no more than %d characters", disruptionCronMaxNameLength)
+	}
+
+	return r.validateSpec()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+// updating the template only affects disruptions created afterwards
+func (r *DisruptionCron) ValidateUpdate(old runtime.Object) error {
+	logger.Debugw("validating updated disruption cron", "instance", r.Name, "namespace", r.Namespace)
+
+	return r.validateSpec()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *DisruptionCron) ValidateDelete() error {
+	return nil
+}
+
+// validateSpec validates the disruption cron spec the same way a disruption spec is validated on creation
+func (r *DisruptionCron) validateSpec() error {
+	// handle a disruption using the onInit feature without the handler being enabled
+	if !handlerEnabled && r.Spec.Template.OnInit {
+		return errors.New("the chaos handler is disabled but the disruption template onInit field is set to true, please enable the handler by specifying the --handler-enabled flag to the controller if you want to use the onInit feature (requires Kubernetes >= 1.15)")
+	}
+
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+
+	multiErr := ddmark.ValidateStructMultierror(r.Spec, "validation_webhook", chaostypes.DDMarkChaoslibPrefix)
+	if multiErr.ErrorOrNil() != nil {
+		return multierror.Prefix(multiErr, "ddmark: ")
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/hashicorp/go-multierror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DisruptionWorkflowPhase represents the phase of a disruption workflow
+type DisruptionWorkflowPhase string
+
+// DisruptionWorkflowStepPhase represents the phase of a disruption workflow step
+type DisruptionWorkflowStepPhase string
+
+const (
+	// DisruptionWorkflowPhaseRunning is the phase of a workflow with steps left to run
+	DisruptionWorkflowPhaseRunning DisruptionWorkflowPhase = "Running"
+	// DisruptionWorkflowPhaseCompleted is the phase of a workflow with all its steps completed
+	DisruptionWorkflowPhaseCompleted DisruptionWorkflowPhase = "Completed"
+	// DisruptionWorkflowPhaseAborted is the phase of a workflow stopped because of a failed step
+	DisruptionWorkflowPhaseAborted DisruptionWorkflowPhase = "Aborted"
+
+	// DisruptionWorkflowStepPhasePending is the phase of a step waiting to be started
+	DisruptionWorkflowStepPhasePending DisruptionWorkflowStepPhase = "Pending"
+	// DisruptionWorkflowStepPhaseRunning is the phase of a step with an ongoing disruption
+	DisruptionWorkflowStepPhaseRunning DisruptionWorkflowStepPhase = "Running"
+	// DisruptionWorkflowStepPhaseCompleted is the phase of a step with an injected disruption which has expired
+	DisruptionWorkflowStepPhaseCompleted DisruptionWorkflowStepPhase = "Completed"
+	// DisruptionWorkflowStepPhaseFailed is the phase of a step with a disruption which could not be created or injected
+	DisruptionWorkflowStepPhaseFailed DisruptionWorkflowStepPhase = "Failed"
+	// DisruptionWorkflowStepPhaseAborted is the phase of a running step stopped because another step failed
+	DisruptionWorkflowStepPhaseAborted DisruptionWorkflowStepPhase = "Aborted"
+	// DisruptionWorkflowStepPhaseSkipped is the phase of a pending step which will never run because another step failed
+	DisruptionWorkflowStepPhaseSkipped DisruptionWorkflowStepPhase = "Skipped"
+)
+
+// DisruptionWorkflowSpec defines the desired state of DisruptionWorkflow
+type DisruptionWorkflowSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +ddmark:validation:Required=true
+	Steps []DisruptionWorkflowStep `json:"steps"` // steps to run, in order
+}
+
+// DisruptionWorkflowStep is a disruption to create at a given time of a workflow
+// consecutive steps are run serially unless parallel is set: a parallel step belongs to the same group as the step before it,
+// a group starts when all the steps of the previous group are completed
+type DisruptionWorkflowStep struct {
+	// +kubebuilder:validation:Required
+	// +ddmark:validation:Required=true
+	Name       string             `json:"name"`                 // step name, used to name the created disruption
+	Parallel   bool               `json:"parallel,omitempty"`   // run the step alongside the previous one
+	StartAfter DisruptionDuration `json:"startAfter,omitempty"` // delay between the step group start and the disruption creation
+	Duration   DisruptionDuration `json:"duration,omitempty"`   // disruption duration, overriding the template one
+	// +kubebuilder:validation:Required
+	Template DisruptionSpec `json:"template"` // spec of the created disruption
+}
+
+// DisruptionWorkflowStepStatus defines the observed state of a DisruptionWorkflow step
+type DisruptionWorkflowStepStatus struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed;Aborted;Skipped
+	Phase DisruptionWorkflowStepPhase `json:"phase"`
+	// Name of the disruption created by the step
+	DisruptionName string `json:"disruptionName,omitempty"`
+	// Injection status of the disruption created by the step before it expired
+	// +kubebuilder:validation:Enum=NotInjected;PartiallyInjected;Injected;PreviouslyInjected;""
+	InjectionStatus chaostypes.DisruptionInjectionStatus `json:"injectionStatus,omitempty"`
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +nullable
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Reason of the step failure, if any
+	Message string `json:"message,omitempty"`
+}
+
+// DisruptionWorkflowStatus defines the observed state of DisruptionWorkflow
+type DisruptionWorkflowStatus struct {
+	// +kubebuilder:validation:Enum=Running;Completed;Aborted;""
+	Phase DisruptionWorkflowPhase `json:"phase,omitempty"`
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +nullable
+	Steps []DisruptionWorkflowStepStatus `json:"steps,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DisruptionWorkflow is the Schema for the disruptionworkflows API
+// +kubebuilder:resource:shortName=disflow
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Start",type=date,JSONPath=`.status.startTime`
+type DisruptionWorkflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DisruptionWorkflowSpec   `json:"spec,omitempty"`
+	Status DisruptionWorkflowStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DisruptionWorkflowList contains a list of DisruptionWorkflow
+type DisruptionWorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DisruptionWorkflow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DisruptionWorkflow{}, &DisruptionWorkflowList{})
+}
+
+// Validate validates the workflow steps and their templated disruption specs
+func (s *DisruptionWorkflowSpec) Validate() (retErr error) {
+	if len(s.Steps) == 0 {
+		retErr = multierror.Append(retErr, errors.New("at least one step must be specified"))
+	}
+
+	names := map[string]struct{}{}
+
+	for _, step := range s.Steps {
+		if errs := validation.IsDNS1123Label(step.Name); len(errs) > 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("invalid step name %s: %v", step.Name, errs))
+		}
+
+		if _, found := names[step.Name]; found {
+			retErr = multierror.Append(retErr, fmt.Errorf("step name %s is used more than once", step.Name))
+		}
+
+		names[step.Name] = struct{}{}
+
+		if step.StartAfter.Duration() < 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("step %s start delay can't be negative", step.Name))
+		}
+
+		if step.Duration.Duration() < 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("step %s duration can't be negative", step.Name))
+		}
+
+		template := step.GetDisruptionSpec()
+		if err := template.Validate(); err != nil {
+			retErr = multierror.Append(retErr, multierror.Prefix(err, fmt.Sprintf("Step %s:", step.Name)))
+		}
+	}
+
+	return multierror.Prefix(retErr, "DisruptionWorkflow:")
+}
+
+// GetStepGroups returns the indexes of the steps grouped by the steps running in parallel, in order
+func (s *DisruptionWorkflowSpec) GetStepGroups() [][]int {
+	groups := [][]int{}
+
+	for i, step := range s.Steps {
+		if i == 0 || !step.Parallel {
+			groups = append(groups, []int{})
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], i)
+	}
+
+	return groups
+}
+
+// GetDisruptionSpec returns the spec of the disruption to create for the step
+func (s *DisruptionWorkflowStep) GetDisruptionSpec() DisruptionSpec {
+	spec := s.Template.DeepCopy()
+
+	if s.Duration != "" {
+		spec.Duration = s.Duration
+	}
+
+	return *spec
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/DataDog/chaos-controller/ddmark"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// disruptionNameMaxLength is the maximum length of a disruption name so it is still a valid label value
+const disruptionNameMaxLength = 63
+
+// SetupWebhookWithManager registers the disruption workflow validating webhook
+// it relies on the configuration initialized by the disruption webhook setup, so it must be called after it
+func (r *DisruptionWorkflow) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:webhookVersions={v1},path=/validate-chaos-datadoghq-com-v1beta1-disruptionworkflow,mutating=false,failurePolicy=fail,sideEffects=None,groups=chaos.datadoghq.com,resources=disruptionworkflows,verbs=create;update,versions=v1beta1,name=vdisruptionworkflow.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &DisruptionWorkflow{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *DisruptionWorkflow) ValidateCreate() error {
+	logger.Debugw("validating created disruption workflow", "instance", r.Name, "namespace", r.Namespace)
+
+	// delete-only mode, reject everything trying to be created
+	if deleteOnly {
+		return errors.New("the controller is currently in delete-only mode, you can't create new disruption workflows for now")
+	}
+
+	// created disruptions are named after the workflow and the step (<workflow name>-<step name>)
+	for _, step := range r.Spec.Steps {
+		if len(r.Name)+len(step.Name)+1 > disruptionNameMaxLength {
+			return fmt.Errorf("invalid step name %s: the workflow and step names length must be no more than %d characters", step.Name, disruptionNameMaxLength-1)
+		}
+	}
+
+	for _, step := range r.Spec.Steps {
+		// handle a disruption using the onInit feature without the handler being enabled
+		if !handlerEnabled && step.Template.OnInit {
+			return fmt.Errorf("the chaos handler is disabled but the step %s disruption template onInit field is set to true, please enable the handler by specifying the --handler-enabled flag to the controller if you want to use the onInit feature (requires Kubernetes >= 1.15)", step.Name)
+		}
+	}
+
+	if err := r.Spec.Validate(); err != nil {
+		return err
+	}
+
+	multiErr := ddmark.ValidateStructMultierror(r.Spec, "validation_webhook", chaostypes.DDMarkChaoslibPrefix)
+	if multiErr.ErrorOrNil() != nil {
+		return multierror.Prefix(multiErr, "ddmark: ")
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *DisruptionWorkflow) ValidateUpdate(old runtime.Object) error {
+	logger.Debugw("validating updated disruption workflow", "instance", r.Name, "namespace", r.Namespace)
+
+	// steps are started according to the workflow status, changing them while the workflow runs would make it inconsistent
+	if !reflect.DeepEqual(old.(*DisruptionWorkflow).Spec, r.Spec) {
+		return errors.New("a disruption workflow spec cannot be updated, please delete and recreate it if needed")
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *DisruptionWorkflow) ValidateDelete() error {
+	return nil
+}
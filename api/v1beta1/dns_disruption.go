@@ -8,8 +8,10 @@ package v1beta1
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/DataDog/chaos-controller/utils"
 	"github.com/hashicorp/go-multierror"
 )
 
@@ -18,16 +20,44 @@ type DNSDisruptionSpec []HostRecordPair
 
 // HostRecordPair represents a hostname and a corresponding dns record override
 type HostRecordPair struct {
-	Hostname string    `json:"hostname"`
-	Record   DNSRecord `json:"record"`
+	Hostname string `json:"hostname"`
+	// HostnameMatch tells how the hostname is matched: as a regular expression (regex, the default), as is (exact),
+	// with `*` matching any characters (wildcard) or as a domain along with all its subdomains (suffix)
+	// +kubebuilder:validation:Enum=regex;exact;wildcard;suffix;""
+	// +ddmark:validation:Enum=regex;exact;wildcard;suffix;""
+	HostnameMatch string    `json:"hostnameMatch,omitempty"`
+	Record        DNSRecord `json:"record"`
 }
 
-// DNSRecord represents a type of DNS Record, such as A or CNAME, and the value of that record
+// DNSRecord represents a type of DNS Record, such as A or CNAME, and the value of that record,
+// or a failure returned instead of any record
 type DNSRecord struct {
+	// Type is the type of record to return (A or CNAME), a response code to return instead of any record (NXDOMAIN, SERVFAIL or REFUSED),
+	// DROP to never answer the queries so they time out, or FORWARD to forward the queries to the upstream server as usual
 	Type  string `json:"type"`
-	Value string `json:"value"`
+	Value string `json:"value,omitempty"`
+	// Delay is the time to wait before answering the matching queries, in ms
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=60000
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=60000
+	Delay uint `json:"delay,omitempty"`
+	// Probability is the percentage of matching queries disrupted, the other ones being forwarded to the upstream server as usual,
+	// all the matching queries being disrupted if not set
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	Probability int `json:"probability,omitempty"`
 }
 
+// dnsRecordTypesWithValue are the record types returning the record value, the other ones returning a failure
+// or forwarding the queries without any value
+var dnsRecordTypesWithValue = []string{"A", "CNAME"}
+
+// dnsRecordTypesWithoutValue are the record types failing or forwarding the queries
+var dnsRecordTypesWithoutValue = []string{"NXDOMAIN", "SERVFAIL", "REFUSED", "DROP", "FORWARD"}
+
 // Validate validates that there are no missing hostnames or records for the given dns disruption spec
 func (s DNSDisruptionSpec) Validate() (retErr error) {
 	for _, pair := range s {
@@ -35,18 +65,62 @@ func (s DNSDisruptionSpec) Validate() (retErr error) {
 			retErr = multierror.Append(retErr, errors.New("no hostname specified in dns disruption"))
 		}
 
-		if pair.Record.Type != "A" && pair.Record.Type != "CNAME" {
-			retErr = multierror.Append(retErr, fmt.Errorf("invalid record type specified in dns disruption, must be A or CNAME but found: %s", pair.Record.Type))
+		switch {
+		case utils.Contains(dnsRecordTypesWithValue, pair.Record.Type):
+			if pair.Record.Value == "" {
+				retErr = multierror.Append(retErr, errors.New("no value specified for dns record in dns disruption"))
+			}
+		case utils.Contains(dnsRecordTypesWithoutValue, pair.Record.Type):
+			if pair.Record.Value != "" {
+				retErr = multierror.Append(retErr, fmt.Errorf("no value can be specified for %s dns records in dns disruption", pair.Record.Type))
+			}
+		default:
+			retErr = multierror.Append(retErr, fmt.Errorf("invalid record type specified in dns disruption, must be one of %s but found: %s", strings.Join(append(dnsRecordTypesWithValue, dnsRecordTypesWithoutValue...), ", "), pair.Record.Type))
+		}
+
+		// forwarding the queries as usual is only a disruption when they are delayed
+		if pair.Record.Type == "FORWARD" && pair.Record.Delay == 0 {
+			retErr = multierror.Append(retErr, errors.New("FORWARD dns records require a delay to be set"))
 		}
 
-		if pair.Record.Value == "" {
-			retErr = multierror.Append(retErr, errors.New("no value specified for dns record in dns disruption"))
+		// the record values and the generated arguments are separated by spaces and semicolons
+		if strings.ContainsAny(pair.Hostname, " ;") {
+			retErr = multierror.Append(retErr, fmt.Errorf("invalid hostname specified in dns disruption: %s", pair.Hostname))
+		}
+
+		// hostnames are compiled the same way the injector resolver does so an invalid pattern can't fail the injection
+		if _, err := pair.CompileHostname(); err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("invalid hostname regular expression specified in dns disruption %s: %w", pair.Hostname, err))
 		}
 	}
 
 	return multierror.Prefix(retErr, "DNS:")
 }
 
+// CompileHostname compiles the hostname according to its match mode, the queried names ending with a dot
+func (p HostRecordPair) CompileHostname() (*regexp.Regexp, error) {
+	var pattern string
+
+	switch p.HostnameMatch {
+	case "exact":
+		pattern = regexp.QuoteMeta(strings.TrimSuffix(p.Hostname, ".")) + `\.?$`
+	case "wildcard":
+		parts := strings.Split(strings.TrimSuffix(p.Hostname, "."), "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+
+		pattern = strings.Join(parts, ".*") + `\.?$`
+	case "suffix":
+		pattern = `(.*\.)?` + regexp.QuoteMeta(strings.Trim(p.Hostname, ".")) + `\.?$`
+	default:
+		pattern = "(?:" + p.Hostname + ")"
+	}
+
+	// the hostnames are matched from the beginning of the queried name, whatever its case
+	return regexp.Compile("(?i)^" + pattern)
+}
+
 // GenerateArgs generates injection pod arguments for the given spec
 func (s DNSDisruptionSpec) GenerateArgs() []string {
 	args := []string{
@@ -58,13 +132,20 @@ func (s DNSDisruptionSpec) GenerateArgs() []string {
 	for _, pair := range s {
 		whiteSpaceCleanedIPList := strings.ReplaceAll(pair.Record.Value, " ", "")
 		arg := fmt.Sprintf("%s;%s;%s", pair.Hostname, pair.Record.Type, whiteSpaceCleanedIPList)
+
+		// the hostname match, delay and probability are only passed when set
+		if pair.HostnameMatch != "" || pair.Record.Delay > 0 || pair.Record.Probability > 0 {
+			arg = fmt.Sprintf("%s;%s;%d;%d", arg, pair.HostnameMatch, pair.Record.Delay, pair.Record.Probability)
+		}
+
 		hostRecordPairArgs = append(hostRecordPairArgs, arg)
 	}
 
 	args = append(args, "--host-record-pairs")
 
-	// Each value passed to --host-record-pairs should be of the form `hostname;type;value`, e.g.
-	// `foo.bar.svc.cluster.local;A;10.0.0.0,10.0.0.13`
+	// Each value passed to --host-record-pairs should be of the form `hostname;type;value` or
+	// `hostname;type;value;hostnameMatch;delay;probability`, e.g.
+	// `foo.bar.svc.cluster.local;A;10.0.0.0,10.0.0.13` or `bar.svc.cluster.local;SERVFAIL;;suffix;500;50`
 	args = append(args, strings.Split(strings.Join(hostRecordPairArgs, " --host-record-pairs "), " ")...)
 
 	return args
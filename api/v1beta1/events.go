@@ -11,6 +11,8 @@ import (
 
 const EventOnTargetTemplate string = "Failing probably caused by disruption %s: "
 const SourceDisruptionComponent string = "disruption-controller"
+const SourceDisruptionCronComponent string = "disruption-cron-controller"
+const SourceDisruptionWorkflowComponent string = "disruption-workflow-controller"
 
 type DisruptionEventCategory string
 
@@ -59,6 +61,9 @@ const (
 	EventDisruptionNoMoreValidTargets   string = "NoMoreTargets"
 	EventDisruptionNoTargetsFound       string = "NoTargetsFound"
 	EventInvalidSpecDisruption          string = "InvalidSpec"
+	EventDisruptionAborted              string = "Aborted"
+	EventDisruptionSafetyNetCaught      string = "SafetyNetCaught"
+	EventDisruptionTargetsCappedByPDB   string = "TargetsCappedByPDB"
 	// Normal events
 	EventDisruptionChaosPodCreated string = "ChaosPodCreated"
 	EventDisruptionFinished        string = "Finished"
@@ -66,6 +71,20 @@ const (
 	EventDisruptionDurationOver    string = "DurationOver"
 	EventDisruptionGCOver          string = "GCOver"
 	EventDisrupted                 string = "Disrupted"
+
+	// Disruption cron related events
+	// Warning events
+	EventDisruptionCronCreationFailed string = "DisruptionCreateFailed"
+	// Normal events
+	EventDisruptionCronScheduled string = "DisruptionScheduled"
+	EventDisruptionCronSkipped   string = "DisruptionSkipped"
+
+	// Disruption workflow related events
+	// Warning events
+	EventDisruptionWorkflowAborted string = "WorkflowAborted"
+	// Normal events
+	EventDisruptionWorkflowStepStarted string = "WorkflowStepStarted"
+	EventDisruptionWorkflowCompleted   string = "WorkflowCompleted"
 )
 
 var Events = map[string]DisruptionEvent{
@@ -211,6 +230,24 @@ var Events = map[string]DisruptionEvent{
 		OnDisruptionTemplateMessage: "%s",
 		Category:                    DisruptEvent,
 	},
+	EventDisruptionAborted: {
+		Type:                        corev1.EventTypeWarning,
+		Reason:                      EventDisruptionAborted,
+		OnDisruptionTemplateMessage: "Disruption has been aborted because an abort condition has been violated: %s",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionSafetyNetCaught: {
+		Type:                        corev1.EventTypeWarning,
+		Reason:                      EventDisruptionSafetyNetCaught,
+		OnDisruptionTemplateMessage: "Disruption has been deleted because a safety net has been caught: %s",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionTargetsCappedByPDB: {
+		Type:                        corev1.EventTypeWarning,
+		Reason:                      EventDisruptionTargetsCappedByPDB,
+		OnDisruptionTemplateMessage: "%s target(s) have been ignored so the allowed disruptions of matching pod disruption budgets are not exceeded",
+		Category:                    DisruptEvent,
+	},
 	EventDisruptionChaosPodCreated: {
 		Type:                        corev1.EventTypeNormal,
 		Reason:                      EventDisruptionChaosPodCreated,
@@ -235,6 +272,42 @@ var Events = map[string]DisruptionEvent{
 		OnTargetTemplateMessage: "Pod %s from disruption %s targeted this resource for injection",
 		Category:                DisruptEvent,
 	},
+	EventDisruptionCronCreationFailed: {
+		Type:                        corev1.EventTypeWarning,
+		Reason:                      EventDisruptionCronCreationFailed,
+		OnDisruptionTemplateMessage: "Scheduled disruption \"%s\" failed to be created: %s",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionCronScheduled: {
+		Type:                        corev1.EventTypeNormal,
+		Reason:                      EventDisruptionCronScheduled,
+		OnDisruptionTemplateMessage: "Created scheduled disruption \"%s\"",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionCronSkipped: {
+		Type:                        corev1.EventTypeNormal,
+		Reason:                      EventDisruptionCronSkipped,
+		OnDisruptionTemplateMessage: "Skipped disruption scheduled at %s: %s",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionWorkflowAborted: {
+		Type:                        corev1.EventTypeWarning,
+		Reason:                      EventDisruptionWorkflowAborted,
+		OnDisruptionTemplateMessage: "Workflow aborted because step \"%s\" failed: %s",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionWorkflowStepStarted: {
+		Type:                        corev1.EventTypeNormal,
+		Reason:                      EventDisruptionWorkflowStepStarted,
+		OnDisruptionTemplateMessage: "Step \"%s\" started, created disruption \"%s\"",
+		Category:                    DisruptEvent,
+	},
+	EventDisruptionWorkflowCompleted: {
+		Type:                        corev1.EventTypeNormal,
+		Reason:                      EventDisruptionWorkflowCompleted,
+		OnDisruptionTemplateMessage: "Workflow completed",
+		Category:                    DisruptEvent,
+	},
 }
 
 // IsNotifiableEvent this event can be broadcasted to our notifiers
@@ -6,9 +6,10 @@
 package v1beta1
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strconv"
-	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"google.golang.org/grpc/codes"
@@ -47,63 +48,147 @@ type GRPCDisruptionSpec struct {
 	// +kubebuilder:validation:Maximum=65535
 	// +ddmark:validation:Minimum=1
 	// +ddmark:validation:Maximum=65535
-	Port      int                  `json:"port"`
-	Endpoints []EndpointAlteration `json:"endpoints"`
+	Port int `json:"port"`
+	// port the disruption listener is served on when it isn't the gRPC server port,
+	// e.g. a listener configuring the client interceptors of the targets outbound calls (defaults to the port)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=65535
+	ListenerPort int                  `json:"listenerPort,omitempty"`
+	Endpoints    []EndpointAlteration `json:"endpoints"`
 }
 
-// EndpointAlteration represents an endpoint to disrupt and the corresponding error to return
+// GetListenerPort returns the port the disruption listener is served on
+func (s GRPCDisruptionSpec) GetListenerPort() int {
+	if s.ListenerPort != 0 {
+		return s.ListenerPort
+	}
+
+	return s.Port
+}
+
+// EndpointAlteration represents an endpoint to disrupt and the corresponding error to return, override to return or latency to add
 // +ddmark:validation:ExclusiveFields={ErrorToReturn,OverrideToReturn}
 type EndpointAlteration struct {
 	TargetEndpoint string `json:"endpoint"`
 	// +kubebuilder:validation:Enum=OK;CANCELED;UNKNOWN;INVALID_ARGUMENT;DEADLINE_EXCEEDED;NOT_FOUND;ALREADY_EXISTS;PERMISSION_DENIED;RESOURCE_EXHAUSTED;FAILED_PRECONDITION;ABORTED;OUT_OF_RANGE;UNIMPLEMENTED;INTERNAL;UNAVAILABLE;DATA_LOSS;UNAUTHENTICATED
 	// +ddmark:validation:Enum=OK;CANCELED;UNKNOWN;INVALID_ARGUMENT;DEADLINE_EXCEEDED;NOT_FOUND;ALREADY_EXISTS;PERMISSION_DENIED;RESOURCE_EXHAUSTED;FAILED_PRECONDITION;ABORTED;OUT_OF_RANGE;UNIMPLEMENTED;INTERNAL;UNAVAILABLE;DATA_LOSS;UNAUTHENTICATED
 	ErrorToReturn string `json:"error,omitempty"`
-	// +kubebuilder:validation:Enum={}
-	// +ddmark:validation:Enum="{}"
+	// response to return, as the protobuf JSON of the endpoint response type (e.g. `{"message": "overridden", "confirmationId": 42}`)
 	OverrideToReturn string `json:"override,omitempty"`
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
 	// +ddmark:validation:Minimum=0
 	// +ddmark:validation:Maximum=100
 	QueryPercent int `json:"queryPercent,omitempty"`
+	// latency added before returning the error, the override or the computed response, bounded by the caller context deadline
+	Latency DisruptionDuration `json:"latency,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	LatencyJitter int `json:"latencyJitter,omitempty"` // jitter added to the latency, in percentage of the latency
+	// +kubebuilder:validation:Minimum=0
+	// +ddmark:validation:Minimum=0
+	FailAfterMessages int `json:"failAfterMessages,omitempty"` // streaming endpoints only, number of messages sent by the server before the stream fails with the error to return
+	// metadata the calls must carry to be altered (e.g. `x-tenant: test` or `:authority: orders.example.com`),
+	// the query percentages of the alterations with the same metadata are applied to the matching calls only
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// encodedMetadata returns the metadata matchers as a URL query string sorted by key, e.g. `%3Aauthority=orders&x-tenant=test`
+func (e EndpointAlteration) encodedMetadata() string {
+	values := url.Values{}
+	for key, value := range e.Metadata {
+		values.Set(key, value)
+	}
+
+	return values.Encode()
+}
+
+// scope returns the endpoint of the alteration along with its metadata matchers, if any,
+// the query percentages of the alterations of a same scope can't exceed 100%
+func (e EndpointAlteration) scope() string {
+	if len(e.Metadata) == 0 {
+		return e.TargetEndpoint
+	}
+
+	return fmt.Sprintf("%s (metadata %s)", e.TargetEndpoint, e.encodedMetadata())
 }
 
-// Validate validates that all alterations have either an error or override to return and at least 1% chance of occurring,
-// as well as that the sum of query percentages of all alterations assigned to a target endpoint do not exceed 100%
+// Validate validates that all alterations have either an error or override to return or a latency to add and at least 1% chance of occurring,
+// as well as that the sum of query percentages of all alterations assigned to a target endpoint with the same metadata matchers do not exceed 100%
 func (s GRPCDisruptionSpec) Validate() (retErr error) {
 	queryPctByEndpoint := map[string]int{}
 	unquantifiedAlts := map[string]int{}
 
 	for _, alteration := range s.Endpoints {
+		scope := alteration.scope()
+
 		if alteration.QueryPercent == 0 {
-			if count, ok := unquantifiedAlts[alteration.TargetEndpoint]; ok {
-				unquantifiedAlts[alteration.TargetEndpoint] = count + 1
+			if count, ok := unquantifiedAlts[scope]; ok {
+				unquantifiedAlts[scope] = count + 1
 
-				pctClaimed := 100 - queryPctByEndpoint[alteration.TargetEndpoint]
+				pctClaimed := 100 - queryPctByEndpoint[scope]
 
 				if pctClaimed < count+1 {
-					retErr = multierror.Append(retErr, fmt.Errorf("alterations must have at least 1%% chance of occurring; %s will never return some alterations because alterations exceed 100%% of possible queries", alteration.TargetEndpoint))
+					retErr = multierror.Append(retErr, fmt.Errorf("alterations must have at least 1%% chance of occurring; %s will never return some alterations because alterations exceed 100%% of possible queries", scope))
 				}
 			} else {
-				unquantifiedAlts[alteration.TargetEndpoint] = 1
+				unquantifiedAlts[scope] = 1
 			}
 		} else {
 			// check that endpoint is not already configured such that the sum of the queryPercents total to more than 100%
-			if totalQueryPercent, ok := queryPctByEndpoint[alteration.TargetEndpoint]; ok {
+			if totalQueryPercent, ok := queryPctByEndpoint[scope]; ok {
 				// always positive because of CRD limitations
-				queryPctByEndpoint[alteration.TargetEndpoint] = totalQueryPercent + alteration.QueryPercent
-				if queryPctByEndpoint[alteration.TargetEndpoint] > 100 {
-					retErr = multierror.Append(retErr, fmt.Errorf("total queryPercent of all alterations applied to endpoint %s is over 100%%", alteration.TargetEndpoint))
+				queryPctByEndpoint[scope] = totalQueryPercent + alteration.QueryPercent
+				if queryPctByEndpoint[scope] > 100 {
+					retErr = multierror.Append(retErr, fmt.Errorf("total queryPercent of all alterations applied to endpoint %s is over 100%%", scope))
 				}
 			} else {
-				queryPctByEndpoint[alteration.TargetEndpoint] = alteration.QueryPercent
+				queryPctByEndpoint[scope] = alteration.QueryPercent
 			}
 		}
 
-		// check that exactly one of ErrorToReturn or OverrideToReturn is configured
-		// (ddmark already prevents both from being configured)
-		if alteration.ErrorToReturn == "" && alteration.OverrideToReturn == "" {
-			retErr = multierror.Append(retErr, fmt.Errorf("the gRPC disruption must have either ErrorToReturn or OverrideToReturn specified for endpoint %s", alteration.TargetEndpoint))
+		// check that at least one of ErrorToReturn, OverrideToReturn or Latency is configured
+		// (ddmark already prevents both ErrorToReturn and OverrideToReturn from being configured)
+		if alteration.ErrorToReturn == "" && alteration.OverrideToReturn == "" && alteration.Latency.Duration() == 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("the gRPC disruption must have either ErrorToReturn, OverrideToReturn or Latency specified for endpoint %s", alteration.TargetEndpoint))
+		}
+
+		// the override is only checked against the endpoint response type by the disruption listener, which knows it
+		if alteration.OverrideToReturn != "" {
+			var override map[string]interface{}
+			if err := json.Unmarshal([]byte(alteration.OverrideToReturn), &override); err != nil || override == nil {
+				retErr = multierror.Append(retErr, fmt.Errorf("the override %s of endpoint %s must be a JSON object", alteration.OverrideToReturn, alteration.TargetEndpoint))
+			}
+		}
+
+		if alteration.Latency.Duration() < 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("the latency can't be negative for endpoint %s", alteration.TargetEndpoint))
+		}
+
+		if alteration.LatencyJitter != 0 && alteration.Latency.Duration() == 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("the latency jitter can't be specified without a latency for endpoint %s", alteration.TargetEndpoint))
+		}
+
+		if alteration.FailAfterMessages < 0 {
+			retErr = multierror.Append(retErr, fmt.Errorf("the number of messages before failing can't be negative for endpoint %s", alteration.TargetEndpoint))
+		}
+
+		if alteration.FailAfterMessages != 0 && alteration.ErrorToReturn == "" {
+			retErr = multierror.Append(retErr, fmt.Errorf("the number of messages before failing can't be specified without an error to return for endpoint %s", alteration.TargetEndpoint))
+		}
+
+		for key := range alteration.Metadata {
+			if key == "" {
+				retErr = multierror.Append(retErr, fmt.Errorf("the metadata matchers keys can't be empty for endpoint %s", alteration.TargetEndpoint))
+			}
+		}
+
+		if alteration.LatencyJitter < 0 || alteration.LatencyJitter > 100 {
+			retErr = multierror.Append(retErr, fmt.Errorf("the latency jitter must be between 0 and 100 percent for endpoint %s", alteration.TargetEndpoint))
 		}
 	}
 
@@ -132,11 +217,15 @@ func (s GRPCDisruptionSpec) GenerateArgs() []string {
 		}
 
 		arg := fmt.Sprintf(
-			"%s;%s;%s;%s",
+			"%s;%s;%s;%s;%s;%s;%s;%s",
 			endptAlt.TargetEndpoint,
 			alterationType,
 			alterationValue,
 			strconv.Itoa(endptAlt.QueryPercent),
+			endptAlt.Latency,
+			strconv.Itoa(endptAlt.LatencyJitter),
+			strconv.Itoa(endptAlt.FailAfterMessages),
+			endptAlt.encodedMetadata(),
 		)
 
 		endpointAlterationArgs = append(endpointAlterationArgs, arg)
@@ -144,13 +233,23 @@ func (s GRPCDisruptionSpec) GenerateArgs() []string {
 
 	args = append(args, []string{"--port", strconv.Itoa(s.Port)}...)
 
+	if s.ListenerPort != 0 {
+		args = append(args, []string{"--listener-port", strconv.Itoa(s.ListenerPort)}...)
+	}
+
 	// Each value passed to --endpoint-alterations should be of the form
-	// `endpoint;alteration_type;alteration_value;optional_query_percent`
+	// `endpoint;alteration_type;alteration_value;optional_query_percent;optional_latency;optional_latency_jitter;optional_fail_after_messages;optional_metadata`
 	// e.g.
-	// `/chaosdogfood.ChaosDogfood/order;error;ALREADY_EXISTS;30`
-	// `/chaosdogfood.ChaosDogfood/order;override;{};`
-	args = append(args, "--endpoint-alterations")
-	args = append(args, strings.Split(strings.Join(endpointAlterationArgs, " --endpoint-alterations "), " ")...)
+	// `/chaosdogfood.ChaosDogfood/order;error;ALREADY_EXISTS;30;;0;0;`
+	// `/chaosdogfood.ChaosDogfood/order;override;{};0;;0;0;`
+	// `/chaosdogfood.ChaosDogfood/order;override;{"message": "overridden"};0;;0;0;`
+	// `/chaosdogfood.ChaosDogfood/order;;;0;2s;10;0;`
+	// `/chaosdogfood.ChaosDogfood/streamCatalog;error;UNAVAILABLE;0;;0;2;`
+	// `/chaosdogfood.ChaosDogfood/order;error;UNAVAILABLE;0;;0;0;x-tenant=test`
+	// values are appended as single args since an override JSON payload may contain spaces
+	for _, arg := range endpointAlterationArgs {
+		args = append(args, "--endpoint-alterations", arg)
+	}
 
 	return args
 }
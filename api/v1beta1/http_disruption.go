@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// HTTPDisruptionSpec represents an HTTP disruption, altering the requests received by the target on the given ports
+type HTTPDisruptionSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Ports []int `json:"ports"` // ports the target serves HTTP requests on
+	// +kubebuilder:validation:MinItems=1
+	Rules []HTTPRule `json:"rules"` // rules evaluated in order, the first rule matching a request and passing its percentage roll applies
+}
+
+// HTTPRule represents the requests to disrupt and how to disrupt them
+// +ddmark:validation:ExclusiveFields={StatusCode,Abort}
+type HTTPRule struct {
+	Method string `json:"method,omitempty"` // request method to match, any method if empty
+	Path   string `json:"path,omitempty"`   // request path to match, as a prefix if ending with *, any path if empty
+	// +nullable
+	Headers map[string]string `json:"headers,omitempty"` // request headers values to match
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	Percent int `json:"percent,omitempty"` // percentage of matching requests to disrupt, defaults to 100
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=599
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=599
+	StatusCode int                `json:"statusCode,omitempty"` // status code returned without forwarding the request to the target
+	Delay      DisruptionDuration `json:"delay,omitempty"`      // latency added before handling the request
+	Abort      bool               `json:"abort,omitempty"`      // abort the connection in the middle of the target response
+}
+
+// Validate validates args for the given disruption
+func (s *HTTPDisruptionSpec) Validate() (retErr error) {
+	if len(s.Ports) == 0 {
+		retErr = multierror.Append(retErr, errors.New("at least one port must be specified"))
+	}
+
+	for _, port := range s.Ports {
+		if port < 1 || port > 65535 {
+			retErr = multierror.Append(retErr, fmt.Errorf("invalid port %d, it must be between 1 and 65535", port))
+		}
+	}
+
+	if len(s.Rules) == 0 {
+		retErr = multierror.Append(retErr, errors.New("at least one rule must be specified"))
+	}
+
+	for _, rule := range s.Rules {
+		if err := rule.Validate(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	return multierror.Prefix(retErr, "HTTP:")
+}
+
+// Validate validates args for the given rule
+func (r *HTTPRule) Validate() (retErr error) {
+	if r.Method != "" && r.Method != strings.ToUpper(r.Method) {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid method %s, it must be uppercase", r.Method))
+	}
+
+	if r.Path != "" && !strings.HasPrefix(r.Path, "/") {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid path %s, it must start with /", r.Path))
+	}
+
+	if strings.Contains(strings.TrimSuffix(r.Path, "*"), "*") {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid path %s, the * wildcard is only allowed at the end of the path", r.Path))
+	}
+
+	if r.Percent < 0 || r.Percent > 100 {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid percent %d, it must be between 0 and 100", r.Percent))
+	}
+
+	if r.StatusCode != 0 && http.StatusText(r.StatusCode) == "" {
+		retErr = multierror.Append(retErr, fmt.Errorf("unknown status code %d", r.StatusCode))
+	}
+
+	if r.StatusCode != 0 && r.Abort {
+		retErr = multierror.Append(retErr, errors.New("a rule can't both return a status code and abort the connection"))
+	}
+
+	if r.Delay.Duration() < 0 {
+		retErr = multierror.Append(retErr, errors.New("the delay can't be negative"))
+	}
+
+	if r.StatusCode == 0 && r.Delay.Duration() == 0 && !r.Abort {
+		retErr = multierror.Append(retErr, errors.New("a rule must either return a status code, add a delay or abort the connection"))
+	}
+
+	return retErr
+}
+
+// GetPercent returns the percentage of matching requests to disrupt, defaulting to 100
+func (r *HTTPRule) GetPercent() int {
+	if r.Percent == 0 {
+		return 100
+	}
+
+	return r.Percent
+}
+
+// GenerateArgs generates injection pod arguments for the given spec
+func (s *HTTPDisruptionSpec) GenerateArgs() []string {
+	args := []string{
+		"http-disruption",
+	}
+
+	for _, port := range s.Ports {
+		args = append(args, "--ports", strconv.Itoa(port))
+	}
+
+	// Each value passed to --rules is a JSON encoded rule since header values can contain any character, e.g.
+	// `{"method":"GET","path":"/api/orders*","percent":20,"statusCode":503}`
+	// it is passed as a single argument and must not be split on spaces
+	for _, rule := range s.Rules {
+		rawRule, _ := json.Marshal(rule)
+
+		args = append(args, "--rules", string(rawRule))
+	}
+
+	return args
+}
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// MemoryPressureSpec represents a memory pressure disruption
+type MemoryPressureSpec struct {
+	// +kubebuilder:validation:Required
+	// +ddmark:validation:Required=true
+	Target string `json:"target"` // target memory usage of the targeted cgroup, either an absolute size (512Mi) or a percentage of the cgroup memory limit (80%)
+	// +nullable
+	RampDuration DisruptionDuration `json:"rampDuration,omitempty"` // time to reach the target memory usage, defaults to an immediate allocation
+}
+
+// Validate validates args for the given disruption
+func (s *MemoryPressureSpec) Validate() (retErr error) {
+	if _, _, err := s.ParseTarget(); err != nil {
+		retErr = multierror.Append(retErr, err)
+	}
+
+	if s.RampDuration.Duration() < 0 {
+		retErr = multierror.Append(retErr, fmt.Errorf("rampDuration must be positive, found: %s", s.RampDuration))
+	}
+
+	return multierror.Prefix(retErr, "MemoryPressure:")
+}
+
+// GenerateArgs generates injection or cleanup pod arguments for the given spec
+func (s *MemoryPressureSpec) GenerateArgs() []string {
+	args := []string{
+		"memory-pressure",
+		"--target",
+		s.Target,
+	}
+
+	// add ramp duration flag if specified
+	if s.RampDuration.Duration() > 0 {
+		args = append(args, []string{"--ramp-duration", s.RampDuration.Duration().String()}...)
+	}
+
+	return args
+}
+
+// ParseTarget parses the target memory usage and returns its value with a boolean being true when
+// the value is a percentage of the cgroup memory limit, or false when the value is an amount of bytes
+func (s *MemoryPressureSpec) ParseTarget() (int64, bool, error) {
+	if strings.HasSuffix(s.Target, "%") {
+		percentage, err := strconv.Atoi(strings.TrimSuffix(s.Target, "%"))
+		if err != nil || percentage < 1 || percentage > 100 {
+			return 0, false, fmt.Errorf("target percentage must be between 1%% and 100%%, found: %s", s.Target)
+		}
+
+		return int64(percentage), true, nil
+	}
+
+	quantity, err := resource.ParseQuantity(s.Target)
+	if err != nil {
+		return 0, false, fmt.Errorf("target must be either a valid memory quantity (512Mi) or a percentage (80%%), found: %s", s.Target)
+	}
+
+	if quantity.Value() <= 0 {
+		return 0, false, fmt.Errorf("target must be a positive memory quantity, found: %s", s.Target)
+	}
+
+	return quantity.Value(), false, nil
+}
@@ -6,12 +6,14 @@
 package v1beta1
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -19,10 +21,19 @@ const (
 	FlowEgress = "egress"
 	// FlowIngress is the string representation of network disruptions applied to incoming packets
 	FlowIngress = "ingress"
+	// MaxNetworkDisruptionProfiles is the maximum number of profiles of a network disruption, each profile using
+	// its own band of a prio qdisc which can't have more than 16 bands (2 of them being used by the disruption itself)
+	MaxNetworkDisruptionProfiles = 14
+	// RejectTCPReset is the reject type resetting the TCP connections
+	RejectTCPReset = "tcp-reset"
+	// RejectICMPPortUnreachable is the reject type refusing the packets with an ICMP port unreachable error
+	RejectICMPPortUnreachable = "icmp-port-unreachable"
 )
 
 // NetworkDisruptionSpec represents a network disruption injection
-// +ddmark:validation:AtLeastOneOf={BandwidthLimit,Drop,Delay,Corrupt,Duplicate}
+// +ddmark:validation:AtLeastOneOf={BandwidthLimit,Drop,Delay,Corrupt,Duplicate,Profiles,LossModel,Slot,Partition,Reject}
+// +ddmark:validation:ExclusiveFields={LossModel,Drop}
+// +ddmark:validation:ExclusiveFields={Partition,BandwidthLimit,Drop,Delay,Corrupt,Duplicate,Profiles,LossModel,Slot,Hosts,Services,Reject}
 type NetworkDisruptionSpec struct {
 	// +nullable
 	Hosts []NetworkDisruptionHostSpec `json:"hosts,omitempty"`
@@ -58,6 +69,52 @@ type NetworkDisruptionSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +ddmark:validation:Minimum=0
 	BandwidthLimit int `json:"bandwidthLimit,omitempty"`
+	// DropCorrelation is the percentage of dependency of each drop decision on the previous one, making packets loss bursty
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	DropCorrelation int `json:"dropCorrelation,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	DuplicateCorrelation int `json:"duplicateCorrelation,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	CorruptCorrelation int `json:"corruptCorrelation,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	DelayCorrelation int `json:"delayCorrelation,omitempty"`
+	// DelayDistribution is the distribution the jittered delays follow, normal by default
+	// +kubebuilder:validation:Enum=normal;pareto;paretonormal;""
+	// +ddmark:validation:Enum=normal;pareto;paretonormal;""
+	DelayDistribution string `json:"delayDistribution,omitempty"`
+	// LossModel drops packets following a Gilbert-Elliott or 4-state loss model instead of a drop percentage
+	// +nullable
+	LossModel *NetworkDisruptionLossModelSpec `json:"lossModel,omitempty"`
+	// Reorder is the percentage of packets sent right away instead of being delayed, requiring a delay
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	Reorder int `json:"reorder,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	ReorderCorrelation int `json:"reorderCorrelation,omitempty"`
+	// ReorderGap only reorders one packet out of the given number of packets, 1 by default
+	// +kubebuilder:validation:Minimum=0
+	// +ddmark:validation:Minimum=0
+	ReorderGap uint `json:"reorderGap,omitempty"`
+	// Slot sends packets in slots instead of right away
+	// +nullable
+	Slot *NetworkDisruptionSlotSpec `json:"slot,omitempty"`
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=65535
 	// +ddmark:validation:Minimum=0
@@ -67,9 +124,167 @@ type NetworkDisruptionSpec struct {
 	// +kubebuilder:validation:Enum=egress;ingress
 	// +ddmark:validation:Enum=egress;ingress
 	DeprecatedFlow string `json:"flow,omitempty"`
+	// +kubebuilder:validation:MaxItems=14
+	// +nullable
+	Profiles []NetworkDisruptionProfileSpec `json:"profiles,omitempty"`
+	// IngressShaping applies the disruptions to the incoming traffic as well, by redirecting it to an IFB device
+	IngressShaping bool `json:"ingressShaping,omitempty"`
+	// Partition drops all the packets exchanged with the selected peer pods in both directions, replacing any other disruption
+	// +nullable
+	Partition *NetworkDisruptionPartitionSpec `json:"partition,omitempty"`
+	// Reject rejects the TCP packets going to the hosts and services with a reset (tcp-reset) or all the packets going to them
+	// with an ICMP port unreachable error (icmp-port-unreachable), making the connections fail fast instead of timing out
+	// +kubebuilder:validation:Enum=tcp-reset;icmp-port-unreachable;""
+	// +ddmark:validation:Enum=tcp-reset;icmp-port-unreachable;""
+	Reject string `json:"reject,omitempty"`
+}
+
+// NetworkDisruptionPartitionSpec represents a network partition between the targets and the pods matching its selector,
+// the peer pods being watched so pods created or deleted during the disruption are partitioned as well
+type NetworkDisruptionPartitionSpec struct {
+	// Selector is the label selector of the peer pods
+	Selector labels.Set `json:"selector"`
+	// Namespace is the namespace of the peer pods
+	// +ddmark:validation:Required=true
+	Namespace string `json:"namespace"`
+}
+
+// NetworkDisruptionProfileSpec represents disruptions applied to the traffic of its own hosts and services only,
+// independently of the disruptions applied at the network disruption level
+// +ddmark:validation:AtLeastOneOf={BandwidthLimit,Drop,Delay,Corrupt,Duplicate}
+// +ddmark:validation:AtLeastOneOf={Hosts,Services}
+type NetworkDisruptionProfileSpec struct {
+	// +nullable
+	Hosts []NetworkDisruptionHostSpec `json:"hosts,omitempty"`
+	// +nullable
+	Services []NetworkDisruptionServiceSpec `json:"services,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	Drop int `json:"drop,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	Duplicate int `json:"duplicate,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	Corrupt int `json:"corrupt,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=60000
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=60000
+	Delay uint `json:"delay,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	DelayJitter uint `json:"delayJitter,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +ddmark:validation:Minimum=0
+	BandwidthLimit int `json:"bandwidthLimit,omitempty"`
+}
+
+// NetworkDisruptionLossModelSpec represents a loss model making packets loss bursty, as observed on real networks
+// +ddmark:validation:ExclusiveFields={GilbertElliott,FourState}
+// +ddmark:validation:AtLeastOneOf={GilbertElliott,FourState}
+type NetworkDisruptionLossModelSpec struct {
+	// +nullable
+	GilbertElliott *NetworkDisruptionGilbertElliottSpec `json:"gilbertElliott,omitempty"`
+	// +nullable
+	FourState *NetworkDisruptionFourStateSpec `json:"fourState,omitempty"`
+}
+
+// NetworkDisruptionGilbertElliottSpec represents a Gilbert-Elliott loss model with a good and a bad state,
+// all values being percentages
+type NetworkDisruptionGilbertElliottSpec struct {
+	// P is the probability to move from the good state to the bad state
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=1
+	// +ddmark:validation:Maximum=100
+	P int `json:"p"`
+	// R is the probability to move from the bad state to the good state
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=1
+	// +ddmark:validation:Maximum=100
+	R int `json:"r"`
+	// BadStateLoss is the probability to drop a packet in the bad state (1-h)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	BadStateLoss int `json:"badStateLoss,omitempty"`
+	// GoodStateLoss is the probability to drop a packet in the good state (1-k)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	GoodStateLoss int `json:"goodStateLoss,omitempty"`
+}
+
+// NetworkDisruptionFourStateSpec represents a 4-state Markov loss model, all values being percentages: pij is the probability
+// to move from the state i to the state j, states being 1 (packets received in a gap period), 2 (packets received in a burst
+// period), 3 (packets lost in a burst period) and 4 (isolated packets lost in a gap period)
+type NetworkDisruptionFourStateSpec struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=1
+	// +ddmark:validation:Maximum=100
+	P13 int `json:"p13"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	P31 int `json:"p31,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	P32 int `json:"p32,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	P23 int `json:"p23,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=100
+	P14 int `json:"p14,omitempty"`
+}
+
+// NetworkDisruptionSlotSpec represents slots packets are sent in, emulating links sending packets in bursts such as wifi
+// or cellular links, the delay between two slots being picked randomly between the minimum and the maximum delays
+type NetworkDisruptionSlotSpec struct {
+	// MinDelay is the minimum delay between two slots in microseconds
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=60000000
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=60000000
+	MinDelay uint `json:"minDelay,omitempty"`
+	// MaxDelay is the maximum delay between two slots in microseconds, the minimum delay by default
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=60000000
+	// +ddmark:validation:Minimum=0
+	// +ddmark:validation:Maximum=60000000
+	MaxDelay uint `json:"maxDelay,omitempty"`
+	// Packets is the maximum number of packets sent per slot, unlimited by default
+	// +kubebuilder:validation:Minimum=0
+	// +ddmark:validation:Minimum=0
+	Packets int `json:"packets,omitempty"`
+	// Bytes is the maximum number of bytes sent per slot, unlimited by default
+	// +kubebuilder:validation:Minimum=0
+	// +ddmark:validation:Minimum=0
+	Bytes int `json:"bytes,omitempty"`
 }
 
 type NetworkDisruptionHostSpec struct {
+	// Host is a hostname, an IPv4 or IPv6 address or an IPv4 or IPv6 CIDR, all the IPv4 and IPv6 traffic being matched if empty
 	Host string `json:"host,omitempty"`
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=65535
@@ -118,9 +333,188 @@ func (s *NetworkDisruptionSpec) Validate() (retErr error) {
 		retErr = multierror.Append(retErr, fmt.Errorf("the flow specification at the network disruption level is deprecated; apply to network disruption hosts instead"))
 	}
 
+	if len(s.Profiles) > MaxNetworkDisruptionProfiles {
+		retErr = multierror.Append(retErr, fmt.Errorf("a network disruption can't have more than %d profiles", MaxNetworkDisruptionProfiles))
+	}
+
+	// hosts and services at the network disruption level are only disrupted by the disruptions defined at the same level
+	if len(s.Profiles) > 0 && !s.HasDisruptions() && (len(s.Hosts) > 0 || len(s.Services) > 0) {
+		retErr = multierror.Append(retErr, errors.New("hosts and services at the network disruption level require at least one of drop, duplicate, corrupt, delay or bandwidthLimit to be set at the same level; move them to a profile instead"))
+	}
+
+	if err := s.validateNetem(); err != nil {
+		retErr = multierror.Append(retErr, err)
+	}
+
+	if s.Partition != nil {
+		if err := s.Partition.Validate(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+
+		// the partition drops all the packets exchanged with the peer pods, other disruptions and destinations would be ignored
+		if s.HasDisruptions() || len(s.Profiles) > 0 || len(s.Hosts) > 0 || len(s.Services) > 0 {
+			retErr = multierror.Append(retErr, errors.New("partition can't be set along with any other disruption, profile, host or service"))
+		}
+	}
+
+	if s.Reject != "" {
+		// only TCP packets can be reset
+		if s.Reject == RejectTCPReset {
+			for _, host := range s.Hosts {
+				if host.Protocol == "udp" {
+					retErr = multierror.Append(retErr, fmt.Errorf("host %s: udp packets can't be rejected with %s, use %s instead", host.Host, RejectTCPReset, RejectICMPPortUnreachable))
+				}
+			}
+		}
+	}
+
+	for idx, profile := range s.Profiles {
+		if err := profile.Validate(); err != nil {
+			retErr = multierror.Append(retErr, multierror.Prefix(err, fmt.Sprintf("Profile %d:", idx)))
+		}
+	}
+
 	return multierror.Prefix(retErr, "Network:")
 }
 
+// validateNetem validates the correlations, loss model, reordering and slots, which only make sense along with other fields
+func (s *NetworkDisruptionSpec) validateNetem() (retErr error) {
+	if s.LossModel != nil && s.Drop > 0 {
+		retErr = multierror.Append(retErr, errors.New("drop and lossModel can't be set together, the loss model replaces the drop percentage"))
+	}
+
+	if s.DropCorrelation > 0 && s.Drop == 0 {
+		retErr = multierror.Append(retErr, errors.New("dropCorrelation requires drop to be set"))
+	}
+
+	if s.DuplicateCorrelation > 0 && s.Duplicate == 0 {
+		retErr = multierror.Append(retErr, errors.New("duplicateCorrelation requires duplicate to be set"))
+	}
+
+	if s.CorruptCorrelation > 0 && s.Corrupt == 0 {
+		retErr = multierror.Append(retErr, errors.New("corruptCorrelation requires corrupt to be set"))
+	}
+
+	if (s.DelayCorrelation > 0 || s.DelayDistribution != "") && s.Delay == 0 {
+		retErr = multierror.Append(retErr, errors.New("delayCorrelation and delayDistribution require delay to be set"))
+	}
+
+	// reordered packets are the ones not being delayed
+	if s.Reorder > 0 && s.Delay == 0 {
+		retErr = multierror.Append(retErr, errors.New("reorder requires delay to be set"))
+	}
+
+	if (s.ReorderCorrelation > 0 || s.ReorderGap > 0) && s.Reorder == 0 {
+		retErr = multierror.Append(retErr, errors.New("reorderCorrelation and reorderGap require reorder to be set"))
+	}
+
+	if s.LossModel != nil {
+		if err := s.LossModel.Validate(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	if s.Slot != nil {
+		if err := s.Slot.Validate(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	return retErr
+}
+
+// HasDisruptions returns true if any disruption is defined at the network disruption level, profiles excluded
+func (s *NetworkDisruptionSpec) HasDisruptions() bool {
+	return s.Drop > 0 || s.Duplicate > 0 || s.Corrupt > 0 || s.Delay > 0 || s.BandwidthLimit > 0 || s.LossModel != nil || s.Slot != nil || s.Reject != ""
+}
+
+// Validate validates args for the given loss model
+func (m *NetworkDisruptionLossModelSpec) Validate() (retErr error) {
+	if (m.GilbertElliott == nil) == (m.FourState == nil) {
+		return errors.New("exactly one of gilbertElliott or fourState loss models must be set")
+	}
+
+	if ge := m.GilbertElliott; ge != nil {
+		if ge.P <= 0 || ge.R <= 0 {
+			retErr = multierror.Append(retErr, errors.New("the gilbertElliott loss model p and r probabilities must be greater than 0"))
+		}
+
+		if ge.BadStateLoss == 0 && ge.GoodStateLoss == 0 {
+			retErr = multierror.Append(retErr, errors.New("the gilbertElliott loss model requires at least one of badStateLoss or goodStateLoss to be set"))
+		}
+	}
+
+	if fs := m.FourState; fs != nil {
+		if fs.P13 <= 0 {
+			retErr = multierror.Append(retErr, errors.New("the fourState loss model p13 probability must be greater than 0"))
+		}
+
+		// the probabilities to leave a state can't exceed 100%
+		if fs.P13+fs.P14 > 100 {
+			retErr = multierror.Append(retErr, errors.New("the fourState loss model p13 and p14 probabilities sum can't exceed 100"))
+		}
+
+		if fs.P31+fs.P32 > 100 {
+			retErr = multierror.Append(retErr, errors.New("the fourState loss model p31 and p32 probabilities sum can't exceed 100"))
+		}
+	}
+
+	return retErr
+}
+
+// Validate validates args for the given partition
+func (p *NetworkDisruptionPartitionSpec) Validate() (retErr error) {
+	if len(p.Selector) == 0 {
+		retErr = multierror.Append(retErr, errors.New("partition selector must select the peer pods with at least one label"))
+	} else if _, err := labels.ValidatedSelectorFromSet(p.Selector); err != nil {
+		retErr = multierror.Append(retErr, fmt.Errorf("invalid partition selector: %w", err))
+	}
+
+	if p.Namespace == "" {
+		retErr = multierror.Append(retErr, errors.New("partition namespace must be set"))
+	}
+
+	return retErr
+}
+
+// Validate validates args for the given slots
+func (s *NetworkDisruptionSlotSpec) Validate() error {
+	if s.MinDelay == 0 && s.MaxDelay == 0 {
+		return errors.New("slot requires at least one of minDelay or maxDelay to be set")
+	}
+
+	if s.MaxDelay != 0 && s.MaxDelay < s.MinDelay {
+		return errors.New("slot maxDelay can't be lower than minDelay")
+	}
+
+	return nil
+}
+
+// Validate validates args for the given profile
+func (p *NetworkDisruptionProfileSpec) Validate() (retErr error) {
+	if k8sClient != nil {
+		if err := validateServices(k8sClient, p.Services); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	if len(p.Hosts) == 0 && len(p.Services) == 0 {
+		retErr = multierror.Append(retErr, errors.New("at least one host or service must be specified"))
+	}
+
+	for _, host := range p.Hosts {
+		if err := host.Validate(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	if p.Drop == 0 && p.Duplicate == 0 && p.Corrupt == 0 && p.Delay == 0 && p.BandwidthLimit == 0 {
+		retErr = multierror.Append(retErr, errors.New("at least one of drop, duplicate, corrupt, delay or bandwidthLimit must be set"))
+	}
+
+	return retErr
+}
+
 // GenerateArgs generates injection or cleanup pod arguments for the given spec
 func (s *NetworkDisruptionSpec) GenerateArgs() []string {
 	args := []string{
@@ -154,6 +548,71 @@ func (s *NetworkDisruptionSpec) GenerateArgs() []string {
 		args = append(args, "--services", fmt.Sprintf("%s;%s", service.Name, service.Namespace))
 	}
 
+	if s.IngressShaping {
+		args = append(args, "--ingress-shaping")
+	}
+
+	if s.Partition != nil {
+		rawPartition, _ := json.Marshal(s.Partition)
+
+		args = append(args, "--partition", string(rawPartition))
+	}
+
+	if s.Reject != "" {
+		args = append(args, "--reject", s.Reject)
+	}
+
+	args = append(args, s.generateNetemArgs()...)
+
+	// Each value passed to --profiles is a JSON encoded profile since it holds its own lists of hosts and services, e.g.
+	// `{"hosts":[{"host":"10.0.0.0/8","port":443}],"delay":200}`
+	for _, profile := range s.Profiles {
+		rawProfile, _ := json.Marshal(profile)
+
+		args = append(args, "--profiles", string(rawProfile))
+	}
+
+	return args
+}
+
+// generateNetemArgs generates the arguments of the netem parameters only set when used, loss models and slots being JSON encoded
+func (s *NetworkDisruptionSpec) generateNetemArgs() []string {
+	args := []string{}
+	values := []struct {
+		flag  string
+		value int
+	}{
+		{"--drop-correlation", s.DropCorrelation},
+		{"--duplicate-correlation", s.DuplicateCorrelation},
+		{"--corrupt-correlation", s.CorruptCorrelation},
+		{"--delay-correlation", s.DelayCorrelation},
+		{"--reorder", s.Reorder},
+		{"--reorder-correlation", s.ReorderCorrelation},
+		{"--reorder-gap", int(s.ReorderGap)},
+	}
+
+	for _, value := range values {
+		if value.value != 0 {
+			args = append(args, value.flag, strconv.Itoa(value.value))
+		}
+	}
+
+	if s.DelayDistribution != "" {
+		args = append(args, "--delay-distribution", s.DelayDistribution)
+	}
+
+	if s.LossModel != nil {
+		rawLossModel, _ := json.Marshal(s.LossModel)
+
+		args = append(args, "--loss-model", string(rawLossModel))
+	}
+
+	if s.Slot != nil {
+		rawSlot, _ := json.Marshal(s.Slot)
+
+		args = append(args, "--slot", string(rawSlot))
+	}
+
 	return args
 }
 
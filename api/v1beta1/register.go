@@ -31,6 +31,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(GroupVersion,
 		&Disruption{},
 		&DisruptionList{},
+		&DisruptionCron{},
+		&DisruptionCronList{},
+		&DisruptionWorkflow{},
+		&DisruptionWorkflowList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, GroupVersion)
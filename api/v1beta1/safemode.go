@@ -8,11 +8,14 @@ package v1beta1
 // UnsafemodeSpec represents a spec with parameters to turn off specific safety nets designed to catch common traps or issues running a disruption
 // All of these are turned off by default, so disabling safety nets requires manually changing these booleans to true
 type UnsafemodeSpec struct {
-	DisableAll                 bool    `json:"disableAll,omitempty"`
-	DisableCountTooLarge       bool    `json:"disableCountTooLarge,omitempty"`
-	DisableNeitherHostNorPort  bool    `json:"disableNeitherHostNorPort,omitempty"`
-	DisableSpecificContainDisk bool    `json:"disableSpecificContainDisk,omitempty"`
-	Config                     *Config `json:"config,omitempty"`
+	DisableAll                     bool    `json:"disableAll,omitempty"`
+	DisableCountTooLarge           bool    `json:"disableCountTooLarge,omitempty"`
+	DisableNeitherHostNorPort      bool    `json:"disableNeitherHostNorPort,omitempty"`
+	DisableSpecificContainDisk     bool    `json:"disableSpecificContainDisk,omitempty"`
+	DisableRootDiskPressure        bool    `json:"disableRootDiskPressure,omitempty"`
+	DisableControlPlaneNodeFailure bool    `json:"disableControlPlaneNodeFailure,omitempty"`
+	DisableKubeDNSRewrite          bool    `json:"disableKubeDNSRewrite,omitempty"`
+	Config                         *Config `json:"config,omitempty"`
 }
 
 // Config represents any configurable parameters for the safetynets, all of which have defaults
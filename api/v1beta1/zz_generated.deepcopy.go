@@ -27,9 +27,49 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AbortConditionsSpec) DeepCopyInto(out *AbortConditionsSpec) {
+	*out = *in
+	if in.HTTPProbes != nil {
+		in, out := &in.HTTPProbes, &out.HTTPProbes
+		*out = make([]HTTPProbeSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxTargetRestarts != nil {
+		in, out := &in.MaxTargetRestarts, &out.MaxTargetRestarts
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxNotReadyTargets != nil {
+		in, out := &in.MaxNotReadyTargets, &out.MaxNotReadyTargets
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AbortConditionsSpec.
+func (in *AbortConditionsSpec) DeepCopy() *AbortConditionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AbortConditionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUPressureSpec) DeepCopyInto(out *CPUPressureSpec) {
 	*out = *in
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int)
+		**out = **in
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPressureSpec.
@@ -194,6 +234,105 @@ func (in *Disruption) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionCron) DeepCopyInto(out *DisruptionCron) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionCron.
+func (in *DisruptionCron) DeepCopy() *DisruptionCron {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionCron)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DisruptionCron) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionCronList) DeepCopyInto(out *DisruptionCronList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DisruptionCron, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionCronList.
+func (in *DisruptionCronList) DeepCopy() *DisruptionCronList {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionCronList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DisruptionCronList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionCronSpec) DeepCopyInto(out *DisruptionCronSpec) {
+	*out = *in
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionCronSpec.
+func (in *DisruptionCronSpec) DeepCopy() *DisruptionCronSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionCronSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionCronStatus) DeepCopyInto(out *DisruptionCronStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionCronStatus.
+func (in *DisruptionCronStatus) DeepCopy() *DisruptionCronStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionCronStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DisruptionEvent) DeepCopyInto(out *DisruptionEvent) {
 	*out = *in
@@ -283,6 +422,11 @@ func (in *DisruptionSpec) DeepCopyInto(out *DisruptionSpec) {
 		*out = new(UnsafemodeSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RespectPodDisruptionBudgets != nil {
+		in, out := &in.RespectPodDisruptionBudgets, &out.RespectPodDisruptionBudgets
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Pulse != nil {
 		in, out := &in.Pulse, &out.Pulse
 		*out = new(DisruptionPulse)
@@ -311,6 +455,11 @@ func (in *DisruptionSpec) DeepCopyInto(out *DisruptionSpec) {
 	if in.CPUPressure != nil {
 		in, out := &in.CPUPressure, &out.CPUPressure
 		*out = new(CPUPressureSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryPressure != nil {
+		in, out := &in.MemoryPressure, &out.MemoryPressure
+		*out = new(MemoryPressureSpec)
 		**out = **in
 	}
 	if in.DiskPressure != nil {
@@ -328,6 +477,16 @@ func (in *DisruptionSpec) DeepCopyInto(out *DisruptionSpec) {
 		*out = new(GRPCDisruptionSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPDisruptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AbortConditions != nil {
+		in, out := &in.AbortConditions, &out.AbortConditions
+		*out = new(AbortConditionsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionSpec.
@@ -348,6 +507,13 @@ func (in *DisruptionStatus) DeepCopyInto(out *DisruptionStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TargetsInitialRestartCount != nil {
+		in, out := &in.TargetsInitialRestartCount, &out.TargetsInitialRestartCount
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionStatus.
@@ -360,9 +526,162 @@ func (in *DisruptionStatus) DeepCopy() *DisruptionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionWorkflow) DeepCopyInto(out *DisruptionWorkflow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionWorkflow.
+func (in *DisruptionWorkflow) DeepCopy() *DisruptionWorkflow {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionWorkflow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DisruptionWorkflow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionWorkflowList) DeepCopyInto(out *DisruptionWorkflowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DisruptionWorkflow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionWorkflowList.
+func (in *DisruptionWorkflowList) DeepCopy() *DisruptionWorkflowList {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionWorkflowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DisruptionWorkflowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionWorkflowSpec) DeepCopyInto(out *DisruptionWorkflowSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]DisruptionWorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionWorkflowSpec.
+func (in *DisruptionWorkflowSpec) DeepCopy() *DisruptionWorkflowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionWorkflowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionWorkflowStatus) DeepCopyInto(out *DisruptionWorkflowStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]DisruptionWorkflowStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionWorkflowStatus.
+func (in *DisruptionWorkflowStatus) DeepCopy() *DisruptionWorkflowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionWorkflowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionWorkflowStep) DeepCopyInto(out *DisruptionWorkflowStep) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionWorkflowStep.
+func (in *DisruptionWorkflowStep) DeepCopy() *DisruptionWorkflowStep {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionWorkflowStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionWorkflowStepStatus) DeepCopyInto(out *DisruptionWorkflowStepStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionWorkflowStepStatus.
+func (in *DisruptionWorkflowStepStatus) DeepCopy() *DisruptionWorkflowStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionWorkflowStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EndpointAlteration) DeepCopyInto(out *EndpointAlteration) {
 	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointAlteration.
@@ -381,7 +700,9 @@ func (in *GRPCDisruptionSpec) DeepCopyInto(out *GRPCDisruptionSpec) {
 	if in.Endpoints != nil {
 		in, out := &in.Endpoints, &out.Endpoints
 		*out = make([]EndpointAlteration, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -395,6 +716,70 @@ func (in *GRPCDisruptionSpec) DeepCopy() *GRPCDisruptionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPDisruptionSpec) DeepCopyInto(out *HTTPDisruptionSpec) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HTTPRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPDisruptionSpec.
+func (in *HTTPDisruptionSpec) DeepCopy() *HTTPDisruptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPDisruptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProbeSpec) DeepCopyInto(out *HTTPProbeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbeSpec.
+func (in *HTTPProbeSpec) DeepCopy() *HTTPProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRule) DeepCopyInto(out *HTTPRule) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRule.
+func (in *HTTPRule) DeepCopy() *HTTPRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRecordPair) DeepCopyInto(out *HostRecordPair) {
 	*out = *in
@@ -411,6 +796,51 @@ func (in *HostRecordPair) DeepCopy() *HostRecordPair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPressureSpec) DeepCopyInto(out *MemoryPressureSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPressureSpec.
+func (in *MemoryPressureSpec) DeepCopy() *MemoryPressureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPressureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDisruptionFourStateSpec) DeepCopyInto(out *NetworkDisruptionFourStateSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionFourStateSpec.
+func (in *NetworkDisruptionFourStateSpec) DeepCopy() *NetworkDisruptionFourStateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDisruptionFourStateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDisruptionGilbertElliottSpec) DeepCopyInto(out *NetworkDisruptionGilbertElliottSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionGilbertElliottSpec.
+func (in *NetworkDisruptionGilbertElliottSpec) DeepCopy() *NetworkDisruptionGilbertElliottSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDisruptionGilbertElliottSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkDisruptionHostSpec) DeepCopyInto(out *NetworkDisruptionHostSpec) {
 	*out = *in
@@ -426,6 +856,78 @@ func (in *NetworkDisruptionHostSpec) DeepCopy() *NetworkDisruptionHostSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDisruptionLossModelSpec) DeepCopyInto(out *NetworkDisruptionLossModelSpec) {
+	*out = *in
+	if in.GilbertElliott != nil {
+		in, out := &in.GilbertElliott, &out.GilbertElliott
+		*out = new(NetworkDisruptionGilbertElliottSpec)
+		**out = **in
+	}
+	if in.FourState != nil {
+		in, out := &in.FourState, &out.FourState
+		*out = new(NetworkDisruptionFourStateSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionLossModelSpec.
+func (in *NetworkDisruptionLossModelSpec) DeepCopy() *NetworkDisruptionLossModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDisruptionLossModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDisruptionPartitionSpec) DeepCopyInto(out *NetworkDisruptionPartitionSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(labels.Set, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionPartitionSpec.
+func (in *NetworkDisruptionPartitionSpec) DeepCopy() *NetworkDisruptionPartitionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDisruptionPartitionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDisruptionProfileSpec) DeepCopyInto(out *NetworkDisruptionProfileSpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]NetworkDisruptionHostSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]NetworkDisruptionServiceSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionProfileSpec.
+func (in *NetworkDisruptionProfileSpec) DeepCopy() *NetworkDisruptionProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDisruptionProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkDisruptionServiceSpec) DeepCopyInto(out *NetworkDisruptionServiceSpec) {
 	*out = *in
@@ -441,6 +943,21 @@ func (in *NetworkDisruptionServiceSpec) DeepCopy() *NetworkDisruptionServiceSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkDisruptionSlotSpec) DeepCopyInto(out *NetworkDisruptionSlotSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionSlotSpec.
+func (in *NetworkDisruptionSlotSpec) DeepCopy() *NetworkDisruptionSlotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkDisruptionSlotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkDisruptionSpec) DeepCopyInto(out *NetworkDisruptionSpec) {
 	*out = *in
@@ -459,11 +976,33 @@ func (in *NetworkDisruptionSpec) DeepCopyInto(out *NetworkDisruptionSpec) {
 		*out = make([]NetworkDisruptionServiceSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.LossModel != nil {
+		in, out := &in.LossModel, &out.LossModel
+		*out = new(NetworkDisruptionLossModelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slot != nil {
+		in, out := &in.Slot, &out.Slot
+		*out = new(NetworkDisruptionSlotSpec)
+		**out = **in
+	}
 	if in.DeprecatedPort != nil {
 		in, out := &in.DeprecatedPort, &out.DeprecatedPort
 		*out = new(int)
 		**out = **in
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]NetworkDisruptionProfileSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(NetworkDisruptionPartitionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkDisruptionSpec.
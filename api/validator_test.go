@@ -81,6 +81,79 @@ var _ = Describe("Validator", func() {
 			})
 		})
 	})
+
+	Describe("validating cpu pressure spec", func() {
+		BeforeEach(func() {
+			yamlDisruptionSpec.WriteString("\ncpuPressure:")
+		})
+
+		Context("with a valid percentage and count", func() {
+			BeforeEach(func() {
+				yamlDisruptionSpec.WriteString("\n  percentage: 60")
+				yamlDisruptionSpec.WriteString("\n  count: 50%")
+			})
+
+			It("should validate", func() {
+				Expect(errList).To(HaveLen(0))
+			})
+		})
+
+		Context("with an out of range percentage", func() {
+			BeforeEach(func() {
+				yamlDisruptionSpec.WriteString("\n  percentage: 120")
+			})
+
+			It("should not validate", func() {
+				Expect(errList).To(HaveLen(2))
+			})
+		})
+
+		Context("with an invalid count", func() {
+			BeforeEach(func() {
+				yamlDisruptionSpec.WriteString("\n  count: 0")
+			})
+
+			It("should not validate", func() {
+				Expect(errList).To(HaveLen(1))
+			})
+		})
+	})
+	Describe("validating memory pressure spec", func() {
+		BeforeEach(func() {
+			yamlDisruptionSpec.WriteString("\nmemoryPressure:")
+		})
+
+		Context("with a valid absolute target and ramp duration", func() {
+			BeforeEach(func() {
+				yamlDisruptionSpec.WriteString("\n  target: 512Mi")
+				yamlDisruptionSpec.WriteString("\n  rampDuration: 5m")
+			})
+
+			It("should validate", func() {
+				Expect(errList).To(HaveLen(0))
+			})
+		})
+
+		Context("with a valid percentage target", func() {
+			BeforeEach(func() {
+				yamlDisruptionSpec.WriteString("\n  target: 80%")
+			})
+
+			It("should validate", func() {
+				Expect(errList).To(HaveLen(0))
+			})
+		})
+
+		Context("without any target", func() {
+			BeforeEach(func() {
+				yamlDisruptionSpec.WriteString("\n  rampDuration: 5m")
+			})
+
+			It("should not validate", func() {
+				Expect(errList).To(HaveLen(2))
+			})
+		})
+	})
 })
 
 var _ = Describe("Validator", func() {
@@ -24,8 +24,13 @@ type Manager interface {
 	Exists(kind string) (bool, error)
 	DiskThrottleRead(identifier, bps int) error
 	DiskThrottleWrite(identifier, bps int) error
+	IsCgroupV2() bool
+	RelativePath(kind string) string
 }
 
+// procPath is the path of the proc filesystem exposing the cgroups of each process
+var procPath = "/proc"
+
 type manager struct {
 	dryRun bool
 	paths  map[string]string
@@ -34,6 +39,7 @@ type manager struct {
 }
 
 // NewManager creates a new cgroup manager from the given cgroup root path
+// the cgroup version (v1 or v2) is detected from the cgroups the given PID belongs to
 func NewManager(dryRun bool, pid uint32, log *zap.SugaredLogger) (Manager, error) {
 	mount, ok := os.LookupEnv(env.InjectorMountCgroup)
 	if !ok {
@@ -41,11 +47,24 @@ func NewManager(dryRun bool, pid uint32, log *zap.SugaredLogger) (Manager, error
 	}
 
 	// create cgroups manager
-	cgroupPaths, err := parse(fmt.Sprintf("/proc/%d/cgroup", pid))
+	cgroupPaths, err := parse(fmt.Sprintf("%s/%d/cgroup", procPath, pid))
 	if err != nil {
 		return nil, err
 	}
 
+	// the process only belongs to the unified hierarchy when running on a cgroup v2 host
+	// it is represented by a single 0::<path> entry, parsed with an empty controller name
+	if unifiedPath, found := cgroupPaths[""]; found && len(cgroupPaths) == 1 {
+		log.Infow("cgroup v2 detected, using the unified hierarchy", "pid", pid, "path", unifiedPath)
+
+		return managerV2{
+			dryRun: dryRun,
+			path:   unifiedPath,
+			mount:  mount,
+			log:    log,
+		}, nil
+	}
+
 	return manager{
 		dryRun: dryRun,
 		paths:  cgroupPaths,
@@ -55,8 +74,8 @@ func NewManager(dryRun bool, pid uint32, log *zap.SugaredLogger) (Manager, error
 }
 
 // read reads the given cgroup file data and returns it as a string, truncating leading \n char
-func (m manager) read(path string) (string, error) {
-	m.log.Infow("reading from cgroup file", "path", path)
+func read(log *zap.SugaredLogger, path string) (string, error) {
+	log.Infow("reading from cgroup file", "path", path)
 	//nolint:gosec
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -68,10 +87,10 @@ func (m manager) read(path string) (string, error) {
 
 // write appends the given data to the given cgroup file path
 // NOTE: depending on the cgroup file, the append will result in an overwrite
-func (m manager) write(path, data string) error {
-	m.log.Infow("writing to cgroup file", "path", path, "data", data)
+func write(log *zap.SugaredLogger, dryRun bool, path, data string) error {
+	log.Infow("writing to cgroup file", "path", path, "data", data)
 	// early exit if dry-run mode is enabled
-	if m.dryRun {
+	if dryRun {
 		return nil
 	}
 
@@ -112,7 +131,7 @@ func (m manager) Read(kind, file string) (string, error) {
 
 	path := fmt.Sprintf("%s/%s", kindPath, file)
 
-	return m.read(path)
+	return read(m.log, path)
 }
 
 // Write writes the given data to the given cgroup kind
@@ -124,7 +143,7 @@ func (m manager) Write(kind, file, data string) error {
 
 	path := fmt.Sprintf("%s/%s", kindPath, file)
 
-	return m.write(path, data)
+	return write(m.log, m.dryRun, path, data)
 }
 
 // Exists returns true if the given cgroup exists, false otherwise
@@ -163,14 +182,14 @@ func (m manager) Join(kind string, pid int, inherit bool) error {
 
 	path := fmt.Sprintf("%s/%s", kindPath, file)
 
-	return m.write(path, strconv.Itoa(pid))
+	return write(m.log, m.dryRun, path, strconv.Itoa(pid))
 }
 
 // diskThrottle writes a disk throttling rule to the given blkio cgroup file
 func (m manager) diskThrottle(path string, identifier, bps int) error {
 	data := fmt.Sprintf("%d:0 %d", identifier, bps)
 
-	return m.write(path, data)
+	return write(m.log, m.dryRun, path, data)
 }
 
 // DiskThrottleRead adds a disk throttle on read operations to the given disk identifier
@@ -196,3 +215,13 @@ func (m manager) DiskThrottleWrite(identifier, bps int) error {
 
 	return m.diskThrottle(path, identifier, bps)
 }
+
+// IsCgroupV2 returns false since this manager handles cgroup v1 hierarchies
+func (m manager) IsCgroupV2() bool {
+	return false
+}
+
+// RelativePath returns the path of the given cgroup kind, relative to the cgroup mount point
+func (m manager) RelativePath(kind string) string {
+	return m.paths[kind]
+}
@@ -53,3 +53,17 @@ func (f *ManagerMock) DiskThrottleWrite(identifier, bps int) error {
 
 	return args.Error(0)
 }
+
+//nolint:golint
+func (f *ManagerMock) IsCgroupV2() bool {
+	args := f.Called()
+
+	return args.Bool(0)
+}
+
+//nolint:golint
+func (f *ManagerMock) RelativePath(kind string) string {
+	args := f.Called(kind)
+
+	return args.String(0)
+}
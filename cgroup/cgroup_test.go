@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/DataDog/chaos-controller/env"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Cgroup manager creation", func() {
+	var (
+		proc        string
+		cgroupFile  string
+		manager     Manager
+		err         error
+		initialProc string
+	)
+
+	BeforeEach(func() {
+		proc, err = ioutil.TempDir("", "proc")
+		Expect(err).To(BeNil())
+		Expect(os.MkdirAll(filepath.Join(proc, "1234"), 0755)).To(BeNil())
+		Expect(os.Setenv(env.InjectorMountCgroup, "/mnt/cgroup/")).To(BeNil())
+
+		initialProc = procPath
+		procPath = proc
+	})
+
+	JustBeforeEach(func() {
+		Expect(ioutil.WriteFile(filepath.Join(proc, "1234", "cgroup"), []byte(cgroupFile), 0644)).To(BeNil())
+
+		manager, err = NewManager(false, 1234, zap.NewNop().Sugar())
+	})
+
+	AfterEach(func() {
+		procPath = initialProc
+
+		Expect(os.Unsetenv(env.InjectorMountCgroup)).To(BeNil())
+		Expect(os.RemoveAll(proc)).To(BeNil())
+	})
+
+	Context("with a process only belonging to the unified hierarchy", func() {
+		BeforeEach(func() {
+			cgroupFile = "0::/kubepods/pod1/ctn1\n"
+		})
+
+		It("should create a cgroup v2 manager", func() {
+			Expect(err).To(BeNil())
+			Expect(manager.IsCgroupV2()).To(BeTrue())
+			Expect(manager.RelativePath("cpu")).To(Equal("/kubepods/pod1/ctn1"))
+		})
+	})
+
+	Context("with a process belonging to cgroup v1 hierarchies", func() {
+		BeforeEach(func() {
+			cgroupFile = "12:memory:/kubepods/pod1/ctn1\n11:cpu,cpuacct:/kubepods/pod1/ctn1\n"
+		})
+
+		It("should create a cgroup v1 manager", func() {
+			Expect(err).To(BeNil())
+			Expect(manager.IsCgroupV2()).To(BeFalse())
+			Expect(manager.RelativePath("memory")).To(Equal("/kubepods/pod1/ctn1"))
+		})
+	})
+
+	Context("with a process belonging to both cgroup v1 hierarchies and the unified hierarchy", func() {
+		BeforeEach(func() {
+			cgroupFile = "12:memory:/kubepods/pod1/ctn1\n0::/kubepods/pod1/ctn1\n"
+		})
+
+		It("should create a cgroup v1 manager", func() {
+			Expect(err).To(BeNil())
+			Expect(manager.IsCgroupV2()).To(BeFalse())
+		})
+	})
+
+	Context("without the cgroup mount environment variable", func() {
+		BeforeEach(func() {
+			cgroupFile = "0::/kubepods/pod1/ctn1\n"
+
+			Expect(os.Unsetenv(env.InjectorMountCgroup)).To(BeNil())
+		})
+
+		It("should fail", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// managerV2 is a cgroup manager working with the cgroup v2 unified hierarchy
+// all the controllers share a single cgroup path, so the given cgroup kinds are only used for logging purpose
+type managerV2 struct {
+	dryRun bool
+	path   string
+	mount  string
+	log    *zap.SugaredLogger
+}
+
+// generatePath generates a path within the unified hierarchy like /<mount>/<path (kubepods)>
+func (m managerV2) generatePath() string {
+	return fmt.Sprintf("%s%s", m.mount, m.path)
+}
+
+// Read reads the given cgroup file data and returns the content as a string
+func (m managerV2) Read(kind, file string) (string, error) {
+	path := fmt.Sprintf("%s/%s", m.generatePath(), file)
+
+	return read(m.log, path)
+}
+
+// Write writes the given data to the given cgroup kind
+func (m managerV2) Write(kind, file, data string) error {
+	path := fmt.Sprintf("%s/%s", m.generatePath(), file)
+
+	return write(m.log, m.dryRun, path, data)
+}
+
+// Exists returns true if the given cgroup exists, false otherwise
+func (m managerV2) Exists(kind string) (bool, error) {
+	path := fmt.Sprintf("%s/cgroup.procs", m.generatePath())
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Join adds the given PID to the unified cgroup
+// Threads can't be moved alone to a domain cgroup in cgroup v2, so all PID of the same group
+// are always moved to the cgroup (writing to cgroup.procs file) whatever the inherit value is
+func (m managerV2) Join(kind string, pid int, inherit bool) error {
+	if !inherit {
+		m.log.Debugw("moving the whole process to the cgroup since cgroup v2 can't move a single thread", "kind", kind, "pid", pid)
+	}
+
+	path := fmt.Sprintf("%s/cgroup.procs", m.generatePath())
+
+	return write(m.log, m.dryRun, path, strconv.Itoa(pid))
+}
+
+// diskThrottle writes a disk throttling rule for the given key (rbps or wbps) to the io.max cgroup file
+// a bps value of 0 removes the throttling
+func (m managerV2) diskThrottle(key string, identifier, bps int) error {
+	value := "max"
+	if bps > 0 {
+		value = strconv.Itoa(bps)
+	}
+
+	path := fmt.Sprintf("%s/io.max", m.generatePath())
+	data := fmt.Sprintf("%d:0 %s=%s", identifier, key, value)
+
+	return write(m.log, m.dryRun, path, data)
+}
+
+// DiskThrottleRead adds a disk throttle on read operations to the given disk identifier
+func (m managerV2) DiskThrottleRead(identifier, bps int) error {
+	return m.diskThrottle("rbps", identifier, bps)
+}
+
+// DiskThrottleWrite adds a disk throttle on write operations to the given disk identifier
+func (m managerV2) DiskThrottleWrite(identifier, bps int) error {
+	return m.diskThrottle("wbps", identifier, bps)
+}
+
+// IsCgroupV2 returns true since this manager handles the cgroup v2 unified hierarchy
+func (m managerV2) IsCgroupV2() bool {
+	return true
+}
+
+// RelativePath returns the path of the unified cgroup, relative to the cgroup mount point
+func (m managerV2) RelativePath(kind string) string {
+	return m.path
+}
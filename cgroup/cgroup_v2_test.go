@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Cgroup v2 manager", func() {
+	var (
+		mount   string
+		cgroup  string
+		manager Manager
+	)
+
+	// readFile returns the content of the given file of the target cgroup
+	readFile := func(file string) string {
+		data, err := ioutil.ReadFile(filepath.Join(cgroup, file))
+		Expect(err).To(BeNil())
+
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		mount, err = ioutil.TempDir("", "cgroup")
+		Expect(err).To(BeNil())
+
+		// create a fake unified cgroup with the files used by the manager
+		cgroup = filepath.Join(mount, "kubepods", "pod1", "ctn1")
+		Expect(os.MkdirAll(cgroup, 0755)).To(BeNil())
+
+		for _, file := range []string{"cgroup.procs", "io.max"} {
+			Expect(ioutil.WriteFile(filepath.Join(cgroup, file), []byte{}, 0644)).To(BeNil())
+		}
+
+		Expect(ioutil.WriteFile(filepath.Join(cgroup, "cpuset.cpus.effective"), []byte("0-3\n"), 0644)).To(BeNil())
+
+		manager = managerV2{
+			path:  "/kubepods/pod1/ctn1",
+			mount: mount,
+			log:   zap.NewNop().Sugar(),
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(mount)).To(BeNil())
+	})
+
+	It("should be a cgroup v2 manager", func() {
+		Expect(manager.IsCgroupV2()).To(BeTrue())
+		Expect(manager.RelativePath("net_cls")).To(Equal("/kubepods/pod1/ctn1"))
+	})
+
+	It("should read files from the unified cgroup whatever the kind is", func() {
+		Expect(manager.Read("cpuset", "cpuset.cpus.effective")).To(Equal("0-3"))
+	})
+
+	It("should move the whole process to the unified cgroup even when not inheriting", func() {
+		Expect(manager.Join("cpu", 666, false)).To(BeNil())
+		Expect(readFile("cgroup.procs")).To(Equal("666"))
+	})
+
+	It("should report the unified cgroup as existing", func() {
+		Expect(manager.Exists("memory")).To(BeTrue())
+	})
+
+	It("should throttle disk reads through io.max", func() {
+		Expect(manager.DiskThrottleRead(8, 1024)).To(BeNil())
+		Expect(readFile("io.max")).To(Equal("8:0 rbps=1024"))
+	})
+
+	It("should remove disk writes throttling through io.max", func() {
+		Expect(manager.DiskThrottleWrite(8, 0)).To(BeNil())
+		Expect(readFile("io.max")).To(Equal("8:0 wbps=max"))
+	})
+})
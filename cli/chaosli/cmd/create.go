@@ -102,7 +102,7 @@ func createSpec() (v1beta1.DisruptionSpec, error) {
 		spec.Containers = getContainers()
 	}
 
-	if spec.ContainerFailure == nil && spec.CPUPressure == nil && spec.DiskPressure == nil && spec.NodeFailure == nil && spec.GRPC == nil && spec.Level == types.DisruptionLevelPod && len(spec.Containers) == 0 {
+	if spec.ContainerFailure == nil && spec.CPUPressure == nil && spec.MemoryPressure == nil && spec.DiskPressure == nil && spec.NodeFailure == nil && spec.GRPC == nil && spec.HTTP == nil && spec.Level == types.DisruptionLevelPod && len(spec.Containers) == 0 {
 		spec.OnInit = getOnInit()
 	}
 
@@ -114,10 +114,11 @@ func createSpec() (v1beta1.DisruptionSpec, error) {
 func promptForKind(spec *v1beta1.DisruptionSpec) error {
 	initial := "Let's begin by choosing the type of disruption to apply! Which disruption kind would you like to add?"
 	followUp := "Would you like to add another disruption kind? It's not necessary, most disruptions involve only one kind. Select .. to finish adding kinds."
-	kinds := []string{"dns", "network", "cpu", "disk", "node failure", "container failure"}
+	kinds := []string{"dns", "network", "cpu", "memory", "disk", "node failure", "container failure"}
 	helpText := `The DNS disruption allows for overriding the A or CNAME records returned by DNS queries.
 The Network disruption allows for injecting a variety of different network issues into your target.
 The CPU and Disk disruptions apply cpu pressure or IO throttling to your target, respectively.
+The Memory disruption allocates memory in your target's memory cgroup until a target usage is reached.
 Tne Node Failure disruption can either shutdown or restart the targeted node, or the node hosting the targeted pod.
 
 Select one for more information on it.`
@@ -176,6 +177,21 @@ Select one for more information on it.`
 
 				spec.CPUPressure = nil
 
+				continue
+			}
+		case "memory":
+			spec.MemoryPressure = getMemoryPressure()
+
+			if spec.MemoryPressure == nil {
+				continue
+			}
+
+			err := spec.MemoryPressure.Validate()
+			if err != nil {
+				fmt.Printf("There were some problems with your memory pressure disruption's spec: %v\n\n", err)
+
+				spec.MemoryPressure = nil
+
 				continue
 			}
 		case "disk":
@@ -358,15 +374,23 @@ func getDNS() v1beta1.DNSDisruptionSpec {
 			survey.WithValidator(survey.Required),
 		)
 		hrPair.Record.Type, _ = selectInput("the type of DNS record to inject",
-			[]string{"A", "CNAME"},
-			"We only support these two types of DNS requests for now. An A record request gets back an IP for a hostname, while a CNAME request maps an alias domain name to the canonical name.")
-		helpText := "We're specifying an A record, so the value should be an IP address. You can specify multiple IP addresses, if desired. Simply delimit them with commas, no whitespace! The disruption will round-robin between the options."
+			[]string{"A", "CNAME", "NXDOMAIN", "SERVFAIL", "REFUSED", "DROP", "FORWARD"},
+			"An A record request gets back an IP for a hostname, while a CNAME request maps an alias domain name to the canonical name. The other types fail the requests (NXDOMAIN, SERVFAIL, REFUSED), never answer them (DROP) or resolve them as usual (FORWARD, along with a delay).")
+
+		if hrPair.Record.Type == "A" || hrPair.Record.Type == "CNAME" {
+			helpText := "We're specifying an A record, so the value should be an IP address. You can specify multiple IP addresses, if desired. Simply delimit them with commas, no whitespace! The disruption will round-robin between the options."
 
-		if hrPair.Record.Type == "CNAME" {
-			helpText = "We're specifying a CNAME record, so the value should be a hostname to redirect to."
+			if hrPair.Record.Type == "CNAME" {
+				helpText = "We're specifying a CNAME record, so the value should be a hostname to redirect to."
+			}
+
+			hrPair.Record.Value = getInput("What value would you like to inject into this DNS record?", helpText, survey.WithValidator(survey.Required))
 		}
 
-		hrPair.Record.Value = getInput("What value would you like to inject into this DNS record?", helpText, survey.WithValidator(survey.Required))
+		if hrPair.Record.Type == "FORWARD" || confirmOption("Would you like to delay the DNS responses?", "The responses are sent after the given delay, simulating a slow resolution.") {
+			delay, _ := strconv.Atoi(getInput("Specify the delay, in ms", "The delay before answering the requests matching the hostname.", survey.WithValidator(survey.Required), survey.WithValidator(integerValidator)))
+			hrPair.Record.Delay = uint(delay)
+		}
 
 		return hrPair
 	}
@@ -410,11 +434,73 @@ func getDiskPressure() *v1beta1.DiskPressureSpec {
 }
 
 func getCPUPressure() *v1beta1.CPUPressureSpec {
-	if confirmKind("CPU Pressure", "Applies CPU pressure to the target") {
-		return &v1beta1.CPUPressureSpec{}
+	if !confirmKind("CPU Pressure", "Applies CPU pressure to the target") {
+		return nil
 	}
 
-	return nil
+	spec := &v1beta1.CPUPressureSpec{}
+
+	if confirmOption("Would you like to apply a partial load on the stressed cores?", "By default, each stressed core is loaded at 100%") {
+		percentage, _ := strconv.Atoi(getInput("What load percentage should we apply to each stressed core?", "1-100", survey.WithValidator(survey.Required), survey.WithValidator(percentageValidator)))
+		spec.Percentage = &percentage
+	}
+
+	if confirmOption("Would you like to stress only some of the target's cores?", "By default, all the cores allocated to the target are stressed") {
+		count := intstr.Parse(getInput(
+			"How many cores would you like to stress? This can be an integer, or a percentage.",
+			"Please specify an integer >0 or a percentage from 1% - 100%. If specifying a percentage, you must suffix with the % character, or we will think its an integer!",
+			survey.WithValidator(survey.Required),
+		))
+		spec.Count = &count
+	}
+
+	return spec
+}
+
+func getMemoryPressure() *v1beta1.MemoryPressureSpec {
+	if !confirmKind("Memory Pressure", "Allocates memory in the target memory cgroup until a target usage is reached") {
+		return nil
+	}
+
+	targetValidator := func(val interface{}) error {
+		if str, ok := val.(string); ok {
+			spec := v1beta1.MemoryPressureSpec{Target: str}
+			_, _, err := spec.ParseTarget()
+
+			return err
+		}
+
+		return fmt.Errorf("expected a string response, rather than type %v", reflect.TypeOf(val).Name())
+	}
+
+	durationValidator := func(val interface{}) error {
+		if str, ok := val.(string); ok {
+			_, err := time.ParseDuration(str)
+
+			return err
+		}
+
+		return fmt.Errorf("expected a string response, rather than type %v", reflect.TypeOf(val).Name())
+	}
+
+	spec := &v1beta1.MemoryPressureSpec{}
+	spec.Target = getInput(
+		"What memory usage should the target reach? This can be an absolute size, or a percentage of the target memory limit.",
+		"Please specify a memory quantity (e.g., \"512Mi\", \"2Gi\") or a percentage from 1% - 100%. If specifying a percentage, you must suffix with the % character!",
+		survey.WithValidator(survey.Required),
+		survey.WithValidator(targetValidator),
+	)
+
+	if confirmOption("Would you like to progressively ramp up the memory usage?", "By default, the memory is allocated at once") {
+		spec.RampDuration = v1beta1.DisruptionDuration(getInput(
+			"How long should it take to reach the target memory usage? This can be a golang's time.Duration.",
+			"Please specify a golang's time.Duration, e.g., \"45s\", \"15m30s\".",
+			survey.WithValidator(survey.Required),
+			survey.WithValidator(durationValidator),
+		))
+	}
+
+	return spec
 }
 
 func getNodeFailure() *v1beta1.NodeFailureSpec {
@@ -107,6 +107,43 @@ func explainCPUPressure(spec v1beta1.DisruptionSpec) {
 	}
 
 	fmt.Println("💉 injects a cpu pressure disruption ...")
+
+	if cpuPressure.Count == nil {
+		fmt.Println("\t🧮 on all the cores allocated to the target")
+	} else {
+		fmt.Printf("\t🧮 on %s of the cores allocated to the target\n", cpuPressure.Count.String())
+	}
+
+	if cpuPressure.Percentage == nil {
+		fmt.Println("\t🔥 with a load of 100% on each stressed core")
+	} else {
+		fmt.Printf("\t🔥 with a load of %d%% on each stressed core\n", *cpuPressure.Percentage)
+	}
+
+	PrintSeparator()
+}
+
+func explainMemoryPressure(spec v1beta1.DisruptionSpec) {
+	memoryPressure := spec.MemoryPressure
+
+	if memoryPressure == nil {
+		return
+	}
+
+	fmt.Println("💉 injects a memory pressure disruption ...")
+
+	if strings.HasSuffix(memoryPressure.Target, "%") {
+		fmt.Printf("\t🧮 allocating memory until the target reaches %s of its memory limit\n", memoryPressure.Target)
+	} else {
+		fmt.Printf("\t🧮 allocating memory until the target reaches a usage of %s\n", memoryPressure.Target)
+	}
+
+	if memoryPressure.RampDuration.Duration() > 0 {
+		fmt.Printf("\t📈 ramping up the memory usage over %s\n", memoryPressure.RampDuration.Duration())
+	} else {
+		fmt.Println("\t📈 allocating the memory at once")
+	}
+
 	PrintSeparator()
 }
 
@@ -165,6 +202,11 @@ func explainGRPC(spec v1beta1.DisruptionSpec) {
 	}
 
 	fmt.Printf("💉 injects a gRPC disruption on port %d ...\n", grpc.Port)
+
+	if grpc.ListenerPort != 0 {
+		fmt.Printf("\t📡 configured through the disruption listener on port %d ...\n", grpc.ListenerPort)
+	}
+
 	fmt.Println("\t🥸  to spoof the following endpoints...")
 
 	endptSpec := grpcapi.GenerateEndpointSpecs(grpc.Endpoints) // []*pb.EndpointSpec
@@ -172,22 +214,82 @@ func explainGRPC(spec v1beta1.DisruptionSpec) {
 	for _, endpt := range endptSpec {
 		fmt.Printf("\t\t👩‍⚕️ endpoint: %s ...\n", endpt.TargetEndpoint) //nolint:stylecheck
 
-		alterationToQueryPercent, err := grpccalc.GetPercentagePerAlteration(endpt.Alterations)
+		for _, group := range grpccalc.GroupSpecificationsByMetadata(endpt.Alterations) {
+			if len(group[0].Metadata) > 0 {
+				fmt.Printf("\t\t\t🏷  for calls with metadata %v ...\n", group[0].Metadata)
+			}
 
-		if err != nil {
-			fmt.Printf("\t\t\t💣  this disruption fails with err: %s\n", err.Error())
-		}
+			alterationToQueryPercent, err := grpccalc.GetPercentagePerAlteration(group)
+
+			if err != nil {
+				fmt.Printf("\t\t\t💣  this disruption fails with err: %s\n", err.Error())
+			}
 
-		var spoof string
+			var spoof string
 
-		for altConfig, pct := range alterationToQueryPercent {
-			if altConfig.ErrorToReturn != "" {
-				spoof = fmt.Sprintf("error: %s", altConfig.ErrorToReturn)
-			} else {
-				spoof = fmt.Sprintf("override: %s", altConfig.OverrideToReturn)
+			for altConfig, pct := range alterationToQueryPercent {
+				switch {
+				case altConfig.ErrorToReturn != "":
+					spoof = fmt.Sprintf("error: %s", altConfig.ErrorToReturn)
+				case altConfig.OverrideToReturn != "":
+					spoof = fmt.Sprintf("override: %s", altConfig.OverrideToReturn)
+				default:
+					spoof = "the computed response"
+				}
+
+				if altConfig.Latency > 0 {
+					spoof = fmt.Sprintf("%s after a latency of %s (%d%% jitter)", spoof, altConfig.Latency, altConfig.LatencyJitter)
+				}
+
+				if altConfig.FailAfterMessages > 0 {
+					spoof = fmt.Sprintf("%s once %d messages are streamed", spoof, altConfig.FailAfterMessages)
+				}
+
+				fmt.Printf("\t\t\t💣  will be %d percent spoofed with %s\n", pct, spoof)
 			}
+		}
+	}
+
+	PrintSeparator()
+}
+
+func explainHTTP(spec v1beta1.DisruptionSpec) {
+	http := spec.HTTP
 
-			fmt.Printf("\t\t\t💣  will be %d percent spoofed with %s\n", pct, spoof)
+	if http == nil {
+		return
+	}
+
+	fmt.Printf("💉 injects an HTTP disruption on ports %v ...\n", http.Ports)
+	fmt.Println("\t🥸  to disrupt the requests matching the following rules, the first matching rule applies...")
+
+	for _, rule := range http.Rules {
+		method, path := rule.Method, rule.Path
+
+		if method == "" {
+			method = "any method"
+		}
+
+		if path == "" {
+			path = "any path"
+		}
+
+		fmt.Printf("\t\t👩‍⚕️ %s on %s ...\n", method, path) //nolint:stylecheck
+
+		for name, value := range rule.Headers {
+			fmt.Printf("\t\t\t🏷  with header %s: %s\n", name, value)
+		}
+
+		if rule.Delay.Duration() > 0 {
+			fmt.Printf("\t\t\t🐌 will be %d percent delayed by %s\n", rule.GetPercent(), rule.Delay.Duration())
+		}
+
+		if rule.StatusCode != 0 {
+			fmt.Printf("\t\t\t💣  will be %d percent answered with status code %d\n", rule.GetPercent(), rule.StatusCode)
+		}
+
+		if rule.Abort {
+			fmt.Printf("\t\t\t💣  will be %d percent aborted in the middle of the response\n", rule.GetPercent())
 		}
 	}
 
@@ -262,6 +364,51 @@ func explainNetworkFailure(spec v1beta1.DisruptionSpec) {
 		fmt.Printf("\t\t💣 applies a bandwidth limit of %d ms.\n", network.BandwidthLimit)
 	}
 
+	if network.LossModel != nil && network.LossModel.GilbertElliott != nil {
+		model := network.LossModel.GilbertElliott
+		fmt.Printf("\t\t💣 drops packets following a Gilbert-Elliott model (%d%% chance to degrade, %d%% chance to recover, %d%% loss when degraded, %d%% loss otherwise).\n", model.P, model.R, model.BadStateLoss, model.GoodStateLoss)
+	}
+
+	if network.LossModel != nil && network.LossModel.FourState != nil {
+		model := network.LossModel.FourState
+		fmt.Printf("\t\t💣 drops packets following a 4-state model (p13 %d%%, p31 %d%%, p32 %d%%, p23 %d%%, p14 %d%%).\n", model.P13, model.P31, model.P32, model.P23, model.P14)
+	}
+
+	if network.Reorder != 0 {
+		fmt.Printf("\t\t💣 reorders %d percent of the packets by sending them without delay.\n", network.Reorder)
+	}
+
+	if network.Slot != nil {
+		maxDelay := network.Slot.MaxDelay
+		if maxDelay == 0 {
+			maxDelay = network.Slot.MinDelay
+		}
+
+		fmt.Printf("\t\t💣 sends packets in slots every %d to %d µs.\n", network.Slot.MinDelay, maxDelay)
+	}
+
+	if network.DropCorrelation != 0 || network.DuplicateCorrelation != 0 || network.CorruptCorrelation != 0 || network.DelayCorrelation != 0 {
+		fmt.Printf("\t\t\t💣 correlates each decision with the previous one (drop %d%%, duplicate %d%%, corrupt %d%%, delay %d%%) to make failures bursty.\n", network.DropCorrelation, network.DuplicateCorrelation, network.CorruptCorrelation, network.DelayCorrelation)
+	}
+
+	if network.IngressShaping {
+		fmt.Println("\t💥  applies network failures on incoming traffic as well by redirecting it to an IFB device.")
+	}
+
+	for idx, profile := range network.Profiles {
+		fmt.Printf("\t💥  profile %d applies its own network failures to outgoing/ingoing traffic from/to the following hosts and services:\n", idx)
+
+		for _, data := range profile.Hosts {
+			fmt.Printf("\t\t🎯 Host: %s (port %d, protocol %s, flow %s)\n", data.Host, data.Port, data.Protocol, data.Flow)
+		}
+
+		for _, data := range profile.Services {
+			fmt.Printf("\t\t🎯 Service: %s/%s\n", data.Namespace, data.Name)
+		}
+
+		fmt.Printf("\t\t💣 drop %d%%, duplicate %d%%, corrupt %d%%, delay %d ms (jitter %d%%), bandwidth limit %d bytes/s.\n", profile.Drop, profile.Duplicate, profile.Corrupt, profile.Delay, profile.DelayJitter, profile.BandwidthLimit)
+	}
+
 	PrintSeparator()
 }
 
@@ -269,7 +416,7 @@ func explainMultiDisruption(spec v1beta1.DisruptionSpec) {
 	existsMulti := false
 
 	if spec.NodeFailure != nil {
-		if spec.CPUPressure != nil || spec.DNS != nil || spec.DiskPressure != nil || spec.Network != nil {
+		if spec.CPUPressure != nil || spec.MemoryPressure != nil || spec.DNS != nil || spec.DiskPressure != nil || spec.Network != nil {
 			fmt.Println("⚠️  You are attempting to run a Node Failure Disruption in addition to another one of our other failures.\n" +
 				"   Keep in mind that once the Node Failure runs (the kernel panic) the other disruptions will most likely not.")
 
@@ -311,9 +458,11 @@ func explanation(path string) {
 	explainContainerFailure(disruption.Spec)
 	explainNetworkFailure(disruption.Spec)
 	explainCPUPressure(disruption.Spec)
+	explainMemoryPressure(disruption.Spec)
 	explainDiskPressure(disruption.Spec)
 	explainDNS(disruption.Spec)
 	explainGRPC(disruption.Spec)
+	explainHTTP(disruption.Spec)
 }
 
 func init() {
@@ -9,6 +9,7 @@ import (
 	"github.com/DataDog/chaos-controller/api/v1beta1"
 	"github.com/DataDog/chaos-controller/injector"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 var cpuPressureCmd = &cobra.Command{
@@ -16,12 +17,33 @@ var cpuPressureCmd = &cobra.Command{
 	Short: "CPU pressure subcommands",
 	Run:   injectAndWait,
 	PreRun: func(cmd *cobra.Command, args []string) {
+		percentage, _ := cmd.Flags().GetInt("percentage")
+		rawCount, _ := cmd.Flags().GetString("count")
+
 		// prepare spec
 		spec := v1beta1.CPUPressureSpec{}
 
+		if percentage != 0 {
+			spec.Percentage = &percentage
+		}
+
+		if rawCount != "" {
+			count := intstr.Parse(rawCount)
+			spec.Count = &count
+		}
+
+		if err := spec.Validate(); err != nil {
+			log.Fatalw("invalid cpu pressure arguments", "error", err)
+		}
+
 		// create injector
 		for _, config := range configs {
 			injectors = append(injectors, injector.NewCPUPressureInjector(spec, injector.CPUPressureInjectorConfig{Config: config}))
 		}
 	},
 }
+
+func init() {
+	cpuPressureCmd.Flags().Int("percentage", 0, "Target load percentage of each stressed core (between 1 and 100, defaults to 100)")
+	cpuPressureCmd.Flags().String("count", "", "Number of cores to stress, either an integer or a percentage of the allocated cores (defaults to all allocated cores)")
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DataDog/chaos-controller/cgroup"
+	"github.com/DataDog/chaos-controller/injector"
+	logger "github.com/DataDog/chaos-controller/log"
+	"github.com/DataDog/chaos-controller/process"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+// cpuStress runs the CPU stress process started by the CPU pressure injector on cgroup v2 hosts
+// the process joins the cgroup of the given target PID and stresses the given cores until an exit signal is received
+// it skips the injector initialization and is not registered as a command since it is only started by the injector itself
+func cpuStress(args []string) {
+	flags := pflag.NewFlagSet(injector.CPUStressCommand, pflag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Enable dry-run mode")
+	targetPID := flags.Uint32("target-pid", 0, "PID of the process whose cgroup is joined")
+	cores := flags.IntSlice("cores", []int{}, "Cores to stress")
+	percentage := flags.Int("percentage", 100, "Target load percentage of each stressed core")
+
+	_ = flags.Parse(args)
+
+	log, err := logger.NewZapLogger()
+	if err != nil {
+		fmt.Printf("error while creating logger: %v", err)
+		os.Exit(2)
+	}
+
+	log = log.With("command", injector.CPUStressCommand, "pid", os.Getpid())
+
+	// handle exit signals before notifying the injector so it can't stop the process too early
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	exit := make(chan struct{})
+
+	if err := startCPUStress(log, *dryRun, *targetPID, *cores, *percentage, exit); err != nil {
+		log.Errorw("error starting the CPU stress", "error", err)
+
+		if err := process.NotifyReady(err); err != nil {
+			log.Errorw("error notifying the injector", "error", err)
+		}
+
+		os.Exit(1)
+	}
+
+	if err := process.NotifyReady(nil); err != nil {
+		log.Errorw("error notifying the injector", "error", err)
+	}
+
+	sig := <-signals
+	log.Infow("an exit signal has been received, stopping the CPU stress", "signal", sig.String())
+
+	close(exit)
+}
+
+// startCPUStress joins the cgroup of the given target PID and starts the stress routines of the given cores
+func startCPUStress(log *zap.SugaredLogger, dryRun bool, targetPID uint32, cores []int, percentage int, exit chan struct{}) error {
+	cgroupMgr, err := cgroup.NewManager(dryRun, targetPID, log)
+	if err != nil {
+		return fmt.Errorf("error creating cgroup manager: %w", err)
+	}
+
+	// the whole process joins the target cgroup before starting the stress routines so they can be pinned on the target allocated cores
+	if err := cgroupMgr.Join("cpu", os.Getpid(), true); err != nil {
+		return fmt.Errorf("failed to join the target CPU cgroup: %w", err)
+	}
+
+	return injector.StressCPU(log, cores, percentage, dryRun, exit)
+}
@@ -6,9 +6,11 @@
 package main
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/dnsresolver"
 	"github.com/DataDog/chaos-controller/injector"
 	"github.com/spf13/cobra"
 )
@@ -22,13 +24,14 @@ var dnsDisruptionCmd = &cobra.Command{
 
 		var hostRecordPairs []v1beta1.HostRecordPair
 
-		// Each value passed to --host-record-pairs should be of the form `hostname;type;value`, e.g.
-		// `foo.bar.svc.cluster.local;A;10.0.0.0,10.0.0.13`
+		// Each value passed to --host-record-pairs should be of the form `hostname;type;value` or
+		// `hostname;type;value;hostnameMatch;delay;probability`, e.g.
+		// `foo.bar.svc.cluster.local;A;10.0.0.0,10.0.0.13` or `bar.svc.cluster.local;SERVFAIL;;suffix;500;50`
 		log.Infow("arguments to dnsDisruptionCmd", "host-record-pairs", rawHostRecordPairs)
 
 		for _, line := range rawHostRecordPairs {
 			split := strings.Split(line, ";")
-			if len(split) != 3 {
+			if len(split) != 3 && len(split) != 6 {
 				log.Fatalw("could not parse --host-record-pairs argument to dns-disruption", "offending argument", line)
 				continue
 			}
@@ -40,12 +43,40 @@ var dnsDisruptionCmd = &cobra.Command{
 					Value: split[2],
 				},
 			}
+
+			if len(split) == 6 {
+				delay, err := strconv.ParseUint(split[4], 10, 0)
+				if err != nil {
+					log.Fatalw("could not parse the delay of --host-record-pairs argument to dns-disruption", "offending argument", line, "error", err)
+				}
+
+				probability, err := strconv.Atoi(split[5])
+				if err != nil {
+					log.Fatalw("could not parse the probability of --host-record-pairs argument to dns-disruption", "offending argument", line, "error", err)
+				}
+
+				hostRecordPair.HostnameMatch = split[3]
+				hostRecordPair.Record.Delay = uint(delay)
+				hostRecordPair.Record.Probability = probability
+			}
+
 			hostRecordPairs = append(hostRecordPairs, hostRecordPair)
 		}
 
-		// create injectors
+		// create injectors sharing the same resolver, listening on the chaos pod DNS port
+		var resolver dnsresolver.Resolver
+
 		for _, config := range configs {
-			inj, err := injector.NewDNSDisruptionInjector(hostRecordPairs, injector.DNSDisruptionInjectorConfig{Config: config})
+			if resolver == nil {
+				var err error
+
+				resolver, err = injector.NewDNSResolver(hostRecordPairs, config)
+				if err != nil {
+					log.Fatalw("error initializing the DNS resolver", "error", err)
+				}
+			}
+
+			inj, err := injector.NewDNSDisruptionInjector(hostRecordPairs, injector.DNSDisruptionInjectorConfig{Config: config, Resolver: resolver})
 			if err != nil {
 				log.Fatalw("error initializing the DNS injector", "error", err)
 			}
@@ -57,5 +88,5 @@ var dnsDisruptionCmd = &cobra.Command{
 
 func init() {
 	// We must use a StringArray rather than StringSlice here, because our ip values can contain commas. StringSlice will split on commas.
-	dnsDisruptionCmd.Flags().StringArray("host-record-pairs", []string{}, "list of host,record,value tuples as strings") // `foo.bar.svc.cluster.local;A;10.0.0.0,10.0.0.13`
+	dnsDisruptionCmd.Flags().StringArray("host-record-pairs", []string{}, "list of host,record,value tuples as strings, optionally followed by the hostname match, delay and probability") // `foo.bar.svc.cluster.local;A;10.0.0.0,10.0.0.13`
 }
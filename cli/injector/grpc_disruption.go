@@ -6,6 +6,7 @@
 package main
 
 import (
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -21,10 +22,16 @@ var grpcDisruptionCmd = &cobra.Command{
 	PreRun: func(cmd *cobra.Command, args []string) {
 		rawEndpointAlterations, _ := cmd.Flags().GetStringArray("endpoint-alterations")
 		port, _ := cmd.Flags().GetInt("port")
+		listenerPort, _ := cmd.Flags().GetInt("listener-port")
 
-		// Each value passed to --endpoint-alterations should be of the form `endpoint;alterationtype;alterationvalue`, e.g.
-		// `/chaosdogfood.ChaosDogfood/order;error;ALREADY_EXISTS`
-		// `/chaosdogfood.ChaosDogfood/order;override;{}`
+		// Each value passed to --endpoint-alterations should be of the form
+		// `endpoint;alterationtype;alterationvalue;querypercent;latency;latencyjitter;failaftermessages;metadata`, e.g.
+		// `/chaosdogfood.ChaosDogfood/order;error;ALREADY_EXISTS;0;;0;0;`
+		// `/chaosdogfood.ChaosDogfood/order;override;{};0;;0;0;`
+		// `/chaosdogfood.ChaosDogfood/order;override;{"message": "overridden"};0;;0;0;`
+		// `/chaosdogfood.ChaosDogfood/order;;;0;2s;10;0;`
+		// `/chaosdogfood.ChaosDogfood/streamCatalog;error;UNAVAILABLE;0;;0;2;`
+		// `/chaosdogfood.ChaosDogfood/order;error;UNAVAILABLE;0;;0;0;x-tenant=test`
 
 		log.Infow("arguments to grpcDisruptionCmd", "endpoint-alterations", rawEndpointAlterations)
 
@@ -32,31 +39,66 @@ var grpcDisruptionCmd = &cobra.Command{
 
 		for _, line := range rawEndpointAlterations {
 			split := strings.Split(line, ";")
-			if len(split) != 4 {
+			if len(split) < 8 {
 				log.Fatalw("could not parse --endpoint-alterations argument to grpc-disruption", "offending argument", line)
 				continue
 			}
 
-			var endpointAlteration v1beta1.EndpointAlteration
+			// an override JSON payload may contain semicolons, join the fields between the alteration type and the query percent back
+			if len(split) > 8 {
+				split = append([]string{split[0], split[1], strings.Join(split[2:len(split)-5], ";")}, split[len(split)-5:]...)
+			}
 
 			queryPercent, err := strconv.Atoi(split[3])
 			if err != nil {
 				log.Fatalw("could not parse --endpoint-alterations argument to grpc-disruption", "parsing failed for queryPercent", split[3])
 				continue
 			}
+
+			latencyJitter, err := strconv.Atoi(split[5])
+			if err != nil {
+				log.Fatalw("could not parse --endpoint-alterations argument to grpc-disruption", "parsing failed for latencyJitter", split[5])
+				continue
+			}
+
+			failAfterMessages, err := strconv.Atoi(split[6])
+			if err != nil {
+				log.Fatalw("could not parse --endpoint-alterations argument to grpc-disruption", "parsing failed for failAfterMessages", split[6])
+				continue
+			}
+
+			metadataValues, err := url.ParseQuery(split[7])
+			if err != nil {
+				log.Fatalw("could not parse --endpoint-alterations argument to grpc-disruption", "parsing failed for metadata", split[7])
+				continue
+			}
+
+			var metadata map[string]string
+
+			if len(metadataValues) > 0 {
+				metadata = map[string]string{}
+
+				for key := range metadataValues {
+					metadata[key] = metadataValues.Get(key)
+				}
+			}
+
+			endpointAlteration := v1beta1.EndpointAlteration{
+				TargetEndpoint:    split[0],
+				QueryPercent:      queryPercent,
+				Latency:           v1beta1.DisruptionDuration(split[4]),
+				LatencyJitter:     latencyJitter,
+				FailAfterMessages: failAfterMessages,
+				Metadata:          metadata,
+			}
+
 			switch split[1] {
 			case v1beta1.ERROR:
-				endpointAlteration = v1beta1.EndpointAlteration{
-					TargetEndpoint: split[0],
-					ErrorToReturn:  split[2],
-					QueryPercent:   queryPercent,
-				}
+				endpointAlteration.ErrorToReturn = split[2]
 			case v1beta1.OVERRIDE:
-				endpointAlteration = v1beta1.EndpointAlteration{
-					TargetEndpoint:   split[0],
-					OverrideToReturn: split[2],
-					QueryPercent:     queryPercent,
-				}
+				endpointAlteration.OverrideToReturn = split[2]
+			case "":
+				// latency only alteration
 			default:
 				log.Fatalw("GRPC injector does not understand alteration type", "type", split[1])
 			}
@@ -65,8 +107,9 @@ var grpcDisruptionCmd = &cobra.Command{
 		}
 
 		spec := v1beta1.GRPCDisruptionSpec{
-			Port:      port,
-			Endpoints: endpointAlterations,
+			Port:         port,
+			ListenerPort: listenerPort,
+			Endpoints:    endpointAlterations,
 		}
 
 		// create injectors
@@ -79,8 +122,9 @@ var grpcDisruptionCmd = &cobra.Command{
 }
 
 func init() {
-	grpcDisruptionCmd.Flags().StringArray("endpoint-alterations", []string{}, "list of endpoint;alteration_type;alteration_value;optional_query_percent tuples as strings") // `/chaosdogfood.ChaosDogfood/order;override;{}`
+	grpcDisruptionCmd.Flags().StringArray("endpoint-alterations", []string{}, "list of endpoint;alteration_type;alteration_value;optional_query_percent;optional_latency;optional_latency_jitter;optional_fail_after_messages;optional_metadata tuples as strings") // `/chaosdogfood.ChaosDogfood/order;override;{};0;2s;10;0;x-tenant=test`
 	grpcDisruptionCmd.Flags().Int("port", 0, "port to disrupt on target pod")
+	grpcDisruptionCmd.Flags().Int("listener-port", 0, "port of the disruption listener on target pod, if it isn't the port to disrupt")
 
 	_ = cobra.MarkFlagRequired(grpcDisruptionCmd.PersistentFlags(), "port")
 }
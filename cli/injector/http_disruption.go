@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/injector"
+	"github.com/spf13/cobra"
+)
+
+var httpDisruptionCmd = &cobra.Command{
+	Use:   "http-disruption",
+	Short: "HTTP disruption subcommand",
+	Run:   injectAndWait,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		ports, _ := cmd.Flags().GetIntSlice("ports")
+		rawRules, _ := cmd.Flags().GetStringArray("rules")
+
+		// Each value passed to --rules should be a JSON encoded rule, e.g.
+		// `{"method":"GET","path":"/api/orders*","percent":20,"statusCode":503}`
+		log.Infow("arguments to httpDisruptionCmd", "ports", ports, "rules", rawRules)
+
+		var rules []v1beta1.HTTPRule
+
+		for _, rawRule := range rawRules {
+			rule := v1beta1.HTTPRule{}
+
+			if err := json.Unmarshal([]byte(rawRule), &rule); err != nil {
+				log.Fatalw("could not parse --rules argument to http-disruption", "offending argument", rawRule, "error", err)
+			}
+
+			rules = append(rules, rule)
+		}
+
+		spec := v1beta1.HTTPDisruptionSpec{
+			Ports: ports,
+			Rules: rules,
+		}
+
+		// create injectors
+		// all the target containers share the same network namespace so a single injector is needed
+		for i, config := range configs {
+			if i == 0 {
+				inj, err := injector.NewHTTPDisruptionInjector(spec, injector.HTTPDisruptionInjectorConfig{Config: config})
+				if err != nil {
+					log.Fatalw("error initializing the HTTP injector", "error", err)
+				}
+
+				injectors = append(injectors, inj)
+			}
+		}
+	},
+}
+
+func init() {
+	httpDisruptionCmd.Flags().IntSlice("ports", []int{}, "ports to disrupt on target pod")
+	// We must use a StringArray rather than StringSlice here, because JSON encoded rules contain commas. StringSlice will split on commas.
+	httpDisruptionCmd.Flags().StringArray("rules", []string{}, "list of JSON encoded rules") // `{"method":"GET","path":"/api/orders*","percent":20,"statusCode":503}`
+}
@@ -83,13 +83,15 @@ func init() {
 	rootCmd.AddCommand(nodeFailureCmd)
 	rootCmd.AddCommand(containerFailureCmd)
 	rootCmd.AddCommand(cpuPressureCmd)
+	rootCmd.AddCommand(memoryPressureCmd)
 	rootCmd.AddCommand(diskPressureCmd)
 	rootCmd.AddCommand(dnsDisruptionCmd)
 	rootCmd.AddCommand(grpcDisruptionCmd)
+	rootCmd.AddCommand(httpDisruptionCmd)
 
 	// basic args
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Enable dry-run mode")
-	rootCmd.PersistentFlags().StringVar(&sink, "metrics-sink", "noop", "Metrics sink (datadog, or noop)")
+	rootCmd.PersistentFlags().StringVar(&sink, "metrics-sink", "noop", "Metrics sink (datadog, prometheus, or noop)")
 	rootCmd.PersistentFlags().StringVar(&level, "level", "", "Level of injection (either pod or node)")
 	rootCmd.PersistentFlags().StringSliceVar(&rawTargetContainers, "target-containers", []string{}, "Targeted containers")
 	rootCmd.PersistentFlags().StringVar(&targetPodIP, "target-pod-ip", "", "Pod IP of targeted pod")
@@ -115,6 +117,13 @@ func init() {
 }
 
 func main() {
+	// the CPU stress processes started by the CPU pressure injector don't go through the injector initialization
+	if len(os.Args) > 1 && os.Args[1] == injector.CPUStressCommand {
+		cpuStress(os.Args[2:])
+
+		return
+	}
+
 	// handle metrics sink client close on exit
 	defer func() {
 		log.Infow("closing metrics sink client before exiting", "sink", ms.GetSinkName())
@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package main
+
+import (
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/injector"
+	"github.com/spf13/cobra"
+)
+
+var memoryPressureCmd = &cobra.Command{
+	Use:   "memory-pressure",
+	Short: "Memory pressure subcommands",
+	Run:   injectAndWait,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		target, _ := cmd.Flags().GetString("target")
+		rampDuration, _ := cmd.Flags().GetDuration("ramp-duration")
+
+		// prepare spec
+		spec := v1beta1.MemoryPressureSpec{
+			Target:       target,
+			RampDuration: v1beta1.DisruptionDuration(rampDuration.String()),
+		}
+
+		if err := spec.Validate(); err != nil {
+			log.Fatalw("invalid memory pressure arguments", "error", err)
+		}
+
+		// create injector
+		for _, config := range configs {
+			injectors = append(injectors, injector.NewMemoryPressureInjector(spec, injector.MemoryPressureInjectorConfig{Config: config}))
+		}
+	},
+}
+
+func init() {
+	memoryPressureCmd.Flags().String("target", "", "Target memory usage, either an absolute size (512Mi) or a percentage of the memory limit (80%)")
+	memoryPressureCmd.Flags().Duration("ramp-duration", 0, "Duration to reach the target memory usage (defaults to an immediate allocation)")
+
+	_ = cobra.MarkFlagRequired(memoryPressureCmd.Flags(), "target")
+}
@@ -6,8 +6,11 @@
 package main
 
 import (
+	"encoding/json"
+
 	"github.com/DataDog/chaos-controller/api/v1beta1"
 	"github.com/DataDog/chaos-controller/injector"
+	"github.com/DataDog/chaos-controller/network"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +28,21 @@ var networkDisruptionCmd = &cobra.Command{
 		delay, _ := cmd.Flags().GetUint("delay")
 		delayJitter, _ := cmd.Flags().GetUint("delay-jitter")
 		bandwidthLimit, _ := cmd.Flags().GetInt("bandwidth-limit")
+		dropCorrelation, _ := cmd.Flags().GetInt("drop-correlation")
+		duplicateCorrelation, _ := cmd.Flags().GetInt("duplicate-correlation")
+		corruptCorrelation, _ := cmd.Flags().GetInt("corrupt-correlation")
+		delayCorrelation, _ := cmd.Flags().GetInt("delay-correlation")
+		delayDistribution, _ := cmd.Flags().GetString("delay-distribution")
+		rawLossModel, _ := cmd.Flags().GetString("loss-model")
+		reorder, _ := cmd.Flags().GetInt("reorder")
+		reorderCorrelation, _ := cmd.Flags().GetInt("reorder-correlation")
+		reorderGap, _ := cmd.Flags().GetUint("reorder-gap")
+		rawSlot, _ := cmd.Flags().GetString("slot")
+		rawProfiles, _ := cmd.Flags().GetStringArray("profiles")
+		ingressShaping, _ := cmd.Flags().GetBool("ingress-shaping")
+		rawPartition, _ := cmd.Flags().GetString("partition")
+		reject, _ := cmd.Flags().GetString("reject")
+		trafficControllerDriver, _ := cmd.Flags().GetString("traffic-controller")
 
 		// prepare injectors
 		for i, config := range configs {
@@ -50,21 +68,87 @@ var networkDisruptionCmd = &cobra.Command{
 					log.Fatalw("error parsing services", "error", err)
 				}
 
+				parsedProfiles := []v1beta1.NetworkDisruptionProfileSpec{}
+
+				for _, rawProfile := range rawProfiles {
+					var profile v1beta1.NetworkDisruptionProfileSpec
+
+					if err := json.Unmarshal([]byte(rawProfile), &profile); err != nil {
+						log.Fatalw("error parsing profiles", "error", err, "profile", rawProfile)
+					}
+
+					parsedProfiles = append(parsedProfiles, profile)
+				}
+
+				var lossModel *v1beta1.NetworkDisruptionLossModelSpec
+
+				if rawLossModel != "" {
+					lossModel = &v1beta1.NetworkDisruptionLossModelSpec{}
+
+					if err := json.Unmarshal([]byte(rawLossModel), lossModel); err != nil {
+						log.Fatalw("error parsing loss model", "error", err, "lossModel", rawLossModel)
+					}
+				}
+
+				var slot *v1beta1.NetworkDisruptionSlotSpec
+
+				if rawSlot != "" {
+					slot = &v1beta1.NetworkDisruptionSlotSpec{}
+
+					if err := json.Unmarshal([]byte(rawSlot), slot); err != nil {
+						log.Fatalw("error parsing slot", "error", err, "slot", rawSlot)
+					}
+				}
+
+				var partition *v1beta1.NetworkDisruptionPartitionSpec
+
+				if rawPartition != "" {
+					partition = &v1beta1.NetworkDisruptionPartitionSpec{}
+
+					if err := json.Unmarshal([]byte(rawPartition), partition); err != nil {
+						log.Fatalw("error parsing partition", "error", err, "partition", rawPartition)
+					}
+				}
+
 				spec = v1beta1.NetworkDisruptionSpec{
-					Hosts:          parsedHosts,
-					AllowedHosts:   parsedAllowedHosts,
-					Services:       parsedServices,
-					Drop:           drop,
-					Duplicate:      duplicate,
-					Corrupt:        corrupt,
-					Delay:          delay,
-					DelayJitter:    delayJitter,
-					BandwidthLimit: bandwidthLimit,
+					Hosts:                parsedHosts,
+					AllowedHosts:         parsedAllowedHosts,
+					Services:             parsedServices,
+					Drop:                 drop,
+					Duplicate:            duplicate,
+					Corrupt:              corrupt,
+					Delay:                delay,
+					DelayJitter:          delayJitter,
+					BandwidthLimit:       bandwidthLimit,
+					DropCorrelation:      dropCorrelation,
+					DuplicateCorrelation: duplicateCorrelation,
+					CorruptCorrelation:   corruptCorrelation,
+					DelayCorrelation:     delayCorrelation,
+					DelayDistribution:    delayDistribution,
+					LossModel:            lossModel,
+					Reorder:              reorder,
+					ReorderCorrelation:   reorderCorrelation,
+					ReorderGap:           reorderGap,
+					Slot:                 slot,
+					Profiles:             parsedProfiles,
+					IngressShaping:       ingressShaping,
+					Partition:            partition,
+					Reject:               reject,
 				}
 			}
 
+			trafficController, err := network.NewTrafficControllerWithDriver(network.TrafficControllerDriver(trafficControllerDriver), config.Log, config.DryRun)
+			if err != nil {
+				log.Fatalw("error initializing the traffic controller", "error", err)
+			}
+
 			// generate injector
-			injectors = append(injectors, injector.NewNetworkDisruptionInjector(spec, injector.NetworkDisruptionInjectorConfig{Config: config}))
+			inj, err := injector.NewNetworkDisruptionInjector(spec, injector.NetworkDisruptionInjectorConfig{Config: config, TrafficController: trafficController})
+			if err != nil {
+				log.Fatalw("error initializing the network disruption injector", "error", err)
+			}
+
+			injectors = append(injectors, inj)
 		}
 	},
 }
@@ -79,4 +163,20 @@ func init() {
 	networkDisruptionCmd.Flags().Uint("delay", 0, "Delay to add to the given container in ms")
 	networkDisruptionCmd.Flags().Uint("delay-jitter", 0, "Sub-command for Delay; adds specified jitter to delay time")
 	networkDisruptionCmd.Flags().Int("bandwidth-limit", 0, "Bandwidth limit in bytes")
+	networkDisruptionCmd.Flags().Int("drop-correlation", 0, "Percentage of dependency of each drop decision on the previous one")
+	networkDisruptionCmd.Flags().Int("duplicate-correlation", 0, "Percentage of dependency of each duplicate decision on the previous one")
+	networkDisruptionCmd.Flags().Int("corrupt-correlation", 0, "Percentage of dependency of each corrupt decision on the previous one")
+	networkDisruptionCmd.Flags().Int("delay-correlation", 0, "Percentage of dependency of each delay on the previous one")
+	networkDisruptionCmd.Flags().String("delay-distribution", "", "Distribution of the jittered delays (normal, pareto or paretonormal)")
+	networkDisruptionCmd.Flags().String("loss-model", "", "JSON encoded loss model replacing the drop percentage") // `{"gilbertElliott":{"p":1,"r":25,"badStateLoss":100}}`
+	networkDisruptionCmd.Flags().Int("reorder", 0, "Percentage of packets sent right away instead of being delayed")
+	networkDisruptionCmd.Flags().Int("reorder-correlation", 0, "Percentage of dependency of each reorder decision on the previous one")
+	networkDisruptionCmd.Flags().Uint("reorder-gap", 0, "Reorder one packet out of the given number of packets only")
+	networkDisruptionCmd.Flags().String("slot", "", "JSON encoded slots packets are sent in") // `{"minDelay":800,"maxDelay":1600,"packets":32}`
+	// JSON encoded profiles contain commas so they must be passed as a StringArray, a StringSlice would split them
+	networkDisruptionCmd.Flags().StringArray("profiles", []string{}, "List of JSON encoded profiles applying their own disruptions to their own hosts and services") // `{"hosts":[{"host":"10.0.0.0/8","port":443}],"delay":200}`
+	networkDisruptionCmd.Flags().Bool("ingress-shaping", false, "Apply the disruptions to the incoming traffic as well by redirecting it to an IFB device")
+	networkDisruptionCmd.Flags().String("partition", "", "JSON encoded partition dropping all the packets exchanged with the selected peer pods") // `{"selector":{"app":"quorum-b"},"namespace":"default"}`
+	networkDisruptionCmd.Flags().String("reject", "", "Reject the packets going to the hosts with a TCP reset (tcp-reset) or an ICMP error (icmp-port-unreachable)")
+	networkDisruptionCmd.Flags().String("traffic-controller", string(network.TrafficControllerDriverTc), "Implementation used to add the qdiscs and filters (tc, which executes the tc command, or netlink)")
 }
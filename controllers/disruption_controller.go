@@ -11,9 +11,11 @@ import (
 	"math"
 	"math/rand"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/DataDog/chaos-controller/abortcondition"
 	chaosapi "github.com/DataDog/chaos-controller/api"
 	"github.com/DataDog/chaos-controller/metrics"
 	"github.com/DataDog/chaos-controller/safemode"
@@ -47,27 +49,29 @@ import (
 // DisruptionReconciler reconciles a Disruption object
 type DisruptionReconciler struct {
 	client.Client
-	BaseLog                               *zap.SugaredLogger
-	Scheme                                *runtime.Scheme
-	Recorder                              record.EventRecorder
-	MetricsSink                           metrics.Sink
-	TargetSelector                        targetselector.TargetSelector
-	InjectorAnnotations                   map[string]string
-	InjectorLabels                        map[string]string
-	InjectorServiceAccount                string
-	InjectorImage                         string
-	ImagePullSecrets                      string
-	log                                   *zap.SugaredLogger
-	ChaosNamespace                        string
-	InjectorDNSDisruptionDNSServer        string
-	InjectorDNSDisruptionKubeDNS          string
-	InjectorNetworkDisruptionAllowedHosts []string
-	SafetyNets                            []safemode.Safemode
-	ExpiredDisruptionGCDelay              *time.Duration
-	CacheContextStore                     map[string]CtxTuple
-	Controller                            controller.Controller
-	Reader                                client.Reader // Use the k8s API without the cache
-	EnableObserver                        bool          // Enable Observer on targets update with dynamic targeting
+	BaseLog                                    *zap.SugaredLogger
+	Scheme                                     *runtime.Scheme
+	Recorder                                   record.EventRecorder
+	MetricsSink                                metrics.Sink
+	TargetSelector                             targetselector.TargetSelector
+	AbortConditionEvaluator                    abortcondition.Evaluator
+	InjectorAnnotations                        map[string]string
+	InjectorLabels                             map[string]string
+	InjectorServiceAccount                     string
+	InjectorImage                              string
+	ImagePullSecrets                           string
+	log                                        *zap.SugaredLogger
+	ChaosNamespace                             string
+	InjectorDNSDisruptionDNSServer             string
+	InjectorDNSDisruptionKubeDNS               string
+	InjectorNetworkDisruptionAllowedHosts      []string
+	InjectorNetworkDisruptionTrafficController string
+	ExpiredDisruptionGCDelay                   *time.Duration
+	CacheContextStore                          map[string]CtxTuple
+	Controller                                 controller.Controller
+	Reader                                     client.Reader // Use the k8s API without the cache
+	EnableObserver                             bool          // Enable Observer on targets update with dynamic targeting
+	RespectPodDisruptionBudgets                bool          // Cap pod targets to matching pod disruption budgets unless specified otherwise in the disruption
 }
 
 type CtxTuple struct {
@@ -84,6 +88,7 @@ type CtxTuple struct {
 //+kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=list;watch
 //+kubebuilder:rbac:groups=core,resources=services,verbs=list;watch
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=list;watch
 
 func (r *DisruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	instance := &chaosv1beta1.Disruption{}
@@ -192,19 +197,6 @@ func (r *DisruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return ctrl.Result{Requeue: false}, err
 		}
 
-		// initialize all safety nets for future use
-		if instance.Spec.Unsafemode == nil || !instance.Spec.Unsafemode.DisableAll {
-			// initialize all relevant safety nets for the first time
-			if len(r.SafetyNets) == 0 {
-				r.SafetyNets = []safemode.Safemode{}
-				r.SafetyNets = safemode.AddAllSafemodeObjects(*instance, r.Client)
-			} else {
-				// it is possible for a disruption to be restarted with new parameters, therefore safety nets need to be reinitialized to catch that case
-				// so that we are not using values from older versions of a disruption for safety nets
-				safemode.Reinit(r.SafetyNets, *instance, r.Client)
-			}
-		}
-
 		// the injection is being created or modified, apply needed actions
 		controllerutil.AddFinalizer(instance, chaostypes.DisruptionFinalizer)
 		if err := r.Update(context.Background(), instance); err != nil {
@@ -260,6 +252,15 @@ func (r *DisruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return ctrl.Result{}, fmt.Errorf("error selecting targets: %w", err)
 		}
 
+		// delete the disruption if a safety net is caught on the selected targets, before or during the injection
+		if caught, err := r.handleSafetyNets(instance); err != nil {
+			r.log.Errorw("error evaluating safety nets", "error", err)
+
+			return ctrl.Result{}, fmt.Errorf("error evaluating safety nets: %w", err)
+		} else if caught {
+			return ctrl.Result{}, nil
+		}
+
 		// start injections
 		if err := r.startInjection(instance); err != nil {
 			r.log.Errorw("error injecting the disruption", "error", err)
@@ -267,6 +268,25 @@ func (r *DisruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return ctrl.Result{}, fmt.Errorf("error injecting the disruption: %w", err)
 		}
 
+		// delete the disruption if the system under test left its steady state
+		if instance.Spec.AbortConditions != nil {
+			violation, err := r.AbortConditionEvaluator.Evaluate(r.Client, instance)
+			if err != nil {
+				r.log.Errorw("error evaluating abort conditions", "error", err)
+			} else if violation != "" {
+				r.log.Infow("an abort condition has been violated, the disruption will now be deleted", "violation", violation)
+				r.recordEventOnDisruption(instance, chaosv1beta1.EventDisruptionAborted, violation)
+
+				if err := r.Client.Delete(context.Background(), instance); err != nil {
+					r.log.Errorw("error deleting disruption after an abort condition has been violated", "error", err)
+
+					return ctrl.Result{}, fmt.Errorf("error deleting aborted disruption: %w", err)
+				}
+
+				return ctrl.Result{}, nil
+			}
+		}
+
 		// send injection duration metric representing the time it took to fully inject the disruption until its creation
 		r.handleMetricSinkError(r.MetricsSink.MetricInjectDuration(time.Since(instance.ObjectMeta.CreationTimestamp.Time), []string{"name:" + instance.Name, "namespace:" + instance.Namespace}))
 
@@ -293,6 +313,11 @@ func (r *DisruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 		requeueDelay := calculateRemainingDuration(*instance)
 
+		// requeue sooner to evaluate abort conditions again
+		if instance.Spec.AbortConditions != nil && instance.Spec.AbortConditions.GetInterval() < requeueDelay {
+			requeueDelay = instance.Spec.AbortConditions.GetInterval()
+		}
+
 		r.log.Infow("requeuing disruption to check for its expiration", "requeueDelay", requeueDelay.String())
 
 		return ctrl.Result{
@@ -745,6 +770,36 @@ func (r *DisruptionReconciler) handleChaosPodTermination(instance *chaosv1beta1.
 	}
 }
 
+// handleSafetyNets evaluates the safety nets related to the given disruption against its targets
+// if a safety net is caught, an event is sent for each caught safety net and the disruption is deleted
+func (r *DisruptionReconciler) handleSafetyNets(instance *chaosv1beta1.Disruption) (bool, error) {
+	if instance.Spec.Unsafemode != nil && instance.Spec.Unsafemode.DisableAll {
+		return false, nil
+	}
+
+	safetyNets := safemode.AddAllSafemodeObjects(*instance, r.Client)
+
+	responses, err := safemode.EvaluateAll(safetyNets, instance.Status.Targets)
+	if err != nil {
+		return false, err
+	}
+
+	if len(responses) == 0 {
+		return false, nil
+	}
+
+	for _, response := range responses {
+		r.log.Infow("a safety net has been caught, the disruption will now be deleted", "response", response)
+		r.recordEventOnDisruption(instance, chaosv1beta1.EventDisruptionSafetyNetCaught, response)
+	}
+
+	if err := r.Client.Delete(context.Background(), instance); err != nil {
+		return true, fmt.Errorf("error deleting disruption after a safety net has been caught: %w", err)
+	}
+
+	return true, nil
+}
+
 // selectTargets will select min(count, all matching targets) random targets (pods or nodes depending on the disruption level)
 // from the targets matching the instance label selector
 // targets will only be selected once per instance
@@ -806,6 +861,23 @@ func (r *DisruptionReconciler) selectTargets(instance *chaosv1beta1.Disruption)
 	dTargetsCount := targetsCount
 
 	if cTargetsCount < dTargetsCount {
+		// only pick targets which won't exceed the allowed disruptions of matching pod disruption budgets
+		if r.respectPodDisruptionBudgets(instance) {
+			allowedTargets, err := targetselector.GetTargetsWithinDisruptionBudgets(r.Client, instance.Namespace, instance.Status.Targets, eligibleTargets, dTargetsCount-cTargetsCount)
+			if err != nil {
+				return fmt.Errorf("error getting targets within pod disruption budgets: %w", err)
+			}
+
+			if cappedTargetsCount := dTargetsCount - cTargetsCount - len(allowedTargets); cappedTargetsCount > 0 {
+				r.log.Infow("capping targets to respect pod disruption budgets", "cappedTargetsCount", cappedTargetsCount)
+				r.recordEventOnDisruption(instance, chaosv1beta1.EventDisruptionTargetsCappedByPDB, strconv.Itoa(cappedTargetsCount))
+
+				targetsCount -= cappedTargetsCount
+			}
+
+			eligibleTargets = allowedTargets
+		}
+
 		// not enough targets: pick more targets from eligibleTargets
 		instance.Status.AddTargets(dTargetsCount-cTargetsCount, eligibleTargets)
 	} else if cTargetsCount > dTargetsCount {
@@ -821,6 +893,20 @@ func (r *DisruptionReconciler) selectTargets(instance *chaosv1beta1.Disruption)
 	return r.Status().Update(context.Background(), instance)
 }
 
+// respectPodDisruptionBudgets returns true if the given disruption targets must be capped to matching pod disruption budgets
+// the disruption spec takes precedence over the controller configuration, node level disruptions are never capped
+func (r *DisruptionReconciler) respectPodDisruptionBudgets(instance *chaosv1beta1.Disruption) bool {
+	if instance.Spec.Level == chaostypes.DisruptionLevelNode {
+		return false
+	}
+
+	if instance.Spec.RespectPodDisruptionBudgets != nil {
+		return *instance.Spec.RespectPodDisruptionBudgets
+	}
+
+	return r.RespectPodDisruptionBudgets
+}
+
 // getMatchingTargets fetches all existing target fitting the disruption's selector
 func (r *DisruptionReconciler) getSelectorMatchingTargets(instance *chaosv1beta1.Disruption) ([]string, int, error) {
 	healthyMatchingTargets := []string{}
@@ -1031,6 +1117,14 @@ func (r *DisruptionReconciler) generatePod(instance *chaosv1beta1.Disruption, ta
 							},
 						},
 					},
+					{
+						Name: env.InjectorChaosPodIPs,
+						ValueFrom: &corev1.EnvVarSource{
+							FieldRef: &corev1.ObjectFieldSelector{
+								FieldPath: "status.podIPs",
+							},
+						},
+					},
 					{
 						Name: env.InjectorPodName,
 						ValueFrom: &corev1.EnvVarSource{
@@ -1249,6 +1343,7 @@ func (r *DisruptionReconciler) generateChaosPods(instance *chaosv1beta1.Disrupti
 			PulseDormantDuration: pulseDormantDuration,
 			MetricsSink:          r.MetricsSink.GetSinkName(),
 			AllowedHosts:         r.InjectorNetworkDisruptionAllowedHosts,
+			TrafficController:    r.InjectorNetworkDisruptionTrafficController,
 			DNSServer:            r.InjectorDNSDisruptionDNSServer,
 			KubeDNS:              r.InjectorDNSDisruptionKubeDNS,
 			ChaosNamespace:       r.ChaosNamespace,
@@ -0,0 +1,236 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DisruptionCronReconciler reconciles a DisruptionCron object
+type DisruptionCronReconciler struct {
+	client.Client
+	BaseLog    *zap.SugaredLogger
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	DeleteOnly bool // Do not create any new disruption when enabled
+}
+
+//+kubebuilder:rbac:groups=chaos.datadoghq.com,resources=disruptioncrons,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=chaos.datadoghq.com,resources=disruptioncrons/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=chaos.datadoghq.com,resources=disruptioncrons/finalizers,verbs=update
+
+func (r *DisruptionCronReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.BaseLog.With("disruptionCronName", req.Name, "disruptionCronNamespace", req.Namespace)
+	instance := &chaosv1beta1.DisruptionCron{}
+
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		// created disruptions are garbage collected through their owner reference
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// retrieve disruptions created by the cron, from the oldest to the newest
+	disruptions, err := r.getChildDisruptions(ctx, instance)
+	if err != nil {
+		log.Errorw("error listing disruptions created by the disruption cron", "error", err)
+
+		return ctrl.Result{}, err
+	}
+
+	ongoing := []chaosv1beta1.Disruption{}
+	finished := []chaosv1beta1.Disruption{}
+
+	for _, disruption := range disruptions {
+		if isDisruptionFinished(disruption) {
+			finished = append(finished, disruption)
+		} else {
+			ongoing = append(ongoing, disruption)
+		}
+	}
+
+	// update the status with the last created disruption injection status
+	if len(disruptions) > 0 {
+		last := disruptions[len(disruptions)-1]
+		instance.Status.LastDisruptionName = last.Name
+		instance.Status.LastInjectionStatus = last.Status.InjectionStatus
+	}
+
+	// delete the oldest finished disruptions exceeding the history limit
+	if historyLimit := instance.Spec.GetHistoryLimit(); len(finished) > historyLimit {
+		for _, disruption := range finished[:len(finished)-historyLimit] {
+			if !disruption.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			log.Infow("deleting finished disruption exceeding the history limit", "disruptionName", disruption.Name)
+
+			if err := r.Delete(ctx, disruption.DeepCopy()); client.IgnoreNotFound(err) != nil {
+				log.Errorw("error deleting finished disruption", "disruptionName", disruption.Name, "error", err)
+			}
+		}
+	}
+
+	// compute the missed schedule time since the last one (or since the cron creation)
+	now := time.Now()
+	earliest := instance.CreationTimestamp.Time
+
+	if instance.Status.LastScheduleTime != nil {
+		earliest = instance.Status.LastScheduleTime.Time
+	}
+
+	scheduledTime, nextScheduledTime, err := instance.Spec.GetScheduleTimes(earliest, now)
+	if err != nil {
+		// the schedule is validated by the admission webhook, retrying would not help
+		log.Errorw("error computing the disruption cron schedule", "error", err)
+
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{RequeueAfter: nextScheduledTime.Sub(now)}
+
+	if !scheduledTime.IsZero() {
+		log.Infow("handling scheduled run", "scheduledTime", scheduledTime)
+
+		if err := r.handleScheduledRun(ctx, instance, scheduledTime, ongoing); err != nil {
+			log.Errorw("error handling scheduled run", "scheduledTime", scheduledTime, "error", err)
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.Status().Update(ctx, instance); err != nil {
+		if errors.IsConflict(err) {
+			log.Warnw("error updating disruption cron status", "error", err)
+
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		log.Errorw("error updating disruption cron status", "error", err)
+
+		return ctrl.Result{}, err
+	}
+
+	log.Debugw("requeuing disruption cron for the next scheduled run", "nextScheduledTime", nextScheduledTime)
+
+	return result, nil
+}
+
+// handleScheduledRun creates a disruption for the given scheduled time according to the concurrency policy
+// and records the scheduled time in the disruption cron status (the instance has to be updated afterward)
+func (r *DisruptionCronReconciler) handleScheduledRun(ctx context.Context, instance *chaosv1beta1.DisruptionCron, scheduledTime time.Time, ongoing []chaosv1beta1.Disruption) error {
+	// delete-only mode, skip the run but record it so it is not triggered late once the mode is disabled
+	if r.DeleteOnly {
+		r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronSkipped].Type, chaosv1beta1.EventDisruptionCronSkipped, fmt.Sprintf(chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronSkipped].OnDisruptionTemplateMessage, scheduledTime, "the controller is in delete-only mode"))
+
+		instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+
+		return nil
+	}
+
+	if len(ongoing) > 0 {
+		switch instance.Spec.GetConcurrencyPolicy() {
+		case chaosv1beta1.DisruptionCronConcurrencyPolicyForbid:
+			r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronSkipped].Type, chaosv1beta1.EventDisruptionCronSkipped, fmt.Sprintf(chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronSkipped].OnDisruptionTemplateMessage, scheduledTime, "the previous disruption is still ongoing"))
+
+			instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+
+			return nil
+		case chaosv1beta1.DisruptionCronConcurrencyPolicyReplace:
+			for _, disruption := range ongoing {
+				if !disruption.DeletionTimestamp.IsZero() {
+					continue
+				}
+
+				if err := r.Delete(ctx, disruption.DeepCopy()); client.IgnoreNotFound(err) != nil {
+					return fmt.Errorf("error deleting ongoing disruption %s: %w", disruption.Name, err)
+				}
+			}
+		}
+	}
+
+	disruption := &chaosv1beta1.Disruption{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", instance.Name, scheduledTime.Unix()),
+			Namespace: instance.Namespace,
+			Labels: map[string]string{
+				chaostypes.DisruptionCronNameLabel: instance.Name,
+			},
+		},
+		Spec: *instance.Spec.Template.DeepCopy(),
+	}
+
+	if err := controllerutil.SetControllerReference(instance, disruption, r.Scheme); err != nil {
+		return fmt.Errorf("error setting disruption owner reference: %w", err)
+	}
+
+	// the disruption goes through the disruption admission webhook which can reject it (safemode, delete-only mode...),
+	// the run is recorded anyway so it is not retried over and over
+	if err := r.Create(ctx, disruption); err != nil && !errors.IsAlreadyExists(err) {
+		r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronCreationFailed].Type, chaosv1beta1.EventDisruptionCronCreationFailed, fmt.Sprintf(chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronCreationFailed].OnDisruptionTemplateMessage, disruption.Name, err))
+	} else {
+		r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronScheduled].Type, chaosv1beta1.EventDisruptionCronScheduled, fmt.Sprintf(chaosv1beta1.Events[chaosv1beta1.EventDisruptionCronScheduled].OnDisruptionTemplateMessage, disruption.Name))
+
+		instance.Status.LastDisruptionName = disruption.Name
+		instance.Status.LastInjectionStatus = disruption.Status.InjectionStatus
+	}
+
+	instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+
+	return nil
+}
+
+// getChildDisruptions returns the disruptions owned by the given disruption cron, sorted by creation time
+func (r *DisruptionCronReconciler) getChildDisruptions(ctx context.Context, instance *chaosv1beta1.DisruptionCron) ([]chaosv1beta1.Disruption, error) {
+	l := chaosv1beta1.DisruptionList{}
+
+	if err := r.List(ctx, &l, client.InNamespace(instance.Namespace), client.MatchingLabels{chaostypes.DisruptionCronNameLabel: instance.Name}); err != nil {
+		return nil, err
+	}
+
+	disruptions := []chaosv1beta1.Disruption{}
+
+	for _, disruption := range l.Items {
+		if metav1.IsControlledBy(&disruption, instance) {
+			disruptions = append(disruptions, disruption)
+		}
+	}
+
+	sort.SliceStable(disruptions, func(i, j int) bool {
+		return disruptions[i].CreationTimestamp.Before(&disruptions[j].CreationTimestamp)
+	})
+
+	return disruptions, nil
+}
+
+// isDisruptionFinished returns true if the given disruption is being deleted or has expired
+func isDisruptionFinished(disruption chaosv1beta1.Disruption) bool {
+	return !disruption.DeletionTimestamp.IsZero() || calculateRemainingDuration(disruption) <= 0
+}
+
+// SetupWithManager setups the current reconciler with the given manager
+func (r *DisruptionCronReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&chaosv1beta1.DisruptionCron{}).
+		Owns(&chaosv1beta1.Disruption{}).
+		Complete(r)
+}
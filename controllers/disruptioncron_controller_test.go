@@ -0,0 +1,302 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+)
+
+// newFakeClientScheme returns a scheme knowing both the kubernetes and the chaos resources, for fake clients
+func newFakeClientScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+
+	Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	Expect(chaosv1beta1.AddToScheme(s)).To(Succeed())
+
+	return s
+}
+
+// receivedEventReasons returns the reasons of the events recorded by the given fake recorder so far
+func receivedEventReasons(recorder *record.FakeRecorder) []string {
+	reasons := []string{}
+
+	for {
+		select {
+		case event := <-recorder.Events:
+			var eventType, reason string
+
+			_, _ = fmt.Sscanf(event, "%s %s", &eventType, &reason)
+			reasons = append(reasons, reason)
+		default:
+			return reasons
+		}
+	}
+}
+
+var _ = Describe("DisruptionCron Controller", func() {
+	var (
+		cron        *chaosv1beta1.DisruptionCron
+		objects     []client.Object
+		fakeClient  client.Client
+		recorder    *record.FakeRecorder
+		reconciler  *DisruptionCronReconciler
+		deleteOnly  bool
+		result      ctrl.Result
+		err         error
+		historySize int
+	)
+
+	cronKey := types.NamespacedName{Name: "cron", Namespace: "default"}
+
+	// newChildDisruption returns a disruption created by the disruption cron at the given time
+	newChildDisruption := func(name string, created time.Time) *chaosv1beta1.Disruption {
+		isController := true
+
+		return &chaosv1beta1.Disruption{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         cronKey.Namespace,
+				CreationTimestamp: metav1.Time{Time: created},
+				Labels:            map[string]string{chaostypes.DisruptionCronNameLabel: cronKey.Name},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: chaosv1beta1.GroupVersion.String(),
+						Kind:       "DisruptionCron",
+						Name:       cronKey.Name,
+						UID:        cron.UID,
+						Controller: &isController,
+					},
+				},
+			},
+			Spec: chaosv1beta1.DisruptionSpec{
+				Duration: "1h0m0s",
+			},
+		}
+	}
+
+	// listChildDisruptions returns the names of the disruptions created by the disruption cron
+	listChildDisruptions := func() []string {
+		l := chaosv1beta1.DisruptionList{}
+		Expect(fakeClient.List(context.Background(), &l, client.InNamespace(cronKey.Namespace), client.MatchingLabels{chaostypes.DisruptionCronNameLabel: cronKey.Name})).To(Succeed())
+
+		names := []string{}
+		for _, disruption := range l.Items {
+			names = append(names, disruption.Name)
+		}
+
+		return names
+	}
+
+	// getCron returns the disruption cron as stored by the fake client
+	getCron := func() *chaosv1beta1.DisruptionCron {
+		instance := &chaosv1beta1.DisruptionCron{}
+		Expect(fakeClient.Get(context.Background(), cronKey, instance)).To(Succeed())
+
+		return instance
+	}
+
+	// reconcile runs the reconcile loop once for the disruption cron
+	reconcile := func() {
+		result, err = reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: cronKey})
+	}
+
+	BeforeEach(func() {
+		count := intstr.FromInt(1)
+
+		// the cron was created a few minutes ago and runs every minute, so a run was missed
+		cron = &chaosv1beta1.DisruptionCron{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              cronKey.Name,
+				Namespace:         cronKey.Namespace,
+				UID:               "cron-uid",
+				CreationTimestamp: metav1.Time{Time: time.Now().Add(-3 * time.Minute)},
+			},
+			Spec: chaosv1beta1.DisruptionCronSpec{
+				Schedule: "* * * * *",
+				Template: chaosv1beta1.DisruptionSpec{
+					Count:    &count,
+					Selector: map[string]string{"foo": "bar"},
+					Duration: "1h0m0s",
+				},
+			},
+		}
+		objects = []client.Object{}
+		deleteOnly = false
+		historySize = 0
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(newFakeClientScheme()).WithObjects(append(objects, cron)...).Build()
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &DisruptionCronReconciler{
+			Client:     fakeClient,
+			BaseLog:    zap.NewNop().Sugar(),
+			Scheme:     fakeClient.Scheme(),
+			Recorder:   recorder,
+			DeleteOnly: deleteOnly,
+		}
+
+		reconcile()
+		historySize = len(listChildDisruptions())
+	})
+
+	It("should create a disruption named after the scheduled time", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		instance := getCron()
+		Expect(instance.Status.LastScheduleTime).ToNot(BeNil())
+
+		name := fmt.Sprintf("%s-%d", cronKey.Name, instance.Status.LastScheduleTime.Unix())
+		Expect(listChildDisruptions()).To(Equal([]string{name}))
+		Expect(instance.Status.LastDisruptionName).To(Equal(name))
+
+		disruption := &chaosv1beta1.Disruption{}
+		Expect(fakeClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: cronKey.Namespace}, disruption)).To(Succeed())
+		Expect(disruption.Spec).To(Equal(cron.Spec.Template))
+		Expect(metav1.IsControlledBy(disruption, instance)).To(BeTrue())
+		Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionCronScheduled}))
+	})
+
+	It("should requeue for the next scheduled run", func() {
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+	})
+
+	It("should not create the disruption of the same scheduled run twice", func() {
+		// the status update can be lost, the created disruption name is deterministic so it is not created again
+		instance := getCron()
+		instance.Status.LastScheduleTime = nil
+		Expect(fakeClient.Status().Update(context.Background(), instance)).To(Succeed())
+
+		reconcile()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listChildDisruptions()).To(HaveLen(historySize))
+	})
+
+	Context("with a schedule not triggered since the cron creation", func() {
+		BeforeEach(func() {
+			cron.Spec.Schedule = fmt.Sprintf("0 0 1 %d *", time.Now().Add(60*24*time.Hour).Month())
+		})
+
+		It("should not create any disruption", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listChildDisruptions()).To(BeEmpty())
+			Expect(getCron().Status.LastScheduleTime).To(BeNil())
+			Expect(result.RequeueAfter).To(BeNumerically(">", time.Hour))
+		})
+	})
+
+	Context("with an ongoing disruption", func() {
+		BeforeEach(func() {
+			objects = append(objects, newChildDisruption("cron-ongoing", time.Now().Add(-time.Minute)))
+		})
+
+		It("should skip the run with the default Forbid concurrency policy", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listChildDisruptions()).To(Equal([]string{"cron-ongoing"}))
+			Expect(getCron().Status.LastScheduleTime).ToNot(BeNil())
+			Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionCronSkipped}))
+		})
+
+		Context("with the Replace concurrency policy", func() {
+			BeforeEach(func() {
+				cron.Spec.ConcurrencyPolicy = chaosv1beta1.DisruptionCronConcurrencyPolicyReplace
+			})
+
+			It("should delete the ongoing disruption and create a new one", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				name := fmt.Sprintf("%s-%d", cronKey.Name, getCron().Status.LastScheduleTime.Unix())
+				Expect(listChildDisruptions()).To(Equal([]string{name}))
+				Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionCronScheduled}))
+			})
+		})
+	})
+
+	Context("with finished disruptions exceeding the history limit", func() {
+		BeforeEach(func() {
+			historyLimit := 1
+			cron.Spec.HistoryLimit = &historyLimit
+
+			// the disruptions last 1 hour
+			objects = append(objects,
+				newChildDisruption("cron-oldest", time.Now().Add(-4*time.Hour)),
+				newChildDisruption("cron-older", time.Now().Add(-3*time.Hour)),
+				newChildDisruption("cron-newest", time.Now().Add(-2*time.Hour)),
+			)
+		})
+
+		It("should only keep the newest finished disruptions", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			name := fmt.Sprintf("%s-%d", cronKey.Name, getCron().Status.LastScheduleTime.Unix())
+			Expect(listChildDisruptions()).To(ConsistOf("cron-newest", name))
+		})
+	})
+
+	Context("with a disruption not created by the cron", func() {
+		BeforeEach(func() {
+			disruption := newChildDisruption("cron-other", time.Now().Add(-time.Minute))
+			disruption.OwnerReferences = nil
+			objects = append(objects, disruption)
+		})
+
+		It("should ignore it", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listChildDisruptions()).To(HaveLen(2))
+			Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionCronScheduled}))
+		})
+	})
+
+	Context("in delete-only mode", func() {
+		BeforeEach(func() {
+			deleteOnly = true
+		})
+
+		It("should skip the run and record it", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listChildDisruptions()).To(BeEmpty())
+			Expect(getCron().Status.LastScheduleTime).ToNot(BeNil())
+			Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionCronSkipped}))
+		})
+
+		It("should not trigger the skipped run once the mode is disabled", func() {
+			reconciler.DeleteOnly = false
+
+			reconcile()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listChildDisruptions()).To(BeEmpty())
+		})
+	})
+
+	Context("with a deleted cron", func() {
+		It("should do nothing", func() {
+			Expect(fakeClient.Delete(context.Background(), getCron())).To(Succeed())
+
+			reconcile()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+	})
+})
@@ -0,0 +1,344 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// stepDisruptionNotFoundGracePeriod is the delay after which a running step whose disruption can't be found is considered over,
+// the disruption created by the step possibly not being readable yet right after its creation
+const stepDisruptionNotFoundGracePeriod = 30 * time.Second
+
+// DisruptionWorkflowReconciler reconciles a DisruptionWorkflow object
+type DisruptionWorkflowReconciler struct {
+	client.Client
+	BaseLog  *zap.SugaredLogger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Reader   client.Reader // Use the k8s API without the cache
+}
+
+//+kubebuilder:rbac:groups=chaos.datadoghq.com,resources=disruptionworkflows,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=chaos.datadoghq.com,resources=disruptionworkflows/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=chaos.datadoghq.com,resources=disruptionworkflows/finalizers,verbs=update
+
+func (r *DisruptionWorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.BaseLog.With("disruptionWorkflowName", req.Name, "disruptionWorkflowNamespace", req.Namespace)
+	instance := &chaosv1beta1.DisruptionWorkflow{}
+
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		// created disruptions are garbage collected through their owner reference
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// nothing left to do once the workflow is over
+	if instance.Status.Phase == chaosv1beta1.DisruptionWorkflowPhaseCompleted || instance.Status.Phase == chaosv1beta1.DisruptionWorkflowPhaseAborted {
+		return ctrl.Result{}, nil
+	}
+
+	// initialize the status on the first reconcile loop
+	if instance.Status.Phase == "" {
+		now := metav1.Now()
+		instance.Status.Phase = chaosv1beta1.DisruptionWorkflowPhaseRunning
+		instance.Status.StartTime = &now
+		instance.Status.Steps = make([]chaosv1beta1.DisruptionWorkflowStepStatus, len(instance.Spec.Steps))
+
+		for i, step := range instance.Spec.Steps {
+			instance.Status.Steps[i] = chaosv1beta1.DisruptionWorkflowStepStatus{
+				Name:  step.Name,
+				Phase: chaosv1beta1.DisruptionWorkflowStepPhasePending,
+			}
+		}
+	}
+
+	disruptions, err := r.getChildDisruptions(ctx, instance)
+	if err != nil {
+		log.Errorw("error listing disruptions created by the disruption workflow", "error", err)
+
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter := r.runSteps(ctx, log, instance, disruptions)
+
+	if err := r.Status().Update(ctx, instance); err != nil {
+		if errors.IsConflict(err) {
+			log.Warnw("error updating disruption workflow status", "error", err)
+
+			return ctrl.Result{Requeue: true}, nil
+		}
+
+		log.Errorw("error updating disruption workflow status", "error", err)
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// runSteps starts and ends the workflow steps depending on the current time and on their disruptions,
+// updates the instance status accordingly (the instance has to be updated afterward) and returns the delay before the next step change
+func (r *DisruptionWorkflowReconciler) runSteps(ctx context.Context, log *zap.SugaredLogger, instance *chaosv1beta1.DisruptionWorkflow, disruptions map[string]chaosv1beta1.Disruption) time.Duration {
+	now := time.Now()
+	groupStart := instance.Status.StartTime.Time
+	requeueAfter := time.Duration(0)
+
+	for _, group := range instance.Spec.GetStepGroups() {
+		groupCompleted := true
+		groupEnd := groupStart
+
+		for _, i := range group {
+			step := instance.Spec.Steps[i]
+			status := &instance.Status.Steps[i]
+
+			switch status.Phase {
+			case chaosv1beta1.DisruptionWorkflowStepPhasePending:
+				startTime := groupStart.Add(step.StartAfter.Duration())
+				if now.Before(startTime) {
+					requeueAfter = minRequeueDelay(requeueAfter, startTime.Sub(now))
+
+					break
+				}
+
+				log.Infow("starting workflow step", "step", step.Name)
+
+				disruption, err := r.startStep(ctx, instance, i)
+				if err != nil {
+					log.Errorw("error starting workflow step", "step", step.Name, "error", err)
+					r.abort(ctx, log, instance, i, fmt.Sprintf("error creating the step disruption: %s", err))
+
+					return 0
+				}
+
+				requeueAfter = minRequeueDelay(requeueAfter, calculateRemainingDuration(*disruption))
+			case chaosv1beta1.DisruptionWorkflowStepPhaseRunning:
+				disruption, found := disruptions[status.DisruptionName]
+
+				// the cache may not contain the disruption created by a previous reconcile loop yet
+				if !found {
+					var err error
+
+					if found, err = r.getStepDisruption(ctx, instance, status.DisruptionName, &disruption); err != nil {
+						log.Errorw("error getting step disruption", "step", step.Name, "disruptionName", status.DisruptionName, "error", err)
+						requeueAfter = minRequeueDelay(requeueAfter, time.Second)
+
+						break
+					}
+
+					if !found && time.Since(status.StartTime.Time) < stepDisruptionNotFoundGracePeriod {
+						log.Infow("step disruption not found yet, waiting for it", "step", step.Name, "disruptionName", status.DisruptionName)
+						requeueAfter = minRequeueDelay(requeueAfter, time.Second)
+
+						break
+					}
+				}
+
+				// keep track of the injection status until the disruption expires
+				if found && disruption.Status.InjectionStatus != "" && disruption.Status.InjectionStatus != chaostypes.DisruptionInjectionStatusPreviouslyInjected {
+					status.InjectionStatus = disruption.Status.InjectionStatus
+				}
+
+				if found && disruption.DeletionTimestamp.IsZero() && calculateRemainingDuration(disruption) > 0 {
+					requeueAfter = minRequeueDelay(requeueAfter, calculateRemainingDuration(disruption))
+
+					break
+				}
+
+				// the step disruption has expired (or has been deleted), the step is over
+				log.Infow("ending workflow step", "step", step.Name, "injectionStatus", status.InjectionStatus)
+
+				if found {
+					r.deleteStepDisruption(ctx, log, instance, status.DisruptionName)
+				}
+
+				if status.InjectionStatus == "" || status.InjectionStatus == chaostypes.DisruptionInjectionStatusNotInjected {
+					r.abort(ctx, log, instance, i, "the step disruption has never been injected")
+
+					return 0
+				}
+
+				status.Phase = chaosv1beta1.DisruptionWorkflowStepPhaseCompleted
+				status.EndTime = &metav1.Time{Time: now}
+			}
+
+			if status.Phase != chaosv1beta1.DisruptionWorkflowStepPhaseCompleted {
+				groupCompleted = false
+			} else if status.EndTime.After(groupEnd) {
+				groupEnd = status.EndTime.Time
+			}
+		}
+
+		// the next group can only start once all the steps of the current one are completed
+		if !groupCompleted {
+			return requeueAfter
+		}
+
+		groupStart = groupEnd
+	}
+
+	log.Infow("all workflow steps are completed")
+
+	instance.Status.Phase = chaosv1beta1.DisruptionWorkflowPhaseCompleted
+	r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionWorkflowCompleted].Type, chaosv1beta1.EventDisruptionWorkflowCompleted, chaosv1beta1.Events[chaosv1beta1.EventDisruptionWorkflowCompleted].OnDisruptionTemplateMessage)
+
+	return 0
+}
+
+// startStep creates the disruption of the given step and marks the step as running
+func (r *DisruptionWorkflowReconciler) startStep(ctx context.Context, instance *chaosv1beta1.DisruptionWorkflow, stepIndex int) (*chaosv1beta1.Disruption, error) {
+	step := instance.Spec.Steps[stepIndex]
+	status := &instance.Status.Steps[stepIndex]
+	disruption := &chaosv1beta1.Disruption{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", instance.Name, step.Name),
+			Namespace: instance.Namespace,
+			Labels: map[string]string{
+				chaostypes.DisruptionWorkflowNameLabel: instance.Name,
+			},
+		},
+		Spec: step.GetDisruptionSpec(),
+	}
+
+	if err := controllerutil.SetControllerReference(instance, disruption, r.Scheme); err != nil {
+		return nil, fmt.Errorf("error setting disruption owner reference: %w", err)
+	}
+
+	// the disruption goes through the disruption admission webhook which can reject it (safemode, delete-only mode...)
+	if err := r.Create(ctx, disruption); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+
+		// the disruption may have been created by a previous reconcile loop which failed to update the status
+		if err := r.Get(ctx, types.NamespacedName{Namespace: disruption.Namespace, Name: disruption.Name}, disruption); err != nil {
+			return nil, err
+		}
+
+		if !metav1.IsControlledBy(disruption, instance) {
+			return nil, fmt.Errorf("disruption %s already exists and is not owned by the workflow", disruption.Name)
+		}
+	}
+
+	status.Phase = chaosv1beta1.DisruptionWorkflowStepPhaseRunning
+	status.DisruptionName = disruption.Name
+	status.StartTime = &metav1.Time{Time: time.Now()}
+
+	r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionWorkflowStepStarted].Type, chaosv1beta1.EventDisruptionWorkflowStepStarted, fmt.Sprintf(chaosv1beta1.Events[chaosv1beta1.EventDisruptionWorkflowStepStarted].OnDisruptionTemplateMessage, step.Name, disruption.Name))
+
+	return disruption, nil
+}
+
+// abort marks the given step as failed, stops the running steps and skips the pending ones
+func (r *DisruptionWorkflowReconciler) abort(ctx context.Context, log *zap.SugaredLogger, instance *chaosv1beta1.DisruptionWorkflow, failedStepIndex int, message string) {
+	now := metav1.Now()
+	failedStep := &instance.Status.Steps[failedStepIndex]
+
+	log.Warnw("aborting workflow", "step", failedStep.Name, "reason", message)
+
+	failedStep.Phase = chaosv1beta1.DisruptionWorkflowStepPhaseFailed
+	failedStep.Message = message
+	failedStep.EndTime = &now
+
+	for i := range instance.Status.Steps {
+		status := &instance.Status.Steps[i]
+
+		switch status.Phase {
+		case chaosv1beta1.DisruptionWorkflowStepPhasePending:
+			status.Phase = chaosv1beta1.DisruptionWorkflowStepPhaseSkipped
+		case chaosv1beta1.DisruptionWorkflowStepPhaseRunning:
+			r.deleteStepDisruption(ctx, log, instance, status.DisruptionName)
+
+			status.Phase = chaosv1beta1.DisruptionWorkflowStepPhaseAborted
+			status.EndTime = &now
+		}
+	}
+
+	instance.Status.Phase = chaosv1beta1.DisruptionWorkflowPhaseAborted
+	r.Recorder.Event(instance, chaosv1beta1.Events[chaosv1beta1.EventDisruptionWorkflowAborted].Type, chaosv1beta1.EventDisruptionWorkflowAborted, fmt.Sprintf(chaosv1beta1.Events[chaosv1beta1.EventDisruptionWorkflowAborted].OnDisruptionTemplateMessage, failedStep.Name, message))
+}
+
+// deleteStepDisruption deletes the given step disruption, errors are only logged since the disruption
+// is deleted anyway along with the workflow
+func (r *DisruptionWorkflowReconciler) deleteStepDisruption(ctx context.Context, log *zap.SugaredLogger, instance *chaosv1beta1.DisruptionWorkflow, name string) {
+	disruption := &chaosv1beta1.Disruption{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+		},
+	}
+
+	if err := r.Delete(ctx, disruption); client.IgnoreNotFound(err) != nil {
+		log.Errorw("error deleting step disruption", "disruptionName", name, "error", err)
+	}
+}
+
+// getStepDisruption reads the given step disruption through the k8s API without the cache,
+// returning false if it does not exist or is not owned by the given disruption workflow
+func (r *DisruptionWorkflowReconciler) getStepDisruption(ctx context.Context, instance *chaosv1beta1.DisruptionWorkflow, name string, disruption *chaosv1beta1.Disruption) (bool, error) {
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: name}, disruption); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return metav1.IsControlledBy(disruption, instance), nil
+}
+
+// getChildDisruptions returns the disruptions owned by the given disruption workflow, indexed by name
+func (r *DisruptionWorkflowReconciler) getChildDisruptions(ctx context.Context, instance *chaosv1beta1.DisruptionWorkflow) (map[string]chaosv1beta1.Disruption, error) {
+	l := chaosv1beta1.DisruptionList{}
+
+	if err := r.List(ctx, &l, client.InNamespace(instance.Namespace), client.MatchingLabels{chaostypes.DisruptionWorkflowNameLabel: instance.Name}); err != nil {
+		return nil, err
+	}
+
+	disruptions := map[string]chaosv1beta1.Disruption{}
+
+	for _, disruption := range l.Items {
+		if metav1.IsControlledBy(&disruption, instance) {
+			disruptions[disruption.Name] = disruption
+		}
+	}
+
+	return disruptions, nil
+}
+
+// minRequeueDelay returns the smallest of the given delays, a zero current delay meaning no delay has been set yet
+func minRequeueDelay(current, delay time.Duration) time.Duration {
+	if current == 0 || delay < current {
+		return delay
+	}
+
+	return current
+}
+
+// SetupWithManager setups the current reconciler with the given manager
+func (r *DisruptionWorkflowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&chaosv1beta1.DisruptionWorkflow{}).
+		Owns(&chaosv1beta1.Disruption{}).
+		Complete(r)
+}
@@ -0,0 +1,421 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+)
+
+var _ = Describe("DisruptionWorkflow Controller", func() {
+	var (
+		workflow        *chaosv1beta1.DisruptionWorkflow
+		objects         []client.Object
+		uncachedObjects []client.Object
+		fakeClient      client.Client
+		recorder        *record.FakeRecorder
+		reconciler      *DisruptionWorkflowReconciler
+		result          ctrl.Result
+		err             error
+	)
+
+	workflowKey := types.NamespacedName{Name: "workflow", Namespace: "default"}
+
+	// newStep returns a workflow step creating a 1 hour long disruption
+	newStep := func(name string, parallel bool) chaosv1beta1.DisruptionWorkflowStep {
+		count := intstr.FromInt(1)
+
+		return chaosv1beta1.DisruptionWorkflowStep{
+			Name:     name,
+			Parallel: parallel,
+			Template: chaosv1beta1.DisruptionSpec{
+				Count:    &count,
+				Selector: map[string]string{"foo": "bar"},
+				Duration: "1h0m0s",
+			},
+		}
+	}
+
+	// newStepDisruption returns the disruption created by the given workflow step at the given time
+	newStepDisruption := func(stepName string, created time.Time, injectionStatus chaostypes.DisruptionInjectionStatus) *chaosv1beta1.Disruption {
+		isController := true
+
+		return &chaosv1beta1.Disruption{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              workflowKey.Name + "-" + stepName,
+				Namespace:         workflowKey.Namespace,
+				CreationTimestamp: metav1.Time{Time: created},
+				Labels:            map[string]string{chaostypes.DisruptionWorkflowNameLabel: workflowKey.Name},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: chaosv1beta1.GroupVersion.String(),
+						Kind:       "DisruptionWorkflow",
+						Name:       workflowKey.Name,
+						UID:        workflow.UID,
+						Controller: &isController,
+					},
+				},
+			},
+			Spec: chaosv1beta1.DisruptionSpec{
+				Duration: "1h0m0s",
+			},
+			Status: chaosv1beta1.DisruptionStatus{
+				InjectionStatus: injectionStatus,
+			},
+		}
+	}
+
+	// setRunning marks the workflow as started at the given time, the given steps being started at the given time as well
+	setRunning := func(started time.Time, runningSteps ...int) {
+		workflow.Status.Phase = chaosv1beta1.DisruptionWorkflowPhaseRunning
+		workflow.Status.StartTime = &metav1.Time{Time: started}
+		workflow.Status.Steps = []chaosv1beta1.DisruptionWorkflowStepStatus{}
+
+		for _, step := range workflow.Spec.Steps {
+			workflow.Status.Steps = append(workflow.Status.Steps, chaosv1beta1.DisruptionWorkflowStepStatus{
+				Name:  step.Name,
+				Phase: chaosv1beta1.DisruptionWorkflowStepPhasePending,
+			})
+		}
+
+		for _, i := range runningSteps {
+			workflow.Status.Steps[i].Phase = chaosv1beta1.DisruptionWorkflowStepPhaseRunning
+			workflow.Status.Steps[i].DisruptionName = workflowKey.Name + "-" + workflow.Spec.Steps[i].Name
+			workflow.Status.Steps[i].StartTime = &metav1.Time{Time: started}
+		}
+	}
+
+	// listStepDisruptions returns the names of the disruptions created by the workflow
+	listStepDisruptions := func() []string {
+		l := chaosv1beta1.DisruptionList{}
+		Expect(fakeClient.List(context.Background(), &l, client.InNamespace(workflowKey.Namespace), client.MatchingLabels{chaostypes.DisruptionWorkflowNameLabel: workflowKey.Name})).To(Succeed())
+
+		names := []string{}
+		for _, disruption := range l.Items {
+			names = append(names, disruption.Name)
+		}
+
+		return names
+	}
+
+	// getWorkflow returns the workflow as stored by the fake client
+	getWorkflow := func() *chaosv1beta1.DisruptionWorkflow {
+		instance := &chaosv1beta1.DisruptionWorkflow{}
+		Expect(fakeClient.Get(context.Background(), workflowKey, instance)).To(Succeed())
+
+		return instance
+	}
+
+	// stepPhases returns the phases of the workflow steps, in order
+	stepPhases := func() []chaosv1beta1.DisruptionWorkflowStepPhase {
+		phases := []chaosv1beta1.DisruptionWorkflowStepPhase{}
+		for _, status := range getWorkflow().Status.Steps {
+			phases = append(phases, status.Phase)
+		}
+
+		return phases
+	}
+
+	BeforeEach(func() {
+		workflow = &chaosv1beta1.DisruptionWorkflow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workflowKey.Name,
+				Namespace: workflowKey.Namespace,
+				UID:       "workflow-uid",
+			},
+			Spec: chaosv1beta1.DisruptionWorkflowSpec{
+				Steps: []chaosv1beta1.DisruptionWorkflowStep{
+					newStep("a", false),
+					newStep("b", false),
+				},
+			},
+		}
+		objects = []client.Object{}
+		uncachedObjects = []client.Object{}
+	})
+
+	JustBeforeEach(func() {
+		s := newFakeClientScheme()
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(append(objects, workflow)...).Build()
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &DisruptionWorkflowReconciler{
+			Client:   fakeClient,
+			BaseLog:  zap.NewNop().Sugar(),
+			Scheme:   s,
+			Recorder: recorder,
+			// the API can contain disruptions not in the cache yet
+			Reader: fake.NewClientBuilder().WithScheme(s).WithObjects(append(objects, uncachedObjects...)...).Build(),
+		}
+
+		result, err = reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: workflowKey})
+	})
+
+	Context("with serial steps", func() {
+		It("should only start the first step", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listStepDisruptions()).To(Equal([]string{"workflow-a"}))
+			Expect(getWorkflow().Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseRunning))
+			Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+				chaosv1beta1.DisruptionWorkflowStepPhaseRunning,
+				chaosv1beta1.DisruptionWorkflowStepPhasePending,
+			}))
+			Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionWorkflowStepStarted}))
+		})
+
+		Context("with a delayed first step", func() {
+			BeforeEach(func() {
+				workflow.Spec.Steps[0].StartAfter = "10m0s"
+			})
+
+			It("should wait for the step start delay", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(listStepDisruptions()).To(BeEmpty())
+				Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+					chaosv1beta1.DisruptionWorkflowStepPhasePending,
+					chaosv1beta1.DisruptionWorkflowStepPhasePending,
+				}))
+				Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Minute, time.Minute))
+			})
+		})
+
+		Context("with the first step disruption injected and expired", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-2*time.Hour), 0)
+				objects = append(objects, newStepDisruption("a", time.Now().Add(-2*time.Hour), chaostypes.DisruptionInjectionStatusInjected))
+			})
+
+			It("should complete the first step and start the second one", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(listStepDisruptions()).To(Equal([]string{"workflow-b"}))
+				Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+					chaosv1beta1.DisruptionWorkflowStepPhaseCompleted,
+					chaosv1beta1.DisruptionWorkflowStepPhaseRunning,
+				}))
+				Expect(getWorkflow().Status.Steps[0].InjectionStatus).To(Equal(chaostypes.DisruptionInjectionStatusInjected))
+			})
+		})
+	})
+
+	Context("with parallel steps", func() {
+		BeforeEach(func() {
+			workflow.Spec.Steps = append(workflow.Spec.Steps, newStep("c", true))
+		})
+
+		It("should start all the steps of the first group", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listStepDisruptions()).To(ConsistOf("workflow-a"))
+
+			// steps b and c belong to the same group
+			Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+				chaosv1beta1.DisruptionWorkflowStepPhaseRunning,
+				chaosv1beta1.DisruptionWorkflowStepPhasePending,
+				chaosv1beta1.DisruptionWorkflowStepPhasePending,
+			}))
+		})
+
+		Context("with the first group completed", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-2*time.Hour), 0)
+				objects = append(objects, newStepDisruption("a", time.Now().Add(-2*time.Hour), chaostypes.DisruptionInjectionStatusInjected))
+			})
+
+			It("should start the parallel steps together", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(listStepDisruptions()).To(ConsistOf("workflow-b", "workflow-c"))
+				Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+					chaosv1beta1.DisruptionWorkflowStepPhaseCompleted,
+					chaosv1beta1.DisruptionWorkflowStepPhaseRunning,
+					chaosv1beta1.DisruptionWorkflowStepPhaseRunning,
+				}))
+			})
+		})
+
+		Context("with only one of the parallel steps expired", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-2*time.Hour), 1, 2)
+				workflow.Status.Steps[0].Phase = chaosv1beta1.DisruptionWorkflowStepPhaseCompleted
+				workflow.Status.Steps[0].EndTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				objects = append(objects,
+					newStepDisruption("b", time.Now().Add(-2*time.Hour), chaostypes.DisruptionInjectionStatusInjected),
+					newStepDisruption("c", time.Now().Add(-30*time.Minute), chaostypes.DisruptionInjectionStatusInjected),
+				)
+			})
+
+			It("should wait for the other parallel step", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(getWorkflow().Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseRunning))
+				Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+					chaosv1beta1.DisruptionWorkflowStepPhaseCompleted,
+					chaosv1beta1.DisruptionWorkflowStepPhaseCompleted,
+					chaosv1beta1.DisruptionWorkflowStepPhaseRunning,
+				}))
+				Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+			})
+		})
+
+		Context("with all the steps expired", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-2*time.Hour), 1, 2)
+				workflow.Status.Steps[0].Phase = chaosv1beta1.DisruptionWorkflowStepPhaseCompleted
+				workflow.Status.Steps[0].EndTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				objects = append(objects,
+					newStepDisruption("b", time.Now().Add(-2*time.Hour), chaostypes.DisruptionInjectionStatusInjected),
+					newStepDisruption("c", time.Now().Add(-2*time.Hour), chaostypes.DisruptionInjectionStatusPartiallyInjected),
+				)
+			})
+
+			It("should complete the workflow", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(listStepDisruptions()).To(BeEmpty())
+				Expect(getWorkflow().Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseCompleted))
+				Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionWorkflowCompleted}))
+			})
+		})
+	})
+
+	Context("with a step disruption expired without being injected", func() {
+		BeforeEach(func() {
+			workflow.Spec.Steps = append(workflow.Spec.Steps, newStep("c", false))
+			workflow.Spec.Steps[1].Parallel = true
+			setRunning(time.Now().Add(-2*time.Hour), 0, 1)
+			objects = append(objects,
+				newStepDisruption("a", time.Now().Add(-2*time.Hour), chaostypes.DisruptionInjectionStatusNotInjected),
+				newStepDisruption("b", time.Now().Add(-time.Minute), chaostypes.DisruptionInjectionStatusInjected),
+			)
+		})
+
+		It("should abort the workflow", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			instance := getWorkflow()
+			Expect(instance.Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseAborted))
+			Expect(instance.Status.Steps[0].Message).To(Equal("the step disruption has never been injected"))
+			Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+				chaosv1beta1.DisruptionWorkflowStepPhaseFailed,
+				chaosv1beta1.DisruptionWorkflowStepPhaseAborted,
+				chaosv1beta1.DisruptionWorkflowStepPhaseSkipped,
+			}))
+			Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionWorkflowAborted}))
+		})
+
+		It("should delete the disruptions of the running steps", func() {
+			Expect(listStepDisruptions()).To(BeEmpty())
+		})
+	})
+
+	Context("with a step disruption expired without any injection status", func() {
+		BeforeEach(func() {
+			setRunning(time.Now().Add(-2*time.Hour), 0)
+			objects = append(objects, newStepDisruption("a", time.Now().Add(-2*time.Hour), ""))
+		})
+
+		It("should abort the workflow", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getWorkflow().Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseAborted))
+			Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+				chaosv1beta1.DisruptionWorkflowStepPhaseFailed,
+				chaosv1beta1.DisruptionWorkflowStepPhaseSkipped,
+			}))
+		})
+	})
+
+	Context("with a step disruption which can't be created", func() {
+		BeforeEach(func() {
+			// a disruption with the step disruption name not owned by the workflow
+			disruption := newStepDisruption("a", time.Now(), "")
+			disruption.OwnerReferences = nil
+			disruption.Labels = nil
+			objects = append(objects, disruption)
+		})
+
+		It("should abort the workflow", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			instance := getWorkflow()
+			Expect(instance.Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseAborted))
+			Expect(instance.Status.Steps[0].Message).To(HavePrefix("error creating the step disruption"))
+			Expect(stepPhases()).To(Equal([]chaosv1beta1.DisruptionWorkflowStepPhase{
+				chaosv1beta1.DisruptionWorkflowStepPhaseFailed,
+				chaosv1beta1.DisruptionWorkflowStepPhaseSkipped,
+			}))
+			Expect(receivedEventReasons(recorder)).To(Equal([]string{chaosv1beta1.EventDisruptionWorkflowAborted}))
+		})
+
+		It("should not delete the existing disruption", func() {
+			disruption := &chaosv1beta1.Disruption{}
+			Expect(fakeClient.Get(context.Background(), types.NamespacedName{Name: "workflow-a", Namespace: workflowKey.Namespace}, disruption)).To(Succeed())
+		})
+	})
+
+	Context("with a running step disruption missing from the cache", func() {
+		Context("but readable from the API", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-time.Minute), 0)
+				uncachedObjects = append(uncachedObjects, newStepDisruption("a", time.Now().Add(-time.Minute), chaostypes.DisruptionInjectionStatusInjected))
+			})
+
+			It("should keep the step running until the disruption expires", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stepPhases()[0]).To(Equal(chaosv1beta1.DisruptionWorkflowStepPhaseRunning))
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, 2*time.Minute))
+			})
+		})
+
+		Context("and from the API within the grace period", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-10*time.Second), 0)
+			})
+
+			It("should wait for the disruption", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(getWorkflow().Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseRunning))
+				Expect(stepPhases()[0]).To(Equal(chaosv1beta1.DisruptionWorkflowStepPhaseRunning))
+				Expect(result.RequeueAfter).To(Equal(time.Second))
+			})
+		})
+
+		Context("and from the API after the grace period", func() {
+			BeforeEach(func() {
+				setRunning(time.Now().Add(-time.Minute), 0)
+			})
+
+			It("should end the step", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				// the disruption has never been seen injected
+				Expect(getWorkflow().Status.Phase).To(Equal(chaosv1beta1.DisruptionWorkflowPhaseAborted))
+				Expect(stepPhases()[0]).To(Equal(chaosv1beta1.DisruptionWorkflowStepPhaseFailed))
+			})
+		})
+	})
+
+	Context("with a completed workflow", func() {
+		BeforeEach(func() {
+			setRunning(time.Now().Add(-2 * time.Hour))
+			workflow.Status.Phase = chaosv1beta1.DisruptionWorkflowPhaseCompleted
+		})
+
+		It("should do nothing", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(listStepDisruptions()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,382 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package dnsresolver
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/network"
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDNSServer is the upstream DNS server used when none is configured
+	defaultDNSServer = "8.8.8.8"
+	// forwardTimeout is the time to wait for the upstream DNS server to answer a forwarded query
+	forwardTimeout = 3 * time.Second
+	// recordTTL is the TTL of the returned records, kept low so clients don't cache them once the disruption is over
+	recordTTL = 1
+)
+
+// failureRcodes are the response codes returned instead of any record by the failure record types
+var failureRcodes = map[string]int{
+	"NXDOMAIN": dns.RcodeNameError,
+	"SERVFAIL": dns.RcodeServerFailure,
+	"REFUSED":  dns.RcodeRefused,
+}
+
+// Resolver is a DNS resolver answering the queries matching its records and forwarding the other ones upstream
+type Resolver interface {
+	// Start listens for UDP DNS queries on the given address and returns the listening address,
+	// the listening socket is created in the network namespace of the calling thread;
+	// a started resolver keeps its listener and only counts the call, so it can be shared by several injectors
+	Start(addr string) (string, error)
+	// Close stops the listener once called as many times as Start, the delayed queries being dropped
+	Close() error
+}
+
+// Upstream describes where the queries which are not disrupted are forwarded
+type Upstream struct {
+	// DNSServer is the address of the upstream DNS server, the default DNS port being used if not specified
+	DNSServer string
+	// KubeDNSServer is the address of the kube-dns server, only used according to KubeDNS
+	KubeDNSServer string
+	// KubeDNS tells which queries are forwarded to kube-dns: none (off), the cluster internal domains only (internal) or all of them (all)
+	KubeDNS string
+}
+
+// NewUpstream returns the upstream of the given DNS config, the kube-dns server being read from the /etc/resolv.conf file when used
+func NewUpstream(config network.DNSConfig) (Upstream, error) {
+	upstream := Upstream{
+		DNSServer: config.DNSServer,
+		KubeDNS:   config.KubeDNS,
+	}
+
+	if upstream.DNSServer == "" {
+		upstream.DNSServer = defaultDNSServer
+	}
+
+	if upstream.KubeDNS == "internal" || upstream.KubeDNS == "all" {
+		resolvConf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil {
+			return upstream, fmt.Errorf("can't read resolv.conf file: %w", err)
+		}
+
+		if len(resolvConf.Servers) == 0 {
+			return upstream, fmt.Errorf("no kube-dns server found in resolv.conf file")
+		}
+
+		upstream.KubeDNSServer = resolvConf.Servers[0]
+	}
+
+	return upstream, nil
+}
+
+// server returns the address of the upstream server the given query name is forwarded to
+func (u Upstream) server(name string) string {
+	server := u.DNSServer
+
+	if u.KubeDNS == "all" || (u.KubeDNS == "internal" && isInternalDomain(name)) {
+		server = u.KubeDNSServer
+	}
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return server
+}
+
+// isInternalDomain returns true if the given fully qualified name is a cluster internal domain
+func isInternalDomain(name string) bool {
+	return strings.HasSuffix(name, ".local.") || strings.HasSuffix(name, ".internal.")
+}
+
+// rule is a record along with its compiled hostname
+type rule struct {
+	record   v1beta1.DNSRecord
+	hostname *regexp.Regexp
+	values   []string
+	next     int
+}
+
+type resolver struct {
+	log      *zap.SugaredLogger
+	rules    []*rule
+	upstream Upstream
+	client   *dns.Client
+	server   *dns.Server
+	starts   int
+	done     chan struct{}
+	lock     sync.Mutex
+}
+
+// NewResolver creates a resolver answering the queries matching the given records, in their order,
+// and forwarding the other ones to the given upstream
+func NewResolver(log *zap.SugaredLogger, records []v1beta1.HostRecordPair, upstream Upstream) (Resolver, error) {
+	rules := []*rule{}
+
+	for _, record := range records {
+		hostname, err := record.CompileHostname()
+		if err != nil {
+			return nil, fmt.Errorf("invalid hostname %s: %w", record.Hostname, err)
+		}
+
+		rule := &rule{
+			record:   record.Record,
+			hostname: hostname,
+		}
+
+		if record.Record.Value != "" {
+			rule.values = strings.Split(record.Record.Value, ",")
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return &resolver{
+		log:      log,
+		rules:    rules,
+		upstream: upstream,
+		client:   &dns.Client{Net: "udp", Timeout: forwardTimeout},
+	}, nil
+}
+
+func (r *resolver) Start(addr string) (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.server != nil {
+		r.starts++
+
+		return r.server.PacketConn.LocalAddr().String(), nil
+	}
+
+	// when no IP is given, the socket listens on both IPv4 and IPv6 if the host supports it
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return "", fmt.Errorf("error listening for DNS queries on %s: %w", addr, err)
+	}
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        conn,
+		Handler:           dns.HandlerFunc(r.serveDNS),
+		NotifyStartedFunc: func() { close(started) },
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- server.ActivateAndServe()
+	}()
+
+	// wait for the server to be started so it can be shut down
+	select {
+	case <-started:
+	case err := <-serveErr:
+		return "", fmt.Errorf("error serving DNS queries on %s: %w", addr, err)
+	}
+
+	r.server = server
+	r.starts = 1
+	r.done = make(chan struct{})
+
+	listeningAddr := conn.LocalAddr().String()
+
+	r.log.Infow("resolving DNS queries", "addr", listeningAddr, "upstream", r.upstream)
+
+	return listeningAddr, nil
+}
+
+func (r *resolver) Close() error {
+	r.lock.Lock()
+
+	if r.server == nil {
+		r.lock.Unlock()
+
+		return nil
+	}
+
+	r.starts--
+	if r.starts > 0 {
+		r.lock.Unlock()
+
+		return nil
+	}
+
+	server, done := r.server, r.done
+	r.server = nil
+
+	// release the lock before shutting down since the server waits for the queries being handled, which need it
+	r.lock.Unlock()
+
+	// stop waiting for the delayed queries so the server is not waiting for them to shut down
+	close(done)
+
+	if err := server.Shutdown(); err != nil {
+		return fmt.Errorf("error stopping the DNS resolver: %w", err)
+	}
+
+	return nil
+}
+
+// serveDNS answers the given query with the first matching rule passing its probability roll, or forwards it upstream
+func (r *resolver) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	r.lock.Lock()
+	done := r.done
+	r.lock.Unlock()
+
+	if len(req.Question) == 0 {
+		r.forward(w, req)
+
+		return
+	}
+
+	question := req.Question[0]
+
+	rule, value := r.matchRule(question, rand.Intn) //nolint:gosec
+	if rule == nil {
+		r.forward(w, req)
+
+		return
+	}
+
+	r.log.Debugw("disrupting DNS query", "name", question.Name, "type", dns.TypeToString[question.Qtype], "record", rule.record)
+
+	if rule.record.Delay > 0 {
+		select {
+		case <-time.After(time.Duration(rule.record.Delay) * time.Millisecond):
+		case <-done:
+			return
+		}
+	}
+
+	switch {
+	case rule.record.Type == "DROP":
+		// never answer so the client times out
+		return
+	case rule.record.Type == "FORWARD":
+		r.forward(w, req)
+
+		return
+	}
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	resp.Authoritative = true
+
+	if rcode, ok := failureRcodes[rule.record.Type]; ok {
+		resp.Rcode = rcode
+	} else if err := addAnswer(resp, rule.record.Type, question.Name, value); err != nil {
+		r.log.Errorw("error building the DNS answer, answering with a server failure", "error", err, "name", question.Name)
+
+		resp.Answer = nil
+		resp.Rcode = dns.RcodeServerFailure
+	}
+
+	r.write(w, resp)
+}
+
+// matchRule returns the first rule matching the given question and passing its probability roll, if any,
+// along with the value to answer, record values being used in a round-robin fashion
+func (r *resolver) matchRule(question dns.Question, intn func(int) int) (*rule, string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, rule := range r.rules {
+		// records only answer the queries of their type while failures apply to all the query types
+		if (rule.record.Type == "A" || rule.record.Type == "CNAME") && dns.StringToType[rule.record.Type] != question.Qtype {
+			continue
+		}
+
+		if !rule.hostname.MatchString(question.Name) {
+			continue
+		}
+
+		// only disrupt the given percentage of the matching queries, the other ones going on to the next rules
+		if rule.record.Probability > 0 && intn(100) >= rule.record.Probability {
+			continue
+		}
+
+		if len(rule.values) == 0 {
+			return rule, ""
+		}
+
+		value := rule.values[rule.next%len(rule.values)]
+		rule.next++
+
+		return rule, value
+	}
+
+	return nil, ""
+}
+
+// addAnswer adds the given record type and value answer to the given response,
+// an A record value being either an IP or NXDOMAIN (or none) to answer that the name does not exist
+func addAnswer(resp *dns.Msg, recordType, name, value string) error {
+	header := dns.RR_Header{Name: name, Class: dns.ClassINET, Ttl: recordTTL}
+
+	switch recordType {
+	case "A":
+		if strings.EqualFold(value, "NXDOMAIN") || strings.EqualFold(value, "none") {
+			resp.Rcode = dns.RcodeNameError
+
+			return nil
+		}
+
+		ip := net.ParseIP(strings.TrimSpace(value)).To4()
+		if ip == nil {
+			return fmt.Errorf("invalid A record value %s", value)
+		}
+
+		header.Rrtype = dns.TypeA
+		resp.Answer = append(resp.Answer, &dns.A{Hdr: header, A: ip})
+	case "CNAME":
+		header.Rrtype = dns.TypeCNAME
+		resp.Answer = append(resp.Answer, &dns.CNAME{Hdr: header, Target: dns.Fqdn(strings.TrimSpace(value))})
+	default:
+		return fmt.Errorf("unsupported record type %s", recordType)
+	}
+
+	return nil
+}
+
+// forward forwards the given query to the upstream server and writes back its response,
+// a server failure being returned if the upstream server can't be reached
+func (r *resolver) forward(w dns.ResponseWriter, req *dns.Msg) {
+	name := ""
+	if len(req.Question) > 0 {
+		name = req.Question[0].Name
+	}
+
+	server := r.upstream.server(name)
+
+	resp, _, err := r.client.Exchange(req, server)
+	if err != nil {
+		r.log.Warnw("error forwarding DNS query, answering with a server failure", "error", err, "name", name, "server", server)
+
+		resp = &dns.Msg{}
+		resp.SetRcode(req, dns.RcodeServerFailure)
+	}
+
+	r.write(w, resp)
+}
+
+// write writes the given response, logging any error since the client times out anyway
+func (r *resolver) write(w dns.ResponseWriter, resp *dns.Msg) {
+	if err := w.WriteMsg(resp); err != nil {
+		r.log.Warnw("error answering DNS query", "error", err)
+	}
+}
@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package dnsresolver
+
+import "github.com/stretchr/testify/mock"
+
+// ResolverMock is a mock implementation of the Resolver interface
+type ResolverMock struct {
+	mock.Mock
+}
+
+//nolint:golint
+func (f *ResolverMock) Start(addr string) (string, error) {
+	args := f.Called(addr)
+
+	return args.String(0), args.Error(1)
+}
+
+//nolint:golint
+func (f *ResolverMock) Close() error {
+	args := f.Called()
+
+	return args.Error(0)
+}
@@ -0,0 +1,295 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package dnsresolver_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	. "github.com/DataDog/chaos-controller/dnsresolver"
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolver", func() {
+	var (
+		dnsServer, kubeDNSServer *dns.Server
+		resolver                 Resolver
+		resolverAddr             string
+		records                  []v1beta1.HostRecordPair
+		upstream                 Upstream
+	)
+
+	// startUpstream starts a DNS server on a random local UDP port answering the A queries with the given IP
+	startUpstream := func(ip string) *dns.Server {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+
+		started := make(chan struct{})
+		server := &dns.Server{
+			PacketConn:        conn,
+			NotifyStartedFunc: func() { close(started) },
+			Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+				resp := &dns.Msg{}
+				resp.SetReply(req)
+
+				if req.Question[0].Qtype == dns.TypeA {
+					resp.Answer = append(resp.Answer, &dns.A{
+						Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+						A:   net.ParseIP(ip),
+					})
+				}
+
+				_ = w.WriteMsg(resp)
+			}),
+		}
+
+		go func() {
+			_ = server.ActivateAndServe()
+		}()
+
+		Eventually(started).Should(BeClosed())
+
+		return server
+	}
+
+	query := func(name string, qtype uint16) (*dns.Msg, error) {
+		req := &dns.Msg{}
+		req.SetQuestion(name, qtype)
+
+		resp, _, err := (&dns.Client{Net: "udp", Timeout: 500 * time.Millisecond}).Exchange(req, resolverAddr)
+
+		return resp, err
+	}
+
+	answers := func(resp *dns.Msg) []string {
+		values := []string{}
+
+		for _, rr := range resp.Answer {
+			switch rr := rr.(type) {
+			case *dns.A:
+				values = append(values, rr.A.String())
+			case *dns.CNAME:
+				values = append(values, rr.Target)
+			}
+		}
+
+		return values
+	}
+
+	BeforeEach(func() {
+		dnsServer = startUpstream("192.0.2.1")
+		kubeDNSServer = startUpstream("192.0.2.53")
+
+		upstream = Upstream{
+			DNSServer:     dnsServer.PacketConn.LocalAddr().String(),
+			KubeDNSServer: kubeDNSServer.PacketConn.LocalAddr().String(),
+			KubeDNS:       "off",
+		}
+
+		records = []v1beta1.HostRecordPair{
+			{
+				Hostname: "foo.bar.svc.cluster.local",
+				Record:   v1beta1.DNSRecord{Type: "A", Value: "10.0.0.1,10.0.0.2"},
+			},
+			{
+				Hostname: "alias.bar.svc.cluster.local",
+				Record:   v1beta1.DNSRecord{Type: "CNAME", Value: "foo.bar.svc.cluster.local"},
+			},
+			{
+				Hostname: "missing.bar.svc.cluster.local",
+				Record:   v1beta1.DNSRecord{Type: "A", Value: "NXDOMAIN"},
+			},
+			{
+				Hostname:      "failing.svc.cluster.local",
+				HostnameMatch: "suffix",
+				Record:        v1beta1.DNSRecord{Type: "SERVFAIL"},
+			},
+			{
+				Hostname:      "refused.example.com",
+				HostnameMatch: "exact",
+				Record:        v1beta1.DNSRecord{Type: "REFUSED"},
+			},
+			{
+				Hostname:      "*.dropped.example.com",
+				HostnameMatch: "wildcard",
+				Record:        v1beta1.DNSRecord{Type: "DROP"},
+			},
+			{
+				Hostname: "slow\\.example\\.com",
+				Record:   v1beta1.DNSRecord{Type: "FORWARD", Delay: 200},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		resolver, err = NewResolver(log, records, upstream)
+		Expect(err).To(BeNil())
+
+		resolverAddr, err = resolver.Start("127.0.0.1:0")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(resolver.Close()).To(BeNil())
+		Expect(dnsServer.Shutdown()).To(BeNil())
+		Expect(kubeDNSServer.Shutdown()).To(BeNil())
+	})
+
+	It("should forward queries matching no record to the DNS server", func() {
+		resp, err := query("datadoghq.com.", dns.TypeA)
+		Expect(err).To(BeNil())
+		Expect(answers(resp)).To(Equal([]string{"192.0.2.1"}))
+	})
+
+	It("should answer the A record values in a round-robin fashion", func() {
+		for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.1"} {
+			resp, err := query("foo.bar.svc.cluster.local.", dns.TypeA)
+			Expect(err).To(BeNil())
+			Expect(resp.Rcode).To(Equal(dns.RcodeSuccess))
+			Expect(answers(resp)).To(Equal([]string{ip}))
+		}
+	})
+
+	It("should only answer the queries of the record type", func() {
+		resp, err := query("foo.bar.svc.cluster.local.", dns.TypeAAAA)
+		Expect(err).To(BeNil())
+		Expect(resp.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(resp.Answer).To(BeEmpty())
+	})
+
+	It("should answer CNAME records", func() {
+		resp, err := query("alias.bar.svc.cluster.local.", dns.TypeCNAME)
+		Expect(err).To(BeNil())
+		Expect(answers(resp)).To(Equal([]string{"foo.bar.svc.cluster.local."}))
+	})
+
+	It("should answer that the name does not exist for an NXDOMAIN A record value", func() {
+		resp, err := query("missing.bar.svc.cluster.local.", dns.TypeA)
+		Expect(err).To(BeNil())
+		Expect(resp.Rcode).To(Equal(dns.RcodeNameError))
+	})
+
+	It("should fail the queries of any type matching a suffix", func() {
+		for _, name := range []string{"failing.svc.cluster.local.", "foo.failing.svc.cluster.local."} {
+			for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+				resp, err := query(name, qtype)
+				Expect(err).To(BeNil())
+				Expect(resp.Rcode).To(Equal(dns.RcodeServerFailure))
+			}
+		}
+
+		resp, err := query("notfailing.svc.cluster.local.", dns.TypeA)
+		Expect(err).To(BeNil())
+		Expect(answers(resp)).To(Equal([]string{"192.0.2.1"}))
+	})
+
+	It("should refuse the queries matching an exact hostname only", func() {
+		resp, err := query("REFUSED.example.com.", dns.TypeA)
+		Expect(err).To(BeNil())
+		Expect(resp.Rcode).To(Equal(dns.RcodeRefused))
+
+		resp, err = query("foo.refused.example.com.", dns.TypeA)
+		Expect(err).To(BeNil())
+		Expect(resp.Rcode).To(Equal(dns.RcodeSuccess))
+	})
+
+	It("should never answer the queries matching a wildcard hostname", func() {
+		_, err := query("foo.dropped.example.com.", dns.TypeA)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should forward the delayed queries", func() {
+		start := time.Now()
+		resp, err := query("slow.example.com.", dns.TypeA)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+		Expect(answers(resp)).To(Equal([]string{"192.0.2.1"}))
+	})
+
+	Context("with kube-dns used for the internal domains", func() {
+		BeforeEach(func() {
+			upstream.KubeDNS = "internal"
+		})
+
+		It("should forward the internal domain queries to kube-dns only", func() {
+			resp, err := query("baz.bar.svc.cluster.local.", dns.TypeA)
+			Expect(err).To(BeNil())
+			Expect(answers(resp)).To(Equal([]string{"192.0.2.53"}))
+
+			resp, err = query("datadoghq.com.", dns.TypeA)
+			Expect(err).To(BeNil())
+			Expect(answers(resp)).To(Equal([]string{"192.0.2.1"}))
+		})
+	})
+
+	Context("with kube-dns used for all the domains", func() {
+		BeforeEach(func() {
+			upstream.KubeDNS = "all"
+		})
+
+		It("should forward all the queries to kube-dns", func() {
+			resp, err := query("datadoghq.com.", dns.TypeA)
+			Expect(err).To(BeNil())
+			Expect(answers(resp)).To(Equal([]string{"192.0.2.53"}))
+		})
+	})
+
+	Context("with an invalid regular expression", func() {
+		It("should not be created", func() {
+			_, err := NewResolver(log, []v1beta1.HostRecordPair{{Hostname: "foo(", Record: v1beta1.DNSRecord{Type: "NXDOMAIN"}}}, upstream)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("once closed", func() {
+		It("should stop listening", func() {
+			Expect(resolver.Close()).To(BeNil())
+
+			_, err := query("datadoghq.com.", dns.TypeA)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("should not wait for the delayed queries", func() {
+			req := &dns.Msg{}
+			req.SetQuestion("slow.example.com.", dns.TypeA)
+
+			go func(addr string) {
+				_, _, _ = (&dns.Client{Net: "udp", Timeout: 500 * time.Millisecond}).Exchange(req, addr)
+			}(resolverAddr)
+
+			time.Sleep(50 * time.Millisecond)
+
+			start := time.Now()
+			Expect(resolver.Close()).To(BeNil())
+			Expect(time.Since(start)).To(BeNumerically("<", 200*time.Millisecond))
+		})
+	})
+
+	Context("when started several times", func() {
+		JustBeforeEach(func() {
+			addr, err := resolver.Start("127.0.0.1:0")
+			Expect(err).To(BeNil())
+			Expect(addr).To(Equal(resolverAddr))
+		})
+
+		It("should keep listening until closed as many times", func() {
+			Expect(resolver.Close()).To(BeNil())
+
+			_, err := query("datadoghq.com.", dns.TypeA)
+			Expect(err).To(BeNil())
+
+			Expect(resolver.Close()).To(BeNil())
+
+			_, err = query("datadoghq.com.", dns.TypeA)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -252,7 +252,7 @@ var file_chaosdogfood_proto_rawDesc = []byte{
 	0x6f, 0x67, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x12, 0x12,
 	0x0a, 0x04, 0x66, 0x6f, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x6f,
-	0x6f, 0x64, 0x32, 0x91, 0x01, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x6f, 0x73, 0x44, 0x6f, 0x67, 0x66,
+	0x6f, 0x64, 0x32, 0xd9, 0x01, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x6f, 0x73, 0x44, 0x6f, 0x67, 0x66,
 	0x6f, 0x6f, 0x64, 0x12, 0x3d, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x63,
 	0x68, 0x61, 0x6f, 0x73, 0x64, 0x6f, 0x67, 0x66, 0x6f, 0x6f, 0x64, 0x2e, 0x46, 0x6f, 0x6f, 0x64,
 	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6f, 0x73, 0x64,
@@ -261,8 +261,13 @@ var file_chaosdogfood_proto_rawDesc = []byte{
 	0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
 	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x6f, 0x73,
 	0x64, 0x6f, 0x67, 0x66, 0x6f, 0x6f, 0x64, 0x2e, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x10, 0x5a, 0x0e, 0x2e, 0x2f, 0x63, 0x68, 0x61, 0x6f,
-	0x73, 0x64, 0x6f, 0x67, 0x66, 0x6f, 0x6f, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x19, 0x2e, 0x63, 0x68, 0x61, 0x6f, 0x73, 0x64, 0x6f, 0x67, 0x66, 0x6f, 0x6f, 0x64, 0x2e, 0x43,
+	0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x49, 0x74, 0x65, 0x6d, 0x22, 0x00, 0x30, 0x01, 0x42, 0x10,
+	0x5a, 0x0e, 0x2e, 0x2f, 0x63, 0x68, 0x61, 0x6f, 0x73, 0x64, 0x6f, 0x67, 0x66, 0x6f, 0x6f, 0x64,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -289,10 +294,12 @@ var file_chaosdogfood_proto_depIdxs = []int32{
 	3, // 0: chaosdogfood.CatalogReply.items:type_name -> chaosdogfood.CatalogItem
 	0, // 1: chaosdogfood.ChaosDogfood.order:input_type -> chaosdogfood.FoodRequest
 	4, // 2: chaosdogfood.ChaosDogfood.getCatalog:input_type -> google.protobuf.Empty
-	1, // 3: chaosdogfood.ChaosDogfood.order:output_type -> chaosdogfood.FoodReply
-	2, // 4: chaosdogfood.ChaosDogfood.getCatalog:output_type -> chaosdogfood.CatalogReply
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
+	4, // 3: chaosdogfood.ChaosDogfood.streamCatalog:input_type -> google.protobuf.Empty
+	1, // 4: chaosdogfood.ChaosDogfood.order:output_type -> chaosdogfood.FoodReply
+	2, // 5: chaosdogfood.ChaosDogfood.getCatalog:output_type -> chaosdogfood.CatalogReply
+	3, // 6: chaosdogfood.ChaosDogfood.streamCatalog:output_type -> chaosdogfood.CatalogItem
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
 	1, // [1:1] is the sub-list for extension extendee
 	0, // [0:1] is the sub-list for field type_name
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion7
 type ChaosDogfoodClient interface {
 	Order(ctx context.Context, in *FoodRequest, opts ...grpc.CallOption) (*FoodReply, error)
 	GetCatalog(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CatalogReply, error)
+	StreamCatalog(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ChaosDogfood_StreamCatalogClient, error)
 }
 
 type chaosDogfoodClient struct {
@@ -49,12 +50,45 @@ func (c *chaosDogfoodClient) GetCatalog(ctx context.Context, in *emptypb.Empty,
 	return out, nil
 }
 
+func (c *chaosDogfoodClient) StreamCatalog(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ChaosDogfood_StreamCatalogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ChaosDogfood_ServiceDesc.Streams[0], "/chaosdogfood.ChaosDogfood/streamCatalog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaosDogfoodStreamCatalogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChaosDogfood_StreamCatalogClient interface {
+	Recv() (*CatalogItem, error)
+	grpc.ClientStream
+}
+
+type chaosDogfoodStreamCatalogClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaosDogfoodStreamCatalogClient) Recv() (*CatalogItem, error) {
+	m := new(CatalogItem)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ChaosDogfoodServer is the server API for ChaosDogfood service.
 // All implementations must embed UnimplementedChaosDogfoodServer
 // for forward compatibility
 type ChaosDogfoodServer interface {
 	Order(context.Context, *FoodRequest) (*FoodReply, error)
 	GetCatalog(context.Context, *emptypb.Empty) (*CatalogReply, error)
+	StreamCatalog(*emptypb.Empty, ChaosDogfood_StreamCatalogServer) error
 	mustEmbedUnimplementedChaosDogfoodServer()
 }
 
@@ -68,6 +102,9 @@ func (UnimplementedChaosDogfoodServer) Order(context.Context, *FoodRequest) (*Fo
 func (UnimplementedChaosDogfoodServer) GetCatalog(context.Context, *emptypb.Empty) (*CatalogReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCatalog not implemented")
 }
+func (UnimplementedChaosDogfoodServer) StreamCatalog(*emptypb.Empty, ChaosDogfood_StreamCatalogServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCatalog not implemented")
+}
 func (UnimplementedChaosDogfoodServer) mustEmbedUnimplementedChaosDogfoodServer() {}
 
 // UnsafeChaosDogfoodServer may be embedded to opt out of forward compatibility for this service.
@@ -117,6 +154,27 @@ func _ChaosDogfood_GetCatalog_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChaosDogfood_StreamCatalog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChaosDogfoodServer).StreamCatalog(m, &chaosDogfoodStreamCatalogServer{stream})
+}
+
+type ChaosDogfood_StreamCatalogServer interface {
+	Send(*CatalogItem) error
+	grpc.ServerStream
+}
+
+type chaosDogfoodStreamCatalogServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaosDogfoodStreamCatalogServer) Send(m *CatalogItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ChaosDogfood_ServiceDesc is the grpc.ServiceDesc for ChaosDogfood service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -133,6 +191,12 @@ var ChaosDogfood_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ChaosDogfood_GetCatalog_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "streamCatalog",
+			Handler:       _ChaosDogfood_StreamCatalog_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "chaosdogfood.proto",
 }
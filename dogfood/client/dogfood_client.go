@@ -7,35 +7,53 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"strconv"
 	"time"
 
 	pb "github.com/DataDog/chaos-controller/dogfood/chaosdogfood"
+	disruption_service "github.com/DataDog/chaos-controller/grpc"
+	dl_pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	zaplog "github.com/DataDog/chaos-controller/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
+const chaosEnabled = true // In your application, make this a feature flag
+
 var serverAddr string
 
+var listenerAddr string
+
 func init() {
 	var serverPort int
 
 	var serverHostname string
 
+	var listenerPort int
+
 	flag.StringVar(&serverHostname, "server_hostname", "<service>.<namespace>.svc.cluster.local", "Hostname of dogfood server")
 	flag.IntVar(&serverPort, "server_port", 50000, "Port where gRPC server is running")
+	flag.IntVar(&listenerPort, "listener_port", 50052, "Port where the disruption listener of the client interceptors is served")
 	flag.Parse()
 
 	serverAddr = fmt.Sprintf("%s:%d", serverHostname, serverPort)
+	listenerAddr = fmt.Sprintf(":%d", listenerPort)
 }
 
 func orderWithTimeout(client pb.ChaosDogfoodClient, animal string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// identify the client so disruptions can be scoped to its calls with metadata matchers
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant", "dogfood")
+
 	res, err := client.Order(ctx, &pb.FoodRequest{Animal: animal})
 	if err != nil {
 		return "", err
@@ -56,6 +74,30 @@ func getCatalogWithTimeout(client pb.ChaosDogfoodClient) ([]*pb.CatalogItem, err
 	return res.Items, nil
 }
 
+func streamCatalogWithTimeout(client pb.ChaosDogfoodClient) ([]*pb.CatalogItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamCatalog(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*pb.CatalogItem{}
+
+	for {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		} else if err != nil {
+			// return the items received before the stream failed
+			return items, err
+		}
+
+		items = append(items, item)
+	}
+}
+
 // regularly order food for different aniamls
 // note: mouse should return error because food for mice is not in the catalog
 func sendsLotsOfRequests(client pb.ChaosDogfoodClient) {
@@ -77,6 +119,15 @@ func sendsLotsOfRequests(client pb.ChaosDogfoodClient) {
 		fmt.Printf("| catalog: %v items returned %s\n", strconv.Itoa(len(items)), stringifyCatalogItems(items))
 		time.Sleep(time.Second)
 
+		// stream catalog
+		items, err = streamCatalogWithTimeout(client)
+		if err != nil {
+			fmt.Printf("| ERROR streaming catalog:%v\n", err.Error())
+		}
+
+		fmt.Printf("| streamed catalog: %v items received %s\n", strconv.Itoa(len(items)), stringifyCatalogItems(items))
+		time.Sleep(time.Second)
+
 		// make an order
 		order, err := orderWithTimeout(client, animals[i])
 		if err != nil {
@@ -104,6 +155,34 @@ func stringifyCatalogItems(items []*pb.CatalogItem) string {
 	return fmt.Sprintf("(%s)", printable)
 }
 
+// serveDisruptionListener serves a disruption listener on its own port so the injector can configure the client interceptors
+func serveDisruptionListener() *disruption_service.ChaosDisruptionListener {
+	disruptionLogger, err := zaplog.NewZapLogger()
+	if err != nil {
+		log.Fatal("error creating controller logger")
+	}
+
+	lis, err := net.Listen("tcp", listenerAddr)
+	if err != nil {
+		log.Fatalf("failed to listen: %s\n", err)
+	}
+
+	disruptionListener := disruption_service.NewDisruptionListener(disruptionLogger)
+
+	listenerServer := grpc.NewServer()
+	dl_pb.RegisterDisruptionListenerServer(listenerServer, disruptionListener)
+
+	go func() {
+		if err := listenerServer.Serve(lis); err != nil {
+			log.Fatalf("failed to serve disruption listener: %v", err)
+		}
+	}()
+
+	fmt.Printf("disruption listener listening on %v...\n", listenerAddr)
+
+	return disruptionListener
+}
+
 func main() {
 	// create and eventually close connection
 	fmt.Printf("connecting to %v...\n", serverAddr)
@@ -112,6 +191,16 @@ func main() {
 	opts = append(opts, grpc.WithInsecure())
 	opts = append(opts, grpc.WithBlock())
 
+	// In your application, check the feature flag to decide if the interceptors should be used
+	if chaosEnabled == true {
+		fmt.Println("CHAOS ENABLED")
+
+		disruptionListener := serveDisruptionListener()
+
+		opts = append(opts, grpc.WithUnaryInterceptor(disruptionListener.ChaosClientInterceptor))
+		opts = append(opts, grpc.WithStreamInterceptor(disruptionListener.ChaosStreamClientInterceptor))
+	}
+
 	conn, err := grpc.Dial(serverAddr, opts...)
 	if err != nil {
 		log.Fatalf("fail to dial: %v", err)
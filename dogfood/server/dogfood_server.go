@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -81,6 +82,25 @@ func (s *chaosDogfoodService) GetCatalog(ctx context.Context, req *emptypb.Empty
 	return &df_pb.CatalogReply{Items: items}, nil
 }
 
+func (s *chaosDogfoodService) StreamCatalog(req *emptypb.Empty, stream df_pb.ChaosDogfood_StreamCatalogServer) error {
+	fmt.Println("x\n| streaming catalog")
+
+	for animal, food := range catalog {
+		if err := stream.Send(&df_pb.CatalogItem{
+			Animal: animal,
+			Food:   food,
+		}); err != nil {
+			fmt.Printf("| * STREAM FAILED - %v\n", err)
+
+			return err
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return nil
+}
+
 func main() {
 	fmt.Printf("listening on %v...\n", serverAddr)
 
@@ -105,10 +125,12 @@ func main() {
 
 		dogfoodServer = grpc.NewServer(
 			grpc.UnaryInterceptor(disruptionListener.ChaosServerInterceptor),
+			grpc.StreamInterceptor(disruptionListener.ChaosStreamServerInterceptor),
 		)
 
 		df_pb.RegisterChaosDogfoodServer(dogfoodServer, &chaosDogfoodService{})
 		dl_pb.RegisterDisruptionListenerServer(dogfoodServer, disruptionListener)
+		disruptionListener.SetServiceInfoProvider(dogfoodServer)
 	} else {
 		dogfoodServer = grpc.NewServer()
 
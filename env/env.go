@@ -14,5 +14,6 @@ const (
 	InjectorMountSysrqTrigger = "CHAOS_INJECTOR_MOUNT_SYSRQ_TRIGGER"
 	InjectorTargetPodHostIP   = "TARGET_POD_HOST_IP"
 	InjectorChaosPodIP        = "CHAOS_POD_IP"
+	InjectorChaosPodIPs       = "CHAOS_POD_IPS"
 	InjectorPodName           = "INJECTOR_POD_NAME"
 )
@@ -6,6 +6,10 @@
 package calculations
 
 import (
+	"math/rand"
+	"net/url"
+	"time"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -31,6 +35,66 @@ func ConvertSpecifications(endpointSpecList []*pb.AlterationSpec) ([]AlterationC
 	return FlattenAlterationMap(alterationToQueryPercent), nil
 }
 
+// ConvertEndpointSpecifications converts the alterations configured for a target endpoint with ConvertSpecifications
+// for each group of alterations with the same metadata matchers (see GroupSpecificationsByMetadata);
+// it returns the alterations without matchers and the groups of alterations with matchers
+func ConvertEndpointSpecifications(endpointSpecList []*pb.AlterationSpec) ([]AlterationConfiguration, []MatchedAlterations, error) {
+	var alterations []AlterationConfiguration
+
+	matchedAlterations := []MatchedAlterations{}
+
+	for _, group := range GroupSpecificationsByMetadata(endpointSpecList) {
+		converted, err := ConvertSpecifications(group)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(group[0].Metadata) == 0 {
+			alterations = converted
+			continue
+		}
+
+		matchedAlterations = append(matchedAlterations, MatchedAlterations{
+			Metadata:    group[0].Metadata,
+			Alterations: converted,
+		})
+	}
+
+	return alterations, matchedAlterations, nil
+}
+
+// GroupSpecificationsByMetadata groups the alterations configured for a target endpoint by metadata matchers,
+// the query percentages of the alterations of a group apply to the calls matching its matchers only;
+// the groups keep the order in which their matchers first appear
+func GroupSpecificationsByMetadata(endpointSpecList []*pb.AlterationSpec) [][]*pb.AlterationSpec {
+	groups := [][]*pb.AlterationSpec{}
+	groupIndexes := map[string]int{}
+
+	for _, altSpec := range endpointSpecList {
+		key := MetadataKey(altSpec.Metadata)
+
+		if i, ok := groupIndexes[key]; ok {
+			groups[i] = append(groups[i], altSpec)
+			continue
+		}
+
+		groupIndexes[key] = len(groups)
+		groups = append(groups, []*pb.AlterationSpec{altSpec})
+	}
+
+	return groups
+}
+
+// MetadataKey returns a key identifying the given metadata matchers
+func MetadataKey(md map[string]string) string {
+	values := url.Values{}
+	for key, value := range md {
+		values.Set(key, value)
+	}
+
+	return values.Encode()
+}
+
 // GetPercentagePerAlteration takes a series of alterations configured for a target endpoint and returns a mapping
 // from the alteration to the percentage of queries which will be altered by it
 func GetPercentagePerAlteration(endpointSpecList []*pb.AlterationSpec) (map[AlterationConfiguration]QueryPercent, error) {
@@ -42,19 +106,24 @@ func GetPercentagePerAlteration(endpointSpecList []*pb.AlterationSpec) (map[Alte
 	pctClaimed := 0
 
 	for _, altSpec := range endpointSpecList {
-		if altSpec.ErrorToReturn == "" && altSpec.OverrideToReturn == "" {
-			return nil, status.Error(codes.InvalidArgument, "cannot map alteration to assigned query percentage without specifying either ErrorToReturn or OverrideToReturn for a target endpoint")
+		if altSpec.ErrorToReturn == "" && altSpec.OverrideToReturn == "" && altSpec.Latency.AsDuration() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "cannot map alteration to assigned query percentage without specifying either ErrorToReturn, OverrideToReturn or Latency for a target endpoint")
 		}
 
 		if altSpec.ErrorToReturn != "" && altSpec.OverrideToReturn != "" {
 			return nil, status.Error(codes.InvalidArgument, "cannot map alteration to assigned query percentage when ErrorToReturn and OverrideToReturn are both specified for a target endpoint")
 		}
 
-		alterationConfig := AlterationConfiguration{
-			ErrorToReturn:    altSpec.ErrorToReturn,
-			OverrideToReturn: altSpec.OverrideToReturn,
+		if altSpec.LatencyJitter < 0 || altSpec.LatencyJitter > 100 {
+			return nil, status.Error(codes.InvalidArgument, "cannot map alteration to assigned query percentage when LatencyJitter is not between 0 and 100 percent for a target endpoint")
+		}
+
+		if altSpec.FailAfterMessages != 0 && (altSpec.FailAfterMessages < 0 || altSpec.ErrorToReturn == "") {
+			return nil, status.Error(codes.InvalidArgument, "cannot map alteration to assigned query percentage when FailAfterMessages is negative or specified without ErrorToReturn for a target endpoint")
 		}
 
+		alterationConfig := NewAlterationConfiguration(altSpec)
+
 		// Intuition:
 		// (1) add all endpoints where queryPercent is specified
 		// (2) track total percentage odds of queries already claimed by an AlterationConfiguration
@@ -96,6 +165,17 @@ func GetPercentagePerAlteration(endpointSpecList []*pb.AlterationSpec) (map[Alte
 	return mapping, nil
 }
 
+// NewAlterationConfiguration converts an AlterationSpec into the AlterationConfiguration applied to the altered queries
+func NewAlterationConfiguration(altSpec *pb.AlterationSpec) AlterationConfiguration {
+	return AlterationConfiguration{
+		ErrorToReturn:     altSpec.ErrorToReturn,
+		OverrideToReturn:  altSpec.OverrideToReturn,
+		Latency:           altSpec.Latency.AsDuration(),
+		LatencyJitter:     int(altSpec.LatencyJitter),
+		FailAfterMessages: int(altSpec.FailAfterMessages),
+	}
+}
+
 // FlattenAlterationMap takes a mapping from alterationConfiguration to the percentage of requests
 // and returns a slice where the slice's "index" between 0 and some number less than 100 are assigned
 // Alterations which reappear as many times as the requested query percentage
@@ -110,3 +190,18 @@ func FlattenAlterationMap(alterationToQueryPercent map[AlterationConfiguration]Q
 
 	return slice
 }
+
+// GetLatency returns the latency to add to a query altered by the given alteration, which is randomly picked
+// between (1 - LatencyJitter%) and (1 + LatencyJitter%) times the configured latency
+func GetLatency(altConfig AlterationConfiguration) time.Duration {
+	if altConfig.Latency <= 0 {
+		return 0
+	}
+
+	jitter := int64(float64(altConfig.Latency) * float64(altConfig.LatencyJitter) / 100)
+	if jitter <= 0 {
+		return altConfig.Latency
+	}
+
+	return altConfig.Latency + time.Duration(rand.Int63n(2*jitter+1)-jitter) //nolint:gosec
+}
@@ -5,21 +5,73 @@
 
 package calculations
 
+import (
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
 // DisruptionConfiguration configures the DisruptionListener to chaos test endpoints of a gRPC server.
 type DisruptionConfiguration map[TargetEndpoint]EndpointConfiguration
 
 // EndpointConfiguration configures endpoints that the DisruptionListener chaos tests on a gRPC server.
 // The Alterations maps integers from 0 to 100 to alteration configurations.
+// The MatchedAlterations are evaluated in order before the Alterations, which apply to the calls matching none of them.
+// The Overrides maps the OverrideToReturn payloads of the alterations to the responses to return.
 type EndpointConfiguration struct {
-	TargetEndpoint TargetEndpoint
-	Alterations    []AlterationConfiguration
+	TargetEndpoint     TargetEndpoint
+	Alterations        []AlterationConfiguration
+	MatchedAlterations []MatchedAlterations
+	Overrides          map[string]proto.Message
+}
+
+// MatchedAlterations maps integers from 0 to 100 to the alteration configurations of the calls
+// carrying all the metadata of the Metadata matchers.
+type MatchedAlterations struct {
+	Metadata    map[string]string
+	Alterations []AlterationConfiguration
+}
+
+// Matches returns true if the given call metadata contain all the metadata of the matchers
+func (m MatchedAlterations) Matches(md metadata.MD) bool {
+	for key, value := range m.Metadata {
+		matched := false
+
+		for _, mdValue := range md.Get(key) {
+			if mdValue == value {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AllAlterations returns the alterations applying to the endpoint calls, whatever their metadata
+func (e EndpointConfiguration) AllAlterations() []AlterationConfiguration {
+	alterations := append([]AlterationConfiguration{}, e.Alterations...)
+
+	for _, matched := range e.MatchedAlterations {
+		alterations = append(alterations, matched.Alterations...)
+	}
+
+	return alterations
 }
 
-// AlterationConfiguration contains either an ErrorToReturn or an OverrideToReturn for a given
-// gRPC query to the disrupted service.
+// AlterationConfiguration contains either an ErrorToReturn or an OverrideToReturn and an optional Latency
+// for a given gRPC query to the disrupted service.
 type AlterationConfiguration struct {
-	ErrorToReturn    string
-	OverrideToReturn string
+	ErrorToReturn     string
+	OverrideToReturn  string
+	Latency           time.Duration
+	LatencyJitter     int // percentage of the latency
+	FailAfterMessages int // number of messages sent by the server before a stream fails with the ErrorToReturn
 }
 
 // QueryPercent is an integer representing the percentage odds that a query for an endpoint is affected by a certain alteration.
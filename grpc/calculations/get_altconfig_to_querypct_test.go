@@ -111,7 +111,7 @@ var _ = Describe("get mapping from alterationSpecs to config based on GetPercent
 
 			By("returning an InvalidArgument error", func() {
 				_, err := GetPercentagePerAlteration(alterationSpecs)
-				Expect(err.Error()).To(Equal("rpc error: code = InvalidArgument desc = cannot map alteration to assigned query percentage without specifying either ErrorToReturn or OverrideToReturn for a target endpoint"))
+				Expect(err.Error()).To(Equal("rpc error: code = InvalidArgument desc = cannot map alteration to assigned query percentage without specifying either ErrorToReturn, OverrideToReturn or Latency for a target endpoint"))
 			})
 		})
 	})
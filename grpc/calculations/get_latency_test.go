@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package calculations_test
+
+import (
+	"time"
+
+	. "github.com/DataDog/chaos-controller/grpc/calculations"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var _ = Describe("latency alterations", func() {
+	Context("with a latency only alteration", func() {
+		It("should map the alteration to its query percentage", func() {
+			config, err := GetPercentagePerAlteration([]*pb.AlterationSpec{
+				{
+					Latency:       durationpb.New(2 * time.Second),
+					LatencyJitter: 10,
+					QueryPercent:  30,
+				},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(config).To(Equal(map[AlterationConfiguration]QueryPercent{
+				{Latency: 2 * time.Second, LatencyJitter: 10}: 30,
+			}))
+		})
+	})
+
+	Context("with an invalid latency jitter", func() {
+		It("should fail", func() {
+			_, err := GetPercentagePerAlteration([]*pb.AlterationSpec{
+				{
+					Latency:       durationpb.New(2 * time.Second),
+					LatencyJitter: 150,
+				},
+			})
+
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with a number of messages to send before failing without an error", func() {
+		It("should fail", func() {
+			_, err := GetPercentagePerAlteration([]*pb.AlterationSpec{
+				{
+					Latency:           durationpb.New(2 * time.Second),
+					FailAfterMessages: 2,
+				},
+			})
+
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("GetLatency", func() {
+		It("should return the latency without jitter", func() {
+			Expect(GetLatency(AlterationConfiguration{Latency: time.Second})).To(Equal(time.Second))
+		})
+
+		It("should keep the jittered latency within the jitter bounds", func() {
+			for i := 0; i < 100; i++ {
+				latency := GetLatency(AlterationConfiguration{Latency: time.Second, LatencyJitter: 10})
+
+				Expect(latency).To(BeNumerically(">=", 900*time.Millisecond))
+				Expect(latency).To(BeNumerically("<=", 1100*time.Millisecond))
+			}
+		})
+
+		It("should not add any latency to other alterations", func() {
+			Expect(GetLatency(AlterationConfiguration{ErrorToReturn: "CANCELED"})).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package calculations_test
+
+import (
+	. "github.com/DataDog/chaos-controller/grpc/calculations"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/metadata"
+)
+
+var _ = Describe("metadata matchers", func() {
+	Describe("ConvertEndpointSpecifications", func() {
+		It("should convert the alterations of each metadata matchers separately", func() {
+			alterations, matched, err := ConvertEndpointSpecifications([]*pb.AlterationSpec{
+				{
+					ErrorToReturn: "UNAVAILABLE",
+					QueryPercent:  100,
+					Metadata:      map[string]string{"x-tenant": "test"},
+				},
+				{
+					ErrorToReturn: "NOT_FOUND",
+					QueryPercent:  20,
+				},
+				{
+					ErrorToReturn: "INTERNAL",
+					QueryPercent:  100,
+					Metadata:      map[string]string{":authority": "orders"},
+				},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(alterations).To(HaveLen(20))
+			Expect(alterations[0].ErrorToReturn).To(Equal("NOT_FOUND"))
+
+			Expect(matched).To(HaveLen(2))
+			Expect(matched[0].Metadata).To(Equal(map[string]string{"x-tenant": "test"}))
+			Expect(matched[0].Alterations).To(HaveLen(100))
+			Expect(matched[0].Alterations[0].ErrorToReturn).To(Equal("UNAVAILABLE"))
+			Expect(matched[1].Metadata).To(Equal(map[string]string{":authority": "orders"}))
+			Expect(matched[1].Alterations[0].ErrorToReturn).To(Equal("INTERNAL"))
+		})
+
+		It("should fail when the alterations of the same metadata matchers are over 100 percent", func() {
+			_, _, err := ConvertEndpointSpecifications([]*pb.AlterationSpec{
+				{
+					ErrorToReturn: "UNAVAILABLE",
+					QueryPercent:  60,
+					Metadata:      map[string]string{"x-tenant": "test"},
+				},
+				{
+					ErrorToReturn: "NOT_FOUND",
+					QueryPercent:  60,
+					Metadata:      map[string]string{"x-tenant": "test"},
+				},
+			})
+
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("MatchedAlterations.Matches", func() {
+		matched := MatchedAlterations{
+			Metadata: map[string]string{"x-tenant": "test", ":authority": "orders"},
+		}
+
+		It("should match metadata containing all the matchers", func() {
+			Expect(matched.Matches(metadata.Pairs("x-tenant", "prod", "X-Tenant", "test", ":authority", "orders", "user-agent", "grpc-go"))).To(BeTrue())
+		})
+
+		It("should not match metadata missing a matcher", func() {
+			Expect(matched.Matches(metadata.Pairs("x-tenant", "test"))).To(BeFalse())
+			Expect(matched.Matches(nil)).To(BeFalse())
+		})
+	})
+})
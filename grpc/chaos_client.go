@@ -7,10 +7,14 @@ package grpc
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	grpccalc "github.com/DataDog/chaos-controller/grpc/calculations"
 	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -37,6 +41,41 @@ func ClearGrpcDisruptions(client pb.DisruptionListenerClient) error {
 	return err
 }
 
+// GetGrpcDisruptionStatus executes a GetStatus call on the provided DisruptionListenerClient
+func GetGrpcDisruptionStatus(client pb.DisruptionListenerClient) (*pb.DisruptionStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return client.GetStatus(ctx, &emptypb.Empty{})
+}
+
+// DescribeAlteration returns a short human readable description of the given alteration, e.g. error:UNAVAILABLE,latency:1s
+func DescribeAlteration(altSpec *pb.AlterationSpec) string {
+	parts := []string{}
+
+	if altSpec.ErrorToReturn != "" {
+		parts = append(parts, "error:"+altSpec.ErrorToReturn)
+	}
+
+	if altSpec.OverrideToReturn != "" {
+		parts = append(parts, "override:"+altSpec.OverrideToReturn)
+	}
+
+	if latency := altSpec.Latency.AsDuration(); latency > 0 {
+		parts = append(parts, "latency:"+latency.String())
+	}
+
+	if altSpec.FailAfterMessages > 0 {
+		parts = append(parts, "failAfterMessages:"+strconv.Itoa(int(altSpec.FailAfterMessages)))
+	}
+
+	if len(altSpec.Metadata) > 0 {
+		parts = append(parts, "metadata:"+grpccalc.MetadataKey(altSpec.Metadata))
+	}
+
+	return strings.Join(parts, ",")
+}
+
 // GenerateEndpointSpecs converts a slice of EndpointAlterations into a slice of EndpointSpecs which
 // can be sent through gRPC call to disruptionListener
 func GenerateEndpointSpecs(endpoints []chaosv1beta1.EndpointAlteration) []*pb.EndpointSpec {
@@ -46,21 +85,12 @@ func GenerateEndpointSpecs(endpoints []chaosv1beta1.EndpointAlteration) []*pb.En
 		targeted := endptAlt.TargetEndpoint
 
 		if existingEndptSpec, ok := targetToEndpointSpec[targeted]; ok {
-			altSpec := &pb.AlterationSpec{
-				ErrorToReturn:    endptAlt.ErrorToReturn,
-				OverrideToReturn: endptAlt.OverrideToReturn,
-				QueryPercent:     int32(endptAlt.QueryPercent),
-			}
-			existingEndptSpec.Alterations = append(existingEndptSpec.Alterations, altSpec)
+			existingEndptSpec.Alterations = append(existingEndptSpec.Alterations, generateAlterationSpec(endptAlt))
 		} else {
 			targetToEndpointSpec[targeted] = &pb.EndpointSpec{
 				TargetEndpoint: targeted,
 				Alterations: []*pb.AlterationSpec{
-					{
-						ErrorToReturn:    endptAlt.ErrorToReturn,
-						OverrideToReturn: endptAlt.OverrideToReturn,
-						QueryPercent:     int32(endptAlt.QueryPercent),
-					},
+					generateAlterationSpec(endptAlt),
 				},
 			}
 		}
@@ -73,3 +103,21 @@ func GenerateEndpointSpecs(endpoints []chaosv1beta1.EndpointAlteration) []*pb.En
 
 	return endpointSpecs
 }
+
+// generateAlterationSpec converts an EndpointAlteration into an AlterationSpec
+func generateAlterationSpec(endptAlt chaosv1beta1.EndpointAlteration) *pb.AlterationSpec {
+	altSpec := &pb.AlterationSpec{
+		ErrorToReturn:     endptAlt.ErrorToReturn,
+		OverrideToReturn:  endptAlt.OverrideToReturn,
+		QueryPercent:      int32(endptAlt.QueryPercent),
+		FailAfterMessages: int32(endptAlt.FailAfterMessages),
+		Metadata:          endptAlt.Metadata,
+	}
+
+	if endptAlt.Latency.Duration() > 0 {
+		altSpec.Latency = durationpb.New(endptAlt.Latency.Duration())
+		altSpec.LatencyJitter = int32(endptAlt.LatencyJitter)
+	}
+
+	return altSpec
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	v1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
 	grpccalc "github.com/DataDog/chaos-controller/grpc/calculations"
@@ -17,7 +19,9 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -25,9 +29,11 @@ import (
 // The interface it is implementing was generated in the grpc/disruptionlistener package.
 type ChaosDisruptionListener struct {
 	pb.UnimplementedDisruptionListenerServer
-	configuration grpccalc.DisruptionConfiguration
-	mutex         sync.Mutex
-	logger        *zap.SugaredLogger
+	configuration       grpccalc.DisruptionConfiguration
+	mutex               sync.Mutex
+	logger              *zap.SugaredLogger
+	serviceInfoProvider ServiceInfoProvider
+	status              *disruptionStatus
 }
 
 // NewDisruptionListener creates a new DisruptionListener Service with the logger instantiated and DisruptionConfiguration set to be empty
@@ -55,7 +61,7 @@ func (d *ChaosDisruptionListener) Disrupt(ctx context.Context, ds *pb.Disruption
 			return nil, status.Error(codes.InvalidArgument, "Cannot execute Disrupt without specifying TargetEndpoint for all endpointAlterations")
 		}
 
-		Alterations, err := grpccalc.ConvertSpecifications(endpointSpec.Alterations)
+		Alterations, MatchedAlterations, err := grpccalc.ConvertEndpointSpecifications(endpointSpec.Alterations)
 		if err != nil {
 			return nil, err
 		}
@@ -63,10 +69,19 @@ func (d *ChaosDisruptionListener) Disrupt(ctx context.Context, ds *pb.Disruption
 		// add endpoint to main configuration
 		targetEndpoint := grpccalc.TargetEndpoint(endpointSpec.TargetEndpoint)
 
-		config[targetEndpoint] = grpccalc.EndpointConfiguration{
-			TargetEndpoint: targetEndpoint,
-			Alterations:    Alterations,
+		endptConfig := grpccalc.EndpointConfiguration{
+			TargetEndpoint:     targetEndpoint,
+			Alterations:        Alterations,
+			MatchedAlterations: MatchedAlterations,
 		}
+
+		endptConfig.Overrides, err = d.resolveOverrides(targetEndpoint, endptConfig.AllAlterations())
+		if err != nil {
+			d.logger.Errorw("cannot apply new DisruptionSpec with an invalid override", "error", err)
+			return nil, err
+		}
+
+		config[targetEndpoint] = endptConfig
 	}
 
 	if len(d.configuration) > 0 {
@@ -81,6 +96,7 @@ func (d *ChaosDisruptionListener) Disrupt(ctx context.Context, ds *pb.Disruption
 		d.logger.Error("cannot apply new DisruptionSpec, gRPC request was canceled")
 	default:
 		d.configuration = config
+		d.status = newDisruptionStatus(ds)
 	}
 
 	d.mutex.Unlock()
@@ -92,6 +108,7 @@ func (d *ChaosDisruptionListener) Disrupt(ctx context.Context, ds *pb.Disruption
 func (d *ChaosDisruptionListener) ResetDisruptions(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	d.mutex.Lock()
 	d.configuration = map[grpccalc.TargetEndpoint]grpccalc.EndpointConfiguration{}
+	d.status = nil
 	d.mutex.Unlock()
 
 	return &emptypb.Empty{}, nil
@@ -101,34 +118,192 @@ func (d *ChaosDisruptionListener) ResetDisruptions(context.Context, *emptypb.Emp
 // to intercept all traffic to the server and crosscheck their endpoints to disrupt them.
 func (d *ChaosDisruptionListener) ChaosServerInterceptor(ctx context.Context, req interface{},
 	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
-	d.logger.Debug("comparing with %s with %d endpoints", info.FullMethod, len(d.configuration))
+	md, _ := metadata.FromIncomingContext(ctx)
 
-	// FullMethod is the full RPC method string, i.e., /package.service/method.
-	targetEndpoint := grpccalc.TargetEndpoint(info.FullMethod)
+	endptConfig, altConfig, ok := d.pickAlteration(md, info.FullMethod)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	if latency := grpccalc.GetLatency(altConfig); latency > 0 {
+		d.logger.Debugw("adding latency", "latency", latency)
+
+		if err := waitLatency(ctx, latency); err != nil {
+			return nil, err
+		}
+	}
 
-	if endptConfig, ok := d.configuration[targetEndpoint]; ok {
-		randomPercent := rand.Intn(100)
+	if altConfig.ErrorToReturn != "" {
+		d.logger.Debugw("returning error code", "code", v1beta1.ErrorMap[altConfig.ErrorToReturn])
 
-		if len(endptConfig.Alterations) > randomPercent {
-			altConfig := endptConfig.Alterations[randomPercent]
+		return nil, injectedError(altConfig)
+	} else if altConfig.OverrideToReturn != "" {
+		d.logger.Debugw("returning override", "override", altConfig.OverrideToReturn)
 
-			if altConfig.ErrorToReturn != "" {
-				d.logger.Debug("error code to return: %s", v1beta1.ErrorMap[altConfig.ErrorToReturn])
+		return overrideResponse(endptConfig, altConfig), nil
+	} else if altConfig.Latency == 0 {
+		d.logger.Errorw("endpoint should define either an ErrorToReturn, OverrideToReturn or Latency but does not", "endpoint", info.FullMethod)
+	}
 
-				return nil, status.Error(
-					v1beta1.ErrorMap[altConfig.ErrorToReturn],
-					// Future Work: interview users about this message //nolint:golint
-					fmt.Sprintf("Chaos Controller injected this error: %s", altConfig.ErrorToReturn),
-				)
-			} else if altConfig.OverrideToReturn != "" {
-				d.logger.Debug("override to return: %s", altConfig.OverrideToReturn)
+	return handler(ctx, req)
+}
+
+// ChaosStreamServerInterceptor is a function which can be registered on instantiation of a gRPC server
+// to intercept all streams opened on the server and crosscheck their endpoints to disrupt them.
+// A stream can fail when opened, fail once the server sent a number of messages, or have the delivery of its messages delayed.
+func (d *ChaosDisruptionListener) ChaosStreamServerInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	md, _ := metadata.FromIncomingContext(ss.Context())
+
+	endptConfig, altConfig, ok := d.pickAlteration(md, info.FullMethod)
+	if !ok {
+		return handler(srv, ss)
+	}
 
-				return &emptypb.Empty{}, nil
+	switch {
+	case altConfig.ErrorToReturn != "" && altConfig.FailAfterMessages == 0:
+		if latency := grpccalc.GetLatency(altConfig); latency > 0 {
+			if err := waitLatency(ss.Context(), latency); err != nil {
+				return err
 			}
+		}
+
+		d.logger.Debugw("returning error code on stream open", "code", v1beta1.ErrorMap[altConfig.ErrorToReturn])
 
-			d.logger.Error("endpoint %s should define either an ErrorToReturn or OverrideToReturn but does not", endptConfig.TargetEndpoint)
+		return injectedError(altConfig)
+	case altConfig.OverrideToReturn != "":
+		if latency := grpccalc.GetLatency(altConfig); latency > 0 {
+			if err := waitLatency(ss.Context(), latency); err != nil {
+				return err
+			}
 		}
+
+		d.logger.Debugw("returning override", "override", altConfig.OverrideToReturn)
+
+		// the override is the only message sent before closing the stream
+		return ss.SendMsg(overrideResponse(endptConfig, altConfig))
 	}
 
-	return handler(ctx, req)
+	return handler(srv, &disruptedServerStream{
+		ServerStream: ss,
+		alteration:   altConfig,
+	})
+}
+
+// pickAlteration randomly picks the alteration to apply to a query to the given endpoint according to the configured percentages
+// of the first alterations whose metadata matchers match the given query metadata, or of the alterations without matchers,
+// it returns the endpoint configuration along with the alteration, and false if the query must not be altered;
+// the query and its alteration are counted in the status of the disruption
+func (d *ChaosDisruptionListener) pickAlteration(md metadata.MD, fullMethod string) (grpccalc.EndpointConfiguration, grpccalc.AlterationConfiguration, bool) {
+	// the configuration and the status are replaced as a whole under the lock when the disruption is applied or reset,
+	// the ones read here are used for the whole query
+	d.mutex.Lock()
+	configuration, disruptionStatus := d.configuration, d.status
+	d.mutex.Unlock()
+
+	d.logger.Debugw("comparing with the disrupted endpoints", "endpoint", fullMethod, "endpoints", len(configuration))
+
+	// FullMethod is the full RPC method string, i.e., /package.service/method.
+	targetEndpoint := grpccalc.TargetEndpoint(fullMethod)
+
+	endptConfig, ok := configuration[targetEndpoint]
+	if !ok {
+		return grpccalc.EndpointConfiguration{}, grpccalc.AlterationConfiguration{}, false
+	}
+
+	disruptionStatus.countCall(targetEndpoint)
+
+	alterations := endptConfig.Alterations
+	metadataKey := ""
+
+	for _, matched := range endptConfig.MatchedAlterations {
+		if matched.Matches(md) {
+			alterations = matched.Alterations
+			metadataKey = grpccalc.MetadataKey(matched.Metadata)
+
+			break
+		}
+	}
+
+	randomPercent := rand.Intn(100)
+
+	if len(alterations) <= randomPercent {
+		return grpccalc.EndpointConfiguration{}, grpccalc.AlterationConfiguration{}, false
+	}
+
+	disruptionStatus.countAlteration(alterationKey{
+		endpoint:   targetEndpoint,
+		metadata:   metadataKey,
+		alteration: alterations[randomPercent],
+	})
+
+	return endptConfig, alterations[randomPercent], true
+}
+
+// overrideResponse returns the response resolved for the override of the given alteration
+func overrideResponse(endptConfig grpccalc.EndpointConfiguration, altConfig grpccalc.AlterationConfiguration) proto.Message {
+	if response, ok := endptConfig.Overrides[altConfig.OverrideToReturn]; ok {
+		return response
+	}
+
+	// overrides are resolved when the disruption is applied, an empty message is valid for any response type
+	return &emptypb.Empty{}
+}
+
+// injectedError returns the gRPC error of the given alteration
+func injectedError(altConfig grpccalc.AlterationConfiguration) error {
+	return status.Error(
+		v1beta1.ErrorMap[altConfig.ErrorToReturn],
+		// Future Work: interview users about this message //nolint:golint
+		fmt.Sprintf("Chaos Controller injected this error: %s", altConfig.ErrorToReturn),
+	)
+}
+
+// disruptedServerStream is a server stream delaying the messages it sends by the alteration latency
+// and failing with the alteration error once it sent the alteration number of messages
+type disruptedServerStream struct {
+	grpc.ServerStream
+	alteration grpccalc.AlterationConfiguration
+	sent       int32
+}
+
+func (s *disruptedServerStream) SendMsg(m interface{}) error {
+	if s.failed(atomic.AddInt32(&s.sent, 1) - 1) {
+		return injectedError(s.alteration)
+	}
+
+	if latency := grpccalc.GetLatency(s.alteration); latency > 0 {
+		if err := waitLatency(s.Context(), latency); err != nil {
+			return err
+		}
+	}
+
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *disruptedServerStream) RecvMsg(m interface{}) error {
+	if s.failed(atomic.LoadInt32(&s.sent)) {
+		return injectedError(s.alteration)
+	}
+
+	return s.ServerStream.RecvMsg(m)
+}
+
+// failed returns true if the stream must fail after sending the given number of messages
+func (s *disruptedServerStream) failed(sent int32) bool {
+	return s.alteration.ErrorToReturn != "" && int(sent) >= s.alteration.FailAfterMessages
+}
+
+// waitLatency waits for the given latency, or until the given context is done in which case
+// the context error is returned as a gRPC status error (e.g. DEADLINE_EXCEEDED when the caller deadline is reached)
+func waitLatency(ctx context.Context, latency time.Duration) error {
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
 }
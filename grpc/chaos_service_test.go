@@ -0,0 +1,317 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc_test
+
+import (
+	"context"
+	"time"
+
+	chaosgrpc "github.com/DataDog/chaos-controller/grpc"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var _ = Describe("ChaosServerInterceptor", func() {
+	const endpoint = "/chaosdogfood.ChaosDogfood/order"
+
+	var (
+		listener      *chaosgrpc.ChaosDisruptionListener
+		handlerCalled bool
+		alteration    *pb.AlterationSpec
+	)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerCalled = true
+
+		return "computed response", nil
+	}
+
+	intercept := func(ctx context.Context) (interface{}, error) {
+		return listener.ChaosServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+	}
+
+	BeforeEach(func() {
+		handlerCalled = false
+		alteration = &pb.AlterationSpec{
+			Latency:      durationpb.New(200 * time.Millisecond),
+			QueryPercent: 100,
+		}
+	})
+
+	JustBeforeEach(func() {
+		listener = chaosgrpc.NewDisruptionListener(zap.NewNop().Sugar())
+
+		_, err := listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+			Endpoints: []*pb.EndpointSpec{
+				{
+					TargetEndpoint: endpoint,
+					Alterations:    []*pb.AlterationSpec{alteration},
+				},
+			},
+		})
+		Expect(err).To(BeNil())
+	})
+
+	It("should delay the call before calling the handler", func() {
+		start := time.Now()
+		resp, err := intercept(context.Background())
+
+		Expect(err).To(BeNil())
+		Expect(resp).To(Equal("computed response"))
+		Expect(handlerCalled).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+	})
+
+	Context("combined with an error", func() {
+		BeforeEach(func() {
+			alteration.ErrorToReturn = "UNAVAILABLE"
+		})
+
+		It("should delay the call before returning the error", func() {
+			start := time.Now()
+			_, err := intercept(context.Background())
+
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Expect(handlerCalled).To(BeFalse())
+			Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+		})
+	})
+
+	Context("while the disruption is reset", func() {
+		BeforeEach(func() {
+			alteration.Latency = nil
+			alteration.ErrorToReturn = "UNAVAILABLE"
+		})
+
+		It("should either alter the calls or call the handler", func() {
+			done := make(chan struct{})
+
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+
+				for i := 0; i < 100; i++ {
+					resp, err := listener.ChaosServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: endpoint},
+						func(ctx context.Context, req interface{}) (interface{}, error) {
+							return "computed response", nil
+						})
+					if err != nil {
+						Expect(status.Code(err)).To(Equal(codes.Unavailable))
+					} else {
+						Expect(resp).To(Equal("computed response"))
+					}
+				}
+			}()
+
+			_, err := listener.ResetDisruptions(context.Background(), &emptypb.Empty{})
+			Expect(err).To(BeNil())
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	Context("with a caller deadline shorter than the latency", func() {
+		It("should return once the deadline is exceeded", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := intercept(ctx)
+
+			Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+			Expect(handlerCalled).To(BeFalse())
+			Expect(time.Since(start)).To(BeNumerically("<", 200*time.Millisecond))
+		})
+	})
+})
+
+// fakeServerStream is a server stream recording the messages sent through it
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []interface{}
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+
+	return nil
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	return nil
+}
+
+var _ = Describe("ChaosStreamServerInterceptor", func() {
+	const endpoint = "/chaosdogfood.ChaosDogfood/streamCatalog"
+
+	var (
+		listener      *chaosgrpc.ChaosDisruptionListener
+		stream        *fakeServerStream
+		handlerCalled bool
+		handlerErr    error
+		alteration    *pb.AlterationSpec
+	)
+
+	// handler sends three messages, stopping at the first error
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		handlerCalled = true
+
+		for i := 0; i < 3; i++ {
+			if handlerErr = ss.SendMsg(i); handlerErr != nil {
+				return handlerErr
+			}
+		}
+
+		return nil
+	}
+
+	intercept := func() error {
+		return listener.ChaosStreamServerInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: endpoint, IsServerStream: true}, handler)
+	}
+
+	BeforeEach(func() {
+		handlerCalled = false
+		handlerErr = nil
+		stream = &fakeServerStream{ctx: context.Background()}
+		alteration = &pb.AlterationSpec{
+			ErrorToReturn: "UNAVAILABLE",
+			QueryPercent:  100,
+		}
+	})
+
+	JustBeforeEach(func() {
+		listener = chaosgrpc.NewDisruptionListener(zap.NewNop().Sugar())
+
+		_, err := listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+			Endpoints: []*pb.EndpointSpec{
+				{
+					TargetEndpoint: endpoint,
+					Alterations:    []*pb.AlterationSpec{alteration},
+				},
+			},
+		})
+		Expect(err).To(BeNil())
+	})
+
+	It("should fail the stream when opened", func() {
+		err := intercept()
+
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		Expect(handlerCalled).To(BeFalse())
+		Expect(stream.sent).To(BeEmpty())
+	})
+
+	Context("with a number of messages to send before failing", func() {
+		BeforeEach(func() {
+			alteration.FailAfterMessages = 2
+		})
+
+		It("should fail the stream once the messages are sent", func() {
+			err := intercept()
+
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Expect(handlerCalled).To(BeTrue())
+			Expect(status.Code(handlerErr)).To(Equal(codes.Unavailable))
+			Expect(stream.sent).To(Equal([]interface{}{0, 1}))
+		})
+	})
+
+	Context("with a latency only alteration", func() {
+		BeforeEach(func() {
+			alteration.ErrorToReturn = ""
+			alteration.Latency = durationpb.New(100 * time.Millisecond)
+		})
+
+		It("should delay every message sent", func() {
+			start := time.Now()
+			err := intercept()
+
+			Expect(err).To(BeNil())
+			Expect(stream.sent).To(Equal([]interface{}{0, 1, 2}))
+			Expect(time.Since(start)).To(BeNumerically(">=", 300*time.Millisecond))
+		})
+	})
+
+	Context("with an endpoint which is not disrupted", func() {
+		It("should call the handler with the original stream", func() {
+			err := listener.ChaosStreamServerInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/chaosdogfood.ChaosDogfood/getCatalog"}, handler)
+
+			Expect(err).To(BeNil())
+			Expect(stream.sent).To(Equal([]interface{}{0, 1, 2}))
+		})
+	})
+})
+
+var _ = Describe("Metadata matchers", func() {
+	const endpoint = "/chaosdogfood.ChaosDogfood/order"
+
+	var listener *chaosgrpc.ChaosDisruptionListener
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "computed response", nil
+	}
+
+	intercept := func(md metadata.MD) (interface{}, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+
+		return listener.ChaosServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+	}
+
+	BeforeEach(func() {
+		listener = chaosgrpc.NewDisruptionListener(zap.NewNop().Sugar())
+
+		_, err := listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+			Endpoints: []*pb.EndpointSpec{
+				{
+					TargetEndpoint: endpoint,
+					Alterations: []*pb.AlterationSpec{
+						{
+							ErrorToReturn: "UNAVAILABLE",
+							QueryPercent:  100,
+							Metadata:      map[string]string{"x-tenant": "test"},
+						},
+						{
+							ErrorToReturn: "NOT_FOUND",
+							QueryPercent:  100,
+							Metadata:      map[string]string{":authority": "orders.test"},
+						},
+					},
+				},
+			},
+		})
+		Expect(err).To(BeNil())
+	})
+
+	It("should alter the calls carrying the matching metadata", func() {
+		_, err := intercept(metadata.Pairs("x-tenant", "test"))
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+
+		_, err = intercept(metadata.Pairs(":authority", "orders.test"))
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("should not alter the other calls", func() {
+		resp, err := intercept(metadata.Pairs("x-tenant", "prod", ":authority", "orders.prod"))
+		Expect(err).To(BeNil())
+		Expect(resp).To(Equal("computed response"))
+
+		resp, err = listener.ChaosServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+		Expect(err).To(BeNil())
+		Expect(resp).To(Equal("computed response"))
+	})
+})
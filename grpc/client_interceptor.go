@@ -0,0 +1,203 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	v1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
+	grpccalc "github.com/DataDog/chaos-controller/grpc/calculations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ChaosClientInterceptor is a function which can be registered on instantiation of a gRPC client connection
+// to intercept all outbound calls of the client and crosscheck their endpoints to disrupt them.
+// The alterations are applied before the call is sent, and a disrupted call never reaches the server when it returns an error or an override.
+func (d *ChaosDisruptionListener) ChaosClientInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	endptConfig, altConfig, ok := d.pickAlteration(md, method)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	if latency := grpccalc.GetLatency(altConfig); latency > 0 {
+		d.logger.Debugw("adding latency", "latency", latency)
+
+		if err := waitLatency(ctx, latency); err != nil {
+			return err
+		}
+	}
+
+	if altConfig.ErrorToReturn != "" {
+		d.logger.Debugw("returning error code", "code", v1beta1.ErrorMap[altConfig.ErrorToReturn])
+
+		return injectedError(altConfig)
+	} else if altConfig.OverrideToReturn != "" {
+		d.logger.Debugw("returning override", "override", altConfig.OverrideToReturn)
+
+		return copyOverride(overrideResponse(endptConfig, altConfig), reply)
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// ChaosStreamClientInterceptor is a function which can be registered on instantiation of a gRPC client connection
+// to intercept all streams opened by the client and crosscheck their endpoints to disrupt them.
+// A stream can fail when opened, fail once the client received a number of messages, or have the delivery of its messages delayed.
+func (d *ChaosDisruptionListener) ChaosStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	endptConfig, altConfig, ok := d.pickAlteration(md, method)
+	if !ok {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	switch {
+	case altConfig.ErrorToReturn != "" && altConfig.FailAfterMessages == 0:
+		if latency := grpccalc.GetLatency(altConfig); latency > 0 {
+			if err := waitLatency(ctx, latency); err != nil {
+				return nil, err
+			}
+		}
+
+		d.logger.Debugw("returning error code on stream open", "code", v1beta1.ErrorMap[altConfig.ErrorToReturn])
+
+		return nil, injectedError(altConfig)
+	case altConfig.OverrideToReturn != "":
+		d.logger.Debugw("returning override", "override", altConfig.OverrideToReturn)
+
+		// the stream is not opened, the override is the only message received by the client
+		return &overriddenClientStream{
+			ctx:        ctx,
+			alteration: altConfig,
+			override:   overrideResponse(endptConfig, altConfig),
+		}, nil
+	}
+
+	// the stream is canceled when it fails so the server stops sending messages
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &disruptedClientStream{
+		ClientStream: stream,
+		alteration:   altConfig,
+		cancel:       cancel,
+	}, nil
+}
+
+// copyOverride copies the given override response into the given reply
+func copyOverride(override proto.Message, reply interface{}) error {
+	replyMessage, ok := reply.(proto.Message)
+	if !ok {
+		return status.Error(codes.Internal, fmt.Sprintf("Chaos Controller cannot override a %T response", reply))
+	}
+
+	// the override is marshaled since its type may differ from the reply one, e.g. an empty message
+	b, err := proto.Marshal(override)
+	if err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("Chaos Controller cannot marshal the override: %s", err))
+	}
+
+	if err := proto.Unmarshal(b, replyMessage); err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("Chaos Controller cannot unmarshal the override into a %T response: %s", reply, err))
+	}
+
+	return nil
+}
+
+// disruptedClientStream is a client stream delaying the messages it receives by the alteration latency
+// and failing with the alteration error once it received the alteration number of messages
+type disruptedClientStream struct {
+	grpc.ClientStream
+	alteration grpccalc.AlterationConfiguration
+	cancel     context.CancelFunc
+	received   int
+}
+
+func (s *disruptedClientStream) RecvMsg(m interface{}) error {
+	if s.alteration.ErrorToReturn != "" && s.received >= s.alteration.FailAfterMessages {
+		s.cancel()
+
+		return injectedError(s.alteration)
+	}
+
+	if latency := grpccalc.GetLatency(s.alteration); latency > 0 {
+		if err := waitLatency(s.Context(), latency); err != nil {
+			s.cancel()
+
+			return err
+		}
+	}
+
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		s.cancel()
+
+		return err
+	}
+
+	s.received++
+
+	return nil
+}
+
+// overriddenClientStream is a client stream which is never opened, it discards the messages sent by the client
+// and the client receives the override as the only message of the stream
+type overriddenClientStream struct {
+	ctx        context.Context
+	alteration grpccalc.AlterationConfiguration
+	override   proto.Message
+	received   bool
+}
+
+func (s *overriddenClientStream) Header() (metadata.MD, error) {
+	return metadata.MD{}, nil
+}
+
+func (s *overriddenClientStream) Trailer() metadata.MD {
+	return metadata.MD{}
+}
+
+func (s *overriddenClientStream) CloseSend() error {
+	return nil
+}
+
+func (s *overriddenClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *overriddenClientStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func (s *overriddenClientStream) RecvMsg(m interface{}) error {
+	if s.received {
+		return io.EOF
+	}
+
+	if latency := grpccalc.GetLatency(s.alteration); latency > 0 {
+		if err := waitLatency(s.ctx, latency); err != nil {
+			return err
+		}
+	}
+
+	s.received = true
+
+	return copyOverride(s.override, m)
+}
@@ -0,0 +1,209 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	df_pb "github.com/DataDog/chaos-controller/dogfood/chaosdogfood"
+	chaosgrpc "github.com/DataDog/chaos-controller/grpc"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClientStream is a client stream receiving the given catalog items
+type fakeClientStream struct {
+	grpc.ClientStream
+	ctx   context.Context
+	items []*df_pb.CatalogItem
+}
+
+func (s *fakeClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	if len(s.items) == 0 {
+		return io.EOF
+	}
+
+	m.(*df_pb.CatalogItem).Animal = s.items[0].Animal
+	s.items = s.items[1:]
+
+	return nil
+}
+
+var _ = Describe("Client interceptors", func() {
+	var (
+		listener   *chaosgrpc.ChaosDisruptionListener
+		endpoint   string
+		alteration *pb.AlterationSpec
+	)
+
+	BeforeEach(func() {
+		alteration = &pb.AlterationSpec{
+			ErrorToReturn: "UNAVAILABLE",
+			QueryPercent:  100,
+		}
+	})
+
+	JustBeforeEach(func() {
+		listener = chaosgrpc.NewDisruptionListener(zap.NewNop().Sugar())
+
+		_, err := listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+			Endpoints: []*pb.EndpointSpec{
+				{
+					TargetEndpoint: endpoint,
+					Alterations:    []*pb.AlterationSpec{alteration},
+				},
+			},
+		})
+		Expect(err).To(BeNil())
+	})
+
+	Describe("ChaosClientInterceptor", func() {
+		var (
+			invokerCalled bool
+			reply         *df_pb.FoodReply
+		)
+
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			invokerCalled = true
+
+			return nil
+		}
+
+		intercept := func(ctx context.Context) error {
+			return listener.ChaosClientInterceptor(ctx, endpoint, &df_pb.FoodRequest{Animal: "cat"}, reply, nil, invoker)
+		}
+
+		BeforeEach(func() {
+			endpoint = "/chaosdogfood.ChaosDogfood/order"
+			invokerCalled = false
+			reply = &df_pb.FoodReply{}
+		})
+
+		It("should return the error without sending the call", func() {
+			Expect(status.Code(intercept(context.Background()))).To(Equal(codes.Unavailable))
+			Expect(invokerCalled).To(BeFalse())
+		})
+
+		Context("with an override", func() {
+			BeforeEach(func() {
+				alteration.ErrorToReturn = ""
+				alteration.OverrideToReturn = `{"message": "overridden", "confirmationId": 42}`
+			})
+
+			It("should copy the override into the reply without sending the call", func() {
+				Expect(intercept(context.Background())).To(BeNil())
+				Expect(invokerCalled).To(BeFalse())
+				Expect(reply.Message).To(Equal("overridden"))
+				Expect(reply.ConfirmationId).To(Equal(int32(42)))
+			})
+		})
+
+		Context("with metadata matchers", func() {
+			BeforeEach(func() {
+				alteration.Metadata = map[string]string{"x-tenant": "test"}
+			})
+
+			It("should only alter the calls carrying the matching outgoing metadata", func() {
+				Expect(intercept(context.Background())).To(BeNil())
+				Expect(invokerCalled).To(BeTrue())
+
+				ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "test")
+				Expect(status.Code(intercept(ctx))).To(Equal(codes.Unavailable))
+			})
+		})
+	})
+
+	Describe("ChaosStreamClientInterceptor", func() {
+		var streamerCalled bool
+
+		streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			streamerCalled = true
+
+			return &fakeClientStream{
+				ctx:   ctx,
+				items: []*df_pb.CatalogItem{{Animal: "cat"}, {Animal: "dog"}, {Animal: "cow"}},
+			}, nil
+		}
+
+		// receive returns the animals received before the stream ended, and the error it ended with if any
+		receive := func(stream grpc.ClientStream) ([]string, error) {
+			animals := []string{}
+
+			for {
+				item := &df_pb.CatalogItem{}
+				if err := stream.RecvMsg(item); err != nil {
+					if errors.Is(err, io.EOF) {
+						return animals, nil
+					}
+
+					return animals, err
+				}
+
+				animals = append(animals, item.Animal)
+			}
+		}
+
+		intercept := func() (grpc.ClientStream, error) {
+			return listener.ChaosStreamClientInterceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, endpoint, streamer)
+		}
+
+		BeforeEach(func() {
+			endpoint = "/chaosdogfood.ChaosDogfood/streamCatalog"
+			streamerCalled = false
+		})
+
+		It("should fail the stream when opened", func() {
+			_, err := intercept()
+
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			Expect(streamerCalled).To(BeFalse())
+		})
+
+		Context("with a number of messages to receive before failing", func() {
+			BeforeEach(func() {
+				alteration.FailAfterMessages = 2
+			})
+
+			It("should fail the stream once the messages are received", func() {
+				stream, err := intercept()
+				Expect(err).To(BeNil())
+
+				animals, err := receive(stream)
+				Expect(status.Code(err)).To(Equal(codes.Unavailable))
+				Expect(animals).To(Equal([]string{"cat", "dog"}))
+			})
+		})
+
+		Context("with an override", func() {
+			BeforeEach(func() {
+				alteration.ErrorToReturn = ""
+				alteration.OverrideToReturn = `{"animal": "mouse"}`
+			})
+
+			It("should receive the override as the only message without opening the stream", func() {
+				stream, err := intercept()
+				Expect(err).To(BeNil())
+
+				animals, err := receive(stream)
+				Expect(err).To(BeNil())
+				Expect(animals).To(Equal([]string{"mouse"}))
+				Expect(streamerCalled).To(BeFalse())
+			})
+		})
+	})
+})
@@ -14,6 +14,7 @@ package disruptionlistener
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
@@ -133,9 +134,13 @@ type AlterationSpec struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ErrorToReturn    string `protobuf:"bytes,1,opt,name=errorToReturn,proto3" json:"errorToReturn,omitempty"`
-	OverrideToReturn string `protobuf:"bytes,2,opt,name=overrideToReturn,proto3" json:"overrideToReturn,omitempty"`
-	QueryPercent     int32  `protobuf:"varint,3,opt,name=queryPercent,proto3" json:"queryPercent,omitempty"`
+	ErrorToReturn     string               `protobuf:"bytes,1,opt,name=errorToReturn,proto3" json:"errorToReturn,omitempty"`
+	OverrideToReturn  string               `protobuf:"bytes,2,opt,name=overrideToReturn,proto3" json:"overrideToReturn,omitempty"`
+	QueryPercent      int32                `protobuf:"varint,3,opt,name=queryPercent,proto3" json:"queryPercent,omitempty"`
+	Latency           *durationpb.Duration `protobuf:"bytes,4,opt,name=latency,proto3" json:"latency,omitempty"`
+	LatencyJitter     int32                `protobuf:"varint,5,opt,name=latencyJitter,proto3" json:"latencyJitter,omitempty"`
+	FailAfterMessages int32                `protobuf:"varint,6,opt,name=failAfterMessages,proto3" json:"failAfterMessages,omitempty"`
+	Metadata          map[string]string    `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *AlterationSpec) Reset() {
@@ -191,6 +196,199 @@ func (x *AlterationSpec) GetQueryPercent() int32 {
 	return 0
 }
 
+func (x *AlterationSpec) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *AlterationSpec) GetLatencyJitter() int32 {
+	if x != nil {
+		return x.LatencyJitter
+	}
+	return 0
+}
+
+func (x *AlterationSpec) GetFailAfterMessages() int32 {
+	if x != nil {
+		return x.FailAfterMessages
+	}
+	return 0
+}
+
+func (x *AlterationSpec) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type DisruptionStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Endpoints []*EndpointStatus `protobuf:"bytes,1,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+}
+
+func (x *DisruptionStatus) Reset() {
+	*x = DisruptionStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disruptionlistener_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisruptionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisruptionStatus) ProtoMessage() {}
+
+func (x *DisruptionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_disruptionlistener_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisruptionStatus.ProtoReflect.Descriptor instead.
+func (*DisruptionStatus) Descriptor() ([]byte, []int) {
+	return file_disruptionlistener_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DisruptionStatus) GetEndpoints() []*EndpointStatus {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+type EndpointStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TargetEndpoint string              `protobuf:"bytes,1,opt,name=targetEndpoint,proto3" json:"targetEndpoint,omitempty"`
+	Calls          int64               `protobuf:"varint,2,opt,name=calls,proto3" json:"calls,omitempty"`
+	Alterations    []*AlterationStatus `protobuf:"bytes,3,rep,name=alterations,proto3" json:"alterations,omitempty"`
+}
+
+func (x *EndpointStatus) Reset() {
+	*x = EndpointStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disruptionlistener_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndpointStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointStatus) ProtoMessage() {}
+
+func (x *EndpointStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_disruptionlistener_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointStatus.ProtoReflect.Descriptor instead.
+func (*EndpointStatus) Descriptor() ([]byte, []int) {
+	return file_disruptionlistener_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EndpointStatus) GetTargetEndpoint() string {
+	if x != nil {
+		return x.TargetEndpoint
+	}
+	return ""
+}
+
+func (x *EndpointStatus) GetCalls() int64 {
+	if x != nil {
+		return x.Calls
+	}
+	return 0
+}
+
+func (x *EndpointStatus) GetAlterations() []*AlterationStatus {
+	if x != nil {
+		return x.Alterations
+	}
+	return nil
+}
+
+type AlterationStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Alteration *AlterationSpec `protobuf:"bytes,1,opt,name=alteration,proto3" json:"alteration,omitempty"`
+	Altered    int64           `protobuf:"varint,2,opt,name=altered,proto3" json:"altered,omitempty"`
+}
+
+func (x *AlterationStatus) Reset() {
+	*x = AlterationStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disruptionlistener_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlterationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlterationStatus) ProtoMessage() {}
+
+func (x *AlterationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_disruptionlistener_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlterationStatus.ProtoReflect.Descriptor instead.
+func (*AlterationStatus) Descriptor() ([]byte, []int) {
+	return file_disruptionlistener_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AlterationStatus) GetAlteration() *AlterationSpec {
+	if x != nil {
+		return x.Alteration
+	}
+	return nil
+}
+
+func (x *AlterationStatus) GetAltered() int64 {
+	if x != nil {
+		return x.Altered
+	}
+	return 0
+}
+
 var File_disruptionlistener_proto protoreflect.FileDescriptor
 
 var file_disruptionlistener_proto_rawDesc = []byte{
@@ -198,7 +396,9 @@ var file_disruptionlistener_proto_rawDesc = []byte{
 	0x65, 0x6e, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x64, 0x69, 0x73, 0x72,
 	0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x1a, 0x1b,
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
-	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x50, 0x0a, 0x0e, 0x44,
+	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x50, 0x0a, 0x0e, 0x44,
 	0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x12, 0x3e, 0x0a,
 	0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x20, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73,
@@ -211,7 +411,7 @@ var file_disruptionlistener_proto_rawDesc = []byte{
 	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x64, 0x69, 0x73,
 	0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e,
 	0x41, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x52, 0x0b,
-	0x61, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x86, 0x01, 0x0a, 0x0e,
+	0x61, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x9a, 0x03, 0x0a, 0x0e,
 	0x41, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x12, 0x24,
 	0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x6f, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x6f, 0x52, 0x65,
@@ -220,19 +420,63 @@ var file_disruptionlistener_proto_rawDesc = []byte{
 	0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x54, 0x6f, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e,
 	0x12, 0x22, 0x0a, 0x0c, 0x71, 0x75, 0x65, 0x72, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
 	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x71, 0x75, 0x65, 0x72, 0x79, 0x50, 0x65, 0x72,
-	0x63, 0x65, 0x6e, 0x74, 0x32, 0xa3, 0x01, 0x0a, 0x12, 0x44, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x47, 0x0a, 0x07, 0x44,
-	0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x12, 0x22, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x73, 0x72,
-	0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x65, 0x74, 0x44, 0x69, 0x73,
-	0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x16, 0x5a, 0x14, 0x2e, 0x2f,
-	0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e,
-	0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x63, 0x65, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x6c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x4a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0d, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12,
+	0x2c, 0x0a, 0x11, 0x66, 0x61, 0x69, 0x6c, 0x41, 0x66, 0x74, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x66, 0x61, 0x69, 0x6c,
+	0x41, 0x66, 0x74, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x4c, 0x0a,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x30, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x70, 0x65, 0x63, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x54, 0x0a, 0x10, 0x44, 0x69, 0x73, 0x72,
+	0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x40, 0x0a, 0x09,
+	0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x96,
+	0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x61, 0x6c,
+	0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x12,
+	0x46, 0x0a, 0x0b, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x6c, 0x74, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0b, 0x61, 0x6c, 0x74, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x70, 0x0a, 0x10, 0x41, 0x6c, 0x74, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x42, 0x0a, 0x0a, 0x61,
+	0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x65, 0x72, 0x2e, 0x41, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x70, 0x65, 0x63, 0x52, 0x0a, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x32, 0xf0, 0x01, 0x0a, 0x12, 0x44, 0x69,
+	0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x12, 0x47, 0x0a, 0x07, 0x44, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x12, 0x22, 0x2e, 0x64, 0x69,
+	0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x2e, 0x44, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x10, 0x52, 0x65, 0x73,
+	0x65, 0x74, 0x44, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12,
+	0x4b, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x24, 0x2e, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x73, 0x72, 0x75, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x42, 0x16, 0x5a, 0x14,
+	0x2e, 0x2f, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x6c, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -247,25 +491,37 @@ func file_disruptionlistener_proto_rawDescGZIP() []byte {
 	return file_disruptionlistener_proto_rawDescData
 }
 
-var file_disruptionlistener_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_disruptionlistener_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_disruptionlistener_proto_goTypes = []interface{}{
-	(*DisruptionSpec)(nil), // 0: disruptionlistener.DisruptionSpec
-	(*EndpointSpec)(nil),   // 1: disruptionlistener.EndpointSpec
-	(*AlterationSpec)(nil), // 2: disruptionlistener.AlterationSpec
-	(*emptypb.Empty)(nil),  // 3: google.protobuf.Empty
+	(*DisruptionSpec)(nil),      // 0: disruptionlistener.DisruptionSpec
+	(*EndpointSpec)(nil),        // 1: disruptionlistener.EndpointSpec
+	(*AlterationSpec)(nil),      // 2: disruptionlistener.AlterationSpec
+	(*DisruptionStatus)(nil),    // 3: disruptionlistener.DisruptionStatus
+	(*EndpointStatus)(nil),      // 4: disruptionlistener.EndpointStatus
+	(*AlterationStatus)(nil),    // 5: disruptionlistener.AlterationStatus
+	nil,                         // 6: disruptionlistener.AlterationSpec.MetadataEntry
+	(*durationpb.Duration)(nil), // 7: google.protobuf.Duration
+	(*emptypb.Empty)(nil),       // 8: google.protobuf.Empty
 }
 var file_disruptionlistener_proto_depIdxs = []int32{
-	1, // 0: disruptionlistener.DisruptionSpec.endpoints:type_name -> disruptionlistener.EndpointSpec
-	2, // 1: disruptionlistener.EndpointSpec.alterations:type_name -> disruptionlistener.AlterationSpec
-	0, // 2: disruptionlistener.DisruptionListener.Disrupt:input_type -> disruptionlistener.DisruptionSpec
-	3, // 3: disruptionlistener.DisruptionListener.ResetDisruptions:input_type -> google.protobuf.Empty
-	3, // 4: disruptionlistener.DisruptionListener.Disrupt:output_type -> google.protobuf.Empty
-	3, // 5: disruptionlistener.DisruptionListener.ResetDisruptions:output_type -> google.protobuf.Empty
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: disruptionlistener.DisruptionSpec.endpoints:type_name -> disruptionlistener.EndpointSpec
+	2,  // 1: disruptionlistener.EndpointSpec.alterations:type_name -> disruptionlistener.AlterationSpec
+	7,  // 2: disruptionlistener.AlterationSpec.latency:type_name -> google.protobuf.Duration
+	6,  // 3: disruptionlistener.AlterationSpec.metadata:type_name -> disruptionlistener.AlterationSpec.MetadataEntry
+	4,  // 4: disruptionlistener.DisruptionStatus.endpoints:type_name -> disruptionlistener.EndpointStatus
+	5,  // 5: disruptionlistener.EndpointStatus.alterations:type_name -> disruptionlistener.AlterationStatus
+	2,  // 6: disruptionlistener.AlterationStatus.alteration:type_name -> disruptionlistener.AlterationSpec
+	0,  // 7: disruptionlistener.DisruptionListener.Disrupt:input_type -> disruptionlistener.DisruptionSpec
+	8,  // 8: disruptionlistener.DisruptionListener.ResetDisruptions:input_type -> google.protobuf.Empty
+	8,  // 9: disruptionlistener.DisruptionListener.GetStatus:input_type -> google.protobuf.Empty
+	8,  // 10: disruptionlistener.DisruptionListener.Disrupt:output_type -> google.protobuf.Empty
+	8,  // 11: disruptionlistener.DisruptionListener.ResetDisruptions:output_type -> google.protobuf.Empty
+	3,  // 12: disruptionlistener.DisruptionListener.GetStatus:output_type -> disruptionlistener.DisruptionStatus
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_disruptionlistener_proto_init() }
@@ -310,6 +566,42 @@ func file_disruptionlistener_proto_init() {
 				return nil
 			}
 		}
+		file_disruptionlistener_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisruptionStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disruptionlistener_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EndpointStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disruptionlistener_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlterationStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -317,7 +609,7 @@ func file_disruptionlistener_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_disruptionlistener_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
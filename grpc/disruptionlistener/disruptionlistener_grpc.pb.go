@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion7
 type DisruptionListenerClient interface {
 	Disrupt(ctx context.Context, in *DisruptionSpec, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	ResetDisruptions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DisruptionStatus, error)
 }
 
 type disruptionListenerClient struct {
@@ -49,12 +50,22 @@ func (c *disruptionListenerClient) ResetDisruptions(ctx context.Context, in *emp
 	return out, nil
 }
 
+func (c *disruptionListenerClient) GetStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DisruptionStatus, error) {
+	out := new(DisruptionStatus)
+	err := c.cc.Invoke(ctx, "/disruptionlistener.DisruptionListener/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DisruptionListenerServer is the server API for DisruptionListener service.
 // All implementations must embed UnimplementedDisruptionListenerServer
 // for forward compatibility
 type DisruptionListenerServer interface {
 	Disrupt(context.Context, *DisruptionSpec) (*emptypb.Empty, error)
 	ResetDisruptions(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	GetStatus(context.Context, *emptypb.Empty) (*DisruptionStatus, error)
 	mustEmbedUnimplementedDisruptionListenerServer()
 }
 
@@ -68,6 +79,9 @@ func (UnimplementedDisruptionListenerServer) Disrupt(context.Context, *Disruptio
 func (UnimplementedDisruptionListenerServer) ResetDisruptions(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResetDisruptions not implemented")
 }
+func (UnimplementedDisruptionListenerServer) GetStatus(context.Context, *emptypb.Empty) (*DisruptionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
 func (UnimplementedDisruptionListenerServer) mustEmbedUnimplementedDisruptionListenerServer() {}
 
 // UnsafeDisruptionListenerServer may be embedded to opt out of forward compatibility for this service.
@@ -117,6 +131,24 @@ func _DisruptionListener_ResetDisruptions_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DisruptionListener_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisruptionListenerServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disruptionlistener.DisruptionListener/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisruptionListenerServer).GetStatus(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DisruptionListener_ServiceDesc is the grpc.ServiceDesc for DisruptionListener service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -132,6 +164,10 @@ var DisruptionListener_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ResetDisruptions",
 			Handler:    _DisruptionListener_ResetDisruptions_Handler,
 		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _DisruptionListener_GetStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "disruptionlistener.proto",
@@ -34,3 +34,10 @@ func (d *DisruptionListenerClientMock) ResetDisruptions(ctx context.Context, emp
 
 	return mockArgs.Get(0).(*emptypb.Empty), mockArgs.Error(1)
 }
+
+//nolint:golint
+func (d *DisruptionListenerClientMock) GetStatus(ctx context.Context, empty *emptypb.Empty, opts ...grpc.CallOption) (*pb.DisruptionStatus, error) {
+	mockArgs := d.Called(ctx, empty)
+
+	return mockArgs.Get(0).(*pb.DisruptionStatus), mockArgs.Error(1)
+}
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	grpccalc "github.com/DataDog/chaos-controller/grpc/calculations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// emptyOverride is the override payload returning an empty response
+const emptyOverride = "{}"
+
+// ServiceInfoProvider provides the services registered on a gRPC server, it is implemented by *grpc.Server
+type ServiceInfoProvider interface {
+	GetServiceInfo() map[string]grpc.ServiceInfo
+}
+
+// SetServiceInfoProvider makes the listener resolve the response types of the disrupted endpoints
+// from the services registered on the given gRPC server before falling back to the global proto registry,
+// it must be called before the server starts serving requests
+func (d *ChaosDisruptionListener) SetServiceInfoProvider(provider ServiceInfoProvider) {
+	d.serviceInfoProvider = provider
+}
+
+// resolveOverrides unmarshals the override payloads of the given alterations into responses of the given endpoint response type,
+// it returns the responses indexed by their payload
+func (d *ChaosDisruptionListener) resolveOverrides(endpoint grpccalc.TargetEndpoint, alterations []grpccalc.AlterationConfiguration) (map[string]proto.Message, error) {
+	overrides := map[string]proto.Message{}
+
+	for _, altConfig := range alterations {
+		if altConfig.OverrideToReturn == "" {
+			continue
+		}
+
+		if _, ok := overrides[altConfig.OverrideToReturn]; ok {
+			continue
+		}
+
+		output, err := d.outputDescriptor(endpoint)
+		if err != nil {
+			// keep supporting empty overrides on endpoints whose response type is unknown, an empty message is valid for any response type
+			if altConfig.OverrideToReturn == emptyOverride {
+				d.logger.Warnw("returning an empty message as override", "endpoint", endpoint, "error", err)
+				overrides[emptyOverride] = &emptypb.Empty{}
+
+				continue
+			}
+
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("cannot resolve the response type of endpoint %s to return its override: %s", endpoint, err))
+		}
+
+		response := newMessage(output)
+
+		if err := protojson.Unmarshal([]byte(altConfig.OverrideToReturn), response); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("override %s of endpoint %s is not a valid %s: %s", altConfig.OverrideToReturn, endpoint, output.FullName(), err))
+		}
+
+		overrides[altConfig.OverrideToReturn] = response
+	}
+
+	return overrides, nil
+}
+
+// outputDescriptor returns the descriptor of the response type of the given endpoint,
+// it is looked up in the services registered on the server first and in the global proto registry then
+func (d *ChaosDisruptionListener) outputDescriptor(endpoint grpccalc.TargetEndpoint) (protoreflect.MessageDescriptor, error) {
+	// endpoint is of the format /package.service/method
+	serviceName, methodName, ok := splitEndpoint(string(endpoint))
+	if !ok {
+		return nil, fmt.Errorf("endpoint %s is not of the format /package.service/method", endpoint)
+	}
+
+	var service protoreflect.ServiceDescriptor
+
+	if d.serviceInfoProvider != nil {
+		if info, ok := d.serviceInfoProvider.GetServiceInfo()[serviceName]; ok {
+			// generated services metadata is the path of the proto file defining them
+			if path, ok := info.Metadata.(string); ok {
+				if file, err := protoregistry.GlobalFiles.FindFileByPath(path); err == nil {
+					service = file.Services().ByName(protoreflect.FullName(serviceName).Name())
+				}
+			}
+		}
+	}
+
+	if service == nil {
+		descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+		if err != nil {
+			return nil, fmt.Errorf("service %s not found: %w", serviceName, err)
+		}
+
+		if service, ok = descriptor.(protoreflect.ServiceDescriptor); !ok {
+			return nil, fmt.Errorf("%s is not a service", serviceName)
+		}
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+
+	return method.Output(), nil
+}
+
+// splitEndpoint splits an endpoint of the format /package.service/method into its service and method names
+func splitEndpoint(endpoint string) (string, string, bool) {
+	endpoint = strings.TrimPrefix(endpoint, "/")
+
+	i := strings.LastIndex(endpoint, "/")
+	if i <= 0 || i == len(endpoint)-1 {
+		return "", "", false
+	}
+
+	return endpoint[:i], endpoint[i+1:], true
+}
+
+// newMessage creates a message of the given type, using its generated Go type when it is registered
+func newMessage(descriptor protoreflect.MessageDescriptor) proto.Message {
+	if messageType, err := protoregistry.GlobalTypes.FindMessageByName(descriptor.FullName()); err == nil {
+		return messageType.New().Interface()
+	}
+
+	return dynamicpb.NewMessage(descriptor)
+}
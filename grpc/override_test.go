@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc_test
+
+import (
+	"context"
+
+	df_pb "github.com/DataDog/chaos-controller/dogfood/chaosdogfood"
+	chaosgrpc "github.com/DataDog/chaos-controller/grpc"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var _ = Describe("Overrides", func() {
+	var (
+		listener *chaosgrpc.ChaosDisruptionListener
+		endpoint string
+		override string
+		err      error
+	)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "computed response", nil
+	}
+
+	BeforeEach(func() {
+		listener = chaosgrpc.NewDisruptionListener(zap.NewNop().Sugar())
+		endpoint = "/chaosdogfood.ChaosDogfood/order"
+		override = `{"message": "overridden", "confirmationId": 42}`
+	})
+
+	JustBeforeEach(func() {
+		_, err = listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+			Endpoints: []*pb.EndpointSpec{
+				{
+					TargetEndpoint: endpoint,
+					Alterations: []*pb.AlterationSpec{
+						{
+							OverrideToReturn: override,
+							QueryPercent:     100,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	It("should return the override as a response of the endpoint type", func() {
+		Expect(err).To(BeNil())
+
+		resp, err := listener.ChaosServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+
+		Expect(err).To(BeNil())
+		Expect(proto.Equal(resp.(proto.Message), &df_pb.FoodReply{Message: "overridden", ConfirmationId: 42})).To(BeTrue())
+	})
+
+	Context("with the services registered on the server", func() {
+		BeforeEach(func() {
+			server := grpc.NewServer()
+			df_pb.RegisterChaosDogfoodServer(server, &df_pb.UnimplementedChaosDogfoodServer{})
+			listener.SetServiceInfoProvider(server)
+		})
+
+		It("should return the override as a response of the endpoint type", func() {
+			Expect(err).To(BeNil())
+
+			resp, err := listener.ChaosServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+
+			Expect(err).To(BeNil())
+			Expect(resp).To(BeAssignableToTypeOf(&df_pb.FoodReply{}))
+		})
+	})
+
+	Context("with an override not matching the endpoint type", func() {
+		BeforeEach(func() {
+			override = `{"animal": "cat"}`
+		})
+
+		It("should fail to apply the disruption", func() {
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("is not a valid chaosdogfood.FoodReply"))
+		})
+	})
+
+	Context("on an unknown endpoint", func() {
+		BeforeEach(func() {
+			endpoint = "/unknown.Service/method"
+		})
+
+		It("should fail to apply the disruption", func() {
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		Context("with an empty override", func() {
+			BeforeEach(func() {
+				override = "{}"
+			})
+
+			It("should return an empty message", func() {
+				Expect(err).To(BeNil())
+
+				resp, err := listener.ChaosServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+
+				Expect(err).To(BeNil())
+				Expect(resp).To(BeAssignableToTypeOf(&emptypb.Empty{}))
+			})
+		})
+	})
+})
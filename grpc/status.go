@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	grpccalc "github.com/DataDog/chaos-controller/grpc/calculations"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// alterationKey identifies an alteration of an endpoint, alterations are scoped by their metadata matchers
+type alterationKey struct {
+	endpoint   grpccalc.TargetEndpoint
+	metadata   string
+	alteration grpccalc.AlterationConfiguration
+}
+
+// disruptionStatus records the calls seen and altered by the listener for the disruption it applies,
+// its counters are all created when the disruption is applied so they can be incremented without locking
+type disruptionStatus struct {
+	spec      *pb.DisruptionSpec
+	calls     map[grpccalc.TargetEndpoint]*int64
+	altered   map[grpccalc.TargetEndpoint][]*int64 // indexed by the position of the alterations in the endpoint spec
+	positions map[alterationKey]int                // position of the alteration counting the calls altered by each applied alteration
+}
+
+// newDisruptionStatus creates the counters of the endpoints and alterations of the given disruption
+func newDisruptionStatus(ds *pb.DisruptionSpec) *disruptionStatus {
+	s := &disruptionStatus{
+		spec:      ds,
+		calls:     map[grpccalc.TargetEndpoint]*int64{},
+		altered:   map[grpccalc.TargetEndpoint][]*int64{},
+		positions: map[alterationKey]int{},
+	}
+
+	for _, endpointSpec := range ds.Endpoints {
+		targetEndpoint := grpccalc.TargetEndpoint(endpointSpec.TargetEndpoint)
+		s.calls[targetEndpoint] = new(int64)
+		s.altered[targetEndpoint] = make([]*int64, len(endpointSpec.Alterations))
+
+		for position, altSpec := range endpointSpec.Alterations {
+			s.altered[targetEndpoint][position] = new(int64)
+
+			// identical alterations with the same matchers are applied as a single one whose query percentage is
+			// the one of the last of them (see grpccalc.GetPercentagePerAlteration), which counts the altered calls
+			s.positions[newAlterationKey(targetEndpoint, altSpec)] = position
+		}
+	}
+
+	return s
+}
+
+// newAlterationKey returns the key of the given alteration of the given endpoint
+func newAlterationKey(endpoint grpccalc.TargetEndpoint, altSpec *pb.AlterationSpec) alterationKey {
+	return alterationKey{
+		endpoint:   endpoint,
+		metadata:   grpccalc.MetadataKey(altSpec.Metadata),
+		alteration: grpccalc.NewAlterationConfiguration(altSpec),
+	}
+}
+
+// countCall records a call to the given endpoint
+func (s *disruptionStatus) countCall(endpoint grpccalc.TargetEndpoint) {
+	if s == nil {
+		return
+	}
+
+	if counter, ok := s.calls[endpoint]; ok {
+		atomic.AddInt64(counter, 1)
+	}
+}
+
+// countAlteration records a call altered by the given alteration
+func (s *disruptionStatus) countAlteration(key alterationKey) {
+	if s == nil {
+		return
+	}
+
+	if position, ok := s.positions[key]; ok {
+		atomic.AddInt64(s.altered[key.endpoint][position], 1)
+	}
+}
+
+// GetStatus returns the disruption applied by the listener along with the number of calls
+// seen by each disrupted endpoint and altered by each of their alterations since the disruption was applied
+func (d *ChaosDisruptionListener) GetStatus(context.Context, *emptypb.Empty) (*pb.DisruptionStatus, error) {
+	d.mutex.Lock()
+	s := d.status
+	d.mutex.Unlock()
+
+	ds := &pb.DisruptionStatus{}
+
+	if s == nil {
+		return ds, nil
+	}
+
+	for _, endpointSpec := range s.spec.Endpoints {
+		targetEndpoint := grpccalc.TargetEndpoint(endpointSpec.TargetEndpoint)
+
+		endpointStatus := &pb.EndpointStatus{
+			TargetEndpoint: endpointSpec.TargetEndpoint,
+			Calls:          atomic.LoadInt64(s.calls[targetEndpoint]),
+		}
+
+		for position, altSpec := range endpointSpec.Alterations {
+			endpointStatus.Alterations = append(endpointStatus.Alterations, &pb.AlterationStatus{
+				Alteration: altSpec,
+				Altered:    atomic.LoadInt64(s.altered[targetEndpoint][position]),
+			})
+		}
+
+		ds.Endpoints = append(ds.Endpoints, endpointStatus)
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package grpc_test
+
+import (
+	"context"
+
+	chaosgrpc "github.com/DataDog/chaos-controller/grpc"
+	pb "github.com/DataDog/chaos-controller/grpc/disruptionlistener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var _ = Describe("GetStatus", func() {
+	const (
+		disruptedEndpoint = "/chaosdogfood.ChaosDogfood/order"
+		otherEndpoint     = "/chaosdogfood.ChaosDogfood/getCatalog"
+	)
+
+	var listener *chaosgrpc.ChaosDisruptionListener
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "computed response", nil
+	}
+
+	intercept := func(md metadata.MD, endpoint string) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+
+		_, _ = listener.ChaosServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: endpoint}, handler)
+	}
+
+	getStatus := func() *pb.DisruptionStatus {
+		ds, err := listener.GetStatus(context.Background(), &emptypb.Empty{})
+		Expect(err).To(BeNil())
+
+		return ds
+	}
+
+	BeforeEach(func() {
+		listener = chaosgrpc.NewDisruptionListener(zap.NewNop().Sugar())
+	})
+
+	Context("without any disruption", func() {
+		It("should return an empty status", func() {
+			Expect(getStatus().Endpoints).To(BeEmpty())
+		})
+	})
+
+	Context("with a disruption", func() {
+		BeforeEach(func() {
+			_, err := listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+				Endpoints: []*pb.EndpointSpec{
+					{
+						TargetEndpoint: disruptedEndpoint,
+						Alterations: []*pb.AlterationSpec{
+							{
+								ErrorToReturn: "UNAVAILABLE",
+								QueryPercent:  100,
+								Metadata:      map[string]string{"x-tenant": "test"},
+							},
+							{
+								ErrorToReturn: "UNAVAILABLE",
+								QueryPercent:  100,
+							},
+						},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			intercept(metadata.Pairs("x-tenant", "test"), disruptedEndpoint)
+			intercept(metadata.Pairs("x-tenant", "test"), disruptedEndpoint)
+			intercept(metadata.Pairs("x-tenant", "prod"), disruptedEndpoint)
+			intercept(metadata.Pairs("x-tenant", "test"), otherEndpoint)
+		})
+
+		It("should count the calls seen and altered per endpoint and alteration", func() {
+			ds := getStatus()
+			Expect(ds.Endpoints).To(HaveLen(1))
+
+			endpointStatus := ds.Endpoints[0]
+			Expect(endpointStatus.TargetEndpoint).To(Equal(disruptedEndpoint))
+			Expect(endpointStatus.Calls).To(Equal(int64(3)))
+			Expect(endpointStatus.Alterations).To(HaveLen(2))
+
+			Expect(endpointStatus.Alterations[0].Alteration.Metadata).To(Equal(map[string]string{"x-tenant": "test"}))
+			Expect(endpointStatus.Alterations[0].Altered).To(Equal(int64(2)))
+			Expect(endpointStatus.Alterations[1].Alteration.Metadata).To(BeEmpty())
+			Expect(endpointStatus.Alterations[1].Altered).To(Equal(int64(1)))
+		})
+
+		It("should forget the counts once the disruption is reset", func() {
+			_, err := listener.ResetDisruptions(context.Background(), &emptypb.Empty{})
+			Expect(err).To(BeNil())
+
+			Expect(getStatus().Endpoints).To(BeEmpty())
+		})
+	})
+
+	Context("with identical alterations", func() {
+		var altered int64
+
+		BeforeEach(func() {
+			_, err := listener.Disrupt(context.Background(), &pb.DisruptionSpec{
+				Endpoints: []*pb.EndpointSpec{
+					{
+						TargetEndpoint: disruptedEndpoint,
+						Alterations: []*pb.AlterationSpec{
+							{
+								ErrorToReturn: "UNAVAILABLE",
+								QueryPercent:  50,
+							},
+							{
+								ErrorToReturn: "UNAVAILABLE",
+								QueryPercent:  50,
+							},
+						},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+
+			altered = 0
+
+			for i := 0; i < 20; i++ {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+
+				if _, err := listener.ChaosServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: disruptedEndpoint}, handler); err != nil {
+					altered++
+				}
+			}
+		})
+
+		It("should count each altered call once", func() {
+			endpointStatus := getStatus().Endpoints[0]
+			Expect(endpointStatus.Alterations).To(HaveLen(2))
+			Expect(endpointStatus.Alterations[0].Altered + endpointStatus.Alterations[1].Altered).To(Equal(altered))
+		})
+	})
+})
+
+var _ = Describe("DescribeAlteration", func() {
+	It("should describe every part of the alteration", func() {
+		Expect(chaosgrpc.DescribeAlteration(&pb.AlterationSpec{
+			ErrorToReturn:     "UNAVAILABLE",
+			FailAfterMessages: 2,
+			Metadata:          map[string]string{"x-tenant": "test"},
+		})).To(Equal("error:UNAVAILABLE,failAfterMessages:2,metadata:x-tenant=test"))
+	})
+})
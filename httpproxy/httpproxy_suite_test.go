@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package httpproxy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var log *zap.SugaredLogger
+
+var _ = BeforeSuite(func() {
+	z, _ := zap.NewDevelopment()
+	log = z.Sugar()
+})
+
+func TestHTTPProxy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTP Proxy Suite")
+}
@@ -0,0 +1,237 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package httpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+)
+
+// errAborted is returned while reading the body of a response to abort
+var errAborted = errors.New("response aborted by the http disruption")
+
+// abortKey is the request context key marking the requests whose response must be aborted
+type abortKey struct{}
+
+// DialFunc opens a connection to the given address
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Proxy is an HTTP proxy altering the requests it receives according to a list of rules
+type Proxy interface {
+	// Start listens on a random port and proxies the received requests to the given upstream port,
+	// it returns the listening port; the listening socket is created in the network namespace of the calling thread
+	Start(upstreamPort int) (int, error)
+	// Close stops all the started listeners and closes their connections
+	Close() error
+}
+
+type proxy struct {
+	log          *zap.SugaredLogger
+	rules        []v1beta1.HTTPRule
+	upstreamHost string
+	dial         DialFunc
+	servers      []*http.Server
+	lock         sync.Mutex
+}
+
+// NewProxy creates a proxy applying the given rules and forwarding requests to the given upstream host,
+// connections to the upstream host are opened with the given dial function
+func NewProxy(log *zap.SugaredLogger, rules []v1beta1.HTTPRule, upstreamHost string, dial DialFunc) Proxy {
+	return &proxy{
+		log:          log,
+		rules:        rules,
+		upstreamHost: upstreamHost,
+		dial:         dial,
+	}
+}
+
+func (p *proxy) Start(upstreamPort int) (int, error) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, fmt.Errorf("error listening for port %d requests: %w", upstreamPort, err)
+	}
+
+	upstream := &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(p.upstreamHost, strconv.Itoa(upstreamPort)),
+	}
+
+	reverseProxy := httputil.NewSingleHostReverseProxy(upstream)
+	reverseProxy.Transport = &http.Transport{
+		DialContext:         p.dial,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	// flush every chunk of the responses immediately so an aborted response is partially received
+	reverseProxy.FlushInterval = -1
+	reverseProxy.ModifyResponse = abortResponse
+	reverseProxy.ErrorLog = zap.NewStdLog(p.log.Desugar())
+
+	server := &http.Server{
+		Handler: handler{
+			log:     p.log,
+			rules:   p.rules,
+			forward: reverseProxy,
+		},
+		ErrorLog: zap.NewStdLog(p.log.Desugar()),
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.log.Errorw("error serving proxied requests", "error", err, "upstream", upstream.Host)
+		}
+	}()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.servers = append(p.servers, server)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	p.log.Infow("proxying requests", "port", port, "upstream", upstream.Host)
+
+	return port, nil
+}
+
+func (p *proxy) Close() (retErr error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, server := range p.servers {
+		if err := server.Close(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+	}
+
+	p.servers = nil
+
+	return retErr
+}
+
+// handler applies the first matching rule to the received requests before forwarding them
+type handler struct {
+	log     *zap.SugaredLogger
+	rules   []v1beta1.HTTPRule
+	forward http.Handler
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rule := matchRule(h.rules, r, rand.Intn) //nolint:gosec
+	if rule == nil {
+		h.forward.ServeHTTP(w, r)
+
+		return
+	}
+
+	h.log.Debugw("disrupting request", "method", r.Method, "path", r.URL.Path, "rule", rule)
+
+	if delay := rule.Delay.Duration(); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if rule.StatusCode != 0 {
+		http.Error(w, http.StatusText(rule.StatusCode), rule.StatusCode)
+
+		return
+	}
+
+	if rule.Abort {
+		r = r.WithContext(context.WithValue(r.Context(), abortKey{}, true))
+	}
+
+	h.forward.ServeHTTP(w, r)
+}
+
+// matchRule returns the first rule matching the given request and passing its percentage roll, if any
+func matchRule(rules []v1beta1.HTTPRule, r *http.Request, intn func(int) int) *v1beta1.HTTPRule {
+	for i := range rules {
+		rule := &rules[i]
+
+		if !ruleMatches(rule, r) {
+			continue
+		}
+
+		if intn(100) < rule.GetPercent() {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// ruleMatches returns true if the given request method, path and headers match the given rule
+func ruleMatches(rule *v1beta1.HTTPRule, r *http.Request) bool {
+	if rule.Method != "" && rule.Method != r.Method {
+		return false
+	}
+
+	if strings.HasSuffix(rule.Path, "*") {
+		if !strings.HasPrefix(r.URL.Path, strings.TrimSuffix(rule.Path, "*")) {
+			return false
+		}
+	} else if rule.Path != "" && rule.Path != r.URL.Path {
+		return false
+	}
+
+	for name, value := range rule.Headers {
+		if r.Header.Get(name) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// abortResponse makes the body of the responses to abort fail after its first chunk,
+// the reverse proxy then aborts the connection with the client
+func abortResponse(resp *http.Response) error {
+	if resp.Request.Context().Value(abortKey{}) != nil {
+		resp.Body = &abortedBody{ReadCloser: resp.Body}
+	}
+
+	return nil
+}
+
+// abortedBody is a response body returning an error after its first chunk
+type abortedBody struct {
+	io.ReadCloser
+	read bool
+}
+
+func (b *abortedBody) Read(p []byte) (int, error) {
+	if b.read {
+		return 0, errAborted
+	}
+
+	b.read = true
+
+	n, _ := b.ReadCloser.Read(p)
+	if n == 0 {
+		return 0, errAborted
+	}
+
+	return n, nil
+}
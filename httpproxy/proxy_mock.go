@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package httpproxy
+
+import "github.com/stretchr/testify/mock"
+
+// ProxyMock is a mock implementation of the Proxy interface
+type ProxyMock struct {
+	mock.Mock
+}
+
+//nolint:golint
+func (f *ProxyMock) Start(upstreamPort int) (int, error) {
+	args := f.Called(upstreamPort)
+
+	return args.Int(0), args.Error(1)
+}
+
+//nolint:golint
+func (f *ProxyMock) Close() error {
+	args := f.Called()
+
+	return args.Error(0)
+}
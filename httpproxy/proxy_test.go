@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package httpproxy_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	. "github.com/DataDog/chaos-controller/httpproxy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Proxy", func() {
+	var (
+		upstream  *httptest.Server
+		proxy     Proxy
+		proxyPort int
+		rules     []v1beta1.HTTPRule
+	)
+
+	get := func(path string, headers map[string]string) (*http.Response, string, error) {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://127.0.0.1:%d%s", proxyPort, path), nil)
+		Expect(err).To(BeNil())
+
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := (&http.Client{Transport: &http.Transport{DisableKeepAlives: true}}).Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+
+		return resp, string(body), err
+	}
+
+	BeforeEach(func() {
+		upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "4096")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(strings.Repeat("a", 2048)))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(strings.Repeat("b", 2048)))
+		}))
+
+		rules = []v1beta1.HTTPRule{
+			{
+				Method:     http.MethodGet,
+				Path:       "/api/orders*",
+				StatusCode: http.StatusServiceUnavailable,
+			},
+			{
+				Path:    "/slow",
+				Headers: map[string]string{"X-Chaos": "true"},
+				Delay:   "200ms",
+			},
+			{
+				Path:  "/abort",
+				Abort: true,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		upstreamURL, err := url.Parse(upstream.URL)
+		Expect(err).To(BeNil())
+
+		upstreamPort, err := strconv.Atoi(upstreamURL.Port())
+		Expect(err).To(BeNil())
+
+		proxy = NewProxy(log, rules, upstreamURL.Hostname(), (&net.Dialer{}).DialContext)
+		proxyPort, err = proxy.Start(upstreamPort)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(proxy.Close()).To(BeNil())
+		upstream.Close()
+	})
+
+	It("should forward requests matching no rule", func() {
+		resp, body, err := get("/api/users", nil)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(body).To(HaveLen(4096))
+	})
+
+	It("should return the status code of a rule matching the request path prefix", func() {
+		resp, _, err := get("/api/orders/42", nil)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("should only delay requests matching the rule headers", func() {
+		start := time.Now()
+		_, _, err := get("/slow", nil)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically("<", 200*time.Millisecond))
+
+		start = time.Now()
+		resp, body, err := get("/slow", map[string]string{"X-Chaos": "true"})
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(body).To(HaveLen(4096))
+	})
+
+	It("should abort the connection in the middle of the response", func() {
+		resp, body, err := get("/abort", nil)
+		Expect(err).ToNot(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(len(body)).To(BeNumerically("<", 4096))
+	})
+
+	Context("once closed", func() {
+		It("should stop listening", func() {
+			Expect(proxy.Close()).To(BeNil())
+
+			_, _, err := get("/api/users", nil)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -7,20 +7,30 @@ package injector
 
 import (
 	"fmt"
+	"math"
+	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/DataDog/chaos-controller/api/v1beta1"
 	"github.com/DataDog/chaos-controller/cpuset"
 	"github.com/DataDog/chaos-controller/process"
 	"github.com/DataDog/chaos-controller/stress"
 	"github.com/DataDog/chaos-controller/types"
+	"go.uber.org/zap"
 )
 
+// CPUStressCommand is the injector command run by the CPU stress processes started by the CPU pressure injector on cgroup v2 hosts
+const CPUStressCommand = "cpu-stress"
+
 type cpuPressureInjector struct {
 	spec     v1beta1.CPUPressureSpec
 	config   CPUPressureInjectorConfig
 	routines int
+	process  *os.Process
 }
 
 // CPUPressureInjectorConfig is the CPU pressure injector config
@@ -35,7 +45,7 @@ type CPUPressureInjectorConfig struct {
 func NewCPUPressureInjector(spec v1beta1.CPUPressureSpec, config CPUPressureInjectorConfig) Injector {
 	// create stresser
 	if config.Stresser == nil {
-		config.Stresser = stress.NewCPU(config.DryRun)
+		config.Stresser = stress.NewCPU(config.DryRun, cpuPressurePercentage(spec))
 	}
 
 	if config.StresserExit == nil {
@@ -61,7 +71,13 @@ func (i *cpuPressureInjector) Inject() error {
 	// read cpuset allocated cores
 	i.config.Log.Infow("retrieving target cpuset allocated cores")
 
-	cpusetCores, err := i.config.Cgroup.Read("cpuset", "cpuset.cpus")
+	// on cgroup v2, the cpuset.cpus file is empty unless explicitly set, the effective file contains the cores granted by the parents
+	cpusetFile := "cpuset.cpus"
+	if i.config.Cgroup.IsCgroupV2() {
+		cpusetFile = "cpuset.cpus.effective"
+	}
+
+	cpusetCores, err := i.config.Cgroup.Read("cpuset", cpusetFile)
 	if err != nil {
 		return fmt.Errorf("failed to read the target allocated cpus from the cpuset cgroup: %w", err)
 	}
@@ -74,21 +90,141 @@ func (i *cpuPressureInjector) Inject() error {
 
 	i.config.Log.Infow(fmt.Sprintf("target identified to be running on %d cores", cores.Size()), "cores", cores.ToSlice())
 
+	// select the cores to stress
+	stressedCores := cores.ToSlice()
+
+	if i.spec.Count != nil {
+		count, isPercent, err := v1beta1.GetIntOrPercentValueSafely(i.spec.Count)
+		if err != nil {
+			return fmt.Errorf("error computing the number of cores to stress: %w", err)
+		}
+
+		// round up the percentage so at least one core is stressed
+		if isPercent {
+			count = int(math.Ceil(float64(count) * float64(cores.Size()) / 100))
+		}
+
+		if count <= 0 {
+			return fmt.Errorf("the number of cores to stress must be positive, found: %d", count)
+		}
+
+		if count < len(stressedCores) {
+			stressedCores = stressedCores[:count]
+		}
+	}
+
+	i.config.Log.Infow(fmt.Sprintf("%d cores will be stressed", len(stressedCores)), "cores", stressedCores)
+
+	// threads can't be moved alone to a cgroup on cgroup v2 hosts, joining the target cgroup moves the whole injector process
+	// so the stress routines run in a dedicated process joining the target cgroup instead, one per targeted container
+	if i.config.Cgroup.IsCgroupV2() {
+		return i.startStressProcess(stressedCores)
+	}
+
 	// set new GOMAXPROCS value
 	oldMaxProcs := runtime.GOMAXPROCS(cores.Size())
 	i.config.Log.Infof("changed GOMAXPROCS value from %d to %d", oldMaxProcs, cores.Size())
 
+	// each thread is moved to the target cpu and cpuset cgroups so it can be scheduled on the target allocated cores
+	routines, err := startCPUStressRoutines(i.config.Log, stressedCores, i.config.Stresser, i.config.StresserExit, i.config.ProcessManager, func(core, pid int) error {
+		i.config.Log.Infow("joining target CPU cgroup", "core", core, "pid", pid)
+
+		if err := i.config.Cgroup.Join("cpu", pid, false); err != nil {
+			return fmt.Errorf("failed join the target CPU cgroup: %w", err)
+		}
+
+		// join target cpuset cgroup in case it is used to pin the target on specific cores
+		i.config.Log.Infow("joining target cpuset cgroup", "core", core, "pid", pid)
+
+		if err := i.config.Cgroup.Join("cpuset", pid, false); err != nil {
+			return fmt.Errorf("failed to join the target cpuset cgroup: %w", err)
+		}
+
+		return nil
+	})
+
+	i.routines = routines
+
+	return err
+}
+
+// startStressProcess starts a CPU stress process stressing the given cores from the target cgroup
+func (i *cpuPressureInjector) startStressProcess(cores []int) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error retrieving the injector executable: %w", err)
+	}
+
+	// the process joins the cgroup of the target container main process, or of the init process at the node level
+	targetPID := uint32(1)
+	if i.config.TargetContainer != nil {
+		targetPID = i.config.TargetContainer.PID()
+	}
+
+	rawCores := make([]string, 0, len(cores))
+	for _, core := range cores {
+		rawCores = append(rawCores, strconv.Itoa(core))
+	}
+
+	args := []string{
+		CPUStressCommand,
+		"--target-pid", strconv.FormatUint(uint64(targetPID), 10),
+		"--cores", strings.Join(rawCores, ","),
+		"--percentage", strconv.Itoa(cpuPressurePercentage(i.spec)),
+	}
+
+	if i.config.DryRun {
+		args = append(args, "--dry-run")
+	}
+
+	i.config.Log.Infow("starting the CPU stress process", "targetPID", targetPID, "cores", cores)
+
+	proc, err := i.config.ProcessManager.Start(executable, args...)
+	if err != nil {
+		return fmt.Errorf("error starting the CPU stress process: %w", err)
+	}
+
+	i.process = proc
+
+	i.config.Log.Infow("the CPU stress process has been started successfully, now stressing", "pid", proc.Pid)
+
+	return nil
+}
+
+// cpuPressurePercentage returns the load percentage of each stressed core of the given spec, defaulting to 100
+func cpuPressurePercentage(spec v1beta1.CPUPressureSpec) int {
+	if spec.Percentage != nil {
+		return *spec.Percentage
+	}
+
+	return 100
+}
+
+// StressCPU starts a stress routine per given core in the current process, each one loading its core at the given percentage
+// until the exit channel is closed, and returns once all of them are initialized
+// it is run by the CPU stress process started by the CPU pressure injector on cgroup v2 hosts, which has joined the target cgroup
+func StressCPU(log *zap.SugaredLogger, cores []int, percentage int, dryRun bool, exit chan struct{}) error {
+	// keep a processor for the process main routine besides the stress routines
+	runtime.GOMAXPROCS(len(cores) + 1)
+
+	_, err := startCPUStressRoutines(log, cores, stress.NewCPU(dryRun, percentage), exit, process.NewManager(dryRun), nil)
+
+	return err
+}
+
+// startCPUStressRoutines creates one stress goroutine per given core and returns the number of created routines once all of them are initialized
+// each goroutine is locked on its current thread, without any other routines running on it
+// it allows to have a 1 routine = 1 thread pattern
+// each thread joins the target cgroups first through the given join function, if any
+// each thread is also pinned to its core so it only loads this one
+// each thread is also niced to the highest priority
+func startCPUStressRoutines(log *zap.SugaredLogger, cores []int, stresser stress.Stresser, exit chan struct{}, processManager process.Manager, join func(core, pid int) error) (int, error) {
 	wg := sync.WaitGroup{}
+	lock := sync.Mutex{}
 	succeeded := true
 	tids := []int{}
 
-	// create one stress goroutine per allocated core
-	// each goroutine is locked on its current thread, without any other routines running on it
-	// it allows to have a 1 routine = 1 thread pattern
-	// each thread is then moved to the target cpu and cpuset cgroups so it can be schedule on the target allocated cores
-	// each thread is also niced to the highest priority
-	// because of linux scheduling, each thread will occupy a different core of allocated cores when stressing the cpu
-	for _, core := range cores.ToSlice() {
+	for _, core := range cores {
 		wg.Add(1)
 
 		go func(core int) {
@@ -96,7 +232,9 @@ func (i *cpuPressureInjector) Inject() error {
 
 			defer func() {
 				if err != nil {
+					lock.Lock()
 					succeeded = false
+					lock.Unlock()
 
 					wg.Done()
 				}
@@ -107,42 +245,42 @@ func (i *cpuPressureInjector) Inject() error {
 			defer runtime.UnlockOSThread()
 
 			// retrieve current thread PID
-			pid := i.config.ProcessManager.ThreadID()
-
-			// join target CPU cgroup
-			i.config.Log.Infow("joining target CPU cgroup", "core", core, "pid", pid)
+			pid := processManager.ThreadID()
 
-			if err = i.config.Cgroup.Join("cpu", pid, false); err != nil {
-				i.config.Log.Errorw("failed join the target CPU cgroup", "error", err, "core", core, "pid", pid)
+			if join != nil {
+				if err = join(core, pid); err != nil {
+					log.Errorw("failed to join the target cgroups", "error", err, "core", core, "pid", pid)
 
-				return
+					return
+				}
 			}
 
-			// join target cpuset cgroup in case it is used to pin the target on specific cores
-			i.config.Log.Infow("joining target cpuset cgroup", "core", core, "pid", pid)
+			// pin the current thread to the core it has to stress
+			log.Infow("pinning thread to the stressed core", "core", core, "pid", pid)
 
-			if err = i.config.Cgroup.Join("cpuset", pid, false); err != nil {
-				i.config.Log.Errorw("failed to join the target cpuset cgroup", "error", err, "core", core, "pid", pid)
+			if err = processManager.SetAffinity([]int{core}); err != nil {
+				log.Errorw("error pinning the thread to the stressed core", "error", err, "core", core, "pid", pid)
 
 				return
 			}
 
 			// prioritize the current process
-			i.config.Log.Infow("highering current process priority", "core", core, "pid", pid)
+			log.Infow("highering current process priority", "core", core, "pid", pid)
 
-			if err = i.config.ProcessManager.Prioritize(); err != nil {
-				i.config.Log.Errorw("error highering the current process priority", "error", err, "core", core, "pid", pid)
+			if err = processManager.Prioritize(); err != nil {
+				log.Errorw("error highering the current process priority", "error", err, "core", core, "pid", pid)
 
 				return
 			}
 
-			i.config.Log.Infow("starting the stresser", "core", core, "pid", pid)
+			log.Infow("starting the stresser", "core", core, "pid", pid)
 
+			lock.Lock()
 			tids = append(tids, pid)
-			i.routines++
+			lock.Unlock()
 
 			wg.Done()
-			i.config.Stresser.Stress(i.config.StresserExit)
+			stresser.Stress(exit)
 		}(core)
 	}
 
@@ -150,12 +288,12 @@ func (i *cpuPressureInjector) Inject() error {
 	wg.Wait()
 
 	if !succeeded {
-		return fmt.Errorf("at least one stresser routine failed to execute")
+		return len(tids), fmt.Errorf("at least one stresser routine failed to execute")
 	}
 
-	i.config.Log.Infow("all routines have been created successfully, now stressing", "routinesPID", tids)
+	log.Infow("all routines have been created successfully, now stressing", "routinesPID", tids)
 
-	return nil
+	return len(tids), nil
 }
 
 func (i *cpuPressureInjector) UpdateConfig(config Config) {
@@ -163,6 +301,23 @@ func (i *cpuPressureInjector) UpdateConfig(config Config) {
 }
 
 func (i *cpuPressureInjector) Clean() error {
+	// stop the stress process
+	if i.process != nil {
+		i.config.Log.Infow("stopping the CPU stress process", "pid", i.process.Pid)
+
+		if err := i.process.Signal(syscall.SIGTERM); err != nil {
+			return fmt.Errorf("error stopping the CPU stress process: %w", err)
+		}
+
+		if _, err := i.process.Wait(); err != nil {
+			return fmt.Errorf("error waiting for the CPU stress process to exit: %w", err)
+		}
+
+		i.process = nil
+
+		return nil
+	}
+
 	i.config.Log.Info("killing routines")
 
 	// exit the stress routines
@@ -170,6 +325,8 @@ func (i *cpuPressureInjector) Clean() error {
 		i.config.StresserExit <- struct{}{}
 	}
 
+	i.routines = 0
+
 	i.config.Log.Info("all routines has been killed, exiting")
 
 	return nil
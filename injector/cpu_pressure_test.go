@@ -5,9 +5,14 @@
 package injector_test
 
 import (
+	"os"
+	"os/exec"
+	"syscall"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/DataDog/chaos-controller/api/v1beta1"
 	"github.com/DataDog/chaos-controller/cgroup"
@@ -21,6 +26,7 @@ var _ = Describe("Failure", func() {
 	var (
 		config        CPUPressureInjectorConfig
 		cgroupManager *cgroup.ManagerMock
+		isCgroupV2    bool
 		ctn           *container.ContainerMock
 		stresser      *stress.StresserMock
 		stresserExit  chan struct{}
@@ -30,10 +36,13 @@ var _ = Describe("Failure", func() {
 	)
 
 	BeforeEach(func() {
+		isCgroupV2 = false
+
 		// cgroup
 		cgroupManager = &cgroup.ManagerMock{}
 		cgroupManager.On("Join", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		cgroupManager.On("Read", "cpuset", "cpuset.cpus").Return("0-1", nil)
+		cgroupManager.On("Read", "cpuset", "cpuset.cpus.effective").Return("0-1", nil)
 
 		// container
 		ctn = &container.ContainerMock{}
@@ -49,6 +58,7 @@ var _ = Describe("Failure", func() {
 		manager = &process.ManagerMock{}
 		manager.On("Prioritize").Return(nil)
 		manager.On("ThreadID").Return(666)
+		manager.On("SetAffinity", mock.Anything).Return(nil)
 
 		//config
 		config = CPUPressureInjectorConfig{
@@ -68,20 +78,17 @@ var _ = Describe("Failure", func() {
 	})
 
 	JustBeforeEach(func() {
+		cgroupManager.On("IsCgroupV2").Return(isCgroupV2)
+
 		inj = NewCPUPressureInjector(spec, config)
 	})
 
 	Describe("injection", func() {
 		JustBeforeEach(func() {
-			// because the cleaning phase is blocking, we start it in a goroutine
-			// and send a signal to the stresser exit handler
+			// the cleaning phase sends an exit signal to each stress routine
+			// and blocks until all of them have received it
 			Expect(inj.Inject()).To(BeNil())
-
-			go func() {
-				Expect(inj.Clean()).To(BeNil())
-			}()
-
-			stresserExit <- struct{}{}
+			Expect(inj.Clean()).To(BeNil())
 		})
 
 		It("should join the cpu and cpuset cgroups", func() {
@@ -90,6 +97,80 @@ var _ = Describe("Failure", func() {
 			cgroupManager.AssertNumberOfCalls(GinkgoT(), "Join", 4)
 		})
 
+		It("should pin each routine to a different core", func() {
+			manager.AssertCalled(GinkgoT(), "SetAffinity", []int{0})
+			manager.AssertCalled(GinkgoT(), "SetAffinity", []int{1})
+			manager.AssertNumberOfCalls(GinkgoT(), "SetAffinity", 2)
+		})
+
+		Context("with a count of cores to stress", func() {
+			BeforeEach(func() {
+				count := intstr.FromInt(1)
+				spec.Count = &count
+			})
+
+			It("should only stress the given number of cores", func() {
+				manager.AssertCalled(GinkgoT(), "SetAffinity", []int{0})
+				manager.AssertNumberOfCalls(GinkgoT(), "SetAffinity", 1)
+				cgroupManager.AssertNumberOfCalls(GinkgoT(), "Join", 2)
+			})
+		})
+
+		Context("with a percentage of cores to stress", func() {
+			BeforeEach(func() {
+				count := intstr.FromString("50%")
+				spec.Count = &count
+			})
+
+			It("should only stress the given percentage of cores", func() {
+				manager.AssertNumberOfCalls(GinkgoT(), "SetAffinity", 1)
+			})
+		})
+
+		Context("on a cgroup v2 host", func() {
+			var stressProcess *exec.Cmd
+
+			BeforeEach(func() {
+				isCgroupV2 = true
+
+				// the stress process is stopped on clean
+				stressProcess = exec.Command("sleep", "60")
+				Expect(stressProcess.Start()).To(BeNil())
+
+				ctn.On("PID").Return(uint32(42))
+				manager.On("Start", mock.Anything, mock.Anything).Return(stressProcess.Process, nil)
+			})
+
+			It("should read the effective cpuset allocated cores", func() {
+				cgroupManager.AssertCalled(GinkgoT(), "Read", "cpuset", "cpuset.cpus.effective")
+				cgroupManager.AssertNotCalled(GinkgoT(), "Read", "cpuset", "cpuset.cpus")
+			})
+
+			It("should start a stress process joining the target cgroup instead of moving the injector", func() {
+				manager.AssertCalled(GinkgoT(), "Start", mock.Anything, []string{CPUStressCommand, "--target-pid", "42", "--cores", "0,1", "--percentage", "100"})
+				cgroupManager.AssertNotCalled(GinkgoT(), "Join", mock.Anything, mock.Anything, mock.Anything)
+				manager.AssertNotCalled(GinkgoT(), "SetAffinity", mock.Anything)
+				stresser.AssertNotCalled(GinkgoT(), "Stress", mock.Anything)
+			})
+
+			It("should stop the stress process on clean", func() {
+				Expect(stressProcess.Process.Signal(syscall.Signal(0))).To(MatchError(os.ErrProcessDone))
+			})
+
+			Context("with a percentage and a count of cores to stress", func() {
+				BeforeEach(func() {
+					percentage := 50
+					count := intstr.FromInt(1)
+					spec.Percentage = &percentage
+					spec.Count = &count
+				})
+
+				It("should pass them to the stress process", func() {
+					manager.AssertCalled(GinkgoT(), "Start", mock.Anything, []string{CPUStressCommand, "--target-pid", "42", "--cores", "0", "--percentage", "50"})
+				})
+			})
+		})
+
 		It("should prioritize the current process", func() {
 			manager.AssertCalled(GinkgoT(), "Prioritize")
 		})
@@ -7,10 +7,12 @@ package injector
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strings"
 
 	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/dnsresolver"
 	"github.com/DataDog/chaos-controller/env"
 	"github.com/DataDog/chaos-controller/network"
 	"github.com/DataDog/chaos-controller/types"
@@ -23,12 +25,22 @@ type DNSDisruptionInjector struct {
 	config DNSDisruptionInjectorConfig
 }
 
-// DNSDisruptionInjectorConfig contains all needed drivers to create a dns disruption using `iptables`
+// dnsResolverAddr is the address the resolver listens on in the chaos pod, the target DNS queries being redirected to it
+const dnsResolverAddr = ":53"
+
+// DNSDisruptionInjectorConfig contains all needed drivers to create a dns disruption using `iptables` and a resolver
 type DNSDisruptionInjectorConfig struct {
 	Config
-	Iptables     network.Iptables
-	FileWriter   FileWriter
-	PythonRunner PythonRunner
+	Iptables  network.Iptables
+	Ip6tables network.Iptables // only used when the chaos pod has an IPv6
+	Resolver  dnsresolver.Resolver
+}
+
+// dnsRedirection is the iptables driver of an IP family along with the chaos pod IP of this family
+// the DNS queries sent over this IP family are redirected to
+type dnsRedirection struct {
+	iptables network.Iptables
+	podIP    string
 }
 
 // NewDNSDisruptionInjector creates a DNSDisruptionInjector object with the given config,
@@ -39,17 +51,12 @@ func NewDNSDisruptionInjector(spec v1beta1.DNSDisruptionSpec, config DNSDisrupti
 		config.Iptables, err = network.NewIptables(config.Log, config.DryRun)
 	}
 
-	if config.FileWriter == nil {
-		config.FileWriter = standardFileWriter{
-			dryRun: config.DryRun,
-		}
+	if config.Ip6tables == nil && err == nil {
+		config.Ip6tables, err = network.NewIp6tables(config.Log, config.DryRun)
 	}
 
-	if config.PythonRunner == nil {
-		config.PythonRunner = standardPythonRunner{
-			dryRun: config.DryRun,
-			log:    config.Log,
-		}
+	if config.Resolver == nil && err == nil {
+		config.Resolver, err = NewDNSResolver(spec, config.Config)
 	}
 
 	return &DNSDisruptionInjector{
@@ -66,65 +73,73 @@ func (i *DNSDisruptionInjector) GetDisruptionKind() chaostypes.DisruptionKindNam
 func (i *DNSDisruptionInjector) Inject() error {
 	i.config.Log.Infow("adding dns disruption", "spec", i.spec)
 
-	// get the chaos pod node IP from the environment variable
-	podIP, ok := os.LookupEnv(env.InjectorChaosPodIP)
-	if !ok {
-		return fmt.Errorf("%s environment variable must be set with the chaos pod IP", env.InjectorChaosPodIP)
+	redirections, err := i.redirections()
+	if err != nil {
+		return err
 	}
 
-	// Set up resolver config file
-	resolverConfig := []string{}
-	for _, record := range i.spec {
-		resolverConfig = append(resolverConfig, fmt.Sprintf("%s %s %s", record.Record.Type, record.Hostname, record.Record.Value))
-	}
+	// start the resolver before entering the target network namespace so it listens in the chaos pod
+	if !i.config.DryRun {
+		addr, err := i.config.Resolver.Start(dnsResolverAddr)
+		if err != nil {
+			return fmt.Errorf("unable to start the resolver: %w", err)
+		}
 
-	if err := i.config.FileWriter.Write("/tmp/dns.conf", 0644, strings.Join(resolverConfig, "\n")); err != nil {
-		return fmt.Errorf("unable to write resolver config: %w", err)
+		i.config.Log.Infow("resolver started", "addr", addr)
 	}
 
-	cmd := []string{"/usr/local/bin/dns_disruption_resolver.py", "-c", "/tmp/dns.conf"}
-
-	if i.config.DNS.DNSServer != "" {
-		cmd = append(cmd, "--dns", i.config.DNS.DNSServer)
+	// enter target network namespace
+	if err := i.config.Netns.Enter(); err != nil {
+		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
 	}
 
-	if i.config.DNS.KubeDNS != "" {
-		cmd = append(cmd, "--kube-dns", i.config.DNS.KubeDNS)
+	if i.config.Level == chaostypes.DisruptionLevelPod && !i.config.OnInit && !i.config.Cgroup.IsCgroupV2() {
+		// write classid to container net_cls cgroup - for iptable filtering
+		if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", types.InjectorCgroupClassID); err != nil {
+			return fmt.Errorf("error writing classid to pod net_cls cgroup: %w", err)
+		}
 	}
 
-	_, _, err := i.config.PythonRunner.RunPython(cmd...)
-	if err != nil {
-		return fmt.Errorf("unable to run resolver: %w", err)
+	// Set up iptables rules for each IP family of the chaos pod
+	for _, redirection := range redirections {
+		if err := i.addRedirectionRules(redirection); err != nil {
+			return err
+		}
 	}
 
-	// enter target network namespace
-	if err := i.config.Netns.Enter(); err != nil {
-		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
+	// exit target network namespace
+	if err := i.config.Netns.Exit(); err != nil {
+		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
 	}
 
-	// Set up iptables rules
-	if err := i.config.Iptables.CreateChain("CHAOS-DNS"); err != nil {
+	return nil
+}
+
+// addRedirectionRules creates the CHAOS-DNS chain DNATing the DNS queries to the given redirection chaos pod IP
+// and the rules sending the target DNS queries to it
+func (i *DNSDisruptionInjector) addRedirectionRules(redirection dnsRedirection) error {
+	if err := redirection.iptables.CreateChain("CHAOS-DNS"); err != nil {
 		return fmt.Errorf("unable to create new iptables chain: %w", err)
 	}
 
-	if err := i.config.Iptables.AddRuleWithIP("CHAOS-DNS", "udp", "53", "DNAT", podIP); err != nil {
+	if err := redirection.iptables.AddRuleWithIP("CHAOS-DNS", "udp", "53", "DNAT", redirection.podIP); err != nil {
 		return fmt.Errorf("unable to create new iptables rule: %w", err)
 	}
 
 	if i.config.Level == chaostypes.DisruptionLevelPod {
-		if !i.config.OnInit {
-			// write classid to container net_cls cgroup - for iptable filtering
-			if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", types.InjectorCgroupClassID); err != nil {
-				return fmt.Errorf("error writing classid to pod net_cls cgroup: %w", err)
+		if !i.config.OnInit && i.config.Cgroup.IsCgroupV2() {
+			// Redirect traffic coming from the target cgroup to CHAOS-DNS, the net_cls cgroup does not exist in cgroup v2
+			if err := redirection.iptables.AddCgroupPathFilterRule("OUTPUT", i.config.Cgroup.RelativePath(""), "udp", "53", "CHAOS-DNS"); err != nil {
+				return fmt.Errorf("unable to create new iptables rule: %w", err)
 			}
-
+		} else if !i.config.OnInit {
 			// Redirect traffic marked by targeted InjectorDNSCgroupClassID to CHAOS-DNS
-			if err := i.config.Iptables.AddCgroupFilterRule("OUTPUT", types.InjectorCgroupClassID, "udp", "53", "CHAOS-DNS"); err != nil {
+			if err := redirection.iptables.AddCgroupFilterRule("OUTPUT", types.InjectorCgroupClassID, "udp", "53", "CHAOS-DNS"); err != nil {
 				return fmt.Errorf("unable to create new iptables rule: %w", err)
 			}
 		} else {
 			// Redirect all dns related traffic in the pod to CHAOS-DNS
-			if err := i.config.Iptables.AddWideFilterRule("OUTPUT", "udp", "53", "CHAOS-DNS"); err != nil {
+			if err := redirection.iptables.AddWideFilterRule("OUTPUT", "udp", "53", "CHAOS-DNS"); err != nil {
 				return fmt.Errorf("unable to create new iptables rule: %w", err)
 			}
 		}
@@ -132,25 +147,20 @@ func (i *DNSDisruptionInjector) Inject() error {
 
 	if i.config.Level == chaostypes.DisruptionLevelNode {
 		// Exempt chaos pod from iptables re-routing
-		if err := i.config.Iptables.PrependRule("CHAOS-DNS", "-s", podIP, "-j", "RETURN"); err != nil {
+		if err := redirection.iptables.PrependRule("CHAOS-DNS", "-s", redirection.podIP, "-j", "RETURN"); err != nil {
 			return fmt.Errorf("unable to create new iptables rule: %w", err)
 		}
 
 		// Re-route all pods under node
-		if err := i.config.Iptables.PrependRule("OUTPUT", "-p", "udp", "--dport", "53", "-j", "CHAOS-DNS"); err != nil {
+		if err := redirection.iptables.PrependRule("OUTPUT", "-p", "udp", "--dport", "53", "-j", "CHAOS-DNS"); err != nil {
 			return fmt.Errorf("unable to create new iptables rule: %w", err)
 		}
 
-		if err := i.config.Iptables.PrependRule("PREROUTING", "-p", "udp", "--dport", "53", "-j", "CHAOS-DNS"); err != nil {
+		if err := redirection.iptables.PrependRule("PREROUTING", "-p", "udp", "--dport", "53", "-j", "CHAOS-DNS"); err != nil {
 			return fmt.Errorf("unable to create new iptables rule: %w", err)
 		}
 	}
 
-	// exit target network namespace
-	if err := i.config.Netns.Exit(); err != nil {
-		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
-	}
-
 	return nil
 }
 
@@ -160,31 +170,66 @@ func (i *DNSDisruptionInjector) UpdateConfig(config Config) {
 
 // Clean removes the injected disruption from the given container
 func (i *DNSDisruptionInjector) Clean() error {
+	redirections, err := i.redirections()
+	if err != nil {
+		return err
+	}
+
 	// enter target network namespace
 	if err := i.config.Netns.Enter(); err != nil {
 		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
 	}
 
+	if i.config.Level == chaostypes.DisruptionLevelPod && !i.config.OnInit && !i.config.Cgroup.IsCgroupV2() {
+		// write default classid to pod net_cls cgroup if it still exists
+		exists, err := i.config.Cgroup.Exists("net_cls")
+		if err != nil {
+			return fmt.Errorf("error checking if pod net_cls cgroup still exists: %w", err)
+		}
+
+		if exists {
+			if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", "0x0"); err != nil {
+				return fmt.Errorf("error reseting classid of pod net_cls cgroup: %w", err)
+			}
+		}
+	}
+
+	for _, redirection := range redirections {
+		if err := i.deleteRedirectionRules(redirection); err != nil {
+			return err
+		}
+	}
+
+	// exit target network namespace
+	if err := i.config.Netns.Exit(); err != nil {
+		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
+	}
+
+	// stop the resolver once no more queries are redirected to it
+	if !i.config.DryRun {
+		if err := i.config.Resolver.Close(); err != nil {
+			return fmt.Errorf("unable to stop the resolver: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteRedirectionRules deletes the rules and the chain created by addRedirectionRules
+func (i *DNSDisruptionInjector) deleteRedirectionRules(redirection dnsRedirection) error {
 	if i.config.Level == chaostypes.DisruptionLevelPod {
 		if i.config.OnInit {
-			if err := i.config.Iptables.DeleteRule("OUTPUT", "udp", "53", "CHAOS-DNS"); err != nil {
+			if err := redirection.iptables.DeleteRule("OUTPUT", "udp", "53", "CHAOS-DNS"); err != nil {
 				return fmt.Errorf("unable to remove injected iptables rule: %w", err)
 			}
-		} else {
-			// write default classid to pod net_cls cgroup if it still exists
-			exists, err := i.config.Cgroup.Exists("net_cls")
-			if err != nil {
-				return fmt.Errorf("error checking if pod net_cls cgroup still exists: %w", err)
-			}
-
-			if exists {
-				if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", "0x0"); err != nil {
-					return fmt.Errorf("error reseting classid of pod net_cls cgroup: %w", err)
-				}
+		} else if i.config.Cgroup.IsCgroupV2() {
+			// Delete iptables rules
+			if err := redirection.iptables.DeleteCgroupPathFilterRule("OUTPUT", i.config.Cgroup.RelativePath(""), "udp", "53", "CHAOS-DNS"); err != nil {
+				return fmt.Errorf("unable to remove injected iptables rule: %w", err)
 			}
-
+		} else {
 			// Delete iptables rules
-			if err := i.config.Iptables.DeleteCgroupFilterRule("OUTPUT", types.InjectorCgroupClassID, "udp", "53", "CHAOS-DNS"); err != nil {
+			if err := redirection.iptables.DeleteCgroupFilterRule("OUTPUT", types.InjectorCgroupClassID, "udp", "53", "CHAOS-DNS"); err != nil {
 				return fmt.Errorf("unable to remove injected iptables rule: %w", err)
 			}
 		}
@@ -192,24 +237,70 @@ func (i *DNSDisruptionInjector) Clean() error {
 
 	if i.config.Level == chaostypes.DisruptionLevelNode {
 		// Delete prerouting rule affecting all pods on node
-		if err := i.config.Iptables.DeleteRule("OUTPUT", "udp", "53", "CHAOS-DNS"); err != nil {
+		if err := redirection.iptables.DeleteRule("OUTPUT", "udp", "53", "CHAOS-DNS"); err != nil {
 			return fmt.Errorf("unable to remove new iptables rule: %w", err)
 		}
 
-		if err := i.config.Iptables.DeleteRule("PREROUTING", "udp", "53", "CHAOS-DNS"); err != nil {
+		if err := redirection.iptables.DeleteRule("PREROUTING", "udp", "53", "CHAOS-DNS"); err != nil {
 			return fmt.Errorf("unable to remove new iptables rule: %w", err)
 		}
 	}
 
-	if err := i.config.Iptables.ClearAndDeleteChain("CHAOS-DNS"); err != nil {
+	if err := redirection.iptables.ClearAndDeleteChain("CHAOS-DNS"); err != nil {
 		return fmt.Errorf("unable to remove injected iptables chain: %w", err)
 	}
 
-	// exit target network namespace
-	if err := i.config.Netns.Exit(); err != nil {
-		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
+	return nil
+}
+
+// NewDNSResolver creates the resolver of the given dns disruption, forwarding the queries it doesn't disrupt
+// according to the given config DNS settings; it can be shared by the injectors of the same disruption
+func NewDNSResolver(spec v1beta1.DNSDisruptionSpec, config Config) (dnsresolver.Resolver, error) {
+	upstream, err := dnsresolver.NewUpstream(config.DNS)
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure the resolver upstream: %w", err)
 	}
 
-	// There is nothing we need to do to shut down the resolver beyond letting the pod terminate
-	return nil
+	return dnsresolver.NewResolver(config.Log, spec, upstream)
+}
+
+// redirections returns the DNS redirection of each IP family of the chaos pod, read from the environment variables,
+// so IPv6 DNS queries are only redirected on dual-stack and IPv6 clusters
+func (i *DNSDisruptionInjector) redirections() ([]dnsRedirection, error) {
+	// get the chaos pod IP from the environment variable
+	podIP, ok := os.LookupEnv(env.InjectorChaosPodIP)
+	if !ok {
+		return nil, fmt.Errorf("%s environment variable must be set with the chaos pod IP", env.InjectorChaosPodIP)
+	}
+
+	// the chaos pod IPs are comma separated, one per IP family
+	podIPs := []string{podIP}
+	if value, ok := os.LookupEnv(env.InjectorChaosPodIPs); ok && value != "" {
+		podIPs = strings.Split(value, ",")
+	}
+
+	var ipv4Redirection, ipv6Redirection *dnsRedirection
+
+	for _, ip := range podIPs {
+		parsedIP := net.ParseIP(strings.TrimSpace(ip))
+
+		switch {
+		case parsedIP == nil:
+			return nil, fmt.Errorf("the chaos pod IP %s is not a valid IP", ip)
+		case parsedIP.To4() != nil && ipv4Redirection == nil:
+			ipv4Redirection = &dnsRedirection{iptables: i.config.Iptables, podIP: parsedIP.String()}
+		case parsedIP.To4() == nil && ipv6Redirection == nil:
+			ipv6Redirection = &dnsRedirection{iptables: i.config.Ip6tables, podIP: parsedIP.String()}
+		}
+	}
+
+	redirections := []dnsRedirection{}
+
+	for _, redirection := range []*dnsRedirection{ipv4Redirection, ipv6Redirection} {
+		if redirection != nil {
+			redirections = append(redirections, *redirection)
+		}
+	}
+
+	return redirections, nil
 }
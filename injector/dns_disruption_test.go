@@ -11,6 +11,7 @@ import (
 	"github.com/DataDog/chaos-controller/api/v1beta1"
 	"github.com/DataDog/chaos-controller/cgroup"
 	"github.com/DataDog/chaos-controller/container"
+	"github.com/DataDog/chaos-controller/dnsresolver"
 	"github.com/DataDog/chaos-controller/env"
 	. "github.com/DataDog/chaos-controller/injector"
 	"github.com/DataDog/chaos-controller/netns"
@@ -24,12 +25,15 @@ import (
 
 var _ = Describe("Failure", func() {
 	var (
-		inj           Injector
-		config        DNSDisruptionInjectorConfig
-		spec          v1beta1.DNSDisruptionSpec
-		cgroupManager *cgroup.ManagerMock
-		netnsManager  *netns.ManagerMock
-		iptables      *network.IptablesMock
+		inj            Injector
+		config         DNSDisruptionInjectorConfig
+		spec           v1beta1.DNSDisruptionSpec
+		cgroupManager  *cgroup.ManagerMock
+		isCgroupV2Call *mock.Call
+		netnsManager   *netns.ManagerMock
+		iptables       *network.IptablesMock
+		ip6tables      *network.IptablesMock
+		resolver       *dnsresolver.ResolverMock
 	)
 
 	BeforeEach(func() {
@@ -37,6 +41,8 @@ var _ = Describe("Failure", func() {
 		cgroupManager = &cgroup.ManagerMock{}
 		cgroupManager.On("Exists", "net_cls").Return(true, nil)
 		cgroupManager.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		cgroupManager.On("RelativePath", mock.Anything).Return("/kubepods/pod1/ctn1")
+		isCgroupV2Call = cgroupManager.On("IsCgroupV2").Return(false)
 
 		// netns
 		netnsManager = &netns.ManagerMock{}
@@ -46,23 +52,34 @@ var _ = Describe("Failure", func() {
 		// container
 		ctn := &container.ContainerMock{}
 
-		// pythonRunner
-		pythonRunner := &PythonRunnerMock{}
-		pythonRunner.On("RunPython", mock.Anything).Return(0, "", nil)
-
-		// iptables
-		iptables = &network.IptablesMock{}
-		iptables.On("CreateChain", mock.Anything).Return(nil)
-		iptables.On("ClearAndDeleteChain", mock.Anything).Return(nil)
-		iptables.On("AddRuleWithIP", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		iptables.On("AddWideFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		iptables.On("AddCgroupFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		iptables.On("PrependRule", mock.Anything, mock.Anything).Return(nil)
-		iptables.On("DeleteRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		iptables.On("DeleteCgroupFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		// resolver
+		resolver = &dnsresolver.ResolverMock{}
+		resolver.On("Start", mock.Anything).Return("[::]:53", nil)
+		resolver.On("Close").Return(nil)
+
+		// iptables and ip6tables
+		newIptablesMock := func() *network.IptablesMock {
+			ipt := &network.IptablesMock{}
+			ipt.On("CreateChain", mock.Anything).Return(nil)
+			ipt.On("ClearAndDeleteChain", mock.Anything).Return(nil)
+			ipt.On("AddRuleWithIP", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			ipt.On("AddWideFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			ipt.On("AddCgroupFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			ipt.On("PrependRule", mock.Anything, mock.Anything).Return(nil)
+			ipt.On("DeleteRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			ipt.On("DeleteCgroupFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			ipt.On("AddCgroupPathFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			ipt.On("DeleteCgroupPathFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			return ipt
+		}
+
+		iptables = newIptablesMock()
+		ip6tables = newIptablesMock()
 
 		// environment variables
 		Expect(os.Setenv(env.InjectorChaosPodIP, "10.0.0.2")).To(BeNil())
+		Expect(os.Unsetenv(env.InjectorChaosPodIPs)).To(BeNil())
 
 		// config
 		config = DNSDisruptionInjectorConfig{
@@ -74,8 +91,9 @@ var _ = Describe("Failure", func() {
 				Cgroup:          cgroupManager,
 				Level:           chaostypes.DisruptionLevelNode,
 			},
-			Iptables:     iptables,
-			PythonRunner: pythonRunner,
+			Iptables:  iptables,
+			Ip6tables: ip6tables,
+			Resolver:  resolver,
 		}
 
 		spec = v1beta1.DNSDisruptionSpec{}
@@ -97,11 +115,36 @@ var _ = Describe("Failure", func() {
 			netnsManager.AssertCalled(GinkgoT(), "Exit")
 		})
 
+		It("should start the resolver on the chaos pod DNS port", func() {
+			resolver.AssertCalled(GinkgoT(), "Start", ":53")
+		})
+
 		It("should create and set the CHAOS-DNS Chain", func() {
 			iptables.AssertCalled(GinkgoT(), "CreateChain", "CHAOS-DNS")
 			iptables.AssertCalled(GinkgoT(), "AddRuleWithIP", "CHAOS-DNS", "udp", "53", "DNAT", "10.0.0.2")
 		})
 
+		It("should not create any ip6tables rule on an IPv4 chaos pod", func() {
+			ip6tables.AssertNotCalled(GinkgoT(), "CreateChain", mock.Anything)
+		})
+
+		Context("with a dual-stack chaos pod", func() {
+			BeforeEach(func() {
+				Expect(os.Setenv(env.InjectorChaosPodIPs, "10.0.0.2,fd00::2")).To(BeNil())
+			})
+
+			It("should create and set the CHAOS-DNS Chain for both IP families", func() {
+				iptables.AssertCalled(GinkgoT(), "AddRuleWithIP", "CHAOS-DNS", "udp", "53", "DNAT", "10.0.0.2")
+				ip6tables.AssertCalled(GinkgoT(), "CreateChain", "CHAOS-DNS")
+				ip6tables.AssertCalled(GinkgoT(), "AddRuleWithIP", "CHAOS-DNS", "udp", "53", "DNAT", "fd00::2")
+			})
+
+			It("creates node-level ip6tables filter rules", func() {
+				ip6tables.AssertCalled(GinkgoT(), "PrependRule", "CHAOS-DNS", []string{"-s", "fd00::2", "-j", "RETURN"})
+				ip6tables.AssertCalled(GinkgoT(), "PrependRule", "OUTPUT", []string{"-p", "udp", "--dport", "53", "-j", "CHAOS-DNS"})
+			})
+		})
+
 		Context("disruption is node-level", func() {
 			It("creates node-level iptable filter rules", func() {
 				iptables.AssertCalled(GinkgoT(), "PrependRule", "CHAOS-DNS", []string{"-s", "10.0.0.2", "-j", "RETURN"})
@@ -120,6 +163,21 @@ var _ = Describe("Failure", func() {
 			It("should write the custom classid to the target net_cls cgroup", func() {
 				cgroupManager.AssertCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", types.InjectorCgroupClassID)
 			})
+
+			Context("on a cgroup v2 host", func() {
+				BeforeEach(func() {
+					isCgroupV2Call.Return(true)
+				})
+
+				It("creates pod-level iptable filter rules matching the target cgroup path", func() {
+					iptables.AssertCalled(GinkgoT(), "AddCgroupPathFilterRule", "OUTPUT", "/kubepods/pod1/ctn1", "udp", "53", "CHAOS-DNS")
+					iptables.AssertNotCalled(GinkgoT(), "AddCgroupFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				})
+
+				It("should not write any classid to the target net_cls cgroup", func() {
+					cgroupManager.AssertNotCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", mock.Anything)
+				})
+			})
 		})
 	})
 
@@ -137,6 +195,21 @@ var _ = Describe("Failure", func() {
 			iptables.AssertCalled(GinkgoT(), "ClearAndDeleteChain", "CHAOS-DNS")
 		})
 
+		It("should stop the resolver", func() {
+			resolver.AssertCalled(GinkgoT(), "Close")
+		})
+
+		Context("with a dual-stack chaos pod", func() {
+			BeforeEach(func() {
+				Expect(os.Setenv(env.InjectorChaosPodIPs, "10.0.0.2,fd00::2")).To(BeNil())
+			})
+
+			It("should clear the ip6tables rules and delete the CHAOS-DNS Chain", func() {
+				ip6tables.AssertCalled(GinkgoT(), "DeleteRule", "OUTPUT", "udp", "53", "CHAOS-DNS")
+				ip6tables.AssertCalled(GinkgoT(), "ClearAndDeleteChain", "CHAOS-DNS")
+			})
+		})
+
 		Context("disruption is node-level", func() {
 			It("should clear the node-level iptable rules", func() {
 				iptables.AssertCalled(GinkgoT(), "DeleteRule", "OUTPUT", "udp", "53", "CHAOS-DNS")
@@ -154,6 +227,16 @@ var _ = Describe("Failure", func() {
 			It("should reset the custom classid", func() {
 				cgroupManager.AssertCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", "0x0")
 			})
+
+			Context("on a cgroup v2 host", func() {
+				BeforeEach(func() {
+					isCgroupV2Call.Return(true)
+				})
+
+				It("should clear the pod-level iptables rules matching the target cgroup path", func() {
+					iptables.AssertCalled(GinkgoT(), "DeleteCgroupPathFilterRule", "OUTPUT", "/kubepods/pod1/ctn1", "udp", "53", "CHAOS-DNS")
+				})
+			})
 		})
 
 		Context("clean should be idempotent", func() {
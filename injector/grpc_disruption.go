@@ -34,7 +34,7 @@ func NewGRPCDisruptionInjector(spec v1beta1.GRPCDisruptionSpec, config GRPCDisru
 	return &GRPCDisruptionInjector{
 		spec:       spec,
 		config:     config,
-		serverAddr: config.TargetPodIP + ":" + strconv.Itoa(spec.Port),
+		serverAddr: config.TargetPodIP + ":" + strconv.Itoa(spec.GetListenerPort()),
 	}
 }
 
@@ -87,9 +87,14 @@ func (i *GRPCDisruptionInjector) Clean() error {
 		return err
 	}
 
+	client := pb.NewDisruptionListenerClient(conn)
+
+	// the status is retrieved before the disruption is removed since the listener forgets about it once reset
+	i.reportStatus(client)
+
 	i.config.Log.Infow("removing grpc disruption", "spec", i.spec)
 
-	err = chaos_grpc.ClearGrpcDisruptions(pb.NewDisruptionListenerClient(conn))
+	err = chaos_grpc.ClearGrpcDisruptions(client)
 
 	if err != nil {
 		i.config.Log.Error("Received an error: %v", err)
@@ -98,6 +103,24 @@ func (i *GRPCDisruptionInjector) Clean() error {
 	return conn.Close()
 }
 
+// reportStatus logs the number of calls seen by the disrupted endpoints and altered by each of their alterations,
+// the disruption is cleaned even if its status cannot be retrieved
+func (i *GRPCDisruptionInjector) reportStatus(client pb.DisruptionListenerClient) {
+	status, err := chaos_grpc.GetGrpcDisruptionStatus(client)
+	if err != nil {
+		i.config.Log.Warnw("could not retrieve the grpc disruption status", "error", err)
+		return
+	}
+
+	for _, endpoint := range status.Endpoints {
+		i.config.Log.Infow("grpc disruption endpoint status", "endpoint", endpoint.TargetEndpoint, "calls", endpoint.Calls)
+
+		for _, alteration := range endpoint.Alterations {
+			i.config.Log.Infow("grpc disruption alteration status", "endpoint", endpoint.TargetEndpoint, "alteration", chaos_grpc.DescribeAlteration(alteration.Alteration), "altered", alteration.Altered)
+		}
+	}
+}
+
 func connectToServer(serverAddr string) (*grpc.ClientConn, error) {
 	var opts []grpc.DialOption
 	opts = append(opts, grpc.WithInsecure()) // Future Work: make secure
@@ -0,0 +1,164 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package injector
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/httpproxy"
+	"github.com/DataDog/chaos-controller/network"
+	"github.com/DataDog/chaos-controller/types"
+)
+
+// httpDisruptionChain is the iptables chain redirecting the target requests to the proxy
+const httpDisruptionChain = "CHAOS-HTTP"
+
+// HTTPDisruptionInjector describes an http disruption
+type HTTPDisruptionInjector struct {
+	spec   v1beta1.HTTPDisruptionSpec
+	config HTTPDisruptionInjectorConfig
+}
+
+// HTTPDisruptionInjectorConfig contains all needed drivers to create an http disruption using `iptables` and a proxy
+type HTTPDisruptionInjectorConfig struct {
+	Config
+	Iptables network.Iptables
+	Proxy    httpproxy.Proxy
+}
+
+// NewHTTPDisruptionInjector creates an HTTPDisruptionInjector object with the given config,
+// missing fields are initialized with the defaults
+func NewHTTPDisruptionInjector(spec v1beta1.HTTPDisruptionSpec, config HTTPDisruptionInjectorConfig) (Injector, error) {
+	var err error
+	if config.Iptables == nil {
+		config.Iptables, err = network.NewIptables(config.Log, config.DryRun)
+	}
+
+	i := &HTTPDisruptionInjector{
+		spec:   spec,
+		config: config,
+	}
+
+	if i.config.Proxy == nil {
+		i.config.Proxy = httpproxy.NewProxy(config.Log, spec.Rules, config.TargetPodIP, i.dialInTargetNetns)
+	}
+
+	return i, err
+}
+
+func (i *HTTPDisruptionInjector) GetDisruptionKind() types.DisruptionKindName {
+	return types.DisruptionKindHTTPDisruption
+}
+
+// Inject injects the given http disruption into the given container
+func (i *HTTPDisruptionInjector) Inject() error {
+	if i.config.DryRun {
+		i.config.Log.Infow("adding dry run mode http disruption", "spec", i.spec)
+		return nil
+	}
+
+	i.config.Log.Infow("adding http disruption", "spec", i.spec)
+
+	// enter target network namespace so the proxy listens in it
+	if err := i.config.Netns.Enter(); err != nil {
+		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
+	}
+
+	if err := i.config.Iptables.CreateChain(httpDisruptionChain); err != nil {
+		return fmt.Errorf("unable to create new iptables chain: %w", err)
+	}
+
+	for _, port := range i.spec.Ports {
+		proxyPort, err := i.config.Proxy.Start(port)
+		if err != nil {
+			return fmt.Errorf("unable to start the proxy for port %d: %w", port, err)
+		}
+
+		// Redirect the requests received on the disrupted port to the proxy
+		if err := i.config.Iptables.AddRedirectRule(httpDisruptionChain, "tcp", strconv.Itoa(port), strconv.Itoa(proxyPort)); err != nil {
+			return fmt.Errorf("unable to create new iptables rule: %w", err)
+		}
+
+		if err := i.config.Iptables.AddWideFilterRule("PREROUTING", "tcp", strconv.Itoa(port), httpDisruptionChain); err != nil {
+			return fmt.Errorf("unable to create new iptables rule: %w", err)
+		}
+	}
+
+	// exit target network namespace
+	if err := i.config.Netns.Exit(); err != nil {
+		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
+	}
+
+	return nil
+}
+
+func (i *HTTPDisruptionInjector) UpdateConfig(config Config) {
+	i.config.Config = config
+}
+
+// Clean removes the injected disruption from the given container
+func (i *HTTPDisruptionInjector) Clean() error {
+	if i.config.DryRun {
+		i.config.Log.Infow("removing dry run mode http disruption", "spec", i.spec)
+		return nil
+	}
+
+	// enter target network namespace
+	if err := i.config.Netns.Enter(); err != nil {
+		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
+	}
+
+	for _, port := range i.spec.Ports {
+		if err := i.config.Iptables.DeleteRule("PREROUTING", "tcp", strconv.Itoa(port), httpDisruptionChain); err != nil {
+			return fmt.Errorf("unable to remove injected iptables rule: %w", err)
+		}
+	}
+
+	if err := i.config.Iptables.ClearAndDeleteChain(httpDisruptionChain); err != nil {
+		return fmt.Errorf("unable to remove injected iptables chain: %w", err)
+	}
+
+	// exit target network namespace
+	if err := i.config.Netns.Exit(); err != nil {
+		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
+	}
+
+	// stop the proxy once no more requests are redirected to it
+	if err := i.config.Proxy.Close(); err != nil {
+		return fmt.Errorf("unable to stop the proxy: %w", err)
+	}
+
+	return nil
+}
+
+// dialInTargetNetns opens the proxy connections to the target from its network namespace,
+// the requests are then seen as local ones and aren't redirected to the proxy again
+func (i *HTTPDisruptionInjector) dialInTargetNetns(ctx context.Context, network, addr string) (net.Conn, error) {
+	// keep the thread locked until the root network namespace is restored, exiting the namespace unlocks it once
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := i.config.Netns.Enter(); err != nil {
+		return nil, fmt.Errorf("unable to enter the given container network namespace: %w", err)
+	}
+
+	conn, dialErr := (&net.Dialer{}).DialContext(ctx, network, addr)
+
+	if err := i.config.Netns.Exit(); err != nil {
+		if conn != nil {
+			conn.Close()
+		}
+
+		return nil, fmt.Errorf("unable to exit the given container network namespace: %w", err)
+	}
+
+	return conn, dialErr
+}
@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package injector_test
+
+import (
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/container"
+	"github.com/DataDog/chaos-controller/httpproxy"
+	. "github.com/DataDog/chaos-controller/injector"
+	"github.com/DataDog/chaos-controller/netns"
+	"github.com/DataDog/chaos-controller/network"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("HTTP disruption", func() {
+	var (
+		inj          Injector
+		config       HTTPDisruptionInjectorConfig
+		spec         v1beta1.HTTPDisruptionSpec
+		netnsManager *netns.ManagerMock
+		iptables     *network.IptablesMock
+		proxy        *httpproxy.ProxyMock
+	)
+
+	BeforeEach(func() {
+		// netns
+		netnsManager = &netns.ManagerMock{}
+		netnsManager.On("Enter").Return(nil)
+		netnsManager.On("Exit").Return(nil)
+
+		// iptables
+		iptables = &network.IptablesMock{}
+		iptables.On("CreateChain", mock.Anything).Return(nil)
+		iptables.On("ClearAndDeleteChain", mock.Anything).Return(nil)
+		iptables.On("AddRedirectRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		iptables.On("AddWideFilterRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		iptables.On("DeleteRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		// proxy
+		proxy = &httpproxy.ProxyMock{}
+		proxy.On("Start", 80).Return(40000, nil)
+		proxy.On("Start", 8080).Return(40001, nil)
+		proxy.On("Close").Return(nil)
+
+		// config
+		config = HTTPDisruptionInjectorConfig{
+			Config: Config{
+				TargetContainer: &container.ContainerMock{},
+				TargetPodIP:     "10.0.0.2",
+				Log:             log,
+				MetricsSink:     ms,
+				Netns:           netnsManager,
+				Level:           chaostypes.DisruptionLevelPod,
+			},
+			Iptables: iptables,
+			Proxy:    proxy,
+		}
+
+		spec = v1beta1.HTTPDisruptionSpec{
+			Ports: []int{80, 8080},
+			Rules: []v1beta1.HTTPRule{
+				{
+					Method:     "GET",
+					Path:       "/api/orders*",
+					Percent:    20,
+					StatusCode: 503,
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		inj, err = NewHTTPDisruptionInjector(spec, config)
+		Expect(err).To(BeNil())
+	})
+
+	Describe("inj.Inject", func() {
+		JustBeforeEach(func() {
+			Expect(inj.Inject()).To(BeNil())
+		})
+
+		It("should enter and exit the target network namespace", func() {
+			netnsManager.AssertCalled(GinkgoT(), "Enter")
+			netnsManager.AssertCalled(GinkgoT(), "Exit")
+		})
+
+		It("should start a proxy for each port", func() {
+			proxy.AssertCalled(GinkgoT(), "Start", 80)
+			proxy.AssertCalled(GinkgoT(), "Start", 8080)
+		})
+
+		It("should redirect the requests received on each port to its proxy", func() {
+			iptables.AssertCalled(GinkgoT(), "CreateChain", "CHAOS-HTTP")
+			iptables.AssertCalled(GinkgoT(), "AddRedirectRule", "CHAOS-HTTP", "tcp", "80", "40000")
+			iptables.AssertCalled(GinkgoT(), "AddRedirectRule", "CHAOS-HTTP", "tcp", "8080", "40001")
+			iptables.AssertCalled(GinkgoT(), "AddWideFilterRule", "PREROUTING", "tcp", "80", "CHAOS-HTTP")
+			iptables.AssertCalled(GinkgoT(), "AddWideFilterRule", "PREROUTING", "tcp", "8080", "CHAOS-HTTP")
+		})
+
+		Context("in dry run mode", func() {
+			BeforeEach(func() {
+				config.DryRun = true
+			})
+
+			It("should neither start the proxy nor redirect requests", func() {
+				proxy.AssertNotCalled(GinkgoT(), "Start", mock.Anything)
+				iptables.AssertNotCalled(GinkgoT(), "AddRedirectRule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		})
+	})
+
+	Describe("inj.Clean", func() {
+		JustBeforeEach(func() {
+			Expect(inj.Clean()).To(BeNil())
+		})
+
+		It("should enter and exit the target network namespace", func() {
+			netnsManager.AssertCalled(GinkgoT(), "Enter")
+			netnsManager.AssertCalled(GinkgoT(), "Exit")
+		})
+
+		It("should remove the redirection rules and chain", func() {
+			iptables.AssertCalled(GinkgoT(), "DeleteRule", "PREROUTING", "tcp", "80", "CHAOS-HTTP")
+			iptables.AssertCalled(GinkgoT(), "DeleteRule", "PREROUTING", "tcp", "8080", "CHAOS-HTTP")
+			iptables.AssertCalled(GinkgoT(), "ClearAndDeleteChain", "CHAOS-HTTP")
+		})
+
+		It("should stop the proxy", func() {
+			proxy.AssertCalled(GinkgoT(), "Close")
+		})
+	})
+})
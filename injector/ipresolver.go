@@ -18,11 +18,9 @@ import (
 func resolveHost(client network.DNSClient, host string) ([]*net.IPNet, error) {
 	var ips []*net.IPNet
 
-	// return the wildcard 0.0.0.0/0 CIDR if the given host is an empty string
+	// return the wildcard 0.0.0.0/0 and ::/0 CIDRs if the given host is an empty string
 	if host == "" {
-		_, null, _ := net.ParseCIDR("0.0.0.0/0")
-
-		return []*net.IPNet{null}, nil
+		return wildcardIPNets(), nil
 	}
 
 	// try to parse the given host as a CIDR
@@ -39,30 +37,11 @@ func resolveHost(client network.DNSClient, host string) ([]*net.IPNet, error) {
 			}
 
 			for _, resolvedIP := range resolvedIPs {
-				ips = append(ips, &net.IPNet{
-					IP:   resolvedIP,
-					Mask: net.CIDRMask(32, 32),
-				})
+				ips = append(ips, singleIPNet(resolvedIP))
 			}
 		} else {
-			// ensure the parsed IP is an IPv4
-			// the net.ParseIP function returns an IPv4 with an IPv6 length
-			// the code blow ensures the parsed IP prefix is the default (empty) prefix
-			// of an IPv6 address:
-			// var v4InV6Prefix = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff}
-			var a, b [12]byte
-			copy(a[:], ip[0:12])
-			b[10] = 0xff
-			b[11] = 0xff
-			if a != b {
-				return nil, fmt.Errorf("the given IP (%s) seems to be an IPv6, aborting", host)
-			}
-
-			// use a /32 mask for a single IP
-			ips = append(ips, &net.IPNet{
-				IP:   ip[12:16],
-				Mask: net.CIDRMask(32, 32),
-			})
+			// use a /32 (IPv4) or /128 (IPv6) mask for a single IP
+			ips = append(ips, singleIPNet(ip))
 		}
 	} else {
 		// use the given CIDR network
@@ -75,3 +54,26 @@ func resolveHost(client network.DNSClient, host string) ([]*net.IPNet, error) {
 
 	return ips, nil
 }
+
+// singleIPNet returns the network containing the given IP only, with a /32 mask for an IPv4 and a /128 mask for an IPv6
+func singleIPNet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{
+			IP:   ip4,
+			Mask: net.CIDRMask(32, 32),
+		}
+	}
+
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(128, 128),
+	}
+}
+
+// wildcardIPNets returns the networks containing all IPv4 and all IPv6 addresses, in this order
+func wildcardIPNets() []*net.IPNet {
+	return []*net.IPNet{
+		{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, 32)},
+		{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)},
+	}
+}
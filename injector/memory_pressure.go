@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package injector
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/stress"
+	"github.com/DataDog/chaos-controller/types"
+)
+
+// memoryUnlimitedThreshold is the value above which a memory cgroup limit is considered as not set
+// (the kernel reports a page-aligned max int64 value when no limit is configured)
+const memoryUnlimitedThreshold = int64(1) << 62
+
+// memoryCgroupMutex is held while the process is moved to a target memory cgroup and allocates memory there
+// the process belongs to a single memory cgroup at once, so the injectors of a disruption targeting several containers take turns
+var memoryCgroupMutex sync.Mutex
+
+type memoryPressureInjector struct {
+	spec      v1beta1.MemoryPressureSpec
+	config    MemoryPressureInjectorConfig
+	stressing bool
+}
+
+// MemoryPressureInjectorConfig is the memory pressure injector config
+type MemoryPressureInjectorConfig struct {
+	Config
+	Stresser     stress.Stresser
+	StresserExit chan struct{}
+	FileWriter   FileWriter
+}
+
+// NewMemoryPressureInjector creates a memory pressure injector with the given config
+func NewMemoryPressureInjector(spec v1beta1.MemoryPressureSpec, config MemoryPressureInjectorConfig) Injector {
+	if config.StresserExit == nil {
+		config.StresserExit = make(chan struct{})
+	}
+
+	if config.FileWriter == nil {
+		config.FileWriter = standardFileWriter{
+			dryRun: config.DryRun,
+		}
+	}
+
+	return &memoryPressureInjector{
+		spec:   spec,
+		config: config,
+	}
+}
+
+func (i *memoryPressureInjector) GetDisruptionKind() types.DisruptionKindName {
+	return types.DisruptionKindMemoryPressure
+}
+
+func (i *memoryPressureInjector) Inject() error {
+	target, isPercent, err := i.spec.ParseTarget()
+	if err != nil {
+		return fmt.Errorf("error parsing the target memory usage: %w", err)
+	}
+
+	// cgroup v2 memory files are named differently
+	limitFile, usageFile := "memory.limit_in_bytes", "memory.usage_in_bytes"
+	if i.config.Cgroup.IsCgroupV2() {
+		limitFile, usageFile = "memory.max", "memory.current"
+	}
+
+	// compute the target usage from the cgroup memory limit if the target is a percentage
+	if isPercent {
+		limit, err := i.readMemoryCgroupValue(limitFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the target memory limit: %w", err)
+		}
+
+		if limit >= memoryUnlimitedThreshold {
+			return fmt.Errorf("the target has no memory limit, a percentage target can't be used")
+		}
+
+		target = limit * target / 100
+	}
+
+	// compute the amount of memory to allocate to reach the target usage
+	usage, err := i.readMemoryCgroupValue(usageFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the target memory usage: %w", err)
+	}
+
+	i.config.Log.Infow("target memory usage computed", "target", target, "usage", usage)
+
+	if usage >= target {
+		i.config.Log.Warnw("the target memory usage is already reached, no memory will be allocated", "target", target, "usage", usage)
+
+		return nil
+	}
+
+	// lower the current process OOM score to its minimum so the OOM killer picks the target processes instead of the injector
+	i.config.Log.Infow("lowering current process OOM score")
+
+	if err := i.config.FileWriter.Write("/proc/self/oom_score_adj", 0, "-1000"); err != nil {
+		return fmt.Errorf("error lowering the current process OOM score: %w", err)
+	}
+
+	// join the target memory cgroup so allocated memory is charged to it
+	// the whole process must be moved since memory is charged to the process owning it and not to the allocating thread
+	pid := os.Getpid()
+	i.config.Log.Infow("joining target memory cgroup", "pid", pid)
+
+	memoryCgroupMutex.Lock()
+	err = i.config.Cgroup.Join("memory", pid, true)
+	memoryCgroupMutex.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to join the target memory cgroup: %w", err)
+	}
+
+	stresser := i.config.Stresser
+	if stresser == nil {
+		stresser = stress.NewMemory(i.config.DryRun, target-usage, i.spec.RampDuration.Duration(), i.chargeToTarget)
+	}
+
+	i.config.Log.Infow("starting the stresser", "bytes", target-usage, "rampDuration", i.spec.RampDuration.Duration())
+
+	i.stressing = true
+
+	go stresser.Stress(i.config.StresserExit)
+
+	return nil
+}
+
+func (i *memoryPressureInjector) UpdateConfig(config Config) {
+	i.config.Config = config
+}
+
+func (i *memoryPressureInjector) Clean() error {
+	if !i.stressing {
+		return nil
+	}
+
+	i.config.Log.Info("releasing allocated memory")
+
+	// exit the stress routine
+	i.config.StresserExit <- struct{}{}
+	i.stressing = false
+
+	i.config.Log.Info("allocated memory has been released")
+
+	return nil
+}
+
+// chargeToTarget runs the given allocation once the process has joined the target memory cgroup, pages being charged to the
+// memory cgroup of the process when they are touched and staying charged to it once the process has moved to another cgroup
+// the allocation is skipped if the target memory cgroup can't be joined, the memory being charged to another target otherwise
+func (i *memoryPressureInjector) chargeToTarget(allocate func()) {
+	memoryCgroupMutex.Lock()
+	defer memoryCgroupMutex.Unlock()
+
+	if err := i.config.Cgroup.Join("memory", os.Getpid(), true); err != nil {
+		i.config.Log.Errorw("failed to join the target memory cgroup, skipping the allocation", "error", err)
+
+		return
+	}
+
+	allocate()
+}
+
+// readMemoryCgroupValue reads the given memory cgroup file and parses its content as an integer
+func (i *memoryPressureInjector) readMemoryCgroupValue(file string) (int64, error) {
+	raw, err := i.config.Cgroup.Read("memory", file)
+	if err != nil {
+		return 0, err
+	}
+
+	// cgroup v2 reports unset limits as max
+	if raw == "max" {
+		return math.MaxInt64, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s value %s: %w", file, raw, err)
+	}
+
+	return value, nil
+}
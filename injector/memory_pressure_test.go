@@ -0,0 +1,201 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+package injector_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/cgroup"
+	. "github.com/DataDog/chaos-controller/injector"
+	"github.com/DataDog/chaos-controller/stress"
+)
+
+var _ = Describe("Memory pressure", func() {
+	var (
+		config        MemoryPressureInjectorConfig
+		cgroupManager *cgroup.ManagerMock
+		stresser      *stress.StresserMock
+		fileWriter    *FileWriterMock
+		inj           Injector
+		spec          v1beta1.MemoryPressureSpec
+		usage         string
+		isCgroupV2    bool
+	)
+
+	BeforeEach(func() {
+		usage = "104857600"
+		isCgroupV2 = false
+
+		// stresser
+		stresser = &stress.StresserMock{}
+		stresser.On("Stress", mock.Anything).Return()
+
+		// file writer
+		fileWriter = &FileWriterMock{}
+		fileWriter.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		// spec
+		spec = v1beta1.MemoryPressureSpec{
+			Target: "512Mi",
+		}
+	})
+
+	JustBeforeEach(func() {
+		// cgroup
+		cgroupManager = &cgroup.ManagerMock{}
+		cgroupManager.On("Join", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		cgroupManager.On("Read", "memory", "memory.limit_in_bytes").Return("1073741824", nil)
+		cgroupManager.On("Read", "memory", "memory.usage_in_bytes").Return(usage, nil)
+		cgroupManager.On("Read", "memory", "memory.max").Return("1073741824", nil)
+		cgroupManager.On("Read", "memory", "memory.current").Return(usage, nil)
+		cgroupManager.On("IsCgroupV2").Return(isCgroupV2)
+
+		// config
+		config = MemoryPressureInjectorConfig{
+			Config: Config{
+				Cgroup:      cgroupManager,
+				Log:         log,
+				MetricsSink: ms,
+			},
+			Stresser:     stresser,
+			StresserExit: make(chan struct{}),
+			FileWriter:   fileWriter,
+		}
+
+		inj = NewMemoryPressureInjector(spec, config)
+	})
+
+	Describe("injection", func() {
+		JustBeforeEach(func() {
+			// the cleaning phase sends an exit signal to the stress routine
+			// and blocks until it has received it
+			Expect(inj.Inject()).To(BeNil())
+			Expect(inj.Clean()).To(BeNil())
+		})
+
+		It("should join the memory cgroup with the whole process", func() {
+			cgroupManager.AssertCalled(GinkgoT(), "Join", "memory", os.Getpid(), true)
+		})
+
+		It("should lower the process OOM score", func() {
+			fileWriter.AssertCalled(GinkgoT(), "Write", "/proc/self/oom_score_adj", mock.Anything, "-1000")
+		})
+
+		It("should run the stress routine", func() {
+			stresser.AssertCalled(GinkgoT(), "Stress")
+		})
+
+		It("should not read the memory limit with an absolute target", func() {
+			cgroupManager.AssertNotCalled(GinkgoT(), "Read", "memory", "memory.limit_in_bytes")
+		})
+
+		Context("with a percentage target", func() {
+			BeforeEach(func() {
+				spec.Target = "80%"
+			})
+
+			It("should read the memory limit", func() {
+				cgroupManager.AssertCalled(GinkgoT(), "Read", "memory", "memory.limit_in_bytes")
+			})
+
+			It("should run the stress routine", func() {
+				stresser.AssertCalled(GinkgoT(), "Stress")
+			})
+		})
+
+		Context("on a cgroup v2 host with a percentage target", func() {
+			BeforeEach(func() {
+				isCgroupV2 = true
+				spec.Target = "80%"
+			})
+
+			It("should read the cgroup v2 memory files", func() {
+				cgroupManager.AssertCalled(GinkgoT(), "Read", "memory", "memory.max")
+				cgroupManager.AssertCalled(GinkgoT(), "Read", "memory", "memory.current")
+				cgroupManager.AssertNotCalled(GinkgoT(), "Read", "memory", "memory.limit_in_bytes")
+			})
+
+			It("should run the stress routine", func() {
+				stresser.AssertCalled(GinkgoT(), "Stress")
+			})
+		})
+
+		Context("with a target already reached", func() {
+			BeforeEach(func() {
+				usage = "1073741824"
+			})
+
+			It("should neither join the memory cgroup nor run the stress routine", func() {
+				cgroupManager.AssertNotCalled(GinkgoT(), "Join", mock.Anything, mock.Anything, mock.Anything)
+				stresser.AssertNotCalled(GinkgoT(), "Stress")
+			})
+		})
+	})
+
+	Describe("injection in several target containers", func() {
+		var (
+			otherCgroupManager *cgroup.ManagerMock
+			otherInj           Injector
+		)
+
+		JustBeforeEach(func() {
+			otherCgroupManager = &cgroup.ManagerMock{}
+			otherCgroupManager.On("Join", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			otherCgroupManager.On("Read", "memory", "memory.usage_in_bytes").Return(usage, nil)
+			otherCgroupManager.On("IsCgroupV2").Return(isCgroupV2)
+
+			otherConfig := config
+			otherConfig.Config.Cgroup = otherCgroupManager
+			otherConfig.StresserExit = make(chan struct{})
+			otherInj = NewMemoryPressureInjector(spec, otherConfig)
+
+			Expect(inj.Inject()).To(BeNil())
+			Expect(otherInj.Inject()).To(BeNil())
+			Expect(inj.Clean()).To(BeNil())
+			Expect(otherInj.Clean()).To(BeNil())
+		})
+
+		It("should join the memory cgroup of each target container", func() {
+			cgroupManager.AssertCalled(GinkgoT(), "Join", "memory", os.Getpid(), true)
+			otherCgroupManager.AssertCalled(GinkgoT(), "Join", "memory", os.Getpid(), true)
+		})
+
+		It("should run a stress routine per target container", func() {
+			stresser.AssertNumberOfCalls(GinkgoT(), "Stress", 2)
+		})
+	})
+
+	Describe("injection with an unlimited target", func() {
+		BeforeEach(func() {
+			spec.Target = "80%"
+		})
+
+		JustBeforeEach(func() {
+			cgroupManager.ExpectedCalls = nil
+			cgroupManager.On("IsCgroupV2").Return(isCgroupV2)
+			cgroupManager.On("Read", "memory", "memory.limit_in_bytes").Return("9223372036854771712", nil)
+			cgroupManager.On("Read", "memory", "memory.max").Return("max", nil)
+		})
+
+		It("should fail", func() {
+			Expect(inj.Inject()).ToNot(BeNil())
+		})
+
+		Context("on a cgroup v2 host", func() {
+			BeforeEach(func() {
+				isCgroupV2 = true
+			})
+
+			It("should fail", func() {
+				Expect(inj.Inject()).ToNot(BeNil())
+			})
+		})
+	})
+})
@@ -11,6 +11,7 @@ import (
 	"math"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -32,6 +33,12 @@ type linkOperation func([]string, string, uint32) error
 // tcPriority the lowest priority set by tc automatically when adding a tc filter
 var tcPriority = uint32(49149)
 
+// ifbName is the name of the IFB device created in the target network namespace to shape the incoming traffic
+const ifbName = "chaos-ifb"
+
+// rejectChain is the name of the filter table chain rejecting the target packets
+const rejectChain = "CHAOS-REJECT"
+
 // networkDisruptionService describes a parsed Kubernetes service, representing an (ip, port, protocol) tuple
 type networkDisruptionService struct {
 	ip       *net.IPNet
@@ -50,12 +57,17 @@ func (n networkDisruptionService) String() string {
 
 // networkDisruptionInjector describes a network disruption
 type networkDisruptionInjector struct {
-	spec       v1beta1.NetworkDisruptionSpec
-	config     NetworkDisruptionInjectorConfig
-	operations []linkOperation
+	spec               v1beta1.NetworkDisruptionSpec
+	config             NetworkDisruptionInjectorConfig
+	operations         []linkOperation
+	profilesOperations [][]linkOperation // operations of each profile, in the same order as the spec profiles
+	peers              *peerWatcher      // partition peer pods watcher, shared by the tc trees
 
 	tcFilterPriority uint32     // keep track of the highest tc filter priority
 	tcFilterMutex    sync.Mutex // since we increment tcFilterPriority in goroutines we use a mutex to lock and unlock
+
+	cleaned      chan struct{} // closed once the disruption is cleaned, stopping all the watchers started by the last injection
+	cleanedMutex sync.Mutex    // the channel is replaced on every injection since the injector is injected again after being cleaned (pulse, reinjection)
 }
 
 // NetworkDisruptionInjectorConfig contains all needed drivers to create a network disruption using `tc`
@@ -64,6 +76,8 @@ type NetworkDisruptionInjectorConfig struct {
 	TrafficController network.TrafficController
 	NetlinkAdapter    network.NetlinkAdapter
 	DNSClient         network.DNSClient
+	Iptables          network.Iptables
+	Ip6tables         network.Iptables
 	State             DisruptionState
 }
 
@@ -74,9 +88,45 @@ type DisruptionState struct {
 // tcServiceFilter describes a tc filter, representing the service filtered and its priority
 type tcServiceFilter struct {
 	service  networkDisruptionService
+	parent   string // qdisc the filter is attached to, needed to delete it
 	priority uint32 // one priority per tc filters applied, the priority is the same for all interfaces
 }
 
+// rejectRule describes a rule of the reject chain, returning or rejecting the packets exchanged with the given IP
+// the rule is added with the iptables driver of the IP family
+type rejectRule struct {
+	ip      *net.IPNet
+	matches []string
+	reject  bool
+}
+
+// tcFilterTarget describes where tc filters are added and where they classify the matching packets
+type tcFilterTarget struct {
+	interfaces []string
+	parent     string // qdisc the filters are attached to
+	flowid     string // class the matching packets are classified in
+	incoming   bool   // filters apply to the incoming traffic redirected to the IFB device, matching the remote peer as the packets source
+}
+
+// peerWatcher watches the peer pods of a partition to keep one filter per peer pod IP on each of its targets
+type peerWatcher struct {
+	partitionSpec v1beta1.NetworkDisruptionPartitionSpec
+	labelSelector string
+	peerPods      []v1.Pod      // peer pods listed before building the tc trees, their filters being added to each target
+	targets       []*peerTarget // the interfaces and the IFB device the incoming traffic is redirected to
+
+	kubernetesPodsWatcher <-chan watch.Event
+	podsResourceVersion   string
+
+	cleaned <-chan struct{} // closed once the injection that started the watcher is cleaned
+}
+
+// peerTarget is a tc filters target of a partition along with its filters
+type peerTarget struct {
+	target          tcFilterTarget
+	tcFiltersPerPod map[string][]tcServiceFilter // filters of each peer pod having an IP, by pod name
+}
+
 // serviceWatcher
 type serviceWatcher struct {
 	// information about the service watched
@@ -94,11 +144,15 @@ type serviceWatcher struct {
 	kubernetesServiceWatcher       <-chan watch.Event
 	tcFiltersFromNamespaceServices []tcServiceFilter
 	servicesResourceVersion        string
+
+	cleaned <-chan struct{} // closed once the injection that started the watcher is cleaned
 }
 
 // NewNetworkDisruptionInjector creates a NetworkDisruptionInjector object with the given config,
 // missing field being initialized with the defaults
-func NewNetworkDisruptionInjector(spec v1beta1.NetworkDisruptionSpec, config NetworkDisruptionInjectorConfig) Injector {
+func NewNetworkDisruptionInjector(spec v1beta1.NetworkDisruptionSpec, config NetworkDisruptionInjectorConfig) (Injector, error) {
+	var err error
+
 	if config.TrafficController == nil {
 		config.TrafficController = network.NewTrafficController(config.Log, config.DryRun)
 	}
@@ -111,17 +165,22 @@ func NewNetworkDisruptionInjector(spec v1beta1.NetworkDisruptionSpec, config Net
 		config.DNSClient = network.NewDNSClient()
 	}
 
-	config.State = DisruptionState{}
+	// iptables is only used to classify the target packets on cgroup v2 hosts and to reject packets
+	usesIptables := spec.Reject != "" || (config.Cgroup != nil && config.Cgroup.IsCgroupV2())
 
-	go func() {
-		config.State.State <- Created
-	}()
+	if config.Iptables == nil && usesIptables {
+		config.Iptables, err = network.NewIptables(config.Log, config.DryRun)
+	}
+
+	if config.Ip6tables == nil && usesIptables && err == nil {
+		config.Ip6tables, err = network.NewIp6tables(config.Log, config.DryRun)
+	}
 
 	return &networkDisruptionInjector{
 		spec:       spec,
 		config:     config,
 		operations: []linkOperation{},
-	}
+	}, err
 }
 
 func (i *networkDisruptionInjector) GetDisruptionKind() chaostypes.DisruptionKindName {
@@ -130,39 +189,35 @@ func (i *networkDisruptionInjector) GetDisruptionKind() chaostypes.DisruptionKin
 
 // Inject injects the given network disruption into the given container
 func (i *networkDisruptionInjector) Inject() error {
+	// the watchers started by this injection stop once it is cleaned
+	i.cleanedMutex.Lock()
+	i.cleaned = make(chan struct{})
+	i.cleanedMutex.Unlock()
+
 	// enter target network namespace
 	if err := i.config.Netns.Enter(); err != nil {
 		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
 	}
 
-	i.config.Log.Infow("adding network disruptions", "drop", i.spec.Drop, "duplicate", i.spec.Duplicate, "corrupt", i.spec.Corrupt, "delay", i.spec.Delay, "delayJitter", i.spec.DelayJitter, "bandwidthLimit", i.spec.BandwidthLimit)
-
-	// add netem
-	if i.spec.Delay > 0 || i.spec.Drop > 0 || i.spec.Corrupt > 0 || i.spec.Duplicate > 0 {
-		delay := time.Duration(i.spec.Delay) * time.Millisecond
-
-		var delayJitter time.Duration
+	i.config.Log.Infow("adding network disruptions", "drop", i.spec.Drop, "duplicate", i.spec.Duplicate, "corrupt", i.spec.Corrupt, "delay", i.spec.Delay, "delayJitter", i.spec.DelayJitter, "bandwidthLimit", i.spec.BandwidthLimit, "ingressShaping", i.spec.IngressShaping, "partition", i.spec.Partition, "reject", i.spec.Reject)
 
-		// add a 10% delayJitter to delay by default if not specified
-		if i.spec.DelayJitter == 0 {
-			delayJitter = time.Duration(float64(i.spec.Delay)*0.1) * time.Millisecond
-		} else {
-			// convert delayJitter into a percentage then multiply that with delay to get correct percentage of delay
-			delayJitter = time.Duration((float64(i.spec.DelayJitter)/100.0)*float64(i.spec.Delay)) * time.Millisecond
-		}
+	netem := i.specNetemParams()
+	if i.spec.Partition != nil {
+		// a partition drops all the packets exchanged with the peer pods
+		netem = network.NetemParams{Drop: 100}
+	}
 
-		delayJitter = time.Duration(math.Max(float64(delayJitter), float64(time.Millisecond)))
+	i.operations = i.buildOperations(netem, i.spec.BandwidthLimit)
+	i.profilesOperations = [][]linkOperation{}
 
-		i.addNetemOperation(delay, delayJitter, i.spec.Drop, i.spec.Corrupt, i.spec.Duplicate)
-	}
+	for idx, profile := range i.spec.Profiles {
+		i.config.Log.Infow("adding network disruptions profile", "profile", idx, "hosts", profile.Hosts, "services", profile.Services, "drop", profile.Drop, "duplicate", profile.Duplicate, "corrupt", profile.Corrupt, "delay", profile.Delay, "delayJitter", profile.DelayJitter, "bandwidthLimit", profile.BandwidthLimit)
 
-	// add tbf
-	if i.spec.BandwidthLimit > 0 {
-		i.addOutputLimitOperation(uint(i.spec.BandwidthLimit))
+		i.profilesOperations = append(i.profilesOperations, i.buildOperations(netemParams(profile.Drop, profile.Duplicate, profile.Corrupt, profile.Delay, profile.DelayJitter), profile.BandwidthLimit))
 	}
 
 	// apply operations if any
-	if len(i.operations) > 0 {
+	if len(i.operations) > 0 || len(i.profilesOperations) > 0 {
 		if err := i.applyOperations(); err != nil {
 			return fmt.Errorf("error applying tc operations: %w", err)
 		}
@@ -170,11 +225,35 @@ func (i *networkDisruptionInjector) Inject() error {
 		i.config.Log.Debug("operations applied successfully")
 	}
 
-	i.config.Log.Info("editing pod net_cls cgroup to apply a classid to target container packets")
+	if i.config.Cgroup.IsCgroupV2() {
+		// the net_cls cgroup does not exist in cgroup v2, packets are classified with iptables by matching the target cgroup path instead
+		// it is only needed when the disruption is scoped to the target processes (see applyOperations)
+		if i.isScopedToTargetCgroup() {
+			i.config.Log.Info("adding an iptables rule to apply a class to target container packets")
+
+			if err := i.config.Iptables.AddCgroupPathClassifyRule(i.config.Cgroup.RelativePath(""), types.InjectorCgroupClass); err != nil {
+				return fmt.Errorf("error adding the iptables rule classifying the target cgroup packets: %w", err)
+			}
+
+			// IPv6 packets are classified by ip6tables, which fails on hosts where IPv6 is disabled and where
+			// there are no IPv6 packets to classify anyway
+			if err := i.config.Ip6tables.AddCgroupPathClassifyRule(i.config.Cgroup.RelativePath(""), types.InjectorCgroupClass); err != nil {
+				i.config.Log.Warnw("error adding the ip6tables rule classifying the target cgroup packets, IPv6 packets won't be disrupted", "error", err)
+			}
+		}
+	} else {
+		i.config.Log.Info("editing pod net_cls cgroup to apply a classid to target container packets")
+
+		// write classid to pod net_cls cgroup
+		if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", types.InjectorCgroupClassID); err != nil {
+			return fmt.Errorf("error writing classid to pod net_cls cgroup: %w", err)
+		}
+	}
 
-	// write classid to pod net_cls cgroup
-	if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", types.InjectorCgroupClassID); err != nil {
-		return fmt.Errorf("error writing classid to pod net_cls cgroup: %w", err)
+	if i.spec.Reject != "" {
+		if err := i.applyReject(); err != nil {
+			return fmt.Errorf("error adding the iptables rules rejecting packets: %w", err)
+		}
 	}
 
 	// exit target network namespace
@@ -182,10 +261,6 @@ func (i *networkDisruptionInjector) Inject() error {
 		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
 	}
 
-	go func() {
-		i.config.State.State <- Injected
-	}()
-
 	return nil
 }
 
@@ -195,11 +270,13 @@ func (i *networkDisruptionInjector) UpdateConfig(config Config) {
 
 // Clean removes all the injected disruption in the given container
 func (i *networkDisruptionInjector) Clean() error {
-	defer func() {
-		go func() {
-			i.config.State.State <- Cleaned
-		}()
-	}()
+	// stop the watchers first so they don't update the tc filters being cleared
+	i.cleanedMutex.Lock()
+	if i.cleaned != nil {
+		close(i.cleaned)
+		i.cleaned = nil
+	}
+	i.cleanedMutex.Unlock()
 
 	// enter container network namespace
 	if err := i.config.Netns.Enter(); err != nil {
@@ -214,15 +291,34 @@ func (i *networkDisruptionInjector) Clean() error {
 		return fmt.Errorf("error clearing tc operations: %w", err)
 	}
 
-	// write default classid to pod net_cls cgroup if it still exists
-	exists, err := i.config.Cgroup.Exists("net_cls")
-	if err != nil {
-		return fmt.Errorf("error checking if pod net_cls cgroup still exists: %w", err)
+	if i.spec.Reject != "" {
+		if err := i.clearReject(); err != nil {
+			return fmt.Errorf("error deleting the iptables rules rejecting packets: %w", err)
+		}
 	}
 
-	if exists {
-		if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", "0x0"); err != nil {
-			return fmt.Errorf("error reseting classid of pod net_cls cgroup: %w", err)
+	if i.config.Cgroup.IsCgroupV2() {
+		// remove the iptables rule classifying the target packets
+		if i.isScopedToTargetCgroup() {
+			if err := i.config.Iptables.DeleteCgroupPathClassifyRule(i.config.Cgroup.RelativePath(""), types.InjectorCgroupClass); err != nil {
+				return fmt.Errorf("error deleting the iptables rule classifying the target cgroup packets: %w", err)
+			}
+
+			if err := i.config.Ip6tables.DeleteCgroupPathClassifyRule(i.config.Cgroup.RelativePath(""), types.InjectorCgroupClass); err != nil {
+				i.config.Log.Warnw("error deleting the ip6tables rule classifying the target cgroup packets", "error", err)
+			}
+		}
+	} else {
+		// write default classid to pod net_cls cgroup if it still exists
+		exists, err := i.config.Cgroup.Exists("net_cls")
+		if err != nil {
+			return fmt.Errorf("error checking if pod net_cls cgroup still exists: %w", err)
+		}
+
+		if exists {
+			if err := i.config.Cgroup.Write("net_cls", "net_cls.classid", "0x0"); err != nil {
+				return fmt.Errorf("error reseting classid of pod net_cls cgroup: %w", err)
+			}
 		}
 	}
 
@@ -246,6 +342,15 @@ func (i *networkDisruptionInjector) Clean() error {
 //     if no host, port or protocol is specified, a filter redirecting all the traffic (0.0.0.0/0) to the disrupted band will be created
 //   - a last filter will be created to redirect traffic related to the local node through a not disrupted band
 //
+// When profiles are defined, a third prio qdisc is created in place of the operations to split the disrupted traffic
+// per destination: all the traffic is redirected to the disrupted band and filters attached to this third qdisc classify
+// the packets related to the hosts and services of each profile in the profile band, with its own chain of operations.
+// The operations defined at the disruption level are chained to the second band, the first band not being disrupted.
+//
+// When ingress shaping is enabled, the same tree is built on an IFB device the incoming traffic of all interfaces is
+// redirected to, without the cgroup prio qdisc since incoming packets are not classified yet, and with filters matching
+// the packets source instead of their destination.
+//
 // Here's the tc tree representation:
 // root (1:) <-- prio qdisc with 4 bands with a filter classifying packets matching the given dst ip, src/dst ports and protocol with class 1:4
 //
@@ -259,6 +364,20 @@ func (i *networkDisruptionInjector) Clean() error {
 //	      |- (3:) <-- first operation
 //	        |- (4:) <-- second operation
 //	          ...
+//
+// And with profiles:
+// root (1:) <-- prio qdisc with 4 bands with a filter classifying all packets with class 1:4
+//
+//	|- (1:4) <-- fourth band
+//	  |- (2:) <-- prio qdisc with 2 bands with a cgroup filter to classify packets according to their classid
+//	    |- (2:2) <-- second band
+//	      |- (3:) <-- prio qdisc with 2 bands plus one per profile with filters to classify packets per destination
+//	        |- (3:1) <-- first band, not disrupted
+//	        |- (3:2) <-- second band, disruption level operations
+//	          |- (4:) <-- first operation
+//	        |- (3:3) <-- third band, first profile operations
+//	          |- (5:) <-- first operation
+//	          ...
 func (i *networkDisruptionInjector) applyOperations() error {
 	i.tcFilterPriority = tcPriority
 
@@ -282,23 +401,9 @@ func (i *networkDisruptionInjector) applyOperations() error {
 
 	i.config.Log.Infof("detected default gateway IPs %s", defaultRoutes)
 
-	// get the targeted pod node IP from the environment variable
-	nodeIP, ok := os.LookupEnv(env.InjectorTargetPodHostIP)
-	if !ok {
-		return fmt.Errorf("%s environment variable must be set with the target pod node IP", env.InjectorTargetPodHostIP)
-	}
-
-	i.config.Log.Infof("target pod node IP is %s", nodeIP)
-
-	nodeIPNet := &net.IPNet{
-		IP:   net.ParseIP(nodeIP),
-		Mask: net.CIDRMask(32, 32),
-	}
-
-	// create cloud provider metadata service ipnet
-	metadataIPNet := &net.IPNet{
-		IP:   net.ParseIP("169.254.169.254"),
-		Mask: net.CIDRMask(32, 32),
+	nodeIPNet, err := i.targetPodNodeIPNet()
+	if err != nil {
+		return err
 	}
 
 	// set the tx qlen if not already set as it is required to create a prio qdisc without dropping
@@ -324,6 +429,84 @@ func (i *networkDisruptionInjector) applyOperations() error {
 		}
 	}
 
+	// the partition peer pods are resolved once, their filters being added to each tc tree and kept up to date by a single watcher
+	i.peers = nil
+	if i.spec.Partition != nil {
+		if i.peers, err = i.listPeers(*i.spec.Partition); err != nil {
+			return err
+		}
+	}
+
+	if err := i.applyTree(interfaces, false, defaultRoutes, nodeIPNet); err != nil {
+		return err
+	}
+
+	if i.shapesIngress() {
+		if err := i.applyIngressShaping(interfaces, defaultRoutes, nodeIPNet); err != nil {
+			return fmt.Errorf("error shaping the incoming traffic: %w", err)
+		}
+	}
+
+	if i.peers != nil {
+		go i.watchPeerChanges(i.peers)
+	}
+
+	return nil
+}
+
+// targetPodNodeIPNet returns the target pod node IP read from the environment variable
+func (i *networkDisruptionInjector) targetPodNodeIPNet() (*net.IPNet, error) {
+	nodeIP, ok := os.LookupEnv(env.InjectorTargetPodHostIP)
+	if !ok {
+		return nil, fmt.Errorf("%s environment variable must be set with the target pod node IP", env.InjectorTargetPodHostIP)
+	}
+
+	i.config.Log.Infof("target pod node IP is %s", nodeIP)
+
+	parsedNodeIP := net.ParseIP(nodeIP)
+	if parsedNodeIP == nil {
+		return nil, fmt.Errorf("the target pod node IP %s is not a valid IP", nodeIP)
+	}
+
+	return singleIPNet(parsedNodeIP), nil
+}
+
+// metadataIPNets returns the cloud provider metadata service ipnets, the IPv6 one being used by AWS on IPv6 enabled instances
+func metadataIPNets() []*net.IPNet {
+	return []*net.IPNet{
+		singleIPNet(net.ParseIP("169.254.169.254")),
+		singleIPNet(net.ParseIP("fd00:ec2::254")),
+	}
+}
+
+// applyIngressShaping creates an IFB device, builds the same tc tree on it as on the given interfaces
+// and redirects the incoming traffic of the given interfaces to it, so the operations apply to the incoming traffic as well
+// as the redirected packets go through the IFB device qdiscs before being received by the interface they come from
+func (i *networkDisruptionInjector) applyIngressShaping(interfaces []string, defaultRoutes []network.NetlinkRoute, nodeIPNet *net.IPNet) error {
+	i.config.Log.Infof("redirecting incoming traffic to the %s IFB device", ifbName)
+
+	// the IFB device is not created in dry-run mode, as any other change made to the network namespace
+	if !i.config.DryRun {
+		if _, err := i.config.NetlinkAdapter.LinkAddIFB(ifbName); err != nil {
+			return err
+		}
+	}
+
+	// the tree is built before redirecting the incoming traffic to the IFB device so it is never partially applied
+	if err := i.applyTree([]string{ifbName}, true, defaultRoutes, nodeIPNet); err != nil {
+		return err
+	}
+
+	if err := i.config.TrafficController.AddIngressRedirect(interfaces, ifbName); err != nil {
+		return fmt.Errorf("can't redirect the incoming traffic to the IFB device: %w", err)
+	}
+
+	return nil
+}
+
+// applyTree builds the tc tree described above on the given interfaces, the incoming parameter telling if the
+// interfaces receive the incoming traffic redirected to an IFB device instead of the outgoing traffic
+func (i *networkDisruptionInjector) applyTree(interfaces []string, incoming bool, defaultRoutes []network.NetlinkRoute, nodeIPNet *net.IPNet) error {
 	// create a new qdisc for the given interface of type prio with 4 bands instead of 3
 	// we keep the default priomap, the extra band will be used to filter traffic going to the specified IP
 	// we only create this qdisc if we want to target traffic going to some hosts only, it avoids to apply disruptions to all the traffic for a bit of time
@@ -342,15 +525,21 @@ func (i *networkDisruptionInjector) applyOperations() error {
 	// create a second qdisc to filter packets coming from this specific pod processes only
 	// if the disruption is applied on init, we consider that some more containers may be created within
 	// the pod so we can't scope the disruption to a specific set of containers
-	if i.config.Level == chaostypes.DisruptionLevelPod && !i.config.OnInit {
+	// incoming packets are not classified yet when going through the IFB device, they are all disrupted
+	// since the pod containers share the same network namespace
+	if i.isScopedToTargetCgroup() && !incoming {
 		// create second prio with only 2 bands to filter traffic with a specific classid
 		if err := i.config.TrafficController.AddPrio(interfaces, "1:4", 2, 2, [16]uint32{}); err != nil {
 			return fmt.Errorf("can't create a new qdisc: %w", err)
 		}
 
 		// create cgroup filter
-		if err := i.config.TrafficController.AddCgroupFilter(interfaces, "2:0", 2); err != nil {
-			return fmt.Errorf("can't create the cgroup filter: %w", err)
+		// on cgroup v2, packets are already classified in the 2:2 class by an iptables rule so the prio qdisc
+		// picks the right band by itself
+		if !i.config.Cgroup.IsCgroupV2() {
+			if err := i.config.TrafficController.AddCgroupFilter(interfaces, "2:0", 2); err != nil {
+				return fmt.Errorf("can't create the cgroup filter: %w", err)
+			}
 		}
 		// parent 2:2 refers to the 2nd band of the 2nd prio qdisc
 		// handle starts from 3 because 1 and 2 are used by the 2 prio qdiscs
@@ -358,87 +547,217 @@ func (i *networkDisruptionInjector) applyOperations() error {
 		handle = uint32(3)
 	}
 
-	// add operations
-	for _, operation := range i.operations {
-		if err := operation(interfaces, parent, handle); err != nil {
-			return fmt.Errorf("could not perform operation on newly created qdisc: %w", err)
+	if len(i.spec.Profiles) == 0 {
+		// add operations
+		if _, err := i.chainOperations(interfaces, i.operations, parent, handle); err != nil {
+			return err
 		}
 
-		// update parent reference and handle identifier for the next operation
-		// the next operation parent will be the current handle identifier
-		// the next handle identifier is just an increment of the actual one
-		parent = fmt.Sprintf("%d:", handle)
-		handle++
-	}
-
-	// create tc filters depending on the given hosts to match
-	// redirect all packets of all interfaces if no host is given
-	if len(i.spec.Hosts) == 0 && len(i.spec.Services) == 0 {
-		_, nullIP, _ := net.ParseCIDR("0.0.0.0/0")
-
-		if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, nil, nullIP, 0, 0, "", "1:4"); err != nil {
-			return fmt.Errorf("can't add a filter: %w", err)
-		}
-	} else {
-		// apply filters for given hosts
-		if err := i.addFiltersForHosts(interfaces, i.spec.Hosts, "1:4"); err != nil {
-			return fmt.Errorf("error adding filters for given hosts: %w", err)
-		}
+		// create tc filters depending on the given hosts and services to match, or on the partition peer pods
+		target := tcFilterTarget{interfaces: interfaces, parent: "1:0", flowid: "1:4", incoming: incoming}
 
-		// add or delete filters for given services depending on changes on the destination kubernetes services and associated pods
-		if err := i.handleFiltersForServices(interfaces, "1:4"); err != nil {
-			return fmt.Errorf("error adding filters for given services: %w", err)
+		if i.peers != nil {
+			if err := i.addPeerTarget(i.peers, target); err != nil {
+				return fmt.Errorf("error adding filters for the partition peer pods: %w", err)
+			}
+		} else if err := i.addFiltersForDestinations(target, i.spec.Hosts, i.spec.Services); err != nil {
+			return err
 		}
+	} else if err := i.applyProfiles(interfaces, incoming, parent, handle); err != nil {
+		return err
 	}
 
 	// the following lines are used to exclude some critical packets from any disruption such as health check probes
 	// depending on the network configuration, only one of those filters can be useful but we must add all of them
 	// those filters are only added if the related interface has been impacted by a disruption so far
 	// NOTE: those filters must be added after every other filters applied to the interface so they are used first
+	notDisrupted := tcFilterTarget{interfaces: interfaces, parent: "1:0", flowid: "1:1", incoming: incoming}
+
 	if i.config.Level == chaostypes.DisruptionLevelPod {
 		// this filter allows the pod to communicate with the default route gateway IP
 		for _, defaultRoute := range defaultRoutes {
-			gatewayIP := &net.IPNet{
-				IP:   defaultRoute.Gateway(),
-				Mask: net.CIDRMask(32, 32),
+			gatewayIP := singleIPNet(defaultRoute.Gateway())
+
+			// the incoming traffic of all interfaces goes through the IFB device
+			gatewayTarget := notDisrupted
+			if !incoming {
+				gatewayTarget.interfaces = []string{defaultRoute.Link().Name()}
 			}
 
-			if err := i.config.TrafficController.AddFilter([]string{defaultRoute.Link().Name()}, "1:0", i.getNewPriority(), 0, nil, gatewayIP, 0, 0, "", "1:1"); err != nil {
+			if err := i.addFilter(gatewayTarget, i.getNewPriority(), gatewayIP, 0, "", v1beta1.FlowEgress); err != nil {
 				return fmt.Errorf("can't add the default route gateway IP filter: %w", err)
 			}
 		}
 
 		// this filter allows the pod to communicate with the node IP
-		if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, nil, nodeIPNet, 0, 0, "", "1:1"); err != nil {
+		if err := i.addFilter(notDisrupted, i.getNewPriority(), nodeIPNet, 0, "", v1beta1.FlowEgress); err != nil {
 			return fmt.Errorf("can't add the target pod node IP filter: %w", err)
 		}
 	} else if i.config.Level == chaostypes.DisruptionLevelNode {
 		// GENERIC SAFEGUARDS
-		// allow SSH connections on all interfaces (port 22/tcp)
-		if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, nil, nil, 22, 0, "tcp", "1:1"); err != nil {
-			return fmt.Errorf("error adding filter allowing SSH connections: %w", err)
+		// allow SSH connections on all interfaces (port 22/tcp), over IPv4 and IPv6
+		for _, wildcardIP := range wildcardIPNets() {
+			if err := i.addFilter(notDisrupted, i.getNewPriority(), wildcardIP, 22, "tcp", v1beta1.FlowIngress); err != nil {
+				return fmt.Errorf("error adding filter allowing SSH connections: %w", err)
+			}
 		}
 
 		// CLOUD PROVIDER SPECIFIC SAFEGUARDS
 		// allow cloud provider health checks on all interfaces(arp)
-		if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, nil, nil, 0, 0, "arp", "1:1"); err != nil {
+		if err := i.addFilter(notDisrupted, i.getNewPriority(), nil, 0, "arp", v1beta1.FlowEgress); err != nil {
 			return fmt.Errorf("error adding filter allowing cloud providers health checks (ARP packets): %w", err)
 		}
 
 		// allow cloud provider metadata service communication
-		if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, nil, metadataIPNet, 0, 0, "", "1:1"); err != nil {
-			return fmt.Errorf("error adding filter allowing cloud providers health checks (ARP packets): %w", err)
+		for _, metadataIPNet := range metadataIPNets() {
+			if err := i.addFilter(notDisrupted, i.getNewPriority(), metadataIPNet, 0, "", v1beta1.FlowEgress); err != nil {
+				return fmt.Errorf("error adding filter allowing cloud providers metadata service communication: %w", err)
+			}
 		}
 	}
 
 	// add filters for allowed hosts
-	if err := i.addFiltersForHosts(interfaces, i.spec.AllowedHosts, "1:1"); err != nil {
+	if err := i.addFiltersForHosts(notDisrupted, i.spec.AllowedHosts); err != nil {
 		return fmt.Errorf("error adding filter for allowed hosts: %w", err)
 	}
 
 	return nil
 }
 
+// applyProfiles creates the prio qdisc splitting the disrupted traffic per destination under the given parent,
+// chains the disruption level and profiles operations to its bands and creates the filters classifying packets in them
+func (i *networkDisruptionInjector) applyProfiles(interfaces []string, incoming bool, parent string, handle uint32) error {
+	splitHandle := handle
+	splitParent := fmt.Sprintf("%d:0", splitHandle)
+	handle++
+
+	// packets not matching any filter go to the first band, unless the disruption level operations apply to all the traffic
+	// in which case they go to the second band
+	priomap := [16]uint32{}
+	hasDestinations := len(i.spec.Hosts) > 0 || len(i.spec.Services) > 0
+
+	if len(i.operations) > 0 && !hasDestinations {
+		for idx := range priomap {
+			priomap[idx] = 1
+		}
+	}
+
+	if err := i.config.TrafficController.AddPrio(interfaces, parent, splitHandle, uint32(2+len(i.spec.Profiles)), priomap); err != nil {
+		return fmt.Errorf("can't create a new qdisc: %w", err)
+	}
+
+	// chain the disruption level operations to the second band and each profile operations to its own band
+	var err error
+
+	if handle, err = i.chainOperations(interfaces, i.operations, fmt.Sprintf("%d:2", splitHandle), handle); err != nil {
+		return err
+	}
+
+	for idx, operations := range i.profilesOperations {
+		if handle, err = i.chainOperations(interfaces, operations, fmt.Sprintf("%d:%d", splitHandle, idx+3), handle); err != nil {
+			return fmt.Errorf("error applying profile %d: %w", idx, err)
+		}
+	}
+
+	// redirect all packets to the disrupted band, they are classified per destination by the next filters
+	for _, nullIP := range wildcardIPNets() {
+		if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, nil, nullIP, 0, 0, "", "1:4"); err != nil {
+			return fmt.Errorf("can't add a filter: %w", err)
+		}
+	}
+
+	// profiles filters are added first so they are used first
+	for idx, profile := range i.spec.Profiles {
+		target := tcFilterTarget{interfaces: interfaces, parent: splitParent, flowid: fmt.Sprintf("%d:%d", splitHandle, idx+3), incoming: incoming}
+
+		if err := i.addFiltersForDestinations(target, profile.Hosts, profile.Services); err != nil {
+			return fmt.Errorf("error applying profile %d: %w", idx, err)
+		}
+	}
+
+	if len(i.operations) > 0 && hasDestinations {
+		target := tcFilterTarget{interfaces: interfaces, parent: splitParent, flowid: fmt.Sprintf("%d:2", splitHandle), incoming: incoming}
+
+		if err := i.addFiltersForDestinations(target, i.spec.Hosts, i.spec.Services); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chainOperations applies the given operations to the given interfaces, each operation being attached to the previous one
+// starting from the given parent, and returns the next available handle identifier
+func (i *networkDisruptionInjector) chainOperations(interfaces []string, operations []linkOperation, parent string, handle uint32) (uint32, error) {
+	for _, operation := range operations {
+		if err := operation(interfaces, parent, handle); err != nil {
+			return 0, fmt.Errorf("could not perform operation on newly created qdisc: %w", err)
+		}
+
+		// update parent reference and handle identifier for the next operation
+		// the next operation parent will be the current handle identifier
+		// the next handle identifier is just an increment of the actual one
+		parent = fmt.Sprintf("%d:", handle)
+		handle++
+	}
+
+	return handle, nil
+}
+
+// addFiltersForDestinations creates tc filters for given hosts and services on the given target,
+// or a filter matching all packets if no host or service is given
+func (i *networkDisruptionInjector) addFiltersForDestinations(target tcFilterTarget, hosts []v1beta1.NetworkDisruptionHostSpec, services []v1beta1.NetworkDisruptionServiceSpec) error {
+	// redirect all IPv4 and IPv6 packets of all interfaces if no host is given
+	if len(hosts) == 0 && len(services) == 0 {
+		for _, nullIP := range wildcardIPNets() {
+			if err := i.addFilter(target, i.getNewPriority(), nullIP, 0, "", v1beta1.FlowEgress); err != nil {
+				return fmt.Errorf("can't add a filter: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	// apply filters for given hosts
+	if err := i.addFiltersForHosts(target, hosts); err != nil {
+		return fmt.Errorf("error adding filters for given hosts: %w", err)
+	}
+
+	// add or delete filters for given services depending on changes on the destination kubernetes services and associated pods
+	if err := i.handleFiltersForServices(target, services); err != nil {
+		return fmt.Errorf("error adding filters for given services: %w", err)
+	}
+
+	return nil
+}
+
+// addFilter creates a tc filter on the given target classifying the packets exchanged with the given IP and port
+// the flow tells if the port is the remote peer one (egress) or a local one (ingress), an outgoing packet being sent
+// to the remote peer and an incoming packet being received from it
+func (i *networkDisruptionInjector) addFilter(target tcFilterTarget, priority uint32, ip *net.IPNet, port int, protocol, flow string) error {
+	var (
+		srcPort, dstPort int
+		srcIP, dstIP     *net.IPNet
+	)
+
+	switch {
+	case flow == v1beta1.FlowIngress && target.incoming:
+		srcIP = ip
+		dstPort = port
+	case flow == v1beta1.FlowIngress:
+		srcIP = ip
+		srcPort = port
+	case target.incoming:
+		srcIP = ip
+		srcPort = port
+	default:
+		dstIP = ip
+		dstPort = port
+	}
+
+	return i.config.TrafficController.AddFilter(target.interfaces, target.parent, priority, 0, srcIP, dstIP, srcPort, dstPort, protocol, target.flowid)
+}
+
 func (i *networkDisruptionInjector) getNewPriority() uint32 {
 	priority := uint32(0)
 
@@ -450,16 +769,39 @@ func (i *networkDisruptionInjector) getNewPriority() uint32 {
 	return priority
 }
 
+// shapesIngress returns true if the disruption applies to the incoming traffic as well, which is always the case
+// for partitions since the packets sent by the peer pods must be dropped too
+func (i *networkDisruptionInjector) shapesIngress() bool {
+	return i.spec.IngressShaping || i.spec.Partition != nil
+}
+
+// isCleaned returns true once the injection that started a watcher is cleaned, the watcher having to stop even if events are pending
+func isCleaned(cleaned <-chan struct{}) bool {
+	select {
+	case <-cleaned:
+		return true
+	default:
+		return false
+	}
+}
+
+// isScopedToTargetCgroup returns true if the disruption only applies to packets coming from the target processes,
+// which is the case for pod level disruptions not applied on init
+func (i *networkDisruptionInjector) isScopedToTargetCgroup() bool {
+	return i.config.Level == chaostypes.DisruptionLevelPod && !i.config.OnInit
+}
+
 // addServiceFilters adds a list of service tc filters on a list of interfaces
-func (i *networkDisruptionInjector) addServiceFilters(serviceName string, filters []tcServiceFilter, interfaces []string, flowid string) ([]tcServiceFilter, error) {
+func (i *networkDisruptionInjector) addServiceFilters(serviceName string, filters []tcServiceFilter, target tcFilterTarget) ([]tcServiceFilter, error) {
 	builtServices := []tcServiceFilter{}
 
 	for _, filter := range filters {
+		filter.parent = target.parent
 		filter.priority = i.getNewPriority()
 
 		i.config.Log.Infow("found service endpoint", "resolvedEndpoint", filter.service.String(), "resolvedService", serviceName)
 
-		err := i.config.TrafficController.AddFilter(interfaces, "1:0", filter.priority, 0, nil, filter.service.ip, 0, filter.service.port, filter.service.protocol, flowid)
+		err := i.addFilter(target, filter.priority, filter.service.ip, filter.service.port, filter.service.protocol, v1beta1.FlowEgress)
 		if err != nil {
 			return nil, err
 		}
@@ -473,7 +815,7 @@ func (i *networkDisruptionInjector) addServiceFilters(serviceName string, filter
 // removeServiceFilter delete tc filters using its priority
 func (i *networkDisruptionInjector) removeServiceFilter(interfaces []string, tcFilter tcServiceFilter) error {
 	for _, iface := range interfaces {
-		if err := i.config.TrafficController.DeleteFilter(iface, tcFilter.priority); err != nil {
+		if err := i.config.TrafficController.DeleteFilter(iface, tcFilter.parent, tcFilter.priority); err != nil {
 			return err
 		}
 	}
@@ -500,22 +842,32 @@ func (i *networkDisruptionInjector) removeServiceFiltersInList(interfaces []stri
 
 // buildServiceFiltersFromPod builds a list of tc filters per pod endpoint using the service ports
 func (i *networkDisruptionInjector) buildServiceFiltersFromPod(pod v1.Pod, servicePorts []v1.ServicePort) []tcServiceFilter {
-	// compute endpoint IP (pod IP)
-	_, endpointIP, _ := net.ParseCIDR(fmt.Sprintf("%s/32", pod.Status.PodIP))
+	// compute endpoint IPs (pod IPs, one per IP family on dual-stack clusters)
+	podIPs := []string{pod.Status.PodIP}
 
-	endpointsToWatch := []tcServiceFilter{}
+	if len(pod.Status.PodIPs) > 0 {
+		podIPs = []string{}
 
-	for _, port := range servicePorts {
-		filter := tcServiceFilter{
-			service: networkDisruptionService{
-				ip:       endpointIP,
-				port:     int(port.TargetPort.IntVal),
-				protocol: string(port.Protocol),
-			},
+		for _, podIP := range pod.Status.PodIPs {
+			podIPs = append(podIPs, podIP.IP)
 		}
+	}
+
+	endpointsToWatch := []tcServiceFilter{}
+
+	for _, endpointIP := range parseIPNets(podIPs) {
+		for _, port := range servicePorts {
+			filter := tcServiceFilter{
+				service: networkDisruptionService{
+					ip:       endpointIP,
+					port:     int(port.TargetPort.IntVal),
+					protocol: string(port.Protocol),
+				},
+			}
 
-		if i.findServiceFilter(endpointsToWatch, filter) == -1 { // forbid duplication
-			endpointsToWatch = append(endpointsToWatch, filter)
+			if i.findServiceFilter(endpointsToWatch, filter) == -1 { // forbid duplication
+				endpointsToWatch = append(endpointsToWatch, filter)
+			}
 		}
 	}
 
@@ -524,8 +876,11 @@ func (i *networkDisruptionInjector) buildServiceFiltersFromPod(pod v1.Pod, servi
 
 // buildServiceFiltersFromService builds a list of tc filters per service using the service ports
 func (i *networkDisruptionInjector) buildServiceFiltersFromService(service v1.Service, servicePorts []v1.ServicePort) []tcServiceFilter {
-	// compute service IP (cluster IP)
-	_, serviceIP, _ := net.ParseCIDR(fmt.Sprintf("%s/32", service.Spec.ClusterIP))
+	// compute service IPs (cluster IPs, one per IP family on dual-stack clusters)
+	clusterIPs := service.Spec.ClusterIPs
+	if len(clusterIPs) == 0 {
+		clusterIPs = []string{service.Spec.ClusterIP}
+	}
 
 	endpointsToWatch := []tcServiceFilter{}
 
@@ -533,23 +888,38 @@ func (i *networkDisruptionInjector) buildServiceFiltersFromService(service v1.Se
 		return endpointsToWatch
 	}
 
-	for _, port := range servicePorts {
-		filter := tcServiceFilter{
-			service: networkDisruptionService{
-				ip:       serviceIP,
-				port:     int(port.Port),
-				protocol: string(port.Protocol),
-			},
-		}
+	for _, serviceIP := range parseIPNets(clusterIPs) {
+		for _, port := range servicePorts {
+			filter := tcServiceFilter{
+				service: networkDisruptionService{
+					ip:       serviceIP,
+					port:     int(port.Port),
+					protocol: string(port.Protocol),
+				},
+			}
 
-		if i.findServiceFilter(endpointsToWatch, filter) == -1 { // forbid duplication
-			endpointsToWatch = append(endpointsToWatch, filter)
+			if i.findServiceFilter(endpointsToWatch, filter) == -1 { // forbid duplication
+				endpointsToWatch = append(endpointsToWatch, filter)
+			}
 		}
 	}
 
 	return endpointsToWatch
 }
 
+// parseIPNets returns the single IP networks of the given IPs, ignoring the ones that can't be parsed
+func parseIPNets(ips []string) []*net.IPNet {
+	ipNets := []*net.IPNet{}
+
+	for _, ip := range ips {
+		if parsedIP := net.ParseIP(ip); parsedIP != nil {
+			ipNets = append(ipNets, singleIPNet(parsedIP))
+		}
+	}
+
+	return ipNets
+}
+
 func (i *networkDisruptionInjector) handleWatchError(event watch.Event) error {
 	err, ok := event.Object.(*metav1.Status)
 	if ok {
@@ -570,7 +940,7 @@ func (i *networkDisruptionInjector) findServiceFilter(tcFilters []tcServiceFilte
 }
 
 // handlePodEndpointsOnServicePortsChange on service changes, delete old filters with the wrong service ports and create new filters
-func (i *networkDisruptionInjector) handlePodEndpointsServiceFiltersOnKubernetesServiceChanges(serviceSpec v1beta1.NetworkDisruptionServiceSpec, oldFilters []tcServiceFilter, pods []v1.Pod, servicePorts []v1.ServicePort, interfaces []string, flowid string) ([]tcServiceFilter, error) {
+func (i *networkDisruptionInjector) handlePodEndpointsServiceFiltersOnKubernetesServiceChanges(serviceSpec v1beta1.NetworkDisruptionServiceSpec, oldFilters []tcServiceFilter, pods []v1.Pod, servicePorts []v1.ServicePort, target tcFilterTarget) ([]tcServiceFilter, error) {
 	tcFiltersToCreate, finalTcFilters := []tcServiceFilter{}, []tcServiceFilter{}
 
 	for _, pod := range pods {
@@ -585,13 +955,13 @@ func (i *networkDisruptionInjector) handlePodEndpointsServiceFiltersOnKubernetes
 			finalTcFilters = append(finalTcFilters, oldFilter)
 			tcFiltersToCreate = append(tcFiltersToCreate[:idx], tcFiltersToCreate[idx+1:]...)
 		} else { // delete tc filters which are not in the updated list of tc filters
-			if err := i.removeServiceFilter(interfaces, oldFilter); err != nil {
+			if err := i.removeServiceFilter(target.interfaces, oldFilter); err != nil {
 				return nil, err
 			}
 		}
 	}
 
-	createdTcFilters, err := i.addServiceFilters(serviceSpec.Name, tcFiltersToCreate, interfaces, flowid)
+	createdTcFilters, err := i.addServiceFilters(serviceSpec.Name, tcFiltersToCreate, target)
 	if err != nil {
 		return nil, err
 	}
@@ -600,7 +970,7 @@ func (i *networkDisruptionInjector) handlePodEndpointsServiceFiltersOnKubernetes
 }
 
 // handleKubernetesPodsChanges for every changes happening in the kubernetes service destination, we update the tc service filters
-func (i *networkDisruptionInjector) handleKubernetesServiceChanges(event watch.Event, watcher *serviceWatcher, interfaces []string, flowid string) error {
+func (i *networkDisruptionInjector) handleKubernetesServiceChanges(event watch.Event, watcher *serviceWatcher, target tcFilterTarget) error {
 	var err error
 
 	if event.Type == watch.Error {
@@ -643,7 +1013,7 @@ func (i *networkDisruptionInjector) handleKubernetesServiceChanges(event watch.E
 		watcher.servicePorts = service.Spec.Ports
 	}
 
-	watcher.tcFiltersFromPodEndpoints, err = i.handlePodEndpointsServiceFiltersOnKubernetesServiceChanges(watcher.watchedServiceSpec, watcher.tcFiltersFromPodEndpoints, podList.Items, service.Spec.Ports, interfaces, flowid)
+	watcher.tcFiltersFromPodEndpoints, err = i.handlePodEndpointsServiceFiltersOnKubernetesServiceChanges(watcher.watchedServiceSpec, watcher.tcFiltersFromPodEndpoints, podList.Items, service.Spec.Ports, target)
 	if err != nil {
 		return err
 	}
@@ -652,23 +1022,23 @@ func (i *networkDisruptionInjector) handleKubernetesServiceChanges(event watch.E
 
 	switch event.Type {
 	case watch.Added:
-		createdTcFilters, err := i.addServiceFilters(watcher.watchedServiceSpec.Name, nsServicesTcFilters, interfaces, flowid)
+		createdTcFilters, err := i.addServiceFilters(watcher.watchedServiceSpec.Name, nsServicesTcFilters, target)
 		if err != nil {
 			return err
 		}
 
 		watcher.tcFiltersFromNamespaceServices = append(watcher.tcFiltersFromNamespaceServices, createdTcFilters...)
 	case watch.Modified:
-		if _, err := i.removeServiceFiltersInList(interfaces, watcher.tcFiltersFromNamespaceServices, watcher.tcFiltersFromNamespaceServices); err != nil {
+		if _, err := i.removeServiceFiltersInList(target.interfaces, watcher.tcFiltersFromNamespaceServices, watcher.tcFiltersFromNamespaceServices); err != nil {
 			return err
 		}
 
-		watcher.tcFiltersFromNamespaceServices, err = i.addServiceFilters(watcher.watchedServiceSpec.Name, nsServicesTcFilters, interfaces, flowid)
+		watcher.tcFiltersFromNamespaceServices, err = i.addServiceFilters(watcher.watchedServiceSpec.Name, nsServicesTcFilters, target)
 		if err != nil {
 			return err
 		}
 	case watch.Deleted:
-		watcher.tcFiltersFromNamespaceServices, err = i.removeServiceFiltersInList(interfaces, watcher.tcFiltersFromNamespaceServices, nsServicesTcFilters)
+		watcher.tcFiltersFromNamespaceServices, err = i.removeServiceFiltersInList(target.interfaces, watcher.tcFiltersFromNamespaceServices, nsServicesTcFilters)
 		if err != nil {
 			return err
 		}
@@ -682,7 +1052,7 @@ func (i *networkDisruptionInjector) handleKubernetesServiceChanges(event watch.E
 }
 
 // handleKubernetesPodsChanges for every changes happening in the pods related to the kubernetes service destination, we update the tc service filters
-func (i *networkDisruptionInjector) handleKubernetesPodsChanges(event watch.Event, watcher *serviceWatcher, interfaces []string, flowid string) error {
+func (i *networkDisruptionInjector) handleKubernetesPodsChanges(event watch.Event, watcher *serviceWatcher, target tcFilterTarget) error {
 	var err error
 
 	if event.Type == watch.Error {
@@ -716,7 +1086,7 @@ func (i *networkDisruptionInjector) handleKubernetesPodsChanges(event watch.Even
 		}
 
 		if pod.Status.PodIP != "" {
-			createdTcFilters, err := i.addServiceFilters(watcher.watchedServiceSpec.Name, tcFiltersFromPod, interfaces, flowid)
+			createdTcFilters, err := i.addServiceFilters(watcher.watchedServiceSpec.Name, tcFiltersFromPod, target)
 			if err != nil {
 				return err
 			}
@@ -740,7 +1110,7 @@ func (i *networkDisruptionInjector) handleKubernetesPodsChanges(event watch.Even
 		}
 
 		if podToCreateIdx > -1 {
-			tcFilters, err := i.addServiceFilters(watcher.watchedServiceSpec.Name, tcFiltersFromPod, interfaces, flowid)
+			tcFilters, err := i.addServiceFilters(watcher.watchedServiceSpec.Name, tcFiltersFromPod, target)
 			if err != nil {
 				return err
 			}
@@ -749,7 +1119,7 @@ func (i *networkDisruptionInjector) handleKubernetesPodsChanges(event watch.Even
 			watcher.podsWithoutIPs = append(watcher.podsWithoutIPs[:podToCreateIdx], watcher.podsWithoutIPs[podToCreateIdx+1:]...)
 		}
 	case watch.Deleted:
-		watcher.tcFiltersFromPodEndpoints, err = i.removeServiceFiltersInList(interfaces, watcher.tcFiltersFromPodEndpoints, tcFiltersFromPod)
+		watcher.tcFiltersFromPodEndpoints, err = i.removeServiceFiltersInList(target.interfaces, watcher.tcFiltersFromPodEndpoints, tcFiltersFromPod)
 		if err != nil {
 			return err
 		}
@@ -763,7 +1133,7 @@ func (i *networkDisruptionInjector) handleKubernetesPodsChanges(event watch.Even
 }
 
 // watchServiceChanges for every changes happening in the kubernetes service destination or in the pods related to the kubernetes service destination, we update the tc service filters
-func (i *networkDisruptionInjector) watchServiceChanges(watcher serviceWatcher, interfaces []string, flowid string) {
+func (i *networkDisruptionInjector) watchServiceChanges(watcher serviceWatcher, target tcFilterTarget) {
 	for {
 		// We create the watcher channels when it's closed
 		if watcher.kubernetesServiceWatcher == nil {
@@ -797,21 +1167,23 @@ func (i *networkDisruptionInjector) watchServiceChanges(watcher serviceWatcher,
 			watcher.kubernetesPodEndpointsWatcher = podsWatcher.ResultChan()
 		}
 
+		if isCleaned(watcher.cleaned) {
+			return
+		}
+
 		select {
-		case state := <-i.config.State.State:
-			if state == Cleaned {
-				return
-			}
+		case <-watcher.cleaned:
+			return
 		case event, ok := <-watcher.kubernetesServiceWatcher: // We have changes in the service watched
 			if !ok { // channel is closed
 				watcher.kubernetesServiceWatcher = nil
 			} else {
 				i.config.Log.Debugw(fmt.Sprintf("changes in service %s/%s", watcher.watchedServiceSpec.Name, watcher.watchedServiceSpec.Namespace), "eventType", event.Type)
 
-				if err := i.handleKubernetesServiceChanges(event, &watcher, interfaces, flowid); err != nil {
+				if err := i.handleKubernetesServiceChanges(event, &watcher, target); err != nil {
 					i.config.Log.Errorf("couldn't apply changes to tc filters: %w... Rebuilding watcher", err)
 
-					if _, err = i.removeServiceFiltersInList(interfaces, watcher.tcFiltersFromNamespaceServices, watcher.tcFiltersFromNamespaceServices); err != nil {
+					if _, err = i.removeServiceFiltersInList(target.interfaces, watcher.tcFiltersFromNamespaceServices, watcher.tcFiltersFromNamespaceServices); err != nil {
 						i.config.Log.Errorf("couldn't clean list of tc filters: %w", err)
 					}
 
@@ -825,10 +1197,10 @@ func (i *networkDisruptionInjector) watchServiceChanges(watcher serviceWatcher,
 			} else {
 				i.config.Log.Debugw(fmt.Sprintf("changes in pods of service %s/%s", watcher.watchedServiceSpec.Name, watcher.watchedServiceSpec.Namespace), "eventType", event.Type)
 
-				if err := i.handleKubernetesPodsChanges(event, &watcher, interfaces, flowid); err != nil {
+				if err := i.handleKubernetesPodsChanges(event, &watcher, target); err != nil {
 					i.config.Log.Errorf("couldn't apply changes to tc filters: %w... Rebuilding watcher", err)
 
-					if _, err = i.removeServiceFiltersInList(interfaces, watcher.tcFiltersFromPodEndpoints, watcher.tcFiltersFromPodEndpoints); err != nil {
+					if _, err = i.removeServiceFiltersInList(target.interfaces, watcher.tcFiltersFromPodEndpoints, watcher.tcFiltersFromPodEndpoints); err != nil {
 						i.config.Log.Errorf("couldn't clean list of tc filters: %w", err)
 					}
 
@@ -840,12 +1212,12 @@ func (i *networkDisruptionInjector) watchServiceChanges(watcher serviceWatcher,
 	}
 }
 
-// handleFiltersForServices creates tc filters on given interfaces for services in disruption spec classifying matching packets in the given flowid
-func (i *networkDisruptionInjector) handleFiltersForServices(interfaces []string, flowid string) error {
+// handleFiltersForServices creates tc filters on the given target for given services
+func (i *networkDisruptionInjector) handleFiltersForServices(target tcFilterTarget, services []v1beta1.NetworkDisruptionServiceSpec) error {
 	// build the watchers to handle changes in services and pod endpoints
 	serviceWatchers := []serviceWatcher{}
 
-	for _, serviceSpec := range i.spec.Services {
+	for _, serviceSpec := range services {
 		// retrieve serviceSpec
 		k8sService, err := i.config.K8sClient.CoreV1().Services(serviceSpec.Namespace).Get(context.Background(), serviceSpec.Name, metav1.GetOptions{})
 		if err != nil {
@@ -865,20 +1237,223 @@ func (i *networkDisruptionInjector) handleFiltersForServices(interfaces []string
 			kubernetesServiceWatcher:       nil,                 // watch service filtered on
 			tcFiltersFromNamespaceServices: []tcServiceFilter{}, // list of tc filters targeting the service filtered on
 			servicesResourceVersion:        "",
+
+			cleaned: i.cleaned,
 		}
 
 		serviceWatchers = append(serviceWatchers, serviceWatcher)
 	}
 
 	for _, serviceWatcher := range serviceWatchers {
-		go i.watchServiceChanges(serviceWatcher, interfaces, flowid)
+		go i.watchServiceChanges(serviceWatcher, target)
+	}
+
+	return nil
+}
+
+// listPeers lists the existing peer pods of the given partition so the partition applies as soon as the injection is done,
+// the returned watcher watching their changes from the listed version once its targets are added
+func (i *networkDisruptionInjector) listPeers(partition v1beta1.NetworkDisruptionPartitionSpec) (*peerWatcher, error) {
+	watcher := &peerWatcher{
+		partitionSpec: partition,
+		labelSelector: labels.SelectorFromValidatedSet(partition.Selector).String(),
+		cleaned:       i.cleaned,
+	}
+
+	podList, err := i.config.K8sClient.CoreV1().Pods(partition.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: watcher.labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing the partition peer pods (%s in %s): %w", watcher.labelSelector, partition.Namespace, err)
+	}
+
+	watcher.peerPods = podList.Items
+	watcher.podsResourceVersion = podList.ResourceVersion
+
+	return watcher, nil
+}
+
+// addPeerTarget creates tc filters on the given target for the listed peer pods of the given watcher,
+// the watcher adding or deleting filters on all its targets when peer pods are created or deleted
+func (i *networkDisruptionInjector) addPeerTarget(watcher *peerWatcher, target tcFilterTarget) error {
+	peerTarget := &peerTarget{
+		target:          target,
+		tcFiltersPerPod: map[string][]tcServiceFilter{},
+	}
+
+	watcher.targets = append(watcher.targets, peerTarget)
+
+	for _, pod := range watcher.peerPods {
+		if err := i.addPeerTargetFilters(peerTarget, pod); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addPeerFilters creates the tc filters of the given peer pod on each target of the given watcher
+func (i *networkDisruptionInjector) addPeerFilters(watcher *peerWatcher, pod v1.Pod) error {
+	for _, peerTarget := range watcher.targets {
+		if err := i.addPeerTargetFilters(peerTarget, pod); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addPeerTargetFilters creates the tc filters of the given peer pod on the given target if it has an IP and if they don't exist yet
+func (i *networkDisruptionInjector) addPeerTargetFilters(peerTarget *peerTarget, pod v1.Pod) error {
+	if _, ok := peerTarget.tcFiltersPerPod[pod.Name]; ok {
+		return nil
+	}
+
+	// pods without IP yet have no filter, they are created once the pod is modified with an IP
+	// a port 0 matches all the ports
+	filters := i.buildServiceFiltersFromPod(pod, []v1.ServicePort{{}})
+	if len(filters) == 0 {
+		return nil
+	}
+
+	createdFilters := []tcServiceFilter{}
+
+	for _, filter := range filters {
+		filter.parent = peerTarget.target.parent
+		filter.priority = i.getNewPriority()
+
+		i.config.Log.Infow("found partition peer", "peerPodName", pod.Name, "peerPodIP", filter.service.ip.String(), "interfaces", strings.Join(peerTarget.target.interfaces, ", "))
+
+		if err := i.addFilter(peerTarget.target, filter.priority, filter.service.ip, 0, "", v1beta1.FlowEgress); err != nil {
+			return fmt.Errorf("error adding filter for partition peer pod %s: %w", pod.Name, err)
+		}
+
+		createdFilters = append(createdFilters, filter)
+	}
+
+	peerTarget.tcFiltersPerPod[pod.Name] = createdFilters
+
+	return nil
+}
+
+// deletePeerFilters deletes the tc filters of the given peer pod on each target of the given watcher, if any
+func (i *networkDisruptionInjector) deletePeerFilters(watcher *peerWatcher, podName string) error {
+	for _, peerTarget := range watcher.targets {
+		filters, ok := peerTarget.tcFiltersPerPod[podName]
+		if !ok {
+			continue
+		}
+
+		if _, err := i.removeServiceFiltersInList(peerTarget.target.interfaces, filters, filters); err != nil {
+			return err
+		}
+
+		delete(peerTarget.tcFiltersPerPod, podName)
+	}
+
+	return nil
+}
+
+// handleKubernetesPeerPodsChanges for every changes happening in the partition peer pods, we update the tc filters
+func (i *networkDisruptionInjector) handleKubernetesPeerPodsChanges(event watch.Event, watcher *peerWatcher) error {
+	if event.Type == watch.Error {
+		return i.handleWatchError(event)
+	}
+
+	pod, ok := event.Object.(*v1.Pod)
+	if !ok {
+		return fmt.Errorf("couldn't watch pods in namespace, invalid type of watched object received")
+	}
+
+	// keep track of resource version to continue watching pods when the watcher has timed out
+	if event.Type == watch.Bookmark {
+		watcher.podsResourceVersion = pod.ResourceVersion
+
+		return nil
+	}
+
+	if err := i.config.Netns.Enter(); err != nil {
+		return fmt.Errorf("unable to enter the given container network namespace: %w", err)
+	}
+
+	switch {
+	// completed pods release their IP which can be given to another pod
+	case event.Type == watch.Deleted || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed:
+		if err := i.deletePeerFilters(watcher, pod.Name); err != nil {
+			return err
+		}
+	case event.Type == watch.Added || event.Type == watch.Modified:
+		if err := i.addPeerFilters(watcher, *pod); err != nil {
+			return err
+		}
+	}
+
+	watcher.podsResourceVersion = pod.ResourceVersion
+
+	if err := i.config.Netns.Exit(); err != nil {
+		return fmt.Errorf("unable to exit the given container network namespace: %w", err)
 	}
 
 	return nil
 }
 
-// addFiltersForHosts creates tc filters on given interfaces for given hosts classifying matching packets in the given flowid
-func (i *networkDisruptionInjector) addFiltersForHosts(interfaces []string, hosts []v1beta1.NetworkDisruptionHostSpec, flowid string) error {
+// watchPeerChanges for every changes happening in the partition peer pods, we update the tc filters of all the watcher targets
+func (i *networkDisruptionInjector) watchPeerChanges(watcher *peerWatcher) {
+	for {
+		// We create the watcher channel when it's closed
+		if watcher.kubernetesPodsWatcher == nil {
+			podsWatcher, err := i.config.K8sClient.CoreV1().Pods(watcher.partitionSpec.Namespace).Watch(context.Background(), metav1.ListOptions{
+				LabelSelector:       watcher.labelSelector,
+				ResourceVersion:     watcher.podsResourceVersion,
+				AllowWatchBookmarks: true,
+			})
+			if err != nil {
+				i.config.Log.Errorf("error watching the partition peer pods (%s in %s): %w", watcher.labelSelector, watcher.partitionSpec.Namespace, err)
+
+				return
+			}
+
+			i.config.Log.Infow("starting partition peer pods watch", "selector", watcher.labelSelector, "namespace", watcher.partitionSpec.Namespace)
+			watcher.kubernetesPodsWatcher = podsWatcher.ResultChan()
+		}
+
+		if isCleaned(watcher.cleaned) {
+			return
+		}
+
+		select {
+		case <-watcher.cleaned:
+			return
+		case event, ok := <-watcher.kubernetesPodsWatcher: // We have changes in the peer pods
+			if !ok { // channel is closed
+				watcher.kubernetesPodsWatcher = nil
+			} else {
+				i.config.Log.Debugw("changes in partition peer pods", "selector", watcher.labelSelector, "namespace", watcher.partitionSpec.Namespace, "eventType", event.Type)
+
+				if err := i.handleKubernetesPeerPodsChanges(event, watcher); err != nil {
+					i.config.Log.Errorf("couldn't apply changes to tc filters: %w... Rebuilding watcher", err)
+
+					for _, peerTarget := range watcher.targets {
+						for podName := range peerTarget.tcFiltersPerPod {
+							if err := i.deletePeerFilters(watcher, podName); err != nil {
+								i.config.Log.Errorf("couldn't clean list of tc filters: %w", err)
+							}
+						}
+
+						peerTarget.tcFiltersPerPod = map[string][]tcServiceFilter{}
+					}
+
+					// restart the watcher from scratch in case of error, existing peer pods being added again
+					watcher.kubernetesPodsWatcher = nil
+					watcher.podsResourceVersion = ""
+				}
+			}
+		}
+	}
+}
+
+// addFiltersForHosts creates tc filters on the given target for given hosts
+func (i *networkDisruptionInjector) addFiltersForHosts(target tcFilterTarget, hosts []v1beta1.NetworkDisruptionHostSpec) error {
 	for _, host := range hosts {
 		// resolve given hosts if needed
 		ips, err := resolveHost(i.config.DNSClient, host.Host)
@@ -889,23 +1464,8 @@ func (i *networkDisruptionInjector) addFiltersForHosts(interfaces []string, host
 		i.config.Log.Infof("resolved %s as %s", host.Host, ips)
 
 		for _, ip := range ips {
-			// handle flow direction
-			var (
-				srcPort, dstPort int
-				srcIP, dstIP     *net.IPNet
-			)
-
-			switch host.Flow {
-			case v1beta1.FlowIngress:
-				srcPort = host.Port
-				srcIP = ip
-			default:
-				dstPort = host.Port
-				dstIP = ip
-			}
-
 			// create tc filter
-			if err := i.config.TrafficController.AddFilter(interfaces, "1:0", i.getNewPriority(), 0, srcIP, dstIP, srcPort, dstPort, host.Protocol, flowid); err != nil {
+			if err := i.addFilter(target, i.getNewPriority(), ip, host.Port, host.Protocol, host.Flow); err != nil {
 				return fmt.Errorf("error adding filter for host %s: %w", host.Host, err)
 			}
 		}
@@ -914,24 +1474,108 @@ func (i *networkDisruptionInjector) addFiltersForHosts(interfaces []string, host
 	return nil
 }
 
-// AddNetem adds network disruptions using the drivers in the networkDisruptionInjector
-func (i *networkDisruptionInjector) addNetemOperation(delay, delayJitter time.Duration, drop int, corrupt int, duplicate int) {
-	// closure which adds netem disruptions
-	operation := func(interfaces []string, parent string, handle uint32) error {
-		return i.config.TrafficController.AddNetem(interfaces, parent, handle, delay, delayJitter, drop, corrupt, duplicate)
+// buildOperations returns the operations to chain to apply the given disruptions
+func (i *networkDisruptionInjector) buildOperations(netem network.NetemParams, bandwidthLimit int) []linkOperation {
+	operations := []linkOperation{}
+
+	// add netem
+	if netem.Delay > 0 || netem.Drop > 0 || netem.Corrupt > 0 || netem.Duplicate > 0 || netem.GilbertElliott != nil || netem.FourStateLoss != nil || netem.Slot != nil {
+		operations = append(operations, i.netemOperation(netem))
+	}
+
+	// add tbf
+	if bandwidthLimit > 0 {
+		operations = append(operations, i.outputLimitOperation(uint(bandwidthLimit)))
+	}
+
+	return operations
+}
+
+// netemParams returns the netem parameters of the given disruptions, the delay jitter being a percentage of the delay
+func netemParams(drop, duplicate, corrupt int, delayMs, delayJitterPercent uint) network.NetemParams {
+	delay := time.Duration(delayMs) * time.Millisecond
+
+	var delayJitter time.Duration
+
+	// add a 10% delayJitter to delay by default if not specified
+	if delayJitterPercent == 0 {
+		delayJitter = time.Duration(float64(delayMs)*0.1) * time.Millisecond
+	} else {
+		// convert delayJitter into a percentage then multiply that with delay to get correct percentage of delay
+		delayJitter = time.Duration((float64(delayJitterPercent)/100.0)*float64(delayMs)) * time.Millisecond
+	}
+
+	delayJitter = time.Duration(math.Max(float64(delayJitter), float64(time.Millisecond)))
+
+	return network.NetemParams{
+		Delay:       delay,
+		DelayJitter: delayJitter,
+		Drop:        drop,
+		Duplicate:   duplicate,
+		Corrupt:     corrupt,
+	}
+}
+
+// specNetemParams returns the netem parameters of the disruptions defined at the network disruption level,
+// along with their correlations, loss model, reordering and slots
+func (i *networkDisruptionInjector) specNetemParams() network.NetemParams {
+	netem := netemParams(i.spec.Drop, i.spec.Duplicate, i.spec.Corrupt, i.spec.Delay, i.spec.DelayJitter)
+	netem.DelayCorrelation = i.spec.DelayCorrelation
+	netem.DelayDistribution = i.spec.DelayDistribution
+	netem.DropCorrelation = i.spec.DropCorrelation
+	netem.DuplicateCorrelation = i.spec.DuplicateCorrelation
+	netem.CorruptCorrelation = i.spec.CorruptCorrelation
+	netem.Reorder = i.spec.Reorder
+	netem.ReorderCorrelation = i.spec.ReorderCorrelation
+	netem.ReorderGap = i.spec.ReorderGap
+
+	if i.spec.LossModel != nil {
+		if ge := i.spec.LossModel.GilbertElliott; ge != nil {
+			netem.GilbertElliott = &network.GilbertElliottLoss{
+				P:             ge.P,
+				R:             ge.R,
+				BadStateLoss:  ge.BadStateLoss,
+				GoodStateLoss: ge.GoodStateLoss,
+			}
+		}
+
+		if fs := i.spec.LossModel.FourState; fs != nil {
+			netem.FourStateLoss = &network.FourStateLoss{
+				P13: fs.P13,
+				P31: fs.P31,
+				P32: fs.P32,
+				P23: fs.P23,
+				P14: fs.P14,
+			}
+		}
 	}
 
-	i.operations = append(i.operations, operation)
+	if i.spec.Slot != nil {
+		netem.Slot = &network.NetemSlot{
+			MinDelay: time.Duration(i.spec.Slot.MinDelay) * time.Microsecond,
+			MaxDelay: time.Duration(i.spec.Slot.MaxDelay) * time.Microsecond,
+			Packets:  i.spec.Slot.Packets,
+			Bytes:    i.spec.Slot.Bytes,
+		}
+	}
+
+	return netem
+}
+
+// netemOperation returns an operation adding network disruptions using the drivers in the networkDisruptionInjector
+func (i *networkDisruptionInjector) netemOperation(netem network.NetemParams) linkOperation {
+	// closure which adds netem disruptions
+	return func(interfaces []string, parent string, handle uint32) error {
+		return i.config.TrafficController.AddNetem(interfaces, parent, handle, netem)
+	}
 }
 
-// AddOutputLimit adds a network bandwidth disruption using the drivers in the networkDisruptionInjector
-func (i *networkDisruptionInjector) addOutputLimitOperation(bytesPerSec uint) {
+// outputLimitOperation returns an operation adding a network bandwidth disruption using the drivers in the networkDisruptionInjector
+func (i *networkDisruptionInjector) outputLimitOperation(bytesPerSec uint) linkOperation {
 	// closure which adds a bandwidth limit
-	operation := func(interfaces []string, parent string, handle uint32) error {
+	return func(interfaces []string, parent string, handle uint32) error {
 		return i.config.TrafficController.AddOutputLimit(interfaces, parent, handle, bytesPerSec)
 	}
-
-	i.operations = append(i.operations, operation)
 }
 
 // clearOperations removes all disruptions by clearing all custom qdiscs created for the given config struct (filters will be deleted as well)
@@ -955,6 +1599,278 @@ func (i *networkDisruptionInjector) clearOperations() error {
 		return fmt.Errorf("error deleting root qdisc: %w", err)
 	}
 
+	// stop redirecting the incoming traffic before deleting the IFB device, packets redirected to a missing device being dropped
+	// the IFB device qdiscs are deleted with it
+	if i.shapesIngress() {
+		i.config.Log.Infof("clearing ingress qdiscs and deleting the %s IFB device", ifbName)
+
+		if err := i.config.TrafficController.ClearIngressQdisc(interfaces); err != nil {
+			return fmt.Errorf("error deleting ingress qdisc: %w", err)
+		}
+
+		if !i.config.DryRun {
+			if err := i.config.NetlinkAdapter.LinkDelete(ifbName); err != nil {
+				return fmt.Errorf("error deleting the IFB device: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyReject creates a CHAOS-REJECT chain in the filter table of each IP family rejecting the packets going to the
+// given hosts, and jumps to it from the OUTPUT chain for the target packets only (see rejectJumpMatches)
+// the packets of the safeguards and allowed hosts are returned first, like they are classified in the not disrupted band of the tc tree
+// IPv6 rules are added by ip6tables which fails on hosts where IPv6 is disabled, in which case there are no IPv6 packets to reject anyway
+func (i *networkDisruptionInjector) applyReject() error {
+	rules, err := i.rejectRules()
+	if err != nil {
+		return err
+	}
+
+	i.config.Log.Infow("rejecting packets with iptables", "reject", i.spec.Reject, "chain", rejectChain)
+
+	if err := i.addRejectRules(i.config.Iptables, false, rules); err != nil {
+		return err
+	}
+
+	if err := i.addRejectRules(i.config.Ip6tables, true, rules); err != nil {
+		i.config.Log.Warnw("error adding the ip6tables rules rejecting packets, IPv6 packets won't be rejected", "error", err)
+	}
+
+	return nil
+}
+
+// addRejectRules creates the reject chain with the rules of the given IP family and the OUTPUT chain rule jumping to it
+func (i *networkDisruptionInjector) addRejectRules(iptables network.Iptables, ipv6 bool, rules []rejectRule) error {
+	rejectWith := i.spec.Reject
+	if ipv6 && rejectWith == v1beta1.RejectICMPPortUnreachable {
+		rejectWith = "icmp6-port-unreachable"
+	}
+
+	if err := iptables.CreateFilterChain(rejectChain); err != nil {
+		return fmt.Errorf("unable to create new iptables chain: %w", err)
+	}
+
+	for _, rule := range rules {
+		if (rule.ip.IP.To4() == nil) != ipv6 {
+			continue
+		}
+
+		rulespec := append([]string{}, rule.matches...)
+		if rule.reject {
+			rulespec = append(rulespec, "-j", "REJECT", "--reject-with", rejectWith)
+		} else {
+			rulespec = append(rulespec, "-j", "RETURN")
+		}
+
+		if err := iptables.AppendFilterRule(rejectChain, rulespec...); err != nil {
+			return fmt.Errorf("unable to create new iptables rule: %w", err)
+		}
+	}
+
+	// the jump rule is added last so no packet is rejected before the safeguards are in place
+	if err := iptables.AddFilterJumpRule("OUTPUT", rejectChain, i.rejectJumpMatches()...); err != nil {
+		return fmt.Errorf("unable to create new iptables rule: %w", err)
+	}
+
+	return nil
+}
+
+// rejectRules returns the rules of the reject chain, the returned packets rules being first so they are used first
+func (i *networkDisruptionInjector) rejectRules() ([]rejectRule, error) {
+	rules := []rejectRule{}
+
+	if i.config.Level == chaostypes.DisruptionLevelPod {
+		// allow the pod to communicate with the default route gateway IP and with the node IP
+		defaultRoutes, err := i.config.NetlinkAdapter.DefaultRoutes()
+		if err != nil {
+			return nil, fmt.Errorf("error getting the default route: %w", err)
+		}
+
+		nodeIPNet, err := i.targetPodNodeIPNet()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, defaultRoute := range defaultRoutes {
+			rules = append(rules, hostRejectRules(singleIPNet(defaultRoute.Gateway()), 0, "", v1beta1.FlowEgress, false)...)
+		}
+
+		rules = append(rules, hostRejectRules(nodeIPNet, 0, "", v1beta1.FlowEgress, false)...)
+	} else if i.config.Level == chaostypes.DisruptionLevelNode {
+		// allow SSH connections and cloud provider metadata service communication
+		for _, wildcardIP := range wildcardIPNets() {
+			rules = append(rules, hostRejectRules(wildcardIP, 22, "tcp", v1beta1.FlowIngress, false)...)
+		}
+
+		for _, metadataIPNet := range metadataIPNets() {
+			rules = append(rules, hostRejectRules(metadataIPNet, 0, "", v1beta1.FlowEgress, false)...)
+		}
+	}
+
+	allowedRules, err := i.hostsRejectRules(i.spec.AllowedHosts, false)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving allowed hosts: %w", err)
+	}
+
+	// reject all the packets if no host or service is given
+	hosts := i.spec.Hosts
+	if len(hosts) == 0 && len(i.spec.Services) == 0 {
+		hosts = []v1beta1.NetworkDisruptionHostSpec{{}}
+	}
+
+	rejectedRules, err := i.hostsRejectRules(hosts, true)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving hosts: %w", err)
+	}
+
+	servicesRules, err := i.servicesRejectRules(i.spec.Services)
+	if err != nil {
+		return nil, fmt.Errorf("error getting services: %w", err)
+	}
+
+	return append(append(append(rules, allowedRules...), rejectedRules...), servicesRules...), nil
+}
+
+// servicesRejectRules returns the reject chain rules of the given services, matching their cluster IPs and ports along with
+// their endpoints IPs and target ports like the tc service filters: the packets still go to the cluster IPs in the target network
+// namespace while they are already translated to the endpoints IPs by kube-proxy in the node network namespace
+// the endpoints are listed once when the disruption is injected, unlike the tc service filters which are updated by watchers
+func (i *networkDisruptionInjector) servicesRejectRules(services []v1beta1.NetworkDisruptionServiceSpec) ([]rejectRule, error) {
+	rules := []rejectRule{}
+
+	for _, serviceSpec := range services {
+		service, err := i.config.K8sClient.CoreV1().Services(serviceSpec.Namespace).Get(context.Background(), serviceSpec.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting the given kubernetes service (%s/%s): %w", serviceSpec.Namespace, serviceSpec.Name, err)
+		}
+
+		filters := i.buildServiceFiltersFromService(*service, service.Spec.Ports)
+
+		pods, err := i.config.K8sClient.CoreV1().Pods(serviceSpec.Namespace).List(context.Background(), metav1.ListOptions{
+			LabelSelector: labels.SelectorFromValidatedSet(service.Spec.Selector).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing the pods of the given kubernetes service (%s/%s): %w", serviceSpec.Namespace, serviceSpec.Name, err)
+		}
+
+		for _, pod := range pods.Items {
+			filters = append(filters, i.buildServiceFiltersFromPod(pod, service.Spec.Ports)...)
+		}
+
+		for _, filter := range filters {
+			protocol := strings.ToLower(filter.service.protocol)
+
+			// only TCP packets can be reset
+			if i.spec.Reject == v1beta1.RejectTCPReset && protocol != "tcp" {
+				i.config.Log.Infow("not rejecting the service port packets which can't be reset", "service", serviceSpec.Name, "namespace", serviceSpec.Namespace, "port", filter.service.port, "protocol", filter.service.protocol)
+
+				continue
+			}
+
+			rules = append(rules, hostRejectRules(filter.service.ip, filter.service.port, protocol, v1beta1.FlowEgress, true)...)
+		}
+	}
+
+	return rules, nil
+}
+
+// hostsRejectRules resolves the given hosts and returns their reject chain rules, only TCP packets being matched
+// when they are reset
+func (i *networkDisruptionInjector) hostsRejectRules(hosts []v1beta1.NetworkDisruptionHostSpec, reject bool) ([]rejectRule, error) {
+	rules := []rejectRule{}
+
+	for _, host := range hosts {
+		ips, err := resolveHost(i.config.DNSClient, host.Host)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving given host %s: %w", host.Host, err)
+		}
+
+		protocol := host.Protocol
+		if reject && i.spec.Reject == v1beta1.RejectTCPReset {
+			protocol = "tcp"
+		}
+
+		for _, ip := range ips {
+			rules = append(rules, hostRejectRules(ip, host.Port, protocol, host.Flow, reject)...)
+		}
+	}
+
+	return rules, nil
+}
+
+// hostRejectRules returns the reject chain rules matching the packets exchanged with the given IP and port, with the same
+// flow semantics as the tc filters: the port is the remote peer one (egress) or a local one (ingress)
+// a port can only be matched along with a protocol so one rule per protocol is returned if no protocol is given
+func hostRejectRules(ip *net.IPNet, port int, protocol, flow string, reject bool) []rejectRule {
+	ipFlag, portFlag := "-d", "--dport"
+	if flow == v1beta1.FlowIngress {
+		ipFlag, portFlag = "-s", "--sport"
+	}
+
+	protocols := []string{protocol}
+	if protocol == "" && port != 0 {
+		protocols = []string{"tcp", "udp"}
+	}
+
+	rules := []rejectRule{}
+
+	for _, protocol := range protocols {
+		matches := []string{ipFlag, ip.String()}
+
+		if protocol != "" {
+			matches = append(matches, "-p", protocol)
+		}
+
+		if port != 0 {
+			matches = append(matches, portFlag, strconv.Itoa(port))
+		}
+
+		rules = append(rules, rejectRule{ip: ip, matches: matches, reject: reject})
+	}
+
+	return rules
+}
+
+// rejectJumpMatches returns the matches of the OUTPUT chain rule jumping to the reject chain, scoping it to the target
+// cgroup packets like the tc tree cgroup filter when the disruption is scoped to the target processes
+func (i *networkDisruptionInjector) rejectJumpMatches() []string {
+	if !i.isScopedToTargetCgroup() {
+		return []string{}
+	}
+
+	if i.config.Cgroup.IsCgroupV2() {
+		return []string{"-m", "cgroup", "--path", i.config.Cgroup.RelativePath("")}
+	}
+
+	return []string{"-m", "cgroup", "--cgroup", types.InjectorCgroupClassID}
+}
+
+// clearReject deletes the rule jumping to the reject chain of each IP family before deleting the chain itself,
+// a chain being referenced by a rule not being deletable
+func (i *networkDisruptionInjector) clearReject() error {
+	if err := i.deleteRejectRules(i.config.Iptables); err != nil {
+		return err
+	}
+
+	if err := i.deleteRejectRules(i.config.Ip6tables); err != nil {
+		i.config.Log.Warnw("error deleting the ip6tables rules rejecting packets", "error", err)
+	}
+
+	return nil
+}
+
+// deleteRejectRules deletes the OUTPUT chain rule jumping to the reject chain and the reject chain with the given iptables driver
+func (i *networkDisruptionInjector) deleteRejectRules(iptables network.Iptables) error {
+	if err := iptables.DeleteFilterJumpRule("OUTPUT", rejectChain, i.rejectJumpMatches()...); err != nil {
+		return fmt.Errorf("unable to remove injected iptables rule: %w", err)
+	}
+
+	if err := iptables.ClearAndDeleteFilterChain(rejectChain); err != nil {
+		return fmt.Errorf("unable to remove injected iptables chain: %w", err)
+	}
+
 	return nil
 }
 
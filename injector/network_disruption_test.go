@@ -38,6 +38,10 @@ var _ = Describe("Failure", func() {
 		spec                                                    v1beta1.NetworkDisruptionSpec
 		cgroupManager                                           *cgroup.ManagerMock
 		cgroupManagerExistsCall                                 *mock.Call
+		cgroupManagerIsCgroupV2Call                             *mock.Call
+		tcAddFilterCall                                         *mock.Call
+		iptables                                                *network.IptablesMock
+		ip6tables                                               *network.IptablesMock
 		tc                                                      *network.TcMock
 		nl                                                      *network.NetlinkAdapterMock
 		nllink1, nllink2, nllink3                               *network.NetlinkLinkMock
@@ -55,6 +59,27 @@ var _ = Describe("Failure", func() {
 		cgroupManager = &cgroup.ManagerMock{}
 		cgroupManagerExistsCall = cgroupManager.On("Exists", "net_cls").Return(true, nil)
 		cgroupManager.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		cgroupManager.On("RelativePath", mock.Anything).Return("/kubepods/pod1/ctn1")
+		cgroupManagerIsCgroupV2Call = cgroupManager.On("IsCgroupV2").Return(false)
+
+		// iptables
+		iptables = &network.IptablesMock{}
+		iptables.On("AddCgroupPathClassifyRule", mock.Anything, mock.Anything).Return(nil)
+		iptables.On("DeleteCgroupPathClassifyRule", mock.Anything, mock.Anything).Return(nil)
+		iptables.On("CreateFilterChain", mock.Anything).Return(nil)
+		iptables.On("ClearAndDeleteFilterChain", mock.Anything).Return(nil)
+		iptables.On("AppendFilterRule", mock.Anything, mock.Anything).Return(nil)
+		iptables.On("AddFilterJumpRule", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		iptables.On("DeleteFilterJumpRule", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		ip6tables = &network.IptablesMock{}
+		ip6tables.On("AddCgroupPathClassifyRule", mock.Anything, mock.Anything).Return(nil)
+		ip6tables.On("DeleteCgroupPathClassifyRule", mock.Anything, mock.Anything).Return(nil)
+		ip6tables.On("CreateFilterChain", mock.Anything).Return(nil)
+		ip6tables.On("ClearAndDeleteFilterChain", mock.Anything).Return(nil)
+		ip6tables.On("AppendFilterRule", mock.Anything, mock.Anything).Return(nil)
+		ip6tables.On("AddFilterJumpRule", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		ip6tables.On("DeleteFilterJumpRule", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 		// netns
 		netnsManager = &netns.ManagerMock{}
@@ -63,13 +88,15 @@ var _ = Describe("Failure", func() {
 
 		// tc
 		tc = &network.TcMock{}
-		tc.On("AddNetem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		tc.On("AddNetem", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		tc.On("AddPrio", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		tc.On("AddFilter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		tcAddFilterCall = tc.On("AddFilter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		tc.On("AddCgroupFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		tc.On("AddOutputLimit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
-		tc.On("DeleteFilter", mock.Anything, mock.Anything).Return(nil)
+		tc.On("DeleteFilter", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		tc.On("ClearQdisc", mock.Anything).Return(nil)
+		tc.On("AddIngressRedirect", mock.Anything, mock.Anything).Return(nil)
+		tc.On("ClearIngressQdisc", mock.Anything).Return(nil)
 
 		// netlink
 		nllink1 = &network.NetlinkLinkMock{}
@@ -104,6 +131,8 @@ var _ = Describe("Failure", func() {
 		nl.On("LinkByName", "eth0").Return(nllink2, nil)
 		nl.On("LinkByName", "eth1").Return(nllink3, nil)
 		nl.On("DefaultRoutes").Return([]network.NetlinkRoute{nlroute2}, nil)
+		nl.On("LinkAddIFB", "chaos-ifb").Return(&network.NetlinkLinkMock{}, nil)
+		nl.On("LinkDelete", "chaos-ifb").Return(nil)
 
 		// dns
 		dns = &network.DNSMock{}
@@ -167,6 +196,8 @@ var _ = Describe("Failure", func() {
 			TrafficController: tc,
 			NetlinkAdapter:    nl,
 			DNSClient:         dns,
+			Iptables:          iptables,
+			Ip6tables:         ip6tables,
 		}
 
 		spec = v1beta1.NetworkDisruptionSpec{
@@ -183,7 +214,9 @@ var _ = Describe("Failure", func() {
 	})
 
 	JustBeforeEach(func() {
-		inj = NewNetworkDisruptionInjector(spec, config)
+		var err error
+		inj, err = NewNetworkDisruptionInjector(spec, config)
+		Expect(err).To(BeNil())
 	})
 
 	Describe("inj.Inject", func() {
@@ -211,10 +244,83 @@ var _ = Describe("Failure", func() {
 		})
 
 		It("should apply disruptions to main interfaces 2nd band", func() {
-			tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "2:2", mock.Anything, time.Second, time.Second, spec.Drop, spec.Corrupt, spec.Duplicate)
+			tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "2:2", mock.Anything, network.NetemParams{Delay: time.Second, DelayJitter: time.Second, Drop: spec.Drop, Duplicate: spec.Duplicate, Corrupt: spec.Corrupt})
 			tc.AssertCalled(GinkgoT(), "AddOutputLimit", []string{"lo", "eth0", "eth1"}, "3:", mock.Anything, uint(spec.BandwidthLimit))
 		})
 
+		Context("with correlations, a loss model, reordering and slots", func() {
+			BeforeEach(func() {
+				spec.Drop = 0
+				spec.DelayCorrelation = 25
+				spec.DelayDistribution = "pareto"
+				spec.DuplicateCorrelation = 10
+				spec.CorruptCorrelation = 20
+				spec.LossModel = &v1beta1.NetworkDisruptionLossModelSpec{
+					GilbertElliott: &v1beta1.NetworkDisruptionGilbertElliottSpec{
+						P:            1,
+						R:            25,
+						BadStateLoss: 100,
+					},
+				}
+				spec.Reorder = 30
+				spec.ReorderCorrelation = 50
+				spec.ReorderGap = 5
+				spec.Slot = &v1beta1.NetworkDisruptionSlotSpec{
+					MinDelay: 800,
+					MaxDelay: 1600,
+					Packets:  32,
+				}
+			})
+
+			It("should pass them to the netem qdisc", func() {
+				tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "2:2", mock.Anything, network.NetemParams{
+					Delay:                time.Second,
+					DelayJitter:          time.Second,
+					DelayCorrelation:     25,
+					DelayDistribution:    "pareto",
+					GilbertElliott:       &network.GilbertElliottLoss{P: 1, R: 25, BadStateLoss: 100},
+					Duplicate:            spec.Duplicate,
+					DuplicateCorrelation: 10,
+					Corrupt:              spec.Corrupt,
+					CorruptCorrelation:   20,
+					Reorder:              30,
+					ReorderCorrelation:   50,
+					ReorderGap:           5,
+					Slot:                 &network.NetemSlot{MinDelay: 800 * time.Microsecond, MaxDelay: 1600 * time.Microsecond, Packets: 32},
+				})
+			})
+
+			Context("with profiles", func() {
+				BeforeEach(func() {
+					spec.Profiles = []v1beta1.NetworkDisruptionProfileSpec{
+						{
+							Hosts: []v1beta1.NetworkDisruptionHostSpec{{Host: "10.0.0.0/8"}},
+							Drop:  10,
+						},
+					}
+				})
+
+				It("should not apply them to the profiles netem qdisc", func() {
+					tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, mock.Anything, mock.Anything, network.NetemParams{DelayJitter: time.Millisecond, Drop: 10})
+				})
+			})
+		})
+
+		Context("with a slot only", func() {
+			BeforeEach(func() {
+				spec = v1beta1.NetworkDisruptionSpec{
+					Slot: &v1beta1.NetworkDisruptionSlotSpec{MinDelay: 1000},
+				}
+			})
+
+			It("should add a netem qdisc", func() {
+				tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "2:2", mock.Anything, network.NetemParams{
+					DelayJitter: time.Millisecond,
+					Slot:        &network.NetemSlot{MinDelay: time.Millisecond},
+				})
+			})
+		})
+
 		// qlen cases
 		Context("with interfaces without a qlen value", func() {
 			It("should set or clear the interface qlen on all interfaces", func() {
@@ -246,6 +352,10 @@ var _ = Describe("Failure", func() {
 			It("should add a filter to redirect all traffic on main interfaces on the disrupted band", func() {
 				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "0.0.0.0/0", 0, 0, "", "1:4")
 			})
+
+			It("should add a filter to redirect all IPv6 traffic on main interfaces on the disrupted band", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "::/0", 0, 0, "", "1:4")
+			})
 		})
 
 		Context("with multiple hosts specified", func() {
@@ -270,6 +380,26 @@ var _ = Describe("Failure", func() {
 			})
 		})
 
+		Context("with IPv6 hosts specified", func() {
+			BeforeEach(func() {
+				spec.Hosts = []v1beta1.NetworkDisruptionHostSpec{
+					{
+						Host:     "2001:db8::1",
+						Port:     80,
+						Protocol: "tcp",
+					},
+					{
+						Host: "2001:db8:1::/48",
+					},
+				}
+			})
+
+			It("should add a filter to redirect targeted traffic on all interfaces on the disrupted band filter on given hosts as destination IP", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "2001:db8::1/128", 0, 80, "tcp", "1:4")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "2001:db8:1::/48", 0, 0, "", "1:4")
+			})
+		})
+
 		Context("with multiple services specified", func() {
 			BeforeEach(func() {
 				spec.Services = []v1beta1.NetworkDisruptionServiceSpec{
@@ -313,13 +443,13 @@ var _ = Describe("Failure", func() {
 				}, time.Second*5, time.Second).Should(BeTrue())
 
 				Eventually(func() bool {
-					return tc.AssertCalled(GinkgoT(), "DeleteFilter", "lo", priority)
+					return tc.AssertCalled(GinkgoT(), "DeleteFilter", "lo", "1:0", priority)
 				}, time.Second*5, time.Second).Should(BeTrue())
 				Eventually(func() bool {
-					return tc.AssertCalled(GinkgoT(), "DeleteFilter", "eth0", priority)
+					return tc.AssertCalled(GinkgoT(), "DeleteFilter", "eth0", "1:0", priority)
 				}, time.Second*5, time.Second).Should(BeTrue())
 				Eventually(func() bool {
-					return tc.AssertCalled(GinkgoT(), "DeleteFilter", "eth1", priority)
+					return tc.AssertCalled(GinkgoT(), "DeleteFilter", "eth1", "1:0", priority)
 				}, time.Second*5, time.Second).Should(BeTrue())
 			})
 
@@ -329,6 +459,91 @@ var _ = Describe("Failure", func() {
 
 		})
 
+		Context("with profiles", func() {
+			BeforeEach(func() {
+				spec.Profiles = []v1beta1.NetworkDisruptionProfileSpec{
+					{
+						Hosts: []v1beta1.NetworkDisruptionHostSpec{
+							{
+								Host:     "1.1.1.1",
+								Port:     443,
+								Protocol: "tcp",
+							},
+						},
+						Delay: 200,
+					},
+					{
+						Hosts: []v1beta1.NetworkDisruptionHostSpec{
+							{
+								Host: "2.2.2.2",
+							},
+						},
+						BandwidthLimit: 1000,
+					},
+				}
+			})
+
+			It("should add a prio qdisc with a band per profile under the cgroup prio qdisc", func() {
+				tc.AssertCalled(GinkgoT(), "AddPrio", []string{"lo", "eth0", "eth1"}, "2:2", uint32(3), uint32(4), [16]uint32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+			})
+
+			It("should chain the disruption level operations to the second band", func() {
+				tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "3:2", uint32(4), network.NetemParams{Delay: time.Second, DelayJitter: time.Second, Drop: spec.Drop, Duplicate: spec.Duplicate, Corrupt: spec.Corrupt})
+				tc.AssertCalled(GinkgoT(), "AddOutputLimit", []string{"lo", "eth0", "eth1"}, "4:", uint32(5), uint(spec.BandwidthLimit))
+			})
+
+			It("should chain each profile operations to its own band", func() {
+				tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "3:3", uint32(6), network.NetemParams{Delay: 200 * time.Millisecond, DelayJitter: 20 * time.Millisecond})
+				tc.AssertCalled(GinkgoT(), "AddOutputLimit", []string{"lo", "eth0", "eth1"}, "3:4", uint32(7), uint(1000))
+			})
+
+			It("should redirect all traffic on the disrupted band", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "0.0.0.0/0", 0, 0, "", "1:4")
+			})
+
+			It("should classify each profile hosts traffic in the profile band", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "3:0", mock.Anything, mock.Anything, "nil", "1.1.1.1/32", 0, 443, "tcp", "3:3")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "3:0", mock.Anything, mock.Anything, "nil", "2.2.2.2/32", 0, 0, "", "3:4")
+			})
+
+			It("should not add any filter for the disruption level operations applying to all the traffic", func() {
+				tc.AssertNotCalled(GinkgoT(), "AddFilter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "3:2")
+			})
+
+			Context("with hosts at the disruption level", func() {
+				BeforeEach(func() {
+					spec.Hosts = []v1beta1.NetworkDisruptionHostSpec{
+						{
+							Host: "testhost",
+							Port: 80,
+						},
+					}
+				})
+
+				It("should leave the traffic not matching any filter undisrupted", func() {
+					tc.AssertCalled(GinkgoT(), "AddPrio", []string{"lo", "eth0", "eth1"}, "2:2", uint32(3), uint32(4), [16]uint32{})
+				})
+
+				It("should classify the disruption level hosts traffic in the second band", func() {
+					tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "3:0", mock.Anything, mock.Anything, "nil", "1.1.1.1/32", 0, 80, "", "3:2")
+				})
+			})
+
+			Context("without disruptions at the disruption level", func() {
+				BeforeEach(func() {
+					spec = v1beta1.NetworkDisruptionSpec{
+						Profiles: spec.Profiles,
+					}
+				})
+
+				It("should not chain any operation to the second band", func() {
+					tc.AssertNotCalled(GinkgoT(), "AddNetem", mock.Anything, "3:2", mock.Anything, mock.Anything)
+					tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "3:3", uint32(4), network.NetemParams{Delay: 200 * time.Millisecond, DelayJitter: 20 * time.Millisecond})
+					tc.AssertCalled(GinkgoT(), "AddOutputLimit", []string{"lo", "eth0", "eth1"}, "3:4", uint32(5), uint(1000))
+				})
+			})
+		})
+
 		// safeguards
 		Context("pod level safeguards", func() {
 			It("should add a filter to redirect default gateway IP traffic on a non-disrupted band", func() {
@@ -346,7 +561,8 @@ var _ = Describe("Failure", func() {
 			})
 
 			It("should add a filter to redirect SSH traffic on a non-disrupted band", func() {
-				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "nil", 22, 0, "tcp", "1:1")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "0.0.0.0/0", "nil", 22, 0, "tcp", "1:1")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "::/0", "nil", 22, 0, "tcp", "1:1")
 			})
 
 			It("should add a filter to redirect ARP traffic on a non-disrupted band", func() {
@@ -355,6 +571,236 @@ var _ = Describe("Failure", func() {
 
 			It("should add a filter to redirect metadata service traffic on a non-disrupted band", func() {
 				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "169.254.169.254/32", 0, 0, "", "1:1")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "fd00:ec2::254/128", 0, 0, "", "1:1")
+			})
+		})
+
+		Context("with reject", func() {
+			BeforeEach(func() {
+				spec = v1beta1.NetworkDisruptionSpec{
+					Hosts: []v1beta1.NetworkDisruptionHostSpec{
+						{Host: "testhost", Port: 443},
+					},
+					AllowedHosts: []v1beta1.NetworkDisruptionHostSpec{
+						{Host: "10.0.0.1"},
+					},
+					Reject: v1beta1.RejectTCPReset,
+				}
+			})
+
+			It("should not apply any tc operation", func() {
+				tc.AssertNotCalled(GinkgoT(), "AddPrio", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+
+			It("should return the safeguards and allowed hosts packets before rejecting the hosts TCP packets", func() {
+				iptables.AssertCalled(GinkgoT(), "CreateFilterChain", "CHAOS-REJECT")
+
+				appendCalls := []interface{}{}
+				for _, call := range iptables.Calls {
+					if call.Method == "AppendFilterRule" {
+						appendCalls = append(appendCalls, call.Arguments.Get(1))
+					}
+				}
+
+				Expect(appendCalls).To(Equal([]interface{}{
+					[]string{"-d", "192.168.0.1/32", "-j", "RETURN"},
+					[]string{"-d", "10.0.0.2/32", "-j", "RETURN"},
+					[]string{"-d", "10.0.0.1/32", "-j", "RETURN"},
+					[]string{"-d", "1.1.1.1/32", "-p", "tcp", "--dport", "443", "-j", "REJECT", "--reject-with", "tcp-reset"},
+				}))
+			})
+
+			It("should jump to the reject chain for the target cgroup packets only", func() {
+				iptables.AssertCalled(GinkgoT(), "AddFilterJumpRule", "OUTPUT", "CHAOS-REJECT", []string{"-m", "cgroup", "--cgroup", "0x00020002"})
+			})
+
+			It("should not add any IPv6 rule without any IPv6 host", func() {
+				ip6tables.AssertCalled(GinkgoT(), "CreateFilterChain", "CHAOS-REJECT")
+				ip6tables.AssertNotCalled(GinkgoT(), "AppendFilterRule", mock.Anything, mock.Anything)
+			})
+
+			Context("with an ICMP error on all the traffic", func() {
+				BeforeEach(func() {
+					spec.Hosts = nil
+					spec.AllowedHosts = nil
+					spec.Reject = v1beta1.RejectICMPPortUnreachable
+				})
+
+				It("should reject all the IPv4 and IPv6 packets with the ICMP error of each family", func() {
+					iptables.AssertCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-d", "0.0.0.0/0", "-j", "REJECT", "--reject-with", "icmp-port-unreachable"})
+					ip6tables.AssertCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-d", "::/0", "-j", "REJECT", "--reject-with", "icmp6-port-unreachable"})
+				})
+			})
+
+			Context("with services", func() {
+				BeforeEach(func() {
+					spec.Hosts = nil
+					spec.Services = []v1beta1.NetworkDisruptionServiceSpec{
+						{
+							Name:      "foo",
+							Namespace: "bar",
+						},
+					}
+				})
+
+				It("should reject the packets going to the service cluster IP and to its endpoints", func() {
+					iptables.AssertCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-d", "172.16.0.1/32", "-p", "tcp", "--dport", "80", "-j", "REJECT", "--reject-with", "tcp-reset"})
+					iptables.AssertCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-d", "10.1.0.4/32", "-p", "tcp", "--dport", "8080", "-j", "REJECT", "--reject-with", "tcp-reset"})
+				})
+
+				It("should not reject all the packets", func() {
+					iptables.AssertNotCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-d", "0.0.0.0/0", "-j", "REJECT", "--reject-with", "tcp-reset"})
+				})
+			})
+
+			Context("at the node level", func() {
+				BeforeEach(func() {
+					config.Level = chaostypes.DisruptionLevelNode
+				})
+
+				It("should allow SSH connections and jump to the reject chain for all the packets", func() {
+					iptables.AssertCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-s", "0.0.0.0/0", "-p", "tcp", "--sport", "22", "-j", "RETURN"})
+					iptables.AssertCalled(GinkgoT(), "AppendFilterRule", "CHAOS-REJECT", []string{"-d", "169.254.169.254/32", "-j", "RETURN"})
+					iptables.AssertCalled(GinkgoT(), "AddFilterJumpRule", "OUTPUT", "CHAOS-REJECT", []string{})
+				})
+			})
+
+			Context("on a cgroup v2 host", func() {
+				BeforeEach(func() {
+					cgroupManagerIsCgroupV2Call.Return(true)
+				})
+
+				It("should jump to the reject chain for the target cgroup path packets only", func() {
+					iptables.AssertCalled(GinkgoT(), "AddFilterJumpRule", "OUTPUT", "CHAOS-REJECT", []string{"-m", "cgroup", "--path", "/kubepods/pod1/ctn1"})
+				})
+			})
+		})
+
+		Context("when injected again after being cleaned", func() {
+			// the injector is cleaned and injected again on pulses and target container restarts
+			var (
+				servicesWatchers   chan *watch.FakeWatcher
+				serviceFilterAdded chan struct{}
+			)
+
+			BeforeEach(func() {
+				spec.Services = []v1beta1.NetworkDisruptionServiceSpec{
+					{
+						Name:      "foo",
+						Namespace: "bar",
+					},
+				}
+
+				watchers := make(chan *watch.FakeWatcher, 10)
+				servicesWatchers = watchers
+
+				// the service filters are added from the watcher goroutine, which is signaled through a channel
+				// instead of reading the mock calls without its lock
+				added := make(chan struct{}, 10)
+				serviceFilterAdded = added
+
+				tcAddFilterCall.Run(func(args mock.Arguments) {
+					if args.Get(5) == "172.16.0.1/32" {
+						added <- struct{}{}
+					}
+				})
+
+				k8sClient.PrependWatchReactor("pods", func(action testing.Action) (bool, watch.Interface, error) {
+					return true, watch.NewFakeWithChanSize(10, false), nil
+				})
+				k8sClient.PrependWatchReactor("services", func(action testing.Action) (bool, watch.Interface, error) {
+					servicesWatcher := watch.NewFakeWithChanSize(10, false)
+					watchers <- servicesWatcher
+
+					return true, servicesWatcher, nil
+				})
+			})
+
+			AfterEach(func() {
+				inj.Clean()
+			})
+
+			It("should add the service filters from the watchers started by the new injection", func() {
+				var servicesWatcher *watch.FakeWatcher
+
+				Eventually(servicesWatchers, time.Second*5, 100*time.Millisecond).Should(Receive(&servicesWatcher))
+
+				Expect(inj.Clean()).To(BeNil())
+				Expect(inj.Inject()).To(BeNil())
+
+				// the watcher of the first injection is stopped and a new one is started
+				Eventually(servicesWatchers, time.Second*5, 100*time.Millisecond).Should(Receive(&servicesWatcher))
+				tc.AssertNotCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "172.16.0.1/32", 0, 80, "TCP", "1:4")
+
+				servicesWatcher.Add(fakeService)
+
+				Eventually(serviceFilterAdded, time.Second*5, 100*time.Millisecond).Should(Receive())
+			})
+		})
+
+		Context("with a partition", func() {
+			// the partition peer pods are watched once for both tc filters targets (interfaces and IFB device)
+			var podsWatchers chan *watch.FakeWatcher
+
+			BeforeEach(func() {
+				spec = v1beta1.NetworkDisruptionSpec{
+					Partition: &v1beta1.NetworkDisruptionPartitionSpec{
+						Selector:  map[string]string{"app": "foo"},
+						Namespace: "bar",
+					},
+				}
+
+				// the watchers are sent to the channel of the spec the client was created for
+				watchers := make(chan *watch.FakeWatcher, 10)
+				podsWatchers = watchers
+
+				k8sClient.PrependWatchReactor("pods", func(action testing.Action) (bool, watch.Interface, error) {
+					podsWatcher := watch.NewFakeWithChanSize(10, false)
+					watchers <- podsWatcher
+
+					return true, podsWatcher, nil
+				})
+			})
+
+			AfterEach(func() {
+				inj.Clean()
+			})
+
+			It("should drop all the packets going to the disrupted band", func() {
+				tc.AssertCalled(GinkgoT(), "AddNetem", []string{"lo", "eth0", "eth1"}, "2:2", uint32(3), network.NetemParams{Drop: 100})
+				tc.AssertNotCalled(GinkgoT(), "AddOutputLimit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+
+			It("should add filters for the existing peer pods in both directions", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "10.1.0.4/32", 0, 0, "", "1:4")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"chaos-ifb"}, "1:0", mock.Anything, mock.Anything, "10.1.0.4/32", "nil", 0, 0, "", "1:4")
+				tc.AssertNotCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "0.0.0.0/0", 0, 0, "", "1:4")
+			})
+
+			It("should redirect the incoming traffic to an IFB device", func() {
+				nl.AssertCalled(GinkgoT(), "LinkAddIFB", "chaos-ifb")
+				tc.AssertCalled(GinkgoT(), "AddIngressRedirect", []string{"lo", "eth0", "eth1"}, "chaos-ifb")
+			})
+
+			It("should add and delete filters on both targets when peer pods are created and deleted", func() {
+				var podsWatcher *watch.FakeWatcher
+
+				// wait for the watcher to be started, a single one updating both targets
+				Eventually(podsWatchers, time.Second*5, 100*time.Millisecond).Should(Receive(&podsWatcher))
+				Consistently(podsWatchers, 500*time.Millisecond, 100*time.Millisecond).ShouldNot(Receive())
+
+				podsWatcher.Add(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo-efgh-5678", Namespace: "bar", Labels: map[string]string{"app": "foo"}},
+					Status:     corev1.PodStatus{PodIP: "10.1.0.5"},
+				})
+				podsWatcher.Delete(fakeEndpoint)
+
+				time.Sleep(time.Second)
+
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "10.1.0.5/32", 0, 0, "", "1:4")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"chaos-ifb"}, "1:0", mock.Anything, mock.Anything, "10.1.0.5/32", "nil", 0, 0, "", "1:4")
+				tc.AssertCalled(GinkgoT(), "DeleteFilter", "eth0", "1:0", mock.Anything)
+				tc.AssertCalled(GinkgoT(), "DeleteFilter", "chaos-ifb", "1:0", mock.Anything)
 			})
 		})
 
@@ -384,6 +830,83 @@ var _ = Describe("Failure", func() {
 			})
 		})
 
+		Context("on a cgroup v2 host", func() {
+			BeforeEach(func() {
+				cgroupManagerIsCgroupV2Call.Return(true)
+			})
+
+			It("should not write any classid to the target net_cls cgroup", func() {
+				cgroupManager.AssertNotCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", mock.Anything)
+			})
+
+			It("should classify the target cgroup packets with iptables", func() {
+				iptables.AssertCalled(GinkgoT(), "AddCgroupPathClassifyRule", "/kubepods/pod1/ctn1", "2:2")
+				ip6tables.AssertCalled(GinkgoT(), "AddCgroupPathClassifyRule", "/kubepods/pod1/ctn1", "2:2")
+			})
+
+			It("should add a second prio band without any cgroup filter", func() {
+				tc.AssertCalled(GinkgoT(), "AddPrio", []string{"lo", "eth0", "eth1"}, "1:4", uint32(2), uint32(2), mock.Anything)
+				tc.AssertNotCalled(GinkgoT(), "AddCgroupFilter", mock.Anything, mock.Anything, mock.Anything)
+			})
+
+			Context("on pod initialization", func() {
+				BeforeEach(func() {
+					config.OnInit = true
+				})
+
+				It("should not classify the target cgroup packets", func() {
+					iptables.AssertNotCalled(GinkgoT(), "AddCgroupPathClassifyRule", mock.Anything, mock.Anything)
+				})
+			})
+		})
+
+		Context("with ingress shaping", func() {
+			BeforeEach(func() {
+				spec.IngressShaping = true
+				spec.Hosts = []v1beta1.NetworkDisruptionHostSpec{
+					{
+						Host:     "1.1.1.1",
+						Port:     443,
+						Protocol: "tcp",
+					},
+					{
+						Port: 80,
+						Flow: "ingress",
+					},
+				}
+			})
+
+			It("should create an IFB device", func() {
+				nl.AssertCalled(GinkgoT(), "LinkAddIFB", "chaos-ifb")
+			})
+
+			It("should build the tree on the IFB device without the cgroup prio qdisc", func() {
+				tc.AssertCalled(GinkgoT(), "AddPrio", []string{"chaos-ifb"}, "root", uint32(1), uint32(4), mock.Anything)
+				tc.AssertNotCalled(GinkgoT(), "AddPrio", []string{"chaos-ifb"}, "1:4", mock.Anything, mock.Anything, mock.Anything)
+				tc.AssertNotCalled(GinkgoT(), "AddCgroupFilter", []string{"chaos-ifb"}, mock.Anything, mock.Anything)
+				tc.AssertCalled(GinkgoT(), "AddNetem", []string{"chaos-ifb"}, "1:4", uint32(2), network.NetemParams{Delay: time.Second, DelayJitter: time.Second, Drop: spec.Drop, Duplicate: spec.Duplicate, Corrupt: spec.Corrupt})
+				tc.AssertCalled(GinkgoT(), "AddOutputLimit", []string{"chaos-ifb"}, "2:", uint32(3), uint(spec.BandwidthLimit))
+			})
+
+			It("should add filters matching the given hosts as source of the incoming packets", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"chaos-ifb"}, "1:0", mock.Anything, mock.Anything, "1.1.1.1/32", "nil", 443, 0, "tcp", "1:4")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"chaos-ifb"}, "1:0", mock.Anything, mock.Anything, "0.0.0.0/0", "nil", 0, 80, "", "1:4")
+			})
+
+			It("should add filters excluding the node and gateway incoming packets from the disruption", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"chaos-ifb"}, "1:0", mock.Anything, mock.Anything, "10.0.0.2/32", "nil", 0, 0, "", "1:1")
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"chaos-ifb"}, "1:0", mock.Anything, mock.Anything, "192.168.0.1/32", "nil", 0, 0, "", "1:1")
+			})
+
+			It("should redirect the incoming traffic of main interfaces to the IFB device", func() {
+				tc.AssertCalled(GinkgoT(), "AddIngressRedirect", []string{"lo", "eth0", "eth1"}, "chaos-ifb")
+			})
+
+			It("should keep disrupting the outgoing traffic", func() {
+				tc.AssertCalled(GinkgoT(), "AddFilter", []string{"lo", "eth0", "eth1"}, "1:0", mock.Anything, mock.Anything, "nil", "1.1.1.1/32", 0, 443, "tcp", "1:4")
+			})
+		})
+
 		Context("with allowed hosts", func() {
 			BeforeEach(func() {
 				spec.AllowedHosts = []v1beta1.NetworkDisruptionHostSpec{
@@ -416,6 +939,24 @@ var _ = Describe("Failure", func() {
 			})
 		})
 
+		Context("with ingress shaping", func() {
+			BeforeEach(func() {
+				spec.IngressShaping = true
+			})
+
+			It("should clear the interfaces ingress qdisc and delete the IFB device", func() {
+				tc.AssertCalled(GinkgoT(), "ClearIngressQdisc", []string{"lo", "eth0", "eth1"})
+				nl.AssertCalled(GinkgoT(), "LinkDelete", "chaos-ifb")
+			})
+		})
+
+		Context("without ingress shaping", func() {
+			It("should not delete any IFB device", func() {
+				tc.AssertNotCalled(GinkgoT(), "ClearIngressQdisc", mock.Anything)
+				nl.AssertNotCalled(GinkgoT(), "LinkDelete", mock.Anything)
+			})
+		})
+
 		Context("with an existing net_cls cgroup", func() {
 			It("should erase the classid value", func() {
 				cgroupManager.AssertCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", "0x0")
@@ -431,5 +972,38 @@ var _ = Describe("Failure", func() {
 				cgroupManager.AssertNotCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", mock.Anything)
 			})
 		})
+
+		Context("with reject", func() {
+			BeforeEach(func() {
+				spec.Reject = v1beta1.RejectTCPReset
+			})
+
+			It("should delete the rule jumping to the reject chain before deleting the chain", func() {
+				iptables.AssertCalled(GinkgoT(), "DeleteFilterJumpRule", "OUTPUT", "CHAOS-REJECT", []string{"-m", "cgroup", "--cgroup", "0x00020002"})
+				iptables.AssertCalled(GinkgoT(), "ClearAndDeleteFilterChain", "CHAOS-REJECT")
+				ip6tables.AssertCalled(GinkgoT(), "ClearAndDeleteFilterChain", "CHAOS-REJECT")
+			})
+		})
+
+		Context("without reject", func() {
+			It("should not delete any iptables chain", func() {
+				iptables.AssertNotCalled(GinkgoT(), "ClearAndDeleteFilterChain", mock.Anything)
+			})
+		})
+
+		Context("on a cgroup v2 host", func() {
+			BeforeEach(func() {
+				cgroupManagerIsCgroupV2Call.Return(true)
+			})
+
+			It("should delete the iptables rule classifying the target cgroup packets", func() {
+				iptables.AssertCalled(GinkgoT(), "DeleteCgroupPathClassifyRule", "/kubepods/pod1/ctn1", "2:2")
+				ip6tables.AssertCalled(GinkgoT(), "DeleteCgroupPathClassifyRule", "/kubepods/pod1/ctn1", "2:2")
+			})
+
+			It("should not try to erase the classid value", func() {
+				cgroupManager.AssertNotCalled(GinkgoT(), "Write", "net_cls", "net_cls.classid", mock.Anything)
+			})
+		})
 	})
 })
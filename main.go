@@ -38,6 +38,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/DataDog/chaos-controller/abortcondition"
 	chaosv1beta1 "github.com/DataDog/chaos-controller/api/v1beta1"
 	"github.com/DataDog/chaos-controller/controllers"
 	"github.com/DataDog/chaos-controller/eventbroadcaster"
@@ -77,6 +78,7 @@ type controllerConfig struct {
 	DeleteOnly               bool                          `json:"deleteOnly"`
 	EnableSafeguards         bool                          `json:"enableSafeguards"`
 	EnableObserver           bool                          `json:"enableObserver"`
+	RespectPDBs              bool                          `json:"respectPDBs"`
 	LeaderElection           bool                          `json:"leaderElection"`
 	Webhook                  controllerWebhookConfig       `json:"webhook"`
 	Notifiers                eventnotifier.NotifiersConfig `json:"notifiersConfig"`
@@ -112,7 +114,8 @@ type injectorDNSDisruptionConfig struct {
 }
 
 type injectorNetworkDisruptionConfig struct {
-	AllowedHosts []string `json:"allowedHosts"`
+	AllowedHosts      []string `json:"allowedHosts"`
+	TrafficController string   `json:"trafficController"`
 }
 
 type handlerConfig struct {
@@ -146,6 +149,9 @@ func main() {
 	pflag.BoolVar(&cfg.Controller.EnableObserver, "enable-observer", true, "Enable observer on targets")
 	handleFatalError(viper.BindPFlag("controller.enableObserver", pflag.Lookup("enable-observer")))
 
+	pflag.BoolVar(&cfg.Controller.RespectPDBs, "respect-pdbs", false, "Cap pod targets to the disruptions allowed by matching pod disruption budgets by default")
+	handleFatalError(viper.BindPFlag("controller.respectPDBs", pflag.Lookup("respect-pdbs")))
+
 	pflag.StringVar(&cfg.Controller.ImagePullSecrets, "image-pull-secrets", "", "Secrets used for pulling the Docker image from a private registry")
 	handleFatalError(viper.BindPFlag("controller.imagePullSecrets", pflag.Lookup("image-pull-secrets")))
 
@@ -155,7 +161,7 @@ func main() {
 	pflag.DurationVar(&cfg.Controller.DefaultDuration, "default-duration", time.Hour, "Default duration for a disruption with none specified")
 	handleFatalError(viper.BindPFlag("controller.defaultDuration", pflag.Lookup("default-duration")))
 
-	pflag.StringVar(&cfg.Controller.MetricsSink, "metrics-sink", "noop", "Metrics sink (datadog, or noop)")
+	pflag.StringVar(&cfg.Controller.MetricsSink, "metrics-sink", "noop", "Metrics sink (datadog, prometheus, or noop)")
 	handleFatalError(viper.BindPFlag("controller.metricsSink", pflag.Lookup("metrics-sink")))
 
 	pflag.StringVar(&cfg.Controller.Notifiers.Common.ClusterName, "notifiers-common-clustername", "", "Cluster Name for notifiers output")
@@ -212,6 +218,9 @@ func main() {
 	pflag.StringSliceVar(&cfg.Injector.NetworkDisruption.AllowedHosts, "injector-network-disruption-allowed-hosts", []string{}, "List of hosts always allowed by network disruptions (format: <host>;<port>;<protocol>;<flow>)")
 	handleFatalError(viper.BindPFlag("injector.networkDisruption.allowedHosts", pflag.Lookup("injector-network-disruption-allowed-hosts")))
 
+	pflag.StringVar(&cfg.Injector.NetworkDisruption.TrafficController, "injector-network-disruption-traffic-controller", "tc", "Implementation used by network disruptions to add the qdiscs and filters (tc, netlink)")
+	handleFatalError(viper.BindPFlag("injector.networkDisruption.trafficController", pflag.Lookup("injector-network-disruption-traffic-controller")))
+
 	pflag.BoolVar(&cfg.Handler.Enabled, "handler-enabled", false, "Enable the chaos handler for on-init disruptions")
 	handleFatalError(viper.BindPFlag("handler.enabled", pflag.Lookup("handler-enabled")))
 
@@ -342,6 +351,7 @@ func main() {
 		Recorder:                              mgr.GetEventRecorderFor(chaosv1beta1.SourceDisruptionComponent),
 		MetricsSink:                           ms,
 		TargetSelector:                        targetSelector,
+		AbortConditionEvaluator:               abortcondition.NewEvaluator(),
 		InjectorAnnotations:                   cfg.Injector.Annotations,
 		InjectorLabels:                        cfg.Injector.Labels,
 		InjectorServiceAccount:                cfg.Injector.ServiceAccount,
@@ -350,11 +360,13 @@ func main() {
 		InjectorDNSDisruptionDNSServer:        cfg.Injector.DNSDisruption.DNSServer,
 		InjectorDNSDisruptionKubeDNS:          cfg.Injector.DNSDisruption.KubeDNS,
 		InjectorNetworkDisruptionAllowedHosts: cfg.Injector.NetworkDisruption.AllowedHosts,
-		ImagePullSecrets:                      cfg.Controller.ImagePullSecrets,
-		ExpiredDisruptionGCDelay:              gcPtr,
-		CacheContextStore:                     make(map[string]controllers.CtxTuple),
-		Reader:                                mgr.GetAPIReader(),
-		EnableObserver:                        cfg.Controller.EnableObserver,
+		InjectorNetworkDisruptionTrafficController: cfg.Injector.NetworkDisruption.TrafficController,
+		ImagePullSecrets:            cfg.Controller.ImagePullSecrets,
+		ExpiredDisruptionGCDelay:    gcPtr,
+		CacheContextStore:           make(map[string]controllers.CtxTuple),
+		Reader:                      mgr.GetAPIReader(),
+		EnableObserver:              cfg.Controller.EnableObserver,
+		RespectPodDisruptionBudgets: cfg.Controller.RespectPDBs,
 	}
 
 	informerClient := kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie())
@@ -373,6 +385,34 @@ func main() {
 
 	go r.ReportMetrics()
 
+	// create disruption cron reconciler
+	cronReconciler := &controllers.DisruptionCronReconciler{
+		Client:     mgr.GetClient(),
+		BaseLog:    logger,
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor(chaosv1beta1.SourceDisruptionCronComponent),
+		DeleteOnly: cfg.Controller.DeleteOnly,
+	}
+
+	if err := cronReconciler.SetupWithManager(mgr); err != nil {
+		logger.Errorw("unable to create controller", "controller", "DisruptionCron", "error", err)
+		os.Exit(1) //nolint:gocritic
+	}
+
+	// create disruption workflow reconciler
+	workflowReconciler := &controllers.DisruptionWorkflowReconciler{
+		Client:   mgr.GetClient(),
+		BaseLog:  logger,
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(chaosv1beta1.SourceDisruptionWorkflowComponent),
+		Reader:   mgr.GetAPIReader(),
+	}
+
+	if err := workflowReconciler.SetupWithManager(mgr); err != nil {
+		logger.Errorw("unable to create controller", "controller", "DisruptionWorkflow", "error", err)
+		os.Exit(1) //nolint:gocritic
+	}
+
 	// register disruption validating webhook
 	setupWebhookConfig := utils.SetupWebhookWithManagerConfig{
 		Manager:                mgr,
@@ -391,6 +431,18 @@ func main() {
 		os.Exit(1) //nolint:gocritic
 	}
 
+	// register disruption cron validating webhook, relying on the disruption webhook configuration
+	if err = (&chaosv1beta1.DisruptionCron{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DisruptionCron")
+		os.Exit(1) //nolint:gocritic
+	}
+
+	// register disruption workflow validating webhook, relying on the disruption webhook configuration
+	if err = (&chaosv1beta1.DisruptionWorkflow{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DisruptionWorkflow")
+		os.Exit(1) //nolint:gocritic
+	}
+
 	if cfg.Handler.Enabled {
 		// register chaos handler init container mutating webhook
 		mgr.GetWebhookServer().Register("/mutate-v1-pod-chaos-handler-init-container", &webhook.Admission{
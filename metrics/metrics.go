@@ -11,6 +11,7 @@ import (
 
 	"github.com/DataDog/chaos-controller/metrics/datadog"
 	"github.com/DataDog/chaos-controller/metrics/noop"
+	"github.com/DataDog/chaos-controller/metrics/prometheus"
 	"github.com/DataDog/chaos-controller/metrics/types"
 	chaostypes "github.com/DataDog/chaos-controller/types"
 )
@@ -51,6 +52,8 @@ func GetSink(driver types.SinkDriver, app types.SinkApp) (Sink, error) {
 	switch driver {
 	case types.SinkDriverDatadog:
 		return datadog.New(app)
+	case types.SinkDriverPrometheus:
+		return prometheus.New(app)
 	case types.SinkDriverNoop:
 		return noop.New(), nil
 	default:
@@ -0,0 +1,409 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/DataDog/chaos-controller/metrics/types"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricPrefixInjector   = "chaos_injector_"
+	metricPrefixController = "chaos_controller_"
+
+	// injectorBindAddressEnv is the environment variable used to override the injector metrics endpoint address
+	injectorBindAddressEnv = "PROMETHEUS_METRICS_BIND_ADDRESS"
+	// injectorDefaultBindAddress is the default injector metrics endpoint address
+	injectorDefaultBindAddress = ":9090"
+)
+
+// durationBuckets are the histogram buckets (in seconds) used for durations, from 5ms to almost 6h
+var durationBuckets = prometheus.ExponentialBuckets(0.005, 4, 12)
+
+// metricDefinition describes a metric and the labels it extracts from the given tags
+type metricDefinition struct {
+	app    types.SinkApp
+	help   string
+	labels []string
+}
+
+var counterDefinitions = map[string]metricDefinition{
+	metricPrefixInjector + "injected_total":                 {types.SinkAppInjector, "Number of injections", []string{"status", "kind"}},
+	metricPrefixInjector + "reinjected_total":               {types.SinkAppInjector, "Number of reinjections", []string{"status", "kind"}},
+	metricPrefixInjector + "cleaned_total":                  {types.SinkAppInjector, "Number of cleanups", []string{"status", "kind"}},
+	metricPrefixInjector + "cleaned_for_reinjection_total":  {types.SinkAppInjector, "Number of cleanups before a reinjection", []string{"status", "kind"}},
+	metricPrefixController + "reconcile_total":              {types.SinkAppController, "Number of reconcile loops", nil},
+	metricPrefixController + "pods_created_total":           {types.SinkAppController, "Number of created chaos pods", []string{"target", "name", "status", "namespace"}},
+	metricPrefixController + "disruptions_stuck_on_removal": {types.SinkAppController, "Number of times a disruption has been found stuck on removal", []string{"name", "namespace"}},
+	metricPrefixController + "disruptions_count_total":      {types.SinkAppController, "Number of finished disruptions", []string{"name", "namespace", "disruption_kind"}},
+	metricPrefixController + "restart_total":                {types.SinkAppController, "Number of controller restarts", nil},
+	metricPrefixController + "validation_failed_total":      {types.SinkAppController, "Number of failed disruption validations", []string{"name", "namespace", "username"}},
+	metricPrefixController + "validation_created_total":     {types.SinkAppController, "Number of validated created disruptions", []string{"name", "namespace", "username"}},
+	metricPrefixController + "validation_updated_total":     {types.SinkAppController, "Number of validated updated disruptions", []string{"name", "namespace", "username"}},
+	metricPrefixController + "validation_deleted_total":     {types.SinkAppController, "Number of validated deleted disruptions", []string{"name", "namespace", "username"}},
+	metricPrefixController + "informed_total":               {types.SinkAppController, "Number of chaos pods events received by the informer", []string{"pod_name", "pod_namespace"}},
+	metricPrefixController + "orphan_found_total":           {types.SinkAppController, "Number of chaos pods found without their disruption", []string{"disruption", "chaos_pod", "namespace"}},
+	metricPrefixController + "selector_cache_triggered":     {types.SinkAppController, "Number of selector cache triggers", []string{"name", "namespace", "event", "target_kind", "target"}},
+}
+
+var histogramDefinitions = map[string]metricDefinition{
+	metricPrefixController + "reconcile_duration_seconds":            {types.SinkAppController, "Duration of reconcile loops", []string{"name", "namespace"}},
+	metricPrefixController + "cleanup_duration_seconds":              {types.SinkAppController, "Duration of disruptions cleanup", []string{"name", "namespace"}},
+	metricPrefixController + "inject_duration_seconds":               {types.SinkAppController, "Duration from disruptions creation until their full injection", []string{"name", "namespace"}},
+	metricPrefixController + "disruption_completed_duration_seconds": {types.SinkAppController, "Duration of completed disruptions", []string{"name", "namespace"}},
+	metricPrefixController + "disruption_ongoing_duration_seconds":   {types.SinkAppController, "Duration of ongoing disruptions so far", []string{"name", "namespace"}},
+}
+
+var gaugeDefinitions = map[string]metricDefinition{
+	metricPrefixController + "disruptions_stuck_on_removal_total": {types.SinkAppController, "Number of disruptions stuck on removal", nil},
+	metricPrefixController + "disruptions_gauge":                  {types.SinkAppController, "Number of ongoing disruptions", nil},
+	metricPrefixController + "pods_gauge":                         {types.SinkAppController, "Number of existing chaos pods", nil},
+	metricPrefixController + "selector_cache_gauge":               {types.SinkAppController, "Number of selector caches", nil},
+}
+
+// Sink describes a Prometheus sink
+type Sink struct {
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+	gauges     map[string]prometheus.Gauge
+	server     *http.Server
+}
+
+// New instantiates a new Prometheus sink
+// the controller sink registers its metrics on the controller-runtime registry, already served by the manager,
+// while the injector sink serves its own metrics endpoint
+func New(app types.SinkApp) (*Sink, error) {
+	if app == types.SinkAppController {
+		return newSink(app, ctrlmetrics.Registry)
+	}
+
+	registry := prometheus.NewRegistry()
+
+	sink, err := newSink(app, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := injectorDefaultBindAddress
+	if envAddr, ok := os.LookupEnv(injectorBindAddressEnv); ok {
+		addr = envAddr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s to serve metrics: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	sink.server = &http.Server{Handler: mux}
+
+	go func() {
+		_ = sink.server.Serve(listener)
+	}()
+
+	return sink, nil
+}
+
+// newSink creates a sink registering the metrics of the given app on the given registerer
+func newSink(app types.SinkApp, registerer prometheus.Registerer) (*Sink, error) {
+	sink := &Sink{
+		counters:   map[string]*prometheus.CounterVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+		gauges:     map[string]prometheus.Gauge{},
+	}
+
+	collectors := []prometheus.Collector{}
+
+	for name, def := range counterDefinitions {
+		if def.app == app {
+			sink.counters[name] = prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: def.help}, def.labels)
+			collectors = append(collectors, sink.counters[name])
+		}
+	}
+
+	for name, def := range histogramDefinitions {
+		if def.app == app {
+			sink.histograms[name] = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: def.help, Buckets: durationBuckets}, def.labels)
+			collectors = append(collectors, sink.histograms[name])
+		}
+	}
+
+	for name, def := range gaugeDefinitions {
+		if def.app == app {
+			sink.gauges[name] = prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: def.help})
+			collectors = append(collectors, sink.gauges[name])
+		}
+	}
+
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("error registering metrics: %w", err)
+		}
+	}
+
+	return sink, nil
+}
+
+// Close stops the metrics endpoint if any
+func (p *Sink) Close() error {
+	if p.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return p.server.Shutdown(ctx)
+}
+
+// GetSinkName returns the name of the sink
+func (p *Sink) GetSinkName() string {
+	return string(types.SinkDriverPrometheus)
+}
+
+// MetricInjected increments the injected metric
+func (p *Sink) MetricInjected(succeed bool, kind string, tags []string) error {
+	return p.incr(metricPrefixInjector+"injected_total", append([]string{"status:" + boolToStatus(succeed), "kind:" + kind}, tags...))
+}
+
+// MetricReinjected increments the reinjected metric
+func (p *Sink) MetricReinjected(succeed bool, kind string, tags []string) error {
+	return p.incr(metricPrefixInjector+"reinjected_total", append([]string{"status:" + boolToStatus(succeed), "kind:" + kind}, tags...))
+}
+
+// MetricCleanedForReinjection increments the cleanedForReinjection metric
+func (p *Sink) MetricCleanedForReinjection(succeed bool, kind string, tags []string) error {
+	return p.incr(metricPrefixInjector+"cleaned_for_reinjection_total", append([]string{"status:" + boolToStatus(succeed), "kind:" + kind}, tags...))
+}
+
+// MetricCleaned increments the cleaned metric
+func (p *Sink) MetricCleaned(succeed bool, kind string, tags []string) error {
+	return p.incr(metricPrefixInjector+"cleaned_total", append([]string{"status:" + boolToStatus(succeed), "kind:" + kind}, tags...))
+}
+
+// MetricReconcile increment reconcile metric
+func (p *Sink) MetricReconcile() error {
+	return p.incr(metricPrefixController+"reconcile_total", nil)
+}
+
+// MetricReconcileDuration send timing metric for reconcile loop
+func (p *Sink) MetricReconcileDuration(duration time.Duration, tags []string) error {
+	return p.timing(metricPrefixController+"reconcile_duration_seconds", duration, tags)
+}
+
+// MetricCleanupDuration send timing metric for cleanup duration
+func (p *Sink) MetricCleanupDuration(duration time.Duration, tags []string) error {
+	return p.timing(metricPrefixController+"cleanup_duration_seconds", duration, tags)
+}
+
+// MetricInjectDuration send timing metric for inject duration
+func (p *Sink) MetricInjectDuration(duration time.Duration, tags []string) error {
+	return p.timing(metricPrefixController+"inject_duration_seconds", duration, tags)
+}
+
+// MetricDisruptionCompletedDuration sends timing metric for entire disruption duration
+func (p *Sink) MetricDisruptionCompletedDuration(duration time.Duration, tags []string) error {
+	return p.timing(metricPrefixController+"disruption_completed_duration_seconds", duration, tags)
+}
+
+// MetricDisruptionOngoingDuration sends timing metric for disruption duration so far
+func (p *Sink) MetricDisruptionOngoingDuration(duration time.Duration, tags []string) error {
+	return p.timing(metricPrefixController+"disruption_ongoing_duration_seconds", duration, tags)
+}
+
+// MetricPodsCreated increment pods.created metric
+func (p *Sink) MetricPodsCreated(target, instanceName, namespace string, succeed bool) error {
+	return p.incr(metricPrefixController+"pods_created_total", []string{"target:" + target, "name:" + instanceName, "status:" + boolToStatus(succeed), "namespace:" + namespace})
+}
+
+// MetricStuckOnRemoval increments disruptions.stuck_on_removal metric
+func (p *Sink) MetricStuckOnRemoval(tags []string) error {
+	return p.incr(metricPrefixController+"disruptions_stuck_on_removal", tags)
+}
+
+// MetricStuckOnRemovalGauge sends disruptions.stuck_on_removal_total metric containing the gauge of stuck disruptions
+func (p *Sink) MetricStuckOnRemovalGauge(gauge float64) error {
+	return p.gauge(metricPrefixController+"disruptions_stuck_on_removal_total", gauge)
+}
+
+// MetricDisruptionsGauge sends the disruptions.gauge metric counting ongoing disruptions
+func (p *Sink) MetricDisruptionsGauge(gauge float64) error {
+	return p.gauge(metricPrefixController+"disruptions_gauge", gauge)
+}
+
+// MetricDisruptionsCount counts finished disruptions, and tags the disruption kind
+func (p *Sink) MetricDisruptionsCount(kind chaostypes.DisruptionKindName, tags []string) error {
+	return p.incr(metricPrefixController+"disruptions_count_total", append(tags, "disruption_kind:"+string(kind)))
+}
+
+// MetricPodsGauge sends the pods.gauge metric counting existing chaos pods
+func (p *Sink) MetricPodsGauge(gauge float64) error {
+	return p.gauge(metricPrefixController+"pods_gauge", gauge)
+}
+
+// MetricRestart sends an increment of the controller restart metric
+func (p *Sink) MetricRestart() error {
+	return p.incr(metricPrefixController+"restart_total", nil)
+}
+
+// MetricValidationFailed increments the failed validation metric
+func (p *Sink) MetricValidationFailed(tags []string) error {
+	return p.incr(metricPrefixController+"validation_failed_total", tags)
+}
+
+// MetricValidationCreated increments the created validation metric
+func (p *Sink) MetricValidationCreated(tags []string) error {
+	return p.incr(metricPrefixController+"validation_created_total", tags)
+}
+
+// MetricValidationUpdated increments the updated validation metric
+func (p *Sink) MetricValidationUpdated(tags []string) error {
+	return p.incr(metricPrefixController+"validation_updated_total", tags)
+}
+
+// MetricValidationDeleted increments the deleted validation metric
+func (p *Sink) MetricValidationDeleted(tags []string) error {
+	return p.incr(metricPrefixController+"validation_deleted_total", tags)
+}
+
+// MetricInformed increments when the pod informer receives an event to process before reconciliation
+func (p *Sink) MetricInformed(tags []string) error {
+	return p.incr(metricPrefixController+"informed_total", tags)
+}
+
+// MetricOrphanFound increments when a chaos pod without a corresponding disruption resource is found
+func (p *Sink) MetricOrphanFound(tags []string) error {
+	return p.incr(metricPrefixController+"orphan_found_total", tags)
+}
+
+// MetricSelectorCacheTriggered signals a selector cache trigger
+func (p *Sink) MetricSelectorCacheTriggered(tags []string) error {
+	return p.incr(metricPrefixController+"selector_cache_triggered", tags)
+}
+
+// MetricSelectorCacheGauge reports how many caches are still in the cache array to prevent leaks
+func (p *Sink) MetricSelectorCacheGauge(gauge float64) error {
+	return p.gauge(metricPrefixController+"selector_cache_gauge", gauge)
+}
+
+func (p *Sink) incr(name string, tags []string) error {
+	counter, ok := p.counters[name]
+	if !ok {
+		return fmt.Errorf("metric %s is not registered", name)
+	}
+
+	c, err := counter.GetMetricWith(tagsToLabels(counterDefinitions[name].labels, tags))
+	if err != nil {
+		return err
+	}
+
+	c.Inc()
+
+	return nil
+}
+
+func (p *Sink) timing(name string, duration time.Duration, tags []string) error {
+	histogram, ok := p.histograms[name]
+	if !ok {
+		return fmt.Errorf("metric %s is not registered", name)
+	}
+
+	h, err := histogram.GetMetricWith(tagsToLabels(histogramDefinitions[name].labels, tags))
+	if err != nil {
+		return err
+	}
+
+	h.Observe(duration.Seconds())
+
+	return nil
+}
+
+func (p *Sink) gauge(name string, value float64) error {
+	gauge, ok := p.gauges[name]
+	if !ok {
+		return fmt.Errorf("metric %s is not registered", name)
+	}
+
+	gauge.Set(value)
+
+	return nil
+}
+
+// tagsToLabels converts the given tags (key:value) to the given label names values
+// tag keys are converted to snake case, values of tags with the same key are joined
+// and label names without any matching tag get an empty value
+func tagsToLabels(labelNames []string, tags []string) prometheus.Labels {
+	labels := prometheus.Labels{}
+
+	for _, name := range labelNames {
+		labels[name] = ""
+	}
+
+	for _, tag := range tags {
+		parts := strings.SplitN(tag, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := toSnakeCase(parts[0])
+
+		value, ok := labels[key]
+		if !ok {
+			continue
+		}
+
+		if value != "" {
+			value += ","
+		}
+
+		labels[key] = value + parts[1]
+	}
+
+	return labels
+}
+
+// toSnakeCase converts the given camel case string to snake case
+func toSnakeCase(s string) string {
+	var builder strings.Builder
+
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteRune('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+func boolToStatus(succeed bool) string {
+	var status string
+	if succeed {
+		status = "succeed"
+	} else {
+		status = "failed"
+	}
+
+	return status
+}
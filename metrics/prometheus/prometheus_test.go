@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/DataDog/chaos-controller/metrics/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Sink", func() {
+	var (
+		registry *prometheus.Registry
+		sink     *Sink
+	)
+
+	// gather returns the gathered metric families indexed by name
+	gather := func() map[string]*dto.MetricFamily {
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		indexed := map[string]*dto.MetricFamily{}
+		for _, family := range families {
+			indexed[family.GetName()] = family
+		}
+
+		return indexed
+	}
+
+	// labels returns the labels of the given metric as a map
+	labels := func(metric *dto.Metric) map[string]string {
+		l := map[string]string{}
+		for _, pair := range metric.GetLabel() {
+			l[pair.GetName()] = pair.GetValue()
+		}
+
+		return l
+	}
+
+	Context("for the controller", func() {
+		BeforeEach(func() {
+			var err error
+
+			registry = prometheus.NewRegistry()
+			sink, err = newSink(types.SinkAppController, registry)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only register controller metrics", func() {
+			Expect(sink.MetricInjected(true, "network-disruption", nil)).ToNot(Succeed())
+			Expect(sink.MetricReconcile()).To(Succeed())
+		})
+
+		It("should derive labels from tags", func() {
+			Expect(sink.MetricSelectorCacheTriggered([]string{"name:foo", "namespace:bar", "event:add", "targetKind:pod", "target:baz", "unknown:tag"})).To(Succeed())
+
+			family := gather()[metricPrefixController+"selector_cache_triggered"]
+			Expect(family).ToNot(BeNil())
+			Expect(family.GetMetric()).To(HaveLen(1))
+			Expect(labels(family.GetMetric()[0])).To(Equal(map[string]string{
+				"name":        "foo",
+				"namespace":   "bar",
+				"event":       "add",
+				"target_kind": "pod",
+				"target":      "baz",
+			}))
+			Expect(family.GetMetric()[0].GetCounter().GetValue()).To(Equal(1.0))
+		})
+
+		It("should leave missing labels empty", func() {
+			Expect(sink.MetricReconcileDuration(2*time.Second, nil)).To(Succeed())
+
+			family := gather()[metricPrefixController+"reconcile_duration_seconds"]
+			Expect(family).ToNot(BeNil())
+			Expect(labels(family.GetMetric()[0])).To(Equal(map[string]string{"name": "", "namespace": ""}))
+			Expect(family.GetMetric()[0].GetHistogram().GetSampleSum()).To(Equal(2.0))
+		})
+
+		It("should set gauges", func() {
+			Expect(sink.MetricDisruptionsGauge(3)).To(Succeed())
+
+			family := gather()[metricPrefixController+"disruptions_gauge"]
+			Expect(family).ToNot(BeNil())
+			Expect(family.GetMetric()[0].GetGauge().GetValue()).To(Equal(3.0))
+		})
+	})
+
+	Context("for the injector", func() {
+		BeforeEach(func() {
+			var err error
+
+			registry = prometheus.NewRegistry()
+			sink, err = newSink(types.SinkAppInjector, registry)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should count injections per kind and status", func() {
+			Expect(sink.MetricInjected(true, "network-disruption", nil)).To(Succeed())
+			Expect(sink.MetricInjected(false, "network-disruption", nil)).To(Succeed())
+			Expect(sink.MetricInjected(true, "network-disruption", nil)).To(Succeed())
+
+			family := gather()[metricPrefixInjector+"injected_total"]
+			Expect(family).ToNot(BeNil())
+			Expect(family.GetMetric()).To(HaveLen(2))
+		})
+	})
+
+	Describe("tagsToLabels", func() {
+		It("should join values of tags sharing the same key", func() {
+			Expect(tagsToLabels([]string{"username"}, []string{"username:foo", "username:bar", "selector:app:demo"})).To(Equal(prometheus.Labels{"username": "foo,bar"}))
+		})
+	})
+})
@@ -12,6 +12,9 @@ const (
 	// SinkDriverDatadog is the Datadog driver
 	SinkDriverDatadog SinkDriver = "datadog"
 
+	// SinkDriverPrometheus is the Prometheus driver
+	SinkDriverPrometheus SinkDriver = "prometheus"
+
 	// SinkDriverNoop is a noop driver mainly used for testing
 	SinkDriverNoop SinkDriver = "noop"
 )
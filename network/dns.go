@@ -58,40 +58,54 @@ func (c dnsClient) Resolve(host string) ([]net.IP, error) {
 	names := append([]string{}, podDNSConfig.NameList(host)...)
 	names = append(names, nodeDNSConfig.NameList(host)...)
 
-	// do the request on the first configured dns resolver
+	// do the requests on the first configured dns resolver, looking for both IPv4 (A) and IPv6 (AAAA) records
+	// a record type failing to be resolved is not an error as long as the other one is
 	dnsClient := dns.Client{}
-	response := &dns.Msg{}
-
-	err = retry.Do(func() error {
-		// query possible resolvers and fqdn based on servers and search domains specified in the dns configuration
-		for _, name := range names {
-			dnsMessage := dns.Msg{}
-			dnsMessage.SetQuestion(name, dns.TypeA)
-
-			for _, server := range resolvers {
-				response, _, err = dnsClient.Exchange(&dnsMessage, fmt.Sprintf("%s:53", server))
-				if response != nil && len(response.Answer) > 0 {
-					return nil
+	var resolveErr error
+
+	for _, recordType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		response := &dns.Msg{}
+
+		err = retry.Do(func() error {
+			// query possible resolvers and fqdn based on servers and search domains specified in the dns configuration
+			for _, name := range names {
+				dnsMessage := dns.Msg{}
+				dnsMessage.SetQuestion(name, recordType)
+
+				for _, server := range resolvers {
+					response, _, err = dnsClient.Exchange(&dnsMessage, net.JoinHostPort(server, "53"))
+					if response != nil && len(response.Answer) > 0 {
+						return nil
+					}
 				}
 			}
+
+			return err
+		}, retry.Attempts(3))
+		if err != nil {
+			resolveErr = err
+
+			continue
 		}
 
-		return err
-	}, retry.Attempts(3))
-	if err != nil {
-		return nil, fmt.Errorf("can't resolve the given hostname %s: %w", host, err)
+		// parse returned records
+		for _, answer := range response.Answer {
+			switch record := answer.(type) {
+			case *dns.A:
+				ips = append(ips, record.A)
+			case *dns.AAAA:
+				ips = append(ips, record.AAAA)
+			}
+		}
 	}
 
-	// parse returned records
-	for _, answer := range response.Answer {
-		if ip, ok := answer.(*dns.A); ok {
-			ips = append(ips, ip.A)
-		}
+	if len(ips) == 0 && resolveErr != nil {
+		return nil, fmt.Errorf("can't resolve the given hostname %s: %w", host, resolveErr)
 	}
 
-	// error if no A records can be found
+	// error if no A or AAAA records can be found
 	if len(ips) == 0 {
-		return nil, fmt.Errorf("no A records were found for the given hostname %s", host)
+		return nil, fmt.Errorf("no A or AAAA records were found for the given hostname %s", host)
 	}
 
 	return ips, nil
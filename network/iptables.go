@@ -6,7 +6,7 @@
 package network
 
 import (
-	"fmt"
+	"net"
 
 	goiptables "github.com/coreos/go-iptables/iptables"
 	"go.uber.org/zap"
@@ -18,10 +18,20 @@ type Iptables interface {
 	ClearAndDeleteChain(name string) error
 	AddRuleWithIP(chain string, protocol string, port string, jump string, destinationIP string) error
 	AddWideFilterRule(chain string, protocol string, port string, jump string) error
+	AddRedirectRule(chain string, protocol string, port string, toPort string) error
 	AddCgroupFilterRule(chain string, cgroupid string, protocol string, port string, jump string) error
 	PrependRule(chain string, rulespec ...string) error
 	DeleteRule(chain string, protocol string, port string, jump string) error
 	DeleteCgroupFilterRule(chain string, cgroupid string, protocol string, port string, jump string) error
+	AddCgroupPathFilterRule(chain string, cgroupPath string, protocol string, port string, jump string) error
+	DeleteCgroupPathFilterRule(chain string, cgroupPath string, protocol string, port string, jump string) error
+	AddCgroupPathClassifyRule(cgroupPath string, class string) error
+	DeleteCgroupPathClassifyRule(cgroupPath string, class string) error
+	CreateFilterChain(name string) error
+	ClearAndDeleteFilterChain(name string) error
+	AppendFilterRule(chain string, rulespec ...string) error
+	AddFilterJumpRule(chain string, jump string, rulespec ...string) error
+	DeleteFilterJumpRule(chain string, jump string, rulespec ...string) error
 }
 
 type iptables struct {
@@ -41,6 +51,17 @@ func NewIptables(log *zap.SugaredLogger, dryRun bool) (Iptables, error) {
 	}, err
 }
 
+// NewIp6tables returns an implementation of the Iptables interface managing the IPv6 rules through ip6tables
+func NewIp6tables(log *zap.SugaredLogger, dryRun bool) (Iptables, error) {
+	ip, err := goiptables.NewWithProtocol(goiptables.ProtocolIPv6)
+
+	return iptables{
+		log:    log,
+		dryRun: dryRun,
+		ip:     ip,
+	}, err
+}
+
 func (i iptables) CreateChain(name string) error {
 	if i.dryRun {
 		return nil
@@ -72,7 +93,7 @@ func (i iptables) AddRuleWithIP(chain string, protocol string, port string, jump
 
 	i.log.Infow("creating new iptables rule", "chain name", chain, "protocol", protocol, "port", port, "jump target", jump, "destination", destinationIP)
 
-	return i.ip.AppendUnique("nat", chain, "-p", protocol, "--dport", port, "-j", jump, "--to-destination", fmt.Sprintf("%s:%s", destinationIP, port))
+	return i.ip.AppendUnique("nat", chain, "-p", protocol, "--dport", port, "-j", jump, "--to-destination", net.JoinHostPort(destinationIP, port))
 }
 
 func (i iptables) PrependRule(chain string, rulespec ...string) error {
@@ -108,6 +129,17 @@ func (i iptables) AddWideFilterRule(chain string, protocol string, port string,
 	return i.ip.AppendUnique("nat", chain, "-p", protocol, "--dport", port, "-j", jump)
 }
 
+// AddRedirectRule redirects the packets matching the given protocol and destination port to the given local port
+func (i iptables) AddRedirectRule(chain string, protocol string, port string, toPort string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("creating new iptables rule", "chain name", chain, "protocol", protocol, "port", port, "jump target", "REDIRECT", "redirection port", toPort)
+
+	return i.ip.AppendUnique("nat", chain, "-p", protocol, "--dport", port, "-j", "REDIRECT", "--to-ports", toPort)
+}
+
 func (i iptables) DeleteRule(chain string, protocol string, port string, jump string) error {
 	if i.dryRun {
 		return nil
@@ -149,3 +181,123 @@ func (i iptables) DeleteCgroupFilterRule(chain string, cgroupid string, protocol
 
 	return i.ip.DeleteIfExists("nat", chain, "-m", "cgroup", "--cgroup", cgroupid, "-p", protocol, "--dport", port, "-j", jump)
 }
+
+// Add a rule with cgroup v2 path filter
+func (i iptables) AddCgroupPathFilterRule(chain string, cgroupPath string, protocol string, port string, jump string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("creating new iptables rule", "chain name", chain, "cgroup path", cgroupPath,
+		"protocol", protocol, "port", port, "jump target", jump)
+
+	return i.ip.AppendUnique("nat", chain, "-m", "cgroup", "--path", cgroupPath, "-p", protocol, "--dport", port, "-j", jump)
+}
+
+// Delete a rule with cgroup v2 path filter
+func (i iptables) DeleteCgroupPathFilterRule(chain string, cgroupPath string, protocol string, port string, jump string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("deleting iptables rule", "chain name", chain, "cgroup path", cgroupPath, "protocol", protocol, "port", port, "jump target", jump)
+
+	if exists, _ := i.ip.ChainExists("nat", chain); !exists {
+		return nil
+	}
+
+	if exists, _ := i.ip.ChainExists("nat", jump); !exists {
+		return nil
+	}
+
+	return i.ip.DeleteIfExists("nat", chain, "-m", "cgroup", "--path", cgroupPath, "-p", protocol, "--dport", port, "-j", jump)
+}
+
+// AddCgroupPathClassifyRule sets the given tc class to all the packets sent by processes of the given cgroup v2 path
+// it replaces the net_cls cgroup classid which does not exist in cgroup v2
+func (i iptables) AddCgroupPathClassifyRule(cgroupPath string, class string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("creating new iptables rule", "table", "mangle", "chain name", "POSTROUTING", "cgroup path", cgroupPath, "class", class)
+
+	return i.ip.AppendUnique("mangle", "POSTROUTING", "-m", "cgroup", "--path", cgroupPath, "-j", "CLASSIFY", "--set-class", class)
+}
+
+// DeleteCgroupPathClassifyRule deletes a rule created by AddCgroupPathClassifyRule
+func (i iptables) DeleteCgroupPathClassifyRule(cgroupPath string, class string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("deleting iptables rule", "table", "mangle", "chain name", "POSTROUTING", "cgroup path", cgroupPath, "class", class)
+
+	return i.ip.DeleteIfExists("mangle", "POSTROUTING", "-m", "cgroup", "--path", cgroupPath, "-j", "CLASSIFY", "--set-class", class)
+}
+
+// CreateFilterChain creates the given chain in the filter table, where packets can be rejected
+func (i iptables) CreateFilterChain(name string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	if res, _ := i.ip.ChainExists("filter", name); res {
+		return nil
+	}
+
+	i.log.Infow("creating new iptables chain", "table", "filter", "chain name", name)
+
+	return i.ip.NewChain("filter", name)
+}
+
+// ClearAndDeleteFilterChain deletes the given chain of the filter table along with its rules
+func (i iptables) ClearAndDeleteFilterChain(name string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("deleting iptables chain", "table", "filter", "chain name", name)
+
+	return i.ip.ClearAndDeleteChain("filter", name)
+}
+
+// AppendFilterRule appends the given rule to the given chain of the filter table
+func (i iptables) AppendFilterRule(chain string, rulespec ...string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("creating new iptables rule", "table", "filter", "chain name", chain, "rulespec", rulespec)
+
+	return i.ip.AppendUnique("filter", chain, rulespec...)
+}
+
+// AddFilterJumpRule inserts a rule jumping to the given chain for the packets matching the given rulespec
+// at the top of the given chain of the filter table, so it is used before any existing rule
+func (i iptables) AddFilterJumpRule(chain string, jump string, rulespec ...string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("creating new iptables rule", "table", "filter", "chain name", chain, "rulespec", rulespec, "jump target", jump)
+
+	// 1 is the first position, not 0
+	return i.ip.Insert("filter", chain, 1, append(append([]string{}, rulespec...), "-j", jump)...)
+}
+
+// DeleteFilterJumpRule deletes a rule created by AddFilterJumpRule
+func (i iptables) DeleteFilterJumpRule(chain string, jump string, rulespec ...string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	i.log.Infow("deleting iptables rule", "table", "filter", "chain name", chain, "rulespec", rulespec, "jump target", jump)
+
+	// checking a rule jumping to a chain which does not exist fails, and no rule can jump to it in this case
+	if exists, _ := i.ip.ChainExists("filter", jump); !exists {
+		return nil
+	}
+
+	return i.ip.DeleteIfExists("filter", chain, append(append([]string{}, rulespec...), "-j", jump)...)
+}
@@ -54,6 +54,13 @@ func (f *IptablesMock) AddWideFilterRule(chain string, protocol string, port str
 	return args.Error(0)
 }
 
+//nolint:golint
+func (f *IptablesMock) AddRedirectRule(chain string, protocol string, port string, toPort string) error {
+	args := f.Called(chain, protocol, port, toPort)
+
+	return args.Error(0)
+}
+
 //nolint:golint
 func (f *IptablesMock) AddCgroupFilterRule(chain string, cgroupid string, protocol string, port string, jump string) error {
 	args := f.Called(chain, cgroupid, protocol, port, jump)
@@ -67,3 +74,66 @@ func (f *IptablesMock) DeleteCgroupFilterRule(chain string, cgroupid string, pro
 
 	return args.Error(0)
 }
+
+//nolint:golint
+func (f *IptablesMock) AddCgroupPathFilterRule(chain string, cgroupPath string, protocol string, port string, jump string) error {
+	args := f.Called(chain, cgroupPath, protocol, port, jump)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) DeleteCgroupPathFilterRule(chain string, cgroupPath string, protocol string, port string, jump string) error {
+	args := f.Called(chain, cgroupPath, protocol, port, jump)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) AddCgroupPathClassifyRule(cgroupPath string, class string) error {
+	args := f.Called(cgroupPath, class)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) DeleteCgroupPathClassifyRule(cgroupPath string, class string) error {
+	args := f.Called(cgroupPath, class)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) CreateFilterChain(name string) error {
+	args := f.Called(name)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) ClearAndDeleteFilterChain(name string) error {
+	args := f.Called(name)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) AppendFilterRule(chain string, rulespec ...string) error {
+	args := f.Called(chain, rulespec)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) AddFilterJumpRule(chain string, jump string, rulespec ...string) error {
+	args := f.Called(chain, jump, rulespec)
+
+	return args.Error(0)
+}
+
+//nolint:golint
+func (f *IptablesMock) DeleteFilterJumpRule(chain string, jump string, rulespec ...string) error {
+	args := f.Called(chain, jump, rulespec)
+
+	return args.Error(0)
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package network
+
+import "time"
+
+// netem delay distributions, each of them matching a distribution table shipped with tc
+const (
+	NetemDistributionNormal       = "normal"
+	NetemDistributionPareto       = "pareto"
+	NetemDistributionParetoNormal = "paretonormal"
+)
+
+// NetemParams describes the disruptions applied by a netem qdisc, all percentages being between 0 and 100
+type NetemParams struct {
+	Delay                time.Duration
+	DelayJitter          time.Duration
+	DelayCorrelation     int    // percentage of dependency of a delay on the previous one
+	DelayDistribution    string // distribution of the jittered delays, normal if empty
+	Drop                 int
+	DropCorrelation      int
+	GilbertElliott       *GilbertElliottLoss // loss model, replacing drop
+	FourStateLoss        *FourStateLoss      // loss model, replacing drop
+	Duplicate            int
+	DuplicateCorrelation int
+	Corrupt              int
+	CorruptCorrelation   int
+	Reorder              int // percentage of packets sent immediately instead of being delayed, requires a delay
+	ReorderCorrelation   int
+	ReorderGap           uint // reorder one packet out of the given number of packets only, 1 if 0
+	Slot                 *NetemSlot
+}
+
+// GilbertElliottLoss describes a Gilbert-Elliott loss model: a packet moves the model from a good state to a bad state
+// (and the other way around) with a given probability, each state losing packets with its own probability
+type GilbertElliottLoss struct {
+	P             int // probability to move from the good state to the bad state
+	R             int // probability to move from the bad state to the good state
+	BadStateLoss  int // loss probability in the bad state (1-h)
+	GoodStateLoss int // loss probability in the good state (1-k)
+}
+
+// FourStateLoss describes a 4-state Markov loss model, states being 1 (packets received in a gap period),
+// 2 (packets received in a burst period), 3 (packets lost in a burst period) and 4 (isolated packets lost in a gap period),
+// Pij being the probability to move from the state i to the state j
+type FourStateLoss struct {
+	P13 int
+	P31 int
+	P32 int
+	P23 int
+	P14 int
+}
+
+// NetemSlot describes slots packets are sent in instead of being sent right away, emulating links
+// sending packets in bursts such as wifi or cellular links
+type NetemSlot struct {
+	MinDelay time.Duration // minimum delay between two slots
+	MaxDelay time.Duration // maximum delay between two slots, the delay being picked randomly between the minimum and the maximum
+	Packets  int           // maximum number of packets sent per slot, unlimited if 0
+	Bytes    int           // maximum number of bytes sent per slot, unlimited if 0
+}
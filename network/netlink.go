@@ -6,6 +6,7 @@
 package network
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -21,6 +22,8 @@ type NetlinkAdapter interface {
 	LinkByIndex(index int) (NetlinkLink, error)
 	LinkByName(name string) (NetlinkLink, error)
 	DefaultRoutes() ([]NetlinkRoute, error)
+	LinkAddIFB(name string) (NetlinkLink, error)
+	LinkDelete(name string) error
 }
 
 type netlinkAdapter struct{}
@@ -34,33 +37,38 @@ func (a netlinkAdapter) listRoutes() ([]netlink.Route, error) {
 		return nil, err
 	}
 
-	// list routing rules for IPv4
-	rules, err := handler.RuleList(unix.AF_INET)
-	if err != nil {
-		return nil, err
-	}
+	// list routes of both IPv4 and IPv6
+	for _, family := range []int{unix.AF_INET, unix.AF_INET6} {
+		// list routing rules, IPv6 being possibly disabled on the host
+		rules, err := handler.RuleList(family)
+		if family == unix.AF_INET6 && errors.Is(err, unix.EAFNOSUPPORT) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
 
-	// get routing tables identifiers from rules so we
-	// are able to list all the existing routing tables
-	tables := map[int]struct{}{}
+		// get routing tables identifiers from rules so we
+		// are able to list all the existing routing tables
+		tables := map[int]struct{}{}
 
-	for _, rule := range rules {
-		if _, found := tables[rule.Table]; !found {
-			tables[rule.Table] = struct{}{}
+		for _, rule := range rules {
+			if _, found := tables[rule.Table]; !found {
+				tables[rule.Table] = struct{}{}
+			}
 		}
-	}
 
-	// get all the existing routing tables routes
-	for table := range tables {
-		// NOTE: we are using a magic number here (1024, which comes from the netlink library constants) for MacOS build compatibility
-		// netlink.RT_FILTER_TABLE == 1024
-		// https://github.com/vishvananda/netlink/blob/v1.1.0/route_linux.go#L34
-		routes, err := handler.RouteListFiltered(unix.AF_INET, &netlink.Route{Table: table}, 1024)
-		if err != nil {
-			return nil, err
-		}
+		// get all the existing routing tables routes
+		for table := range tables {
+			// NOTE: we are using a magic number here (1024, which comes from the netlink library constants) for MacOS build compatibility
+			// netlink.RT_FILTER_TABLE == 1024
+			// https://github.com/vishvananda/netlink/blob/v1.1.0/route_linux.go#L34
+			routes, err := handler.RouteListFiltered(family, &netlink.Route{Table: table}, 1024)
+			if err != nil {
+				return nil, err
+			}
 
-		allRoutes = append(allRoutes, routes...)
+			allRoutes = append(allRoutes, routes...)
+		}
 	}
 
 	return allRoutes, nil
@@ -71,7 +79,7 @@ func NewNetlinkAdapter() NetlinkAdapter {
 	return netlinkAdapter{}
 }
 
-// LinkList lists links used in the routing tables for IPv4 only
+// LinkList lists the local ethernet links
 func (a netlinkAdapter) LinkList() ([]NetlinkLink, error) {
 	// retrieve links from indexes and cast them
 	links, err := netlink.LinkList()
@@ -117,6 +125,40 @@ func (a netlinkAdapter) LinkByName(name string) (NetlinkLink, error) {
 	return newNetlinkLink(link), nil
 }
 
+// LinkAddIFB creates an IFB (intermediate functional block) device with the given name and sets it up,
+// packets redirected to it go through its qdiscs before being sent back to the interface they come from
+func (a netlinkAdapter) LinkAddIFB(name string) (NetlinkLink, error) {
+	ifb := &netlink.Ifb{
+		LinkAttrs: netlink.NewLinkAttrs(),
+	}
+	ifb.Name = name
+
+	if err := netlink.LinkAdd(ifb); err != nil {
+		return nil, fmt.Errorf("error creating the %s IFB device: %w", name, err)
+	}
+
+	if err := netlink.LinkSetUp(ifb); err != nil {
+		return nil, fmt.Errorf("error setting up the %s IFB device: %w", name, err)
+	}
+
+	return a.LinkByName(name)
+}
+
+// LinkDelete deletes the link with the given name, if it still exists
+func (a netlinkAdapter) LinkDelete(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	return netlink.LinkDel(link)
+}
+
 func (a netlinkAdapter) DefaultRoutes() ([]NetlinkRoute, error) {
 	defaultRoutes := []NetlinkRoute{}
 
@@ -44,6 +44,20 @@ func (f *NetlinkAdapterMock) DefaultRoutes() ([]NetlinkRoute, error) {
 	return args.Get(0).([]NetlinkRoute), args.Error(1)
 }
 
+//nolint:golint
+func (f *NetlinkAdapterMock) LinkAddIFB(name string) (NetlinkLink, error) {
+	args := f.Called(name)
+
+	return args.Get(0).(NetlinkLink), args.Error(1)
+}
+
+//nolint:golint
+func (f *NetlinkAdapterMock) LinkDelete(name string) error {
+	args := f.Called(name)
+
+	return args.Error(0)
+}
+
 // NetlinkLinkMock is a mock implementation of the NetlinkLink interface
 type NetlinkLinkMock struct {
 	mock.Mock
@@ -12,7 +12,6 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
-	"time"
 
 	"go.uber.org/zap"
 )
@@ -28,16 +27,38 @@ const (
 
 type protocolIdentifier int
 
+// ipFamily is the IP family of the packets a filter applies to
+type ipFamily int
+
+const (
+	// ipFamilyAll filters don't match any IP and apply to all packets, matching IPv4 headers fields
+	ipFamilyAll ipFamily = iota
+	ipFamilyV4
+	ipFamilyV6
+)
+
+// TrafficControllerDriver is the implementation a traffic controller uses to interact with the host queueing discipline
+type TrafficControllerDriver string
+
+const (
+	// TrafficControllerDriverTc executes the tc command for every qdisc and filter
+	TrafficControllerDriverTc TrafficControllerDriver = "tc"
+	// TrafficControllerDriverNetlink sends the qdiscs and filters to the kernel through netlink
+	TrafficControllerDriverNetlink TrafficControllerDriver = "netlink"
+)
+
 // TrafficController is an interface being able to interact with the host
 // queueing discipline
 type TrafficController interface {
-	AddNetem(ifaces []string, parent string, handle uint32, delay time.Duration, delayJitter time.Duration, drop int, corrupt int, duplicate int) error
+	AddNetem(ifaces []string, parent string, handle uint32, netem NetemParams) error
 	AddPrio(ifaces []string, parent string, handle uint32, bands uint32, priomap [16]uint32) error
 	AddFilter(ifaces []string, parent string, priority uint32, handle uint32, srcIP, dstIP *net.IPNet, srcPort, dstPort int, protocol string, flowid string) error
-	DeleteFilter(iface string, priority uint32) error
+	DeleteFilter(iface string, parent string, priority uint32) error
 	AddCgroupFilter(ifaces []string, parent string, handle uint32) error
 	AddOutputLimit(ifaces []string, parent string, handle uint32, bytesPerSec uint) error
+	AddIngressRedirect(ifaces []string, target string) error
 	ClearQdisc(ifaces []string) error
+	ClearIngressQdisc(ifaces []string) error
 }
 
 type tcExecuter interface {
@@ -91,23 +112,79 @@ func NewTrafficController(log *zap.SugaredLogger, dryRun bool) TrafficController
 	}
 }
 
-func (t tc) AddNetem(ifaces []string, parent string, handle uint32, delay time.Duration, delayJitter time.Duration, drop int, corrupt int, duplicate int) error {
+// NewTrafficControllerWithDriver creates a traffic controller using the given driver
+func NewTrafficControllerWithDriver(driver TrafficControllerDriver, log *zap.SugaredLogger, dryRun bool) (TrafficController, error) {
+	switch driver {
+	case TrafficControllerDriverTc:
+		return NewTrafficController(log, dryRun), nil
+	case TrafficControllerDriverNetlink:
+		return NewNetlinkTrafficController(log, dryRun), nil
+	default:
+		return nil, fmt.Errorf("unsupported traffic controller driver: %s", driver)
+	}
+}
+
+func (t tc) AddNetem(ifaces []string, parent string, handle uint32, netem NetemParams) error {
 	params := ""
 
-	if delay.Milliseconds() != 0 {
-		params = fmt.Sprintf("%s delay %dms %dms distribution normal", params, delay.Milliseconds(), delayJitter.Milliseconds())
+	if netem.Delay.Milliseconds() != 0 {
+		params = fmt.Sprintf("%s delay %dms %dms", params, netem.Delay.Milliseconds(), netem.DelayJitter.Milliseconds())
+
+		if netem.DelayCorrelation != 0 {
+			params = fmt.Sprintf("%s %d%%", params, netem.DelayCorrelation)
+		}
+
+		distribution := netem.DelayDistribution
+		if distribution == "" {
+			distribution = NetemDistributionNormal
+		}
+
+		params = fmt.Sprintf("%s distribution %s", params, distribution)
+	}
+
+	switch {
+	case netem.GilbertElliott != nil:
+		// tc names the third parameter 1-h but sends it as is as h, the probability for a packet not to be lost in the bad state
+		model := netem.GilbertElliott
+		params = fmt.Sprintf("%s loss gemodel %d%% %d%% %d%% %d%%", params, model.P, model.R, 100-model.BadStateLoss, model.GoodStateLoss)
+	case netem.FourStateLoss != nil:
+		model := netem.FourStateLoss
+		params = fmt.Sprintf("%s loss state %d%% %d%% %d%% %d%% %d%%", params, model.P13, model.P31, model.P32, model.P23, model.P14)
+	case netem.Drop != 0:
+		params = fmt.Sprintf("%s loss %d%%%s", params, netem.Drop, correlationParam(netem.DropCorrelation))
+	}
+
+	if netem.Duplicate != 0 {
+		params = fmt.Sprintf("%s duplicate %d%%%s", params, netem.Duplicate, correlationParam(netem.DuplicateCorrelation))
 	}
 
-	if drop != 0 {
-		params = fmt.Sprintf("%s loss %d%%", params, drop)
+	if netem.Corrupt != 0 {
+		params = fmt.Sprintf("%s corrupt %d%%%s", params, netem.Corrupt, correlationParam(netem.CorruptCorrelation))
 	}
 
-	if duplicate != 0 {
-		params = fmt.Sprintf("%s duplicate %d%%", params, duplicate)
+	if netem.Reorder != 0 {
+		params = fmt.Sprintf("%s reorder %d%%%s", params, netem.Reorder, correlationParam(netem.ReorderCorrelation))
+
+		if netem.ReorderGap != 0 {
+			params = fmt.Sprintf("%s gap %d", params, netem.ReorderGap)
+		}
 	}
 
-	if corrupt != 0 {
-		params = fmt.Sprintf("%s corrupt %d%%", params, corrupt)
+	if netem.Slot != nil {
+		maxDelay := netem.Slot.MaxDelay
+		if maxDelay < netem.Slot.MinDelay {
+			maxDelay = netem.Slot.MinDelay
+		}
+
+		params = fmt.Sprintf("%s slot %dus %dus", params, netem.Slot.MinDelay.Microseconds(), maxDelay.Microseconds())
+
+		if netem.Slot.Packets != 0 {
+			params = fmt.Sprintf("%s packets %d", params, netem.Slot.Packets)
+		}
+
+		if netem.Slot.Bytes != 0 {
+			params = fmt.Sprintf("%s bytes %d", params, netem.Slot.Bytes)
+		}
 	}
 
 	params = strings.TrimPrefix(params, " ")
@@ -121,6 +198,15 @@ func (t tc) AddNetem(ifaces []string, parent string, handle uint32, delay time.D
 	return nil
 }
 
+// correlationParam returns the optional correlation parameter following a netem percentage
+func correlationParam(correlation int) string {
+	if correlation == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" %d%%", correlation)
+}
+
 func (t tc) AddPrio(ifaces []string, parent string, handle uint32, bands uint32, priomap [16]uint32) error {
 	priomapStr := ""
 	for _, bit := range priomap {
@@ -166,6 +252,33 @@ func (t tc) ClearQdisc(ifaces []string) error {
 	return nil
 }
 
+// AddIngressRedirect adds an ingress qdisc to the given interfaces with a filter redirecting all the incoming packets
+// to the egress of the target interface, so they go through its qdiscs
+func (t tc) AddIngressRedirect(ifaces []string, target string) error {
+	for _, iface := range ifaces {
+		if _, _, err := t.executer.Run(strings.Split(fmt.Sprintf("qdisc add dev %s ingress", iface), " ")...); err != nil {
+			return err
+		}
+
+		if _, _, err := t.executer.Run(buildCmd("filter", iface, "ffff:", 0, 0, "u32", fmt.Sprintf("match u32 0 0 action mirred egress redirect dev %s", target))...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t tc) ClearIngressQdisc(ifaces []string) error {
+	for _, iface := range ifaces {
+		// tc exits with code 2 when the qdisc does not exist anymore
+		if exitCode, _, err := t.executer.Run(strings.Split(fmt.Sprintf("qdisc del dev %s ingress", iface), " ")...); err != nil && exitCode != 2 {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // AddFilter generates a filter to redirect the traffic matching the given ip, port and protocol to the given flowid
 func (t tc) AddFilter(ifaces []string, parent string, priority uint32, handle uint32, srcIP, dstIP *net.IPNet, srcPort, dstPort int, protocol string, flowid string) error {
 	var params string
@@ -175,33 +288,48 @@ func (t tc) AddFilter(ifaces []string, parent string, priority uint32, handle ui
 		return fmt.Errorf("wrong filter, at least an IP or a port must be specified")
 	}
 
+	family, err := filterFamily(srcIP, dstIP)
+	if err != nil {
+		return err
+	}
+
+	// filters matching an IP only apply to the packets of its family, IPv6 headers fields being matched with ip6
+	kind, match := "u32", "ip"
+
+	switch family {
+	case ipFamilyV4:
+		kind = "protocol ip u32"
+	case ipFamilyV6:
+		kind, match = "protocol ipv6 u32", "ip6"
+	}
+
 	// match ip if specified
 	if srcIP != nil {
-		params += fmt.Sprintf("match ip src %s ", srcIP.String())
+		params += fmt.Sprintf("match %s src %s ", match, srcIP.String())
 	}
 
 	if dstIP != nil {
-		params += fmt.Sprintf("match ip dst %s ", dstIP.String())
+		params += fmt.Sprintf("match %s dst %s ", match, dstIP.String())
 	}
 
 	// match port if specified
 	if srcPort != 0 {
-		params += fmt.Sprintf("match ip sport %s 0xffff ", strconv.Itoa(srcPort))
+		params += fmt.Sprintf("match %s sport %s 0xffff ", match, strconv.Itoa(srcPort))
 	}
 
 	if dstPort != 0 {
-		params += fmt.Sprintf("match ip dport %s 0xffff ", strconv.Itoa(dstPort))
+		params += fmt.Sprintf("match %s dport %s 0xffff ", match, strconv.Itoa(dstPort))
 	}
 
 	// match protocol if specified
 	if protocol != "" {
-		params += fmt.Sprintf("match ip protocol %d 0xff ", getProtocolIndentifier(protocol))
+		params += fmt.Sprintf("match %s protocol %d 0xff ", match, getProtocolIndentifier(protocol))
 	}
 
 	params += fmt.Sprintf("flowid %s", flowid)
 
 	for _, iface := range ifaces {
-		if _, _, err := t.executer.Run(buildCmd("filter", iface, parent, priority, handle, "u32", params)...); err != nil {
+		if _, _, err := t.executer.Run(buildCmd("filter", iface, parent, priority, handle, kind, params)...); err != nil {
 			return err
 		}
 	}
@@ -209,8 +337,8 @@ func (t tc) AddFilter(ifaces []string, parent string, priority uint32, handle ui
 	return nil
 }
 
-func (t tc) DeleteFilter(iface string, priority uint32) error {
-	if _, _, err := t.executer.Run("filter", "delete", "dev", iface, "priority", fmt.Sprintf("%d", priority)); err != nil {
+func (t tc) DeleteFilter(iface string, parent string, priority uint32) error {
+	if _, _, err := t.executer.Run("filter", "delete", "dev", iface, "parent", parent, "priority", fmt.Sprintf("%d", priority)); err != nil {
 		return err
 	}
 
@@ -228,6 +356,30 @@ func (t tc) AddCgroupFilter(ifaces []string, parent string, handle uint32) error
 	return nil
 }
 
+// filterFamily returns the IP family of a filter matching the given networks, which must be of the same family
+func filterFamily(srcIP, dstIP *net.IPNet) (ipFamily, error) {
+	family := ipFamilyAll
+
+	for _, ipNet := range []*net.IPNet{srcIP, dstIP} {
+		if ipNet == nil {
+			continue
+		}
+
+		ipNetFamily := ipFamilyV6
+		if ipNet.IP.To4() != nil {
+			ipNetFamily = ipFamilyV4
+		}
+
+		if family != ipFamilyAll && family != ipNetFamily {
+			return ipFamilyAll, fmt.Errorf("wrong filter, %s and %s are not of the same IP family", srcIP, dstIP)
+		}
+
+		family = ipNetFamily
+	}
+
+	return family, nil
+}
+
 func getProtocolIndentifier(protocol string) protocolIdentifier {
 	switch strings.ToLower(protocol) {
 	case "tcp":
@@ -7,7 +7,6 @@ package network
 
 import (
 	"net"
-	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -18,8 +17,8 @@ type TcMock struct {
 }
 
 //nolint:golint
-func (f *TcMock) AddNetem(ifaces []string, parent string, handle uint32, delay time.Duration, delayJitter time.Duration, drop int, corrupt int, duplicate int) error {
-	args := f.Called(ifaces, parent, handle, delay, delayJitter, drop, corrupt, duplicate)
+func (f *TcMock) AddNetem(ifaces []string, parent string, handle uint32, netem NetemParams) error {
+	args := f.Called(ifaces, parent, handle, netem)
 
 	return args.Error(0)
 }
@@ -63,6 +62,13 @@ func (f *TcMock) AddOutputLimit(ifaces []string, parent string, handle uint32, b
 	return args.Error(0)
 }
 
+//nolint:golint
+func (f *TcMock) AddIngressRedirect(ifaces []string, target string) error {
+	args := f.Called(ifaces, target)
+
+	return args.Error(0)
+}
+
 //nolint:golint
 func (f *TcMock) ClearQdisc(ifaces []string) error {
 	args := f.Called(ifaces)
@@ -70,8 +76,15 @@ func (f *TcMock) ClearQdisc(ifaces []string) error {
 	return args.Error(0)
 }
 
-func (f *TcMock) DeleteFilter(iface string, priority uint32) error {
-	args := f.Called(iface, priority)
+//nolint:golint
+func (f *TcMock) ClearIngressQdisc(ifaces []string) error {
+	args := f.Called(ifaces)
+
+	return args.Error(0)
+}
+
+func (f *TcMock) DeleteFilter(iface string, parent string, priority uint32) error {
+	args := f.Called(iface, parent, priority)
 
 	return args.Error(0)
 }
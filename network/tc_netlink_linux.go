@@ -0,0 +1,727 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+// tbfLatency is the max length of time a packet can sit in the tbf queue before being sent
+const tbfLatency = 50 * time.Millisecond
+
+// netem attributes and loss models missing from the netlink library
+const (
+	tcaNetemSlot = 12 // TCA_NETEM_SLOT
+	netemLossGI  = 1  // NETEM_LOSS_GI, 4-state loss model
+	netemLossGE  = 2  // NETEM_LOSS_GE, Gilbert-Elliott loss model
+)
+
+// u32Offsets are the offsets of the IP header fields u32 filters match, along with the position of the protocol
+// byte in its 32 bits word
+type u32Offsets struct {
+	protocol      int32
+	protocolShift uint32
+	srcIP         int32
+	dstIP         int32
+	ports         int32
+}
+
+// u32 match offsets of the IPv4 and IPv6 (without extension headers) header fields
+var (
+	u32OffsetsV4 = u32Offsets{protocol: 8, protocolShift: 16, srcIP: 12, dstIP: 16, ports: 20}
+	u32OffsetsV6 = u32Offsets{protocol: 4, protocolShift: 8, srcIP: 8, dstIP: 24, ports: 40}
+)
+
+type netlinkTc struct {
+	log    *zap.SugaredLogger
+	dryRun bool
+}
+
+// NewNetlinkTrafficController creates a traffic controller sending the qdiscs and filters
+// to the kernel through netlink instead of executing tc, with the same semantics
+func NewNetlinkTrafficController(log *zap.SugaredLogger, dryRun bool) TrafficController {
+	return netlinkTc{
+		log:    log,
+		dryRun: dryRun,
+	}
+}
+
+func (t netlinkTc) AddNetem(ifaces []string, parent string, handle uint32, netem NetemParams) error {
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return err
+	}
+
+	attrs := netlink.NetemQdiscAttrs{
+		Duplicate:     float32(netem.Duplicate),
+		DuplicateCorr: float32(netem.DuplicateCorrelation),
+		CorruptProb:   float32(netem.Corrupt),
+		CorruptCorr:   float32(netem.CorruptCorrelation),
+		ReorderProb:   float32(netem.Reorder),
+		ReorderCorr:   float32(netem.ReorderCorrelation),
+		Gap:           uint32(netem.ReorderGap),
+	}
+
+	// loss models replace the random loss
+	if netem.GilbertElliott == nil && netem.FourStateLoss == nil {
+		attrs.Loss = float32(netem.Drop)
+		attrs.LossCorr = float32(netem.DropCorrelation)
+	}
+
+	// delays are applied with a millisecond precision, following the given distribution
+	var distribution []int16
+
+	if netem.Delay.Milliseconds() != 0 {
+		attrs.Latency = uint32(netem.Delay.Milliseconds() * 1000)
+		attrs.Jitter = uint32(netem.DelayJitter.Milliseconds() * 1000)
+		attrs.DelayCorr = float32(netem.DelayCorrelation)
+
+		if attrs.Jitter != 0 {
+			distribution, err = delayDistribution(netem.DelayDistribution)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, iface := range ifaces {
+		err := t.run(iface, fmt.Sprintf("add netem qdisc %+v", netem), func(link netlink.Link) error {
+			return addNetem(netlink.NewNetem(qdiscAttrs(link, parentHandle, handle), attrs), distribution, netem)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t netlinkTc) AddPrio(ifaces []string, parent string, handle uint32, bands uint32, priomap [16]uint32) error {
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range ifaces {
+		err := t.run(iface, fmt.Sprintf("add prio qdisc with %d bands and priomap %v", bands, priomap), func(link netlink.Link) error {
+			prio := netlink.NewPrio(qdiscAttrs(link, parentHandle, handle))
+			prio.Bands = uint8(bands)
+
+			for i, band := range priomap {
+				prio.PriorityMap[i] = uint8(band)
+			}
+
+			return netlink.QdiscAdd(prio)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t netlinkTc) AddOutputLimit(ifaces []string, parent string, handle uint32, bytesPerSec uint) error {
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return err
+	}
+
+	// tc reads the unit-less rate it is given as bits per second, the same rate is applied here
+	rate := uint64(bytesPerSec) / 8
+	if rate == 0 {
+		return fmt.Errorf("wrong output limit, the rate of %d must be at least 8", bytesPerSec)
+	}
+
+	// `burst` is the number of bytes that can be sent at unlimited speed before the rate limiting kicks in,
+	// it is set to the same value as the given rate (see tc.AddOutputLimit)
+	burst := uint32(bytesPerSec)
+
+	for _, iface := range ifaces {
+		err := t.run(iface, fmt.Sprintf("add tbf qdisc with rate %d and burst %d", rate, burst), func(link netlink.Link) error {
+			return netlink.QdiscAdd(&netlink.Tbf{
+				QdiscAttrs: qdiscAttrs(link, parentHandle, handle),
+				Rate:       rate,
+				Buffer:     netlink.Xmittime(rate, burst),
+				Limit:      uint32(float64(rate)*tbfLatency.Seconds()) + burst,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t netlinkTc) ClearQdisc(ifaces []string) error {
+	for _, iface := range ifaces {
+		err := t.run(iface, "delete root qdisc", func(link netlink.Link) error {
+			err := netlink.QdiscDel(&netlink.GenericQdisc{
+				QdiscAttrs: netlink.QdiscAttrs{
+					LinkIndex: link.Attrs().Index,
+					Parent:    netlink.HANDLE_ROOT,
+				},
+			})
+
+			// errors returned by the kernel are ignored, as with tc exiting with code 2, since the qdisc may not exist anymore
+			var errno syscall.Errno
+			if errors.As(err, &errno) {
+				return nil
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddIngressRedirect adds an ingress qdisc to the given interfaces with a filter redirecting all the incoming packets
+// to the egress of the target interface, so they go through its qdiscs
+func (t netlinkTc) AddIngressRedirect(ifaces []string, target string) error {
+	for _, iface := range ifaces {
+		err := t.run(iface, fmt.Sprintf("redirect incoming packets to %s", target), func(link netlink.Link) error {
+			targetLink, err := netlink.LinkByName(target)
+			if err != nil {
+				return fmt.Errorf("error getting the redirection target interface: %w", err)
+			}
+
+			if err := netlink.QdiscAdd(&netlink.Ingress{
+				QdiscAttrs: netlink.QdiscAttrs{
+					LinkIndex: link.Attrs().Index,
+					Parent:    netlink.HANDLE_INGRESS,
+					Handle:    netlink.MakeHandle(0xffff, 0),
+				},
+			}); err != nil {
+				return err
+			}
+
+			// a u32 filter without any key matches all packets
+			return netlink.FilterAdd(&netlink.U32{
+				FilterAttrs: netlink.FilterAttrs{
+					LinkIndex: link.Attrs().Index,
+					Parent:    netlink.MakeHandle(0xffff, 0),
+					Protocol:  unix.ETH_P_ALL,
+				},
+				Actions: []netlink.Action{
+					&netlink.MirredAction{
+						ActionAttrs: netlink.ActionAttrs{
+							Action: netlink.TC_ACT_STOLEN,
+						},
+						MirredAction: netlink.TCA_EGRESS_REDIR,
+						Ifindex:      targetLink.Attrs().Index,
+					},
+				},
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t netlinkTc) ClearIngressQdisc(ifaces []string) error {
+	for _, iface := range ifaces {
+		err := t.run(iface, "delete ingress qdisc", func(link netlink.Link) error {
+			err := netlink.QdiscDel(&netlink.Ingress{
+				QdiscAttrs: netlink.QdiscAttrs{
+					LinkIndex: link.Attrs().Index,
+					Parent:    netlink.HANDLE_INGRESS,
+				},
+			})
+
+			// errors returned by the kernel are ignored, as with tc exiting with code 2, since the qdisc may not exist anymore
+			var errno syscall.Errno
+			if errors.As(err, &errno) {
+				return nil
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddFilter generates a filter to redirect the traffic matching the given ip, port and protocol to the given flowid
+func (t netlinkTc) AddFilter(ifaces []string, parent string, priority uint32, handle uint32, srcIP, dstIP *net.IPNet, srcPort, dstPort int, protocol string, flowid string) error {
+	// ensure at least an IP or a port has been specified (otherwise the filter doesn't make sense)
+	if srcIP == nil && dstIP == nil && srcPort == 0 && dstPort == 0 && protocol == "" {
+		return fmt.Errorf("wrong filter, at least an IP or a port must be specified")
+	}
+
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return err
+	}
+
+	classID, err := parseHandle(flowid)
+	if err != nil {
+		return err
+	}
+
+	family, err := filterFamily(srcIP, dstIP)
+	if err != nil {
+		return err
+	}
+
+	// filters matching an IP only apply to the packets of its family, as tc does
+	offsets, ethProtocol := u32OffsetsV4, uint16(unix.ETH_P_ALL)
+
+	switch family {
+	case ipFamilyV4:
+		ethProtocol = unix.ETH_P_IP
+	case ipFamilyV6:
+		offsets, ethProtocol = u32OffsetsV6, unix.ETH_P_IPV6
+	}
+
+	sel := &netlink.TcU32Sel{
+		Flags: nl.TC_U32_TERMINAL,
+	}
+
+	// match ip if specified
+	if srcIP != nil {
+		if err := addIPKeys(sel, offsets.srcIP, srcIP); err != nil {
+			return err
+		}
+	}
+
+	if dstIP != nil {
+		if err := addIPKeys(sel, offsets.dstIP, dstIP); err != nil {
+			return err
+		}
+	}
+
+	// match port if specified, ports are read right after an IP header without options
+	if srcPort != 0 {
+		if err := addKey(sel, offsets.ports, uint32(srcPort)<<16, 0xffff0000); err != nil {
+			return err
+		}
+	}
+
+	if dstPort != 0 {
+		if err := addKey(sel, offsets.ports, uint32(dstPort), 0x0000ffff); err != nil {
+			return err
+		}
+	}
+
+	// match protocol if specified
+	if protocol != "" {
+		if err := addKey(sel, offsets.protocol, uint32(getProtocolIndentifier(protocol))<<offsets.protocolShift, 0xff<<offsets.protocolShift); err != nil {
+			return err
+		}
+	}
+
+	for _, iface := range ifaces {
+		err := t.run(iface, fmt.Sprintf("add u32 filter with priority %d matching %+v to flowid %s", priority, sel.Keys, flowid), func(link netlink.Link) error {
+			return netlink.FilterAdd(&netlink.U32{
+				FilterAttrs: netlink.FilterAttrs{
+					LinkIndex: link.Attrs().Index,
+					Parent:    parentHandle,
+					Priority:  uint16(priority),
+					// u32 filter handles are the id of the hash table holding them
+					Handle:   handle << 20,
+					Protocol: ethProtocol,
+				},
+				ClassId: classID,
+				Sel:     sel,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t netlinkTc) DeleteFilter(iface string, parent string, priority uint32) error {
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return err
+	}
+
+	return t.run(iface, fmt.Sprintf("delete filters of %s with priority %d", parent, priority), func(link netlink.Link) error {
+		return netlink.FilterDel(&netlink.U32{
+			FilterAttrs: netlink.FilterAttrs{
+				LinkIndex: link.Attrs().Index,
+				Parent:    parentHandle,
+				Priority:  uint16(priority),
+			},
+		})
+	})
+}
+
+// AddCgroupFilter generates a cgroup filter
+func (t netlinkTc) AddCgroupFilter(ifaces []string, parent string, handle uint32) error {
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return err
+	}
+
+	for _, iface := range ifaces {
+		err := t.run(iface, "add cgroup filter", func(link netlink.Link) error {
+			return netlink.FilterAdd(&netlink.GenericFilter{
+				FilterAttrs: netlink.FilterAttrs{
+					LinkIndex: link.Attrs().Index,
+					Parent:    parentHandle,
+					Handle:    handle,
+					Protocol:  unix.ETH_P_ALL,
+				},
+				FilterType: "cgroup",
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run executes the given operation on the given interface, unless the dry-run mode is enabled,
+// and returns an error describing the failed operation if any
+func (t netlinkTc) run(iface string, description string, operation func(link netlink.Link) error) error {
+	t.log.Debugf("running netlink tc operation on %s: %s", iface, description)
+
+	// early exit if dry-run mode is enabled
+	if t.dryRun {
+		return nil
+	}
+
+	link, err := netlink.LinkByName(iface)
+	if err != nil {
+		return fmt.Errorf("error getting interface %s: %w", iface, err)
+	}
+
+	if err := operation(link); err != nil {
+		return fmt.Errorf("encountered error (%w) running operation on %s: %s", err, iface, description)
+	}
+
+	return nil
+}
+
+// qdiscAttrs returns the attributes of a qdisc of the given link, parent and handle major number
+func qdiscAttrs(link netlink.Link, parent uint32, handle uint32) netlink.QdiscAttrs {
+	return netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Parent:    parent,
+		Handle:    netlink.MakeHandle(uint16(handle), 0),
+	}
+}
+
+// parseHandle parses a tc handle (root or major:minor, hexadecimal numbers, minor being optional)
+func parseHandle(handle string) (uint32, error) {
+	if handle == "root" {
+		return netlink.HANDLE_ROOT, nil
+	}
+
+	parts := strings.SplitN(handle, ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid handle %s, expected major:minor", handle)
+	}
+
+	major, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid handle %s major number: %w", handle, err)
+	}
+
+	var minor uint64
+
+	if parts[1] != "" {
+		minor, err = strconv.ParseUint(parts[1], 16, 16)
+		if err != nil {
+			return 0, fmt.Errorf("invalid handle %s minor number: %w", handle, err)
+		}
+	}
+
+	return netlink.MakeHandle(uint16(major), uint16(minor)), nil
+}
+
+// addIPKeys adds the keys matching the given network at the given offset to the given selector, as tc does: a single
+// key for an IPv4 network and a key per 32 bits word of the prefix for an IPv6 network, none for the ::/0 network
+func addIPKeys(sel *netlink.TcU32Sel, offset int32, ipNet *net.IPNet) error {
+	if ip := ipNet.IP.To4(); ip != nil {
+		mask := net.IP(ipNet.Mask).To4()
+		if mask == nil {
+			return fmt.Errorf("wrong filter, %s is not a valid IPv4 network", ipNet)
+		}
+
+		return addKey(sel, offset, binary.BigEndian.Uint32(ip)&binary.BigEndian.Uint32(mask), binary.BigEndian.Uint32(mask))
+	}
+
+	ip := ipNet.IP.To16()
+	mask := net.IP(ipNet.Mask).To16()
+
+	if ip == nil || mask == nil || len(ipNet.Mask) != net.IPv6len {
+		return fmt.Errorf("wrong filter, %s is not a valid IPv6 network", ipNet)
+	}
+
+	prefix, _ := ipNet.Mask.Size()
+
+	for word := 0; word*32 < prefix; word++ {
+		wordMask := binary.BigEndian.Uint32(mask[4*word:])
+
+		if err := addKey(sel, offset+int32(4*word), binary.BigEndian.Uint32(ip[4*word:])&wordMask, wordMask); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addKey adds a key matching the given value and mask at the given offset to the given selector,
+// keys of the same offset are merged into a single key as tc does
+func addKey(sel *netlink.TcU32Sel, offset int32, value uint32, mask uint32) error {
+	for i, key := range sel.Keys {
+		if key.Off != offset {
+			continue
+		}
+
+		if (key.Val^value)&key.Mask&mask != 0 {
+			return fmt.Errorf("wrong filter, conflicting matches at offset %d", offset)
+		}
+
+		sel.Keys[i].Val |= value
+		sel.Keys[i].Mask |= mask
+
+		return nil
+	}
+
+	sel.Keys = append(sel.Keys, netlink.TcU32Key{
+		Off:  offset,
+		Val:  value,
+		Mask: mask,
+	})
+
+	return nil
+}
+
+// addNetem adds the given netem qdisc along with the given delay distribution and the loss model and slots
+// of the given parameters, which the netlink library can't send
+func addNetem(netem *netlink.Netem, distribution []int16, params NetemParams) error {
+	req := nl.NewNetlinkRequest(unix.RTM_NEWQDISC, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
+	req.AddData(&nl.TcMsg{
+		Family:  nl.FAMILY_ALL,
+		Ifindex: int32(netem.LinkIndex),
+		Handle:  netem.Handle,
+		Parent:  netem.Parent,
+	})
+	req.AddData(nl.NewRtAttr(nl.TCA_KIND, nl.ZeroTerminated(netem.Type())))
+
+	opt := nl.TcNetemQopt{
+		Latency:   netem.Latency,
+		Limit:     netem.Limit,
+		Loss:      netem.Loss,
+		Gap:       netem.Gap,
+		Duplicate: netem.Duplicate,
+		Jitter:    netem.Jitter,
+	}
+	options := nl.NewRtAttr(nl.TCA_OPTIONS, opt.Serialize())
+
+	if netem.DelayCorr > 0 || netem.LossCorr > 0 || netem.DuplicateCorr > 0 {
+		correlation := nl.TcNetemCorr{
+			DelayCorr: netem.DelayCorr,
+			LossCorr:  netem.LossCorr,
+			DupCorr:   netem.DuplicateCorr,
+		}
+		options.AddRtAttr(nl.TCA_NETEM_CORR, correlation.Serialize())
+	}
+
+	if netem.CorruptProb > 0 {
+		corruption := nl.TcNetemCorrupt{Probability: netem.CorruptProb, Correlation: netem.CorruptCorr}
+		options.AddRtAttr(nl.TCA_NETEM_CORRUPT, corruption.Serialize())
+	}
+
+	if netem.ReorderProb > 0 {
+		reorder := nl.TcNetemReorder{Probability: netem.ReorderProb, Correlation: netem.ReorderCorr}
+		options.AddRtAttr(nl.TCA_NETEM_REORDER, reorder.Serialize())
+	}
+
+	native := nl.NativeEndian()
+
+	if len(distribution) > 0 {
+		data := make([]byte, 2*len(distribution))
+
+		for i, value := range distribution {
+			native.PutUint16(data[2*i:], uint16(value))
+		}
+
+		options.AddRtAttr(nl.TCA_NETEM_DELAY_DIST, data)
+	}
+
+	// loss models are nested attributes holding the probabilities of the model, in the order of the kernel structures
+	var lossModel []uint32
+
+	switch {
+	case params.GilbertElliott != nil:
+		model := params.GilbertElliott
+		lossModel = []uint32{netemLossGE, percentage(model.P), percentage(model.R), percentage(100 - model.BadStateLoss), percentage(model.GoodStateLoss)}
+	case params.FourStateLoss != nil:
+		model := params.FourStateLoss
+		lossModel = []uint32{netemLossGI, percentage(model.P13), percentage(model.P31), percentage(model.P32), percentage(model.P14), percentage(model.P23)}
+	}
+
+	if len(lossModel) > 0 {
+		data := make([]byte, 4*(len(lossModel)-1))
+
+		for i, value := range lossModel[1:] {
+			native.PutUint32(data[4*i:], value)
+		}
+
+		options.AddRtAttr(nl.TCA_NETEM_LOSS, nil).AddRtAttr(int(lossModel[0]), data)
+	}
+
+	if params.Slot != nil {
+		maxDelay := params.Slot.MaxDelay
+		if maxDelay < params.Slot.MinDelay {
+			maxDelay = params.Slot.MinDelay
+		}
+
+		// struct tc_netem_slot: min and max delays in ns, max packets and bytes, distribution delay and jitter in ns
+		data := make([]byte, 40)
+		native.PutUint64(data[0:], uint64(params.Slot.MinDelay.Nanoseconds()))
+		native.PutUint64(data[8:], uint64(maxDelay.Nanoseconds()))
+		native.PutUint32(data[16:], uint32(params.Slot.Packets))
+		native.PutUint32(data[20:], uint32(params.Slot.Bytes))
+
+		options.AddRtAttr(tcaNetemSlot, data)
+	}
+
+	req.AddData(options)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+
+	return err
+}
+
+// percentage converts the given percentage to the netem probability representation
+func percentage(value int) uint32 {
+	return netlink.Percentage2u32(float32(value))
+}
+
+// delayDistribution returns the netem delay distribution table of the given distribution
+func delayDistribution(name string) ([]int16, error) {
+	switch name {
+	case "", NetemDistributionNormal:
+		return normalDistribution(), nil
+	case NetemDistributionPareto:
+		return paretoDistribution(), nil
+	case NetemDistributionParetoNormal:
+		return paretoNormalDistribution(), nil
+	default:
+		return nil, fmt.Errorf("unsupported delay distribution %s", name)
+	}
+}
+
+// normalDistribution returns the netem delay distribution table of the normal distribution,
+// generated the same way as the normal.dist table shipped with tc (see iproute2 netem/normal.c)
+func normalDistribution() []int16 {
+	const (
+		tableSize   = 16384
+		tableFactor = 8192 // NETEM_DIST_SCALE
+	)
+
+	// inverse of the cumulative distribution function
+	table := make([]float64, tableSize+1)
+
+	for x := -10.0; x < 10.05; x += .00005 {
+		i := int(math.RoundToEven(tableSize * (.5 + .5*math.Erf(x/math.Sqrt2))))
+		table[i] = x
+	}
+
+	distribution := make([]int16, 0, tableSize/4)
+
+	for i := 0; i < tableSize; i += 4 {
+		value := math.RoundToEven(table[i] * tableFactor)
+		value = math.Max(math.MinInt16, math.Min(math.MaxInt16, value))
+
+		distribution = append(distribution, int16(value))
+	}
+
+	return distribution
+}
+
+// paretoDistribution returns the netem delay distribution table of the pareto distribution,
+// generated the same way as the pareto.dist table shipped with tc (see iproute2 netem/pareto.c)
+func paretoDistribution() []int16 {
+	distribution := make([]int16, 0, 4096)
+
+	for i := 65536; i > 0; i -= 16 {
+		distribution = append(distribution, int16(paretoValue(i)))
+	}
+
+	return distribution
+}
+
+// paretoNormalDistribution returns the netem delay distribution table of the pareto normal distribution, a quarter normal
+// and three quarters pareto, generated the same way as the paretonormal.dist table shipped with tc (see iproute2 netem/paretonormal.c)
+func paretoNormalDistribution() []int16 {
+	const (
+		tableSize   = 16384
+		tableFactor = 8192 // NETEM_DIST_SCALE
+	)
+
+	// inverse of the normal cumulative distribution function
+	table := make([]float64, tableSize+1)
+
+	for x := -10.0; x < 10.05; x += .00005 {
+		i := int(math.RoundToEven(tableSize * (.5 + .5*math.Erf(x/math.Sqrt2))))
+		table[i] = x
+	}
+
+	distribution := make([]int16, 0, tableSize/4)
+
+	for i := 0; i < tableSize; i += 4 {
+		normalValue := int(math.RoundToEven(table[i] * tableFactor))
+		value := (normalValue + 3*paretoValue(65536-4*i)) / 4
+		value = int(math.Max(math.MinInt16, math.Min(math.MaxInt16, float64(value))))
+
+		distribution = append(distribution, int16(value))
+	}
+
+	return distribution
+}
+
+// paretoValue returns the value of the pareto distribution table at the given position out of 65536
+func paretoValue(i int) int {
+	const (
+		shape       = 3.0
+		tableFactor = 8192 // NETEM_DIST_SCALE
+	)
+
+	value := 1.0 / math.Pow(float64(i)/65536, 1.0/shape)
+	value -= 1.5
+	value *= (4.0 / 3.0) * tableFactor
+	value = math.Min(math.MaxInt16, value)
+
+	return int(math.RoundToEven(value))
+}
@@ -0,0 +1,483 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"net"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("Netlink tc", func() {
+	const iface = "chaos0"
+
+	var (
+		origin, ns netns.NsHandle
+		dryRun     bool
+		tcRunner   TrafficController
+		link       netlink.Link
+	)
+
+	// probeLink returns a link, apart from the one under test, to check the kernel support of a qdisc or filter kind on
+	probeLink := func() netlink.Link {
+		Expect(netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: "chaos-probe"},
+			PeerName:  "chaos-probe1",
+		})).To(Succeed())
+
+		probe, err := netlink.LinkByName("chaos-probe")
+		Expect(err).ToNot(HaveOccurred())
+
+		return probe
+	}
+
+	// skipUnlessQdiscSupported skips the test if the kernel doesn't support the kind of the qdisc built for the given link
+	skipUnlessQdiscSupported := func(qdisc func(link netlink.Link) netlink.Qdisc) {
+		if err := netlink.QdiscAdd(qdisc(probeLink())); err != nil {
+			Skip("qdisc kind not supported by the kernel: " + err.Error())
+		}
+	}
+
+	// skipUnlessFilterSupported skips the test if the kernel doesn't support the kind of the filter built for the given link,
+	// the filter being attached to a classful root qdisc
+	skipUnlessFilterSupported := func(filter func(link netlink.Link) netlink.Filter) {
+		probe := probeLink()
+
+		Expect(netlink.QdiscAdd(netlink.NewHtb(netlink.QdiscAttrs{
+			LinkIndex: probe.Attrs().Index,
+			Parent:    netlink.HANDLE_ROOT,
+			Handle:    netlink.MakeHandle(1, 0),
+		}))).To(Succeed())
+
+		if err := netlink.FilterAdd(filter(probe)); err != nil {
+			Skip("filter kind not supported by the kernel: " + err.Error())
+		}
+	}
+
+	// addRootHtb adds a classful root qdisc to attach filters to, as the prio qdisc does in network disruptions
+	addRootHtb := func() {
+		Expect(netlink.QdiscAdd(netlink.NewHtb(netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    netlink.HANDLE_ROOT,
+			Handle:    netlink.MakeHandle(1, 0),
+		}))).To(Succeed())
+	}
+
+	qdiscs := func() []netlink.Qdisc {
+		list, err := netlink.QdiscList(link)
+		Expect(err).ToNot(HaveOccurred())
+
+		return list
+	}
+
+	filters := func() []netlink.Filter {
+		list, err := netlink.FilterList(link, netlink.MakeHandle(1, 0))
+		Expect(err).ToNot(HaveOccurred())
+
+		return list
+	}
+
+	BeforeEach(func() {
+		dryRun = false
+
+		// the network namespace is bound to the current thread, it must not change during the test
+		runtime.LockOSThread()
+
+		var err error
+
+		origin, err = netns.Get()
+		Expect(err).ToNot(HaveOccurred())
+
+		ns, err = netns.New()
+		if err != nil {
+			_ = origin.Close()
+			runtime.UnlockOSThread()
+			Skip("cannot create a network namespace: " + err.Error())
+		}
+
+		Expect(netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: iface},
+			PeerName:  "chaos1",
+		})).To(Succeed())
+
+		link, err = netlink.LinkByName(iface)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		tcRunner = NewNetlinkTrafficController(zap.NewNop().Sugar(), dryRun)
+	})
+
+	AfterEach(func() {
+		// the test network namespace is destroyed once the thread left it and its handle is closed
+		Expect(netns.Set(origin)).To(Succeed())
+		Expect(ns.Close()).To(Succeed())
+		Expect(origin.Close()).To(Succeed())
+		runtime.UnlockOSThread()
+	})
+
+	Describe("AddOutputLimit", func() {
+		It("should add a tbf qdisc with the same rate as tc", func() {
+			Expect(tcRunner.AddOutputLimit([]string{iface}, "root", 1, 1000)).To(Succeed())
+
+			list := qdiscs()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0]).To(BeAssignableToTypeOf(&netlink.Tbf{}))
+
+			tbf := list[0].(*netlink.Tbf)
+			Expect(tbf.Handle).To(Equal(netlink.MakeHandle(1, 0)))
+			Expect(tbf.Parent).To(Equal(uint32(netlink.HANDLE_ROOT)))
+			Expect(tbf.Rate).To(Equal(uint64(125)))
+			Expect(tbf.Limit).To(Equal(uint32(1006)))
+		})
+
+		Context("with dry-run mode enabled", func() {
+			BeforeEach(func() {
+				dryRun = true
+			})
+
+			It("should not add any qdisc", func() {
+				Expect(tcRunner.AddOutputLimit([]string{iface}, "root", 1, 1000)).To(Succeed())
+
+				for _, qdisc := range qdiscs() {
+					Expect(qdisc).ToNot(BeAssignableToTypeOf(&netlink.Tbf{}))
+				}
+			})
+		})
+	})
+
+	Describe("AddNetem", func() {
+		It("should add a netem qdisc", func() {
+			skipUnlessQdiscSupported(func(link netlink.Link) netlink.Qdisc {
+				return netlink.NewNetem(netlink.QdiscAttrs{LinkIndex: link.Attrs().Index, Parent: netlink.HANDLE_ROOT}, netlink.NetemQdiscAttrs{})
+			})
+
+			err := tcRunner.AddNetem([]string{iface}, "root", 1, NetemParams{
+				Delay:       time.Second,
+				DelayJitter: 100 * time.Millisecond,
+				Drop:        5,
+				Corrupt:     1,
+				Duplicate:   2,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			list := qdiscs()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0]).To(BeAssignableToTypeOf(&netlink.Netem{}))
+
+			netem := list[0].(*netlink.Netem)
+			Expect(netem.Handle).To(Equal(netlink.MakeHandle(1, 0)))
+			Expect(netem.Loss).To(Equal(netlink.Percentage2u32(5)))
+			Expect(netem.CorruptProb).To(Equal(netlink.Percentage2u32(1)))
+			Expect(netem.Duplicate).To(Equal(netlink.Percentage2u32(2)))
+		})
+	})
+
+	Describe("AddNetem with loss models and slots", func() {
+		It("should add a netem qdisc with a Gilbert-Elliott loss model", func() {
+			skipUnlessQdiscSupported(func(link netlink.Link) netlink.Qdisc {
+				return netlink.NewNetem(netlink.QdiscAttrs{LinkIndex: link.Attrs().Index, Parent: netlink.HANDLE_ROOT}, netlink.NetemQdiscAttrs{})
+			})
+
+			err := tcRunner.AddNetem([]string{iface}, "root", 1, NetemParams{
+				Delay:              100 * time.Millisecond,
+				DelayJitter:        10 * time.Millisecond,
+				DelayDistribution:  NetemDistributionParetoNormal,
+				GilbertElliott:     &GilbertElliottLoss{P: 1, R: 25, BadStateLoss: 100},
+				Reorder:            25,
+				ReorderCorrelation: 50,
+				Slot:               &NetemSlot{MinDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, Packets: 32},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			list := qdiscs()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0]).To(BeAssignableToTypeOf(&netlink.Netem{}))
+
+			netem := list[0].(*netlink.Netem)
+			Expect(netem.Loss).To(BeZero())
+			Expect(netem.ReorderProb).To(Equal(netlink.Percentage2u32(25)))
+			Expect(netem.Gap).To(Equal(uint32(1)))
+		})
+
+		It("should fail with an unknown delay distribution", func() {
+			Expect(tcRunner.AddNetem([]string{iface}, "root", 1, NetemParams{
+				Delay:             100 * time.Millisecond,
+				DelayJitter:       10 * time.Millisecond,
+				DelayDistribution: "uniform",
+			})).ToNot(Succeed())
+		})
+	})
+
+	Describe("AddPrio", func() {
+		It("should add a prio qdisc", func() {
+			skipUnlessQdiscSupported(func(link netlink.Link) netlink.Qdisc {
+				return netlink.NewPrio(netlink.QdiscAttrs{LinkIndex: link.Attrs().Index, Parent: netlink.HANDLE_ROOT})
+			})
+
+			priomap := [16]uint32{1, 2, 2, 2, 1, 2, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1}
+
+			err := tcRunner.AddPrio([]string{iface}, "root", 1, 4, priomap)
+			Expect(err).ToNot(HaveOccurred())
+
+			list := qdiscs()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0]).To(BeAssignableToTypeOf(&netlink.Prio{}))
+
+			prio := list[0].(*netlink.Prio)
+			Expect(prio.Bands).To(Equal(uint8(4)))
+			Expect(prio.PriorityMap).To(Equal([16]uint8{1, 2, 2, 2, 1, 2, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1}))
+		})
+	})
+
+	Describe("AddFilter", func() {
+		BeforeEach(func() {
+			addRootHtb()
+		})
+
+		It("should add a u32 filter with the same keys as tc", func() {
+			srcIP := &net.IPNet{IP: net.IPv4(192, 168, 0, 1), Mask: net.CIDRMask(32, 32)}
+			dstIP := &net.IPNet{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)}
+
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1000, 0, srcIP, dstIP, 12345, 80, "tcp", "1:4")).To(Succeed())
+
+			list := filters()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0]).To(BeAssignableToTypeOf(&netlink.U32{}))
+
+			u32 := list[0].(*netlink.U32)
+			Expect(u32.Priority).To(Equal(uint16(1000)))
+			Expect(u32.Protocol).To(Equal(uint16(unix.ETH_P_IP)))
+			Expect(u32.ClassId).To(Equal(netlink.MakeHandle(1, 4)))
+			Expect(u32.Sel.Keys).To(Equal([]netlink.TcU32Key{
+				{Off: 12, Val: 0xc0a80001, Mask: 0xffffffff},
+				{Off: 16, Val: 0x0a000000, Mask: 0xff000000},
+				{Off: 20, Val: 0x30390050, Mask: 0xffffffff},
+				{Off: 8, Val: 0x00060000, Mask: 0x00ff0000},
+			}))
+		})
+
+		It("should fail without anything to match", func() {
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1000, 0, nil, nil, 0, 0, "", "1:4")).ToNot(Succeed())
+		})
+
+		It("should add an IPv6 u32 filter with the same keys as tc", func() {
+			dstIP := &net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(48, 128)}
+
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1000, 0, nil, dstIP, 0, 443, "tcp", "1:4")).To(Succeed())
+
+			list := filters()
+			Expect(list).To(HaveLen(1))
+
+			u32 := list[0].(*netlink.U32)
+			Expect(u32.Protocol).To(Equal(uint16(unix.ETH_P_IPV6)))
+			Expect(u32.Sel.Keys).To(Equal([]netlink.TcU32Key{
+				{Off: 24, Val: 0x20010db8, Mask: 0xffffffff},
+				{Off: 28, Val: 0x00000000, Mask: 0xffff0000},
+				{Off: 40, Val: 0x000001bb, Mask: 0x0000ffff},
+				{Off: 4, Val: 0x00000600, Mask: 0x0000ff00},
+			}))
+		})
+
+		It("should match any IPv6 packet with the IPv6 wildcard network", func() {
+			dstIP := &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1000, 0, nil, dstIP, 0, 0, "", "1:4")).To(Succeed())
+
+			list := filters()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].Attrs().Protocol).To(Equal(uint16(unix.ETH_P_IPV6)))
+		})
+
+		It("should fail to match networks of different families", func() {
+			srcIP := &net.IPNet{IP: net.IPv4(192, 168, 0, 1), Mask: net.CIDRMask(32, 32)}
+			dstIP := &net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(128, 128)}
+
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1000, 0, srcIP, dstIP, 0, 0, "", "1:4")).ToNot(Succeed())
+		})
+	})
+
+	Describe("DeleteFilter", func() {
+		BeforeEach(func() {
+			addRootHtb()
+		})
+
+		It("should delete the filters of the given priority only", func() {
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1000, 0, nil, nil, 0, 80, "", "1:4")).To(Succeed())
+			Expect(tcRunner.AddFilter([]string{iface}, "1:0", 1001, 0, nil, nil, 0, 443, "", "1:4")).To(Succeed())
+
+			Expect(tcRunner.DeleteFilter(iface, "1:0", 1000)).To(Succeed())
+
+			list := filters()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].Attrs().Priority).To(Equal(uint16(1001)))
+		})
+	})
+
+	Describe("AddCgroupFilter", func() {
+		BeforeEach(func() {
+			addRootHtb()
+		})
+
+		It("should add a cgroup filter", func() {
+			skipUnlessFilterSupported(func(link netlink.Link) netlink.Filter {
+				return &netlink.GenericFilter{
+					FilterAttrs: netlink.FilterAttrs{
+						LinkIndex: link.Attrs().Index,
+						Parent:    netlink.MakeHandle(1, 0),
+						Priority:  1,
+						Protocol:  unix.ETH_P_ALL,
+					},
+					FilterType: "cgroup",
+				}
+			})
+
+			err := tcRunner.AddCgroupFilter([]string{iface}, "1:0", 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			list := filters()
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].Type()).To(Equal("cgroup"))
+		})
+	})
+
+	Describe("AddIngressRedirect", func() {
+		BeforeEach(func() {
+			Expect(netlink.LinkAdd(&netlink.Ifb{LinkAttrs: netlink.LinkAttrs{Name: "chaos-ifb"}})).To(Succeed())
+		})
+
+		It("should redirect every incoming packet to the target", func() {
+			skipUnlessQdiscSupported(func(link netlink.Link) netlink.Qdisc {
+				return &netlink.Ingress{QdiscAttrs: netlink.QdiscAttrs{LinkIndex: link.Attrs().Index, Parent: netlink.HANDLE_INGRESS}}
+			})
+
+			err := tcRunner.AddIngressRedirect([]string{iface}, "chaos-ifb")
+			Expect(err).ToNot(HaveOccurred())
+
+			ingress := false
+			for _, qdisc := range qdiscs() {
+				if _, ok := qdisc.(*netlink.Ingress); ok {
+					ingress = true
+				}
+			}
+			Expect(ingress).To(BeTrue())
+
+			list, err := netlink.FilterList(link, netlink.HANDLE_INGRESS)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(list).To(HaveLen(1))
+			Expect(list[0]).To(BeAssignableToTypeOf(&netlink.U32{}))
+
+			target, err := netlink.LinkByName("chaos-ifb")
+			Expect(err).ToNot(HaveOccurred())
+
+			u32 := list[0].(*netlink.U32)
+			Expect(u32.Actions).To(HaveLen(1))
+			Expect(u32.Actions[0]).To(BeAssignableToTypeOf(&netlink.MirredAction{}))
+			Expect(u32.Actions[0].(*netlink.MirredAction).Ifindex).To(Equal(target.Attrs().Index))
+
+			Expect(tcRunner.ClearIngressQdisc([]string{iface})).To(Succeed())
+
+			for _, qdisc := range qdiscs() {
+				Expect(qdisc).ToNot(BeAssignableToTypeOf(&netlink.Ingress{}))
+			}
+		})
+
+		It("should fail if the target does not exist", func() {
+			Expect(tcRunner.AddIngressRedirect([]string{iface}, "chaos-missing")).ToNot(Succeed())
+		})
+	})
+
+	Describe("ClearIngressQdisc", func() {
+		It("should succeed without any ingress qdisc", func() {
+			Expect(tcRunner.ClearIngressQdisc([]string{iface})).To(Succeed())
+		})
+	})
+
+	Describe("ClearQdisc", func() {
+		It("should delete the root qdisc", func() {
+			Expect(tcRunner.AddOutputLimit([]string{iface}, "root", 1, 1000)).To(Succeed())
+			Expect(tcRunner.ClearQdisc([]string{iface})).To(Succeed())
+
+			for _, qdisc := range qdiscs() {
+				Expect(qdisc).ToNot(BeAssignableToTypeOf(&netlink.Tbf{}))
+			}
+		})
+
+		It("should succeed if the root qdisc is the default one", func() {
+			Expect(tcRunner.ClearQdisc([]string{iface})).To(Succeed())
+		})
+
+		It("should fail if the interface does not exist", func() {
+			Expect(tcRunner.ClearQdisc([]string{"chaos-missing"})).ToNot(Succeed())
+		})
+	})
+})
+
+var _ = Describe("parseHandle", func() {
+	It("should parse hexadecimal handles", func() {
+		Expect(parseHandle("root")).To(Equal(uint32(netlink.HANDLE_ROOT)))
+		Expect(parseHandle("1:4")).To(Equal(netlink.MakeHandle(1, 4)))
+		Expect(parseHandle("a:")).To(Equal(netlink.MakeHandle(10, 0)))
+	})
+
+	It("should fail on invalid handles", func() {
+		_, err := parseHandle("1")
+		Expect(err).To(HaveOccurred())
+
+		_, err = parseHandle("g:1")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("normalDistribution", func() {
+	It("should return the inverse of the normal cumulative distribution function", func() {
+		distribution := normalDistribution()
+
+		Expect(distribution).To(HaveLen(4096))
+		Expect(distribution[0]).To(Equal(int16(-32768)))
+		Expect(distribution[2048]).To(Equal(int16(0)))
+
+		for i := 1; i < len(distribution); i++ {
+			Expect(distribution[i]).To(BeNumerically(">=", distribution[i-1]))
+		}
+	})
+})
+
+var _ = Describe("paretoDistribution", func() {
+	It("should return the pareto table shipped with tc", func() {
+		distribution := paretoDistribution()
+
+		Expect(distribution).To(HaveLen(4096))
+		Expect(distribution[:4]).To(Equal([]int16{-5461, -5460, -5460, -5459}))
+		Expect(distribution[len(distribution)-1]).To(Equal(int16(32767)))
+
+		for i := 1; i < len(distribution); i++ {
+			Expect(distribution[i]).To(BeNumerically(">=", distribution[i-1]))
+		}
+	})
+})
+
+var _ = Describe("paretoNormalDistribution", func() {
+	It("should mix the normal and pareto tables", func() {
+		distribution := paretoNormalDistribution()
+
+		Expect(distribution).To(HaveLen(4096))
+
+		for i := 1; i < len(distribution); i++ {
+			Expect(distribution[i]).To(BeNumerically(">=", distribution[i-1]))
+		}
+	})
+})
@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+//go:build !linux
+// +build !linux
+
+package network
+
+import (
+	"errors"
+	"net"
+
+	"go.uber.org/zap"
+)
+
+type netlinkTc struct{}
+
+// NewNetlinkTrafficController creates a traffic controller sending the qdiscs and filters
+// to the kernel through netlink instead of executing tc, with the same semantics
+func NewNetlinkTrafficController(log *zap.SugaredLogger, dryRun bool) TrafficController {
+	return netlinkTc{}
+}
+
+func (t netlinkTc) AddNetem(ifaces []string, parent string, handle uint32, netem NetemParams) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) AddPrio(ifaces []string, parent string, handle uint32, bands uint32, priomap [16]uint32) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) AddFilter(ifaces []string, parent string, priority uint32, handle uint32, srcIP, dstIP *net.IPNet, srcPort, dstPort int, protocol string, flowid string) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) DeleteFilter(iface string, parent string, priority uint32) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) AddCgroupFilter(ifaces []string, parent string, handle uint32) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) AddOutputLimit(ifaces []string, parent string, handle uint32, bytesPerSec uint) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) AddIngressRedirect(ifaces []string, target string) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) ClearQdisc(ifaces []string) error {
+	return errors.New("unsupported")
+}
+
+func (t netlinkTc) ClearIngressQdisc(ifaces []string) error {
+	return errors.New("unsupported")
+}
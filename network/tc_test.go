@@ -32,11 +32,7 @@ var _ = Describe("Tc", func() {
 		ifaces            []string
 		parent            string
 		handle            uint32
-		delay             time.Duration
-		delayJitter       time.Duration
-		drop              int
-		duplicate         int
-		corrupt           int
+		netem             NetemParams
 		bands             uint32
 		priomap           [16]uint32
 		srcIP, dstIP      *net.IPNet
@@ -59,11 +55,13 @@ var _ = Describe("Tc", func() {
 		ifaces = []string{"lo", "eth0"}
 		parent = "root"
 		handle = 0
-		delay = time.Second
-		delayJitter = time.Second
-		drop = 5
-		duplicate = 5
-		corrupt = 1
+		netem = NetemParams{
+			Delay:       time.Second,
+			DelayJitter: time.Second,
+			Drop:        5,
+			Duplicate:   5,
+			Corrupt:     1,
+		}
 		bands = 16
 		priomap = [16]uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
 		srcIP = &net.IPNet{
@@ -82,7 +80,7 @@ var _ = Describe("Tc", func() {
 
 	Describe("AddNetem", func() {
 		JustBeforeEach(func() {
-			tcRunner.AddNetem(ifaces, parent, handle, delay, delayJitter, drop, corrupt, duplicate)
+			tcRunner.AddNetem(ifaces, parent, handle, netem)
 		})
 
 		Context("add 1s delay and 1s delayJitter to lo interface to the root parent without any handle", func() {
@@ -110,6 +108,88 @@ var _ = Describe("Tc", func() {
 				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo parent 1:4 netem delay 1000ms 1000ms distribution normal loss 5% duplicate 5% corrupt 1%")
 			})
 		})
+
+		Context("add correlated disruptions with a pareto delay distribution", func() {
+			BeforeEach(func() {
+				netem.DelayCorrelation = 25
+				netem.DelayDistribution = NetemDistributionPareto
+				netem.DropCorrelation = 50
+				netem.DuplicateCorrelation = 10
+				netem.CorruptCorrelation = 20
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo root netem delay 1000ms 1000ms 25% distribution pareto loss 5% 50% duplicate 5% 10% corrupt 1% 20%")
+			})
+		})
+
+		Context("add a Gilbert-Elliott loss model", func() {
+			BeforeEach(func() {
+				netem = NetemParams{
+					GilbertElliott: &GilbertElliottLoss{
+						P:             1,
+						R:             25,
+						BadStateLoss:  90,
+						GoodStateLoss: 2,
+					},
+				}
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo root netem loss gemodel 1% 25% 10% 2%")
+			})
+		})
+
+		Context("add a 4-state loss model replacing the drop percentage", func() {
+			BeforeEach(func() {
+				netem.Delay = 0
+				netem.Duplicate = 0
+				netem.Corrupt = 0
+				netem.FourStateLoss = &FourStateLoss{
+					P13: 5,
+					P31: 80,
+					P32: 10,
+					P23: 90,
+					P14: 1,
+				}
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo root netem loss state 5% 80% 10% 90% 1%")
+			})
+		})
+
+		Context("add reordering with a gap", func() {
+			BeforeEach(func() {
+				netem = NetemParams{
+					Delay:              10 * time.Millisecond,
+					DelayJitter:        time.Millisecond,
+					Reorder:            25,
+					ReorderCorrelation: 50,
+					ReorderGap:         5,
+				}
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo root netem delay 10ms 1ms distribution normal reorder 25% 50% gap 5")
+			})
+		})
+
+		Context("add slots", func() {
+			BeforeEach(func() {
+				netem = NetemParams{
+					Slot: &NetemSlot{
+						MinDelay: 800 * time.Microsecond,
+						Packets:  32,
+						Bytes:    64000,
+					},
+				}
+			})
+
+			It("should execute with the minimum delay as maximum delay", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo root netem slot 800us 800us packets 32 bytes 64000")
+			})
+		})
 	})
 
 	Describe("AddPrio", func() {
@@ -136,7 +216,7 @@ var _ = Describe("Tc", func() {
 			})
 
 			It("should execute", func() {
-				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root u32 match ip dst 10.0.0.1/32 match ip dport 80 0xffff match ip protocol 6 0xff flowid 1:2")
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root protocol ip u32 match ip dst 10.0.0.1/32 match ip dport 80 0xffff match ip protocol 6 0xff flowid 1:2")
 			})
 		})
 
@@ -147,13 +227,48 @@ var _ = Describe("Tc", func() {
 			})
 
 			It("should execute", func() {
-				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root u32 match ip src 192.168.0.1/32 match ip sport 12345 0xffff match ip protocol 6 0xff flowid 1:2")
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root protocol ip u32 match ip src 192.168.0.1/32 match ip sport 12345 0xffff match ip protocol 6 0xff flowid 1:2")
 			})
 		})
 
 		Context("add a filter on packets leaving IP 192.168.0.1 port 12345 and going to IP 10.0.0.1 port 80 with flowid 1:4 on egress traffic", func() {
 			It("should execute", func() {
-				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root u32 match ip src 192.168.0.1/32 match ip dst 10.0.0.1/32 match ip sport 12345 0xffff match ip dport 80 0xffff match ip protocol 6 0xff flowid 1:2")
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root protocol ip u32 match ip src 192.168.0.1/32 match ip dst 10.0.0.1/32 match ip sport 12345 0xffff match ip dport 80 0xffff match ip protocol 6 0xff flowid 1:2")
+			})
+		})
+
+		Context("add a filter on packets going to IPv6 network 2001:db8::/32 and port 443 with flowid 1:4 on egress traffic", func() {
+			BeforeEach(func() {
+				srcIP = nil
+				srcPort = 0
+				dstIP = &net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(32, 128)}
+				dstPort = 443
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root protocol ipv6 u32 match ip6 dst 2001:db8::/32 match ip6 dport 443 0xffff match ip6 protocol 6 0xff flowid 1:2")
+			})
+		})
+
+		Context("add a filter on packets going to port 80 of any IP with flowid 1:4 on egress traffic", func() {
+			BeforeEach(func() {
+				srcIP = nil
+				srcPort = 0
+				dstIP = nil
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo root u32 match ip dport 80 0xffff match ip protocol 6 0xff flowid 1:2")
+			})
+		})
+
+		Context("add a filter on packets leaving an IPv4 address and going to an IPv6 address", func() {
+			BeforeEach(func() {
+				dstIP = &net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(128, 128)}
+			})
+
+			It("should not execute anything", func() {
+				tcExecuter.AssertNotCalled(GinkgoT(), "Run", mock.Anything)
 			})
 		})
 	})
@@ -181,6 +296,37 @@ var _ = Describe("Tc", func() {
 		})
 	})
 
+	Describe("AddIngressRedirect", func() {
+		JustBeforeEach(func() {
+			Expect(tcRunner.AddIngressRedirect(ifaces, "chaos-ifb")).To(BeNil())
+		})
+
+		It("should add an ingress qdisc and a filter redirecting every packet to the target", func() {
+			tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc add dev lo ingress")
+			tcExecuter.AssertCalled(GinkgoT(), "Run", "filter add dev lo parent ffff: u32 match u32 0 0 action mirred egress redirect dev chaos-ifb")
+		})
+	})
+
+	Describe("ClearIngressQdisc", func() {
+		JustBeforeEach(func() {
+			Expect(tcRunner.ClearIngressQdisc(ifaces)).To(BeNil())
+		})
+
+		It("should execute", func() {
+			tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc del dev lo ingress")
+		})
+
+		Context("clear an already cleared ingress qdisc", func() {
+			BeforeEach(func() {
+				tcExecuterRunCall.Return(2, "", nil) // return exit code 2
+			})
+
+			It("should execute", func() {
+				tcExecuter.AssertCalled(GinkgoT(), "Run", "qdisc del dev lo ingress")
+			})
+		})
+	})
+
 	Describe("ClearQdisc", func() {
 		JustBeforeEach(func() {
 			Expect(tcRunner.ClearQdisc(ifaces)).To(BeNil())
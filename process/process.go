@@ -5,12 +5,44 @@
 
 package process
 
-import "os"
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// readyFD is the file descriptor of the pipe used by a process created by a manager to report it is ready
+	readyFD = 3
+	// readyMessage is the message written to the ready pipe by a process successfully initialized
+	readyMessage = "ready"
+)
 
 // Manager manages a process
 type Manager interface {
 	Prioritize() error
 	ThreadID() int
+	SetAffinity(cpus []int) error
 	Find(pid int) (*os.Process, error)
 	Signal(process *os.Process, signal os.Signal) error
+	Start(name string, args ...string) (*os.Process, error)
+}
+
+// NotifyReady reports the result of the initialization of the current process, created by a manager Start call,
+// to the process which created it: the creating process is unblocked and gets the given error, if any
+func NotifyReady(initErr error) error {
+	file := os.NewFile(readyFD, "ready")
+	if file == nil {
+		return fmt.Errorf("the ready pipe file descriptor %d is invalid", readyFD)
+	}
+
+	message := readyMessage
+	if initErr != nil {
+		message = initErr.Error()
+	}
+
+	if _, err := file.WriteString(message); err != nil {
+		return fmt.Errorf("error writing to the ready pipe: %w", err)
+	}
+
+	return file.Close()
 }
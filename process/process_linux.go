@@ -10,8 +10,12 @@ package process
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -42,6 +46,21 @@ func (p manager) ThreadID() int {
 	return syscall.Gettid()
 }
 
+// SetAffinity pins the caller thread to the given cpus
+func (p manager) SetAffinity(cpus []int) error {
+	set := unix.CPUSet{}
+
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return fmt.Errorf("error setting the thread cpu affinity: %w", err)
+	}
+
+	return nil
+}
+
 // Find looks for a running process by its pid
 func (p manager) Find(pid int) (*os.Process, error) {
 	proc, err := os.FindProcess(pid)
@@ -65,3 +84,48 @@ func (p manager) Signal(process *os.Process, signal os.Signal) error {
 
 	return nil
 }
+
+// Start creates a new process running the given command and blocks until the process reports being ready with NotifyReady
+// the new process shares the current process outputs and is killed if the current process exits
+func (p manager) Start(name string, args ...string) (*os.Process, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("error creating the ready pipe: %w", err)
+	}
+
+	defer reader.Close()
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{writer} // available as the readyFD file descriptor in the new process
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+
+	err = cmd.Start()
+
+	// close the current process end of the pipe so reading it ends once the new process closes it or exits
+	writer.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("error starting the %s process: %w", name, err)
+	}
+
+	message, err := ioutil.ReadAll(reader)
+	if err == nil && string(message) == readyMessage {
+		return cmd.Process, nil
+	}
+
+	// the process failed to initialize, make sure it is not left running
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading the %s process ready pipe: %w", name, err)
+	}
+
+	if len(message) == 0 {
+		return nil, fmt.Errorf("the %s process exited before being ready", name)
+	}
+
+	return nil, fmt.Errorf("the %s process failed to initialize: %s", name, message)
+}
@@ -30,6 +30,13 @@ func (f *ManagerMock) ThreadID() int {
 	return args.Int(0)
 }
 
+//nolint:golint
+func (f *ManagerMock) SetAffinity(cpus []int) error {
+	args := f.Called(cpus)
+
+	return args.Error(0)
+}
+
 //nolint:golint
 func (f *ManagerMock) Find(pid int) (*os.Process, error) {
 	args := f.Called(pid)
@@ -43,3 +50,12 @@ func (f *ManagerMock) Signal(process *os.Process, signal os.Signal) error {
 
 	return args.Error(0)
 }
+
+//nolint:golint
+func (f *ManagerMock) Start(name string, args ...string) (*os.Process, error) {
+	mockArgs := f.Called(name, args)
+
+	proc, _ := mockArgs.Get(0).(*os.Process)
+
+	return proc, mockArgs.Error(1)
+}
@@ -32,6 +32,11 @@ func (p manager) ThreadID() int {
 	return -1
 }
 
+// SetAffinity pins the caller thread to the given cpus
+func (p manager) SetAffinity(cpus []int) error {
+	return errors.New("unsupported")
+}
+
 // Find looks for a running process by its pid
 func (p manager) Find(pid int) (*os.Process, error) {
 	return nil, errors.New("unsupported")
@@ -41,3 +46,8 @@ func (p manager) Find(pid int) (*os.Process, error) {
 func (p manager) Signal(process *os.Process, signal os.Signal) error {
 	return errors.New("unsupported")
 }
+
+// Start creates a new process running the given command and blocks until the process reports being ready with NotifyReady
+func (p manager) Start(name string, args ...string) (*os.Process, error) {
+	return nil, errors.New("unsupported")
+}
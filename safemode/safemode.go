@@ -15,6 +15,10 @@ type Safemode interface {
 	// and grab the disruption itself for data such as the kubernetes namespace the disruption is running on
 	// It will also grab the kube client for functions that require state information from k8s system
 	Init(disruption v1beta1.Disruption, client client.Client)
+	// Evaluate runs the runtime safety nets against the given targets (pod or node names depending on the disruption level)
+	// It returns true along with a response describing the issue if a safety net is caught, and returns any errors when attempting to run the safety nets
+	// It is called before creating chaos pods and during the whole disruption lifetime as targets can change
+	Evaluate(targets []string) (bool, string, error)
 }
 
 // AddAllSafemodeObjects will populate a list of Safemode objects with Safemode's related to the disruptions described
@@ -52,6 +56,12 @@ func AddAllSafemodeObjects(disruption v1beta1.Disruption, k8sClient client.Clien
 		safemodeList = append(safemodeList, &safemodeCPU)
 	}
 
+	if disruption.Spec.MemoryPressure != nil {
+		safemodeMemory := Memory{}
+		safemodeMemory.Init(disruption, k8sClient)
+		safemodeList = append(safemodeList, &safemodeMemory)
+	}
+
 	if disruption.Spec.DNS != nil {
 		safemodeDNS := DNS{}
 		safemodeDNS.Init(disruption, k8sClient)
@@ -64,6 +74,12 @@ func AddAllSafemodeObjects(disruption v1beta1.Disruption, k8sClient client.Clien
 		safemodeList = append(safemodeList, &safemodeGRPC)
 	}
 
+	if disruption.Spec.HTTP != nil {
+		safemodeHTTP := HTTP{}
+		safemodeHTTP.Init(disruption, k8sClient)
+		safemodeList = append(safemodeList, &safemodeHTTP)
+	}
+
 	if disruption.Spec.NodeFailure != nil {
 		safemodeNode := Node{}
 		safemodeNode.Init(disruption, k8sClient)
@@ -73,11 +89,23 @@ func AddAllSafemodeObjects(disruption v1beta1.Disruption, k8sClient client.Clien
 	return safemodeList
 }
 
-// Reinit resets the saved disruption configs for each safety net in the case where the same disruption is updated with new parameters
-func Reinit(safetyNets []Safemode, disruption v1beta1.Disruption, k8sClient client.Client) {
+// EvaluateAll evaluates each of the given safety nets against the given targets
+// It returns the responses of all caught safety nets
+func EvaluateAll(safetyNets []Safemode, targets []string) ([]string, error) {
+	responses := []string{}
+
 	for _, safetyNet := range safetyNets {
-		safetyNet.Init(disruption, k8sClient)
+		caught, response, err := safetyNet.Evaluate(targets)
+		if err != nil {
+			return nil, err
+		}
+
+		if caught {
+			responses = append(responses, response)
+		}
 	}
+
+	return responses, nil
 }
 
 type Generic struct {
@@ -90,3 +118,8 @@ func (sm *Generic) Init(disruption v1beta1.Disruption, client client.Client) {
 	sm.dis = disruption
 	sm.client = client
 }
+
+// Evaluate Refer to safemode.Safemode interface for documentation
+func (sm *Generic) Evaluate(targets []string) (bool, string, error) {
+	return false, "", nil
+}
@@ -20,3 +20,8 @@ func (sm *ContainerFailure) Init(disruption v1beta1.Disruption, client client.Cl
 	sm.dis = disruption
 	sm.client = client
 }
+
+// Evaluate Refer to safemode.Safemode interface for documentation
+func (sm *ContainerFailure) Evaluate(targets []string) (bool, string, error) {
+	return false, "", nil
+}
@@ -6,7 +6,15 @@
 package safemode
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
 	"github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,3 +28,86 @@ func (sm *Disk) Init(disruption v1beta1.Disruption, client client.Client) {
 	sm.dis = disruption
 	sm.client = client
 }
+
+// Evaluate Refer to safemode.Safemode interface for documentation
+// It catches disk pressure disruptions applied on a path backed by the node root device, throttling a device shared by the whole node
+// On pod level disruptions, the path is resolved against the volume mounts of each container of the targeted pods: the container
+// root filesystem, emptyDir volumes not backed by memory and hostPath volumes are considered to be backed by the node root device
+func (sm *Disk) Evaluate(targets []string) (bool, string, error) {
+	if sm.dis.Spec.Unsafemode != nil && sm.dis.Spec.Unsafemode.DisableRootDiskPressure {
+		return false, "", nil
+	}
+
+	if sm.dis.Spec.DiskPressure == nil {
+		return false, "", nil
+	}
+
+	path := filepath.Clean(sm.dis.Spec.DiskPressure.Path)
+
+	if path == "/" {
+		return true, fmt.Sprintf("The specified disk pressure path %s is the root path, throttling its device would impact the whole node and not only the targets. Please specify a more specific mount point or disable the safety net with disableRootDiskPressure.", sm.dis.Spec.DiskPressure.Path), nil
+	}
+
+	// a node level disk pressure is applied on the node path directly
+	if sm.dis.Spec.Level == chaostypes.DisruptionLevelNode {
+		return false, "", nil
+	}
+
+	for _, target := range targets {
+		pod := corev1.Pod{}
+
+		if err := sm.client.Get(context.Background(), types.NamespacedName{Namespace: sm.dis.Namespace, Name: target}, &pod); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+
+			return false, "", fmt.Errorf("error getting target pod %s: %w", target, err)
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if backing := rootDeviceBacking(pod, container, path); backing != "" {
+				return true, fmt.Sprintf("The specified disk pressure path %s is backed by the %s of the %s container of the %s target pod, stored on the node root device: throttling it would impact the whole node and not only the targets. Please specify a mount point backed by a dedicated device or disable the safety net with disableRootDiskPressure.", sm.dis.Spec.DiskPressure.Path, backing, container.Name, pod.Name), nil
+			}
+		}
+	}
+
+	return false, "", nil
+}
+
+// rootDeviceBacking returns what backs the given path in the given container if it is stored on the node root device, or an empty string otherwise
+func rootDeviceBacking(pod corev1.Pod, container corev1.Container, path string) string {
+	var mount *corev1.VolumeMount
+
+	// the path is backed by the deepest volume mount containing it
+	for i, volumeMount := range container.VolumeMounts {
+		mountPath := filepath.Clean(volumeMount.MountPath)
+
+		if path != mountPath && !strings.HasPrefix(path, strings.TrimSuffix(mountPath, "/")+"/") {
+			continue
+		}
+
+		if mount == nil || len(mountPath) > len(filepath.Clean(mount.MountPath)) {
+			mount = &container.VolumeMounts[i]
+		}
+	}
+
+	// the container writable layer is stored on the node root filesystem
+	if mount == nil {
+		return "root filesystem"
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name != mount.Name {
+			continue
+		}
+
+		switch {
+		case volume.HostPath != nil:
+			return fmt.Sprintf("%s hostPath volume", volume.Name)
+		case volume.EmptyDir != nil && volume.EmptyDir.Medium != corev1.StorageMediumMemory:
+			return fmt.Sprintf("%s emptyDir volume", volume.Name)
+		}
+	}
+
+	return ""
+}
@@ -6,10 +6,20 @@
 package safemode
 
 import (
+	"fmt"
+
 	"github.com/DataDog/chaos-controller/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// kubeDNSHostnames are the hostnames of the cluster DNS service
+var kubeDNSHostnames = []string{
+	"kube-dns",
+	"kube-dns.kube-system",
+	"kube-dns.kube-system.svc",
+	"kube-dns.kube-system.svc.cluster.local",
+}
+
 type DNS struct {
 	dis    v1beta1.Disruption
 	client client.Client
@@ -20,3 +30,28 @@ func (sm *DNS) Init(disruption v1beta1.Disruption, client client.Client) {
 	sm.dis = disruption
 	sm.client = client
 }
+
+// Evaluate Refer to safemode.Safemode interface for documentation
+// It catches DNS disruptions rewriting the cluster DNS service hostnames, breaking every resolution relying on it
+func (sm *DNS) Evaluate(targets []string) (bool, string, error) {
+	if sm.dis.Spec.Unsafemode != nil && sm.dis.Spec.Unsafemode.DisableKubeDNSRewrite {
+		return false, "", nil
+	}
+
+	for _, pair := range sm.dis.Spec.DNS {
+		// hostnames are matched the way the injector resolver does, against queried names ending with a dot
+		hostname, err := pair.CompileHostname()
+		if err != nil {
+			// an invalid hostname is rejected by the admission webhook and can't be injected
+			continue
+		}
+
+		for _, kubeDNSHostname := range kubeDNSHostnames {
+			if hostname.MatchString(kubeDNSHostname + ".") {
+				return true, fmt.Sprintf("The specified DNS disruption rewrites the %s hostname of the cluster DNS service, which would break every resolution relying on it. Please disable the safety net with disableKubeDNSRewrite if this is expected.", pair.Hostname), nil
+			}
+		}
+	}
+
+	return false, "", nil
+}
@@ -20,3 +20,8 @@ func (sm *GRPC) Init(disruption v1beta1.Disruption, client client.Client) {
 	sm.dis = disruption
 	sm.client = client
 }
+
+// Evaluate Refer to safemode.Safemode interface for documentation
+func (sm *GRPC) Evaluate(targets []string) (bool, string, error) {
+	return false, "", nil
+}
@@ -6,10 +6,22 @@
 package safemode
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/DataDog/chaos-controller/api/v1beta1"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// controlPlaneNodeLabels are the labels identifying control plane nodes
+var controlPlaneNodeLabels = []string{
+	"node-role.kubernetes.io/control-plane",
+	"node-role.kubernetes.io/master",
+}
+
 type Node struct {
 	dis    v1beta1.Disruption
 	client client.Client
@@ -20,3 +32,53 @@ func (sm *Node) Init(disruption v1beta1.Disruption, client client.Client) {
 	sm.dis = disruption
 	sm.client = client
 }
+
+// Evaluate Refer to safemode.Safemode interface for documentation
+// It catches node failures hitting control plane nodes, either directly or through a targeted pod running on them
+func (sm *Node) Evaluate(targets []string) (bool, string, error) {
+	if sm.dis.Spec.Unsafemode != nil && sm.dis.Spec.Unsafemode.DisableControlPlaneNodeFailure {
+		return false, "", nil
+	}
+
+	for _, target := range targets {
+		nodeName := target
+
+		// a pod level node failure impacts the node the targeted pod is running on
+		if sm.dis.Spec.Level != chaostypes.DisruptionLevelNode {
+			pod := corev1.Pod{}
+
+			if err := sm.client.Get(context.Background(), types.NamespacedName{Namespace: sm.dis.Namespace, Name: target}, &pod); err != nil {
+				if client.IgnoreNotFound(err) == nil {
+					continue
+				}
+
+				return false, "", fmt.Errorf("error getting target pod %s: %w", target, err)
+			}
+
+			// the pod is not scheduled yet
+			if pod.Spec.NodeName == "" {
+				continue
+			}
+
+			nodeName = pod.Spec.NodeName
+		}
+
+		node := corev1.Node{}
+
+		if err := sm.client.Get(context.Background(), types.NamespacedName{Name: nodeName}, &node); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+
+			return false, "", fmt.Errorf("error getting target node %s: %w", nodeName, err)
+		}
+
+		for _, label := range controlPlaneNodeLabels {
+			if _, found := node.Labels[label]; found {
+				return true, fmt.Sprintf("The node failure would hit the %s control plane node. Please exclude control plane nodes from the targets or disable the safety net with disableControlPlaneNodeFailure.", node.Name), nil
+			}
+		}
+	}
+
+	return false, "", nil
+}
@@ -0,0 +1,420 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package safemode_test
+
+import (
+	"github.com/DataDog/chaos-controller/api/v1beta1"
+	"github.com/DataDog/chaos-controller/safemode"
+	chaostypes "github.com/DataDog/chaos-controller/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Safemode", func() {
+	var disruption v1beta1.Disruption
+
+	BeforeEach(func() {
+		disruption = v1beta1.Disruption{}
+	})
+
+	Describe("Disk", func() {
+		var (
+			sm      safemode.Disk
+			targets []string
+		)
+
+		BeforeEach(func() {
+			disruption.Namespace = "default"
+			disruption.Spec.Level = chaostypes.DisruptionLevelPod
+			disruption.Spec.DiskPressure = &v1beta1.DiskPressureSpec{
+				Path: "/mnt/data",
+			}
+			targets = []string{"pvc-pod"}
+		})
+
+		JustBeforeEach(func() {
+			newPod := func(name string, volume corev1.VolumeSource) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:         "app",
+								VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/mnt/data/"}},
+							},
+						},
+						Volumes: []corev1.Volume{{Name: "data", VolumeSource: volume}},
+					},
+				}
+			}
+
+			k8sClient := fake.NewClientBuilder().WithObjects(
+				newPod("pvc-pod", corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}),
+				newPod("memory-pod", corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}),
+				newPod("empty-dir-pod", corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}),
+				newPod("host-path-pod", corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}}),
+			).Build()
+
+			sm = safemode.Disk{}
+			sm.Init(disruption, k8sClient)
+		})
+
+		It("should not catch a path backed by a persistent volume", func() {
+			caught, _, err := sm.Evaluate(targets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caught).To(BeFalse())
+		})
+
+		It("should not catch a path backed by a memory emptyDir volume", func() {
+			caught, _, err := sm.Evaluate([]string{"memory-pod"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caught).To(BeFalse())
+		})
+
+		It("should ignore targets not found", func() {
+			caught, _, err := sm.Evaluate([]string{"unknown-pod"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caught).To(BeFalse())
+		})
+
+		Context("with a path backed by the node root device", func() {
+			It("should catch an emptyDir volume", func() {
+				caught, response, err := sm.Evaluate([]string{"pvc-pod", "empty-dir-pod"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+				Expect(response).To(ContainSubstring("data emptyDir volume"))
+			})
+
+			It("should catch a hostPath volume", func() {
+				caught, response, err := sm.Evaluate([]string{"host-path-pod"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+				Expect(response).To(ContainSubstring("data hostPath volume"))
+			})
+
+			Context("on the container root filesystem", func() {
+				BeforeEach(func() {
+					disruption.Spec.DiskPressure.Path = "/mnt/database"
+				})
+
+				It("should catch it", func() {
+					caught, response, err := sm.Evaluate(targets)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeTrue())
+					Expect(response).To(ContainSubstring("root filesystem"))
+				})
+			})
+
+			Context("with the safety net disabled", func() {
+				BeforeEach(func() {
+					disruption.Spec.Unsafemode = &v1beta1.UnsafemodeSpec{DisableRootDiskPressure: true}
+				})
+
+				It("should not catch it", func() {
+					caught, _, err := sm.Evaluate([]string{"host-path-pod"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeFalse())
+				})
+			})
+		})
+
+		Context("with a path in a volume", func() {
+			BeforeEach(func() {
+				disruption.Spec.DiskPressure.Path = "/mnt/data/db"
+			})
+
+			It("should resolve it against the volume mount", func() {
+				caught, _, err := sm.Evaluate(targets)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeFalse())
+
+				caught, _, err = sm.Evaluate([]string{"empty-dir-pod"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+			})
+		})
+
+		Context("with a node level disruption", func() {
+			BeforeEach(func() {
+				disruption.Spec.Level = chaostypes.DisruptionLevelNode
+				targets = []string{"worker"}
+			})
+
+			It("should not catch a specific node path", func() {
+				caught, _, err := sm.Evaluate(targets)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeFalse())
+			})
+		})
+
+		Context("with the root path", func() {
+			BeforeEach(func() {
+				disruption.Spec.DiskPressure.Path = "/mnt/.."
+			})
+
+			It("should catch it", func() {
+				caught, response, err := sm.Evaluate(targets)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+				Expect(response).To(ContainSubstring("disableRootDiskPressure"))
+			})
+
+			Context("with the safety net disabled", func() {
+				BeforeEach(func() {
+					disruption.Spec.Unsafemode = &v1beta1.UnsafemodeSpec{DisableRootDiskPressure: true}
+				})
+
+				It("should not catch it", func() {
+					caught, _, err := sm.Evaluate(targets)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeFalse())
+				})
+			})
+		})
+	})
+
+	Describe("DNS", func() {
+		var sm safemode.DNS
+
+		BeforeEach(func() {
+			disruption.Spec.DNS = v1beta1.DNSDisruptionSpec{
+				{
+					Hostname: "foo.bar.svc.cluster.local",
+					Record:   v1beta1.DNSRecord{Type: "A", Value: "10.0.0.1"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			sm = safemode.DNS{}
+			sm.Init(disruption, nil)
+		})
+
+		It("should not catch other hostnames", func() {
+			caught, _, err := sm.Evaluate(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caught).To(BeFalse())
+		})
+
+		Context("with a kube-dns hostname", func() {
+			BeforeEach(func() {
+				disruption.Spec.DNS[0].Hostname = "kube-dns.kube-system.svc.cluster.local."
+			})
+
+			It("should catch it", func() {
+				caught, response, err := sm.Evaluate(nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+				Expect(response).To(ContainSubstring("disableKubeDNSRewrite"))
+			})
+
+			Context("with the safety net disabled", func() {
+				BeforeEach(func() {
+					disruption.Spec.Unsafemode = &v1beta1.UnsafemodeSpec{DisableKubeDNSRewrite: true}
+				})
+
+				It("should not catch it", func() {
+					caught, _, err := sm.Evaluate(nil)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeFalse())
+				})
+			})
+		})
+
+		Context("with a regular expression matching kube-dns", func() {
+			BeforeEach(func() {
+				disruption.Spec.DNS[0].Hostname = ".*"
+			})
+
+			It("should catch it", func() {
+				caught, response, err := sm.Evaluate(nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+				Expect(response).To(ContainSubstring("disableKubeDNSRewrite"))
+			})
+		})
+
+		Context("with a wildcard hostname", func() {
+			BeforeEach(func() {
+				disruption.Spec.DNS[0].HostnameMatch = "wildcard"
+			})
+
+			Context("matching kube-dns", func() {
+				BeforeEach(func() {
+					disruption.Spec.DNS[0].Hostname = "*.svc.cluster.local"
+				})
+
+				It("should catch it", func() {
+					caught, _, err := sm.Evaluate(nil)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeTrue())
+				})
+			})
+
+			Context("not matching kube-dns", func() {
+				BeforeEach(func() {
+					disruption.Spec.DNS[0].Hostname = "*.example.com"
+				})
+
+				It("should not catch it", func() {
+					caught, _, err := sm.Evaluate(nil)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeFalse())
+				})
+			})
+		})
+
+		Context("with a suffix hostname", func() {
+			BeforeEach(func() {
+				disruption.Spec.DNS[0].HostnameMatch = "suffix"
+			})
+
+			Context("matching kube-dns", func() {
+				BeforeEach(func() {
+					disruption.Spec.DNS[0].Hostname = "cluster.local"
+				})
+
+				It("should catch it", func() {
+					caught, _, err := sm.Evaluate(nil)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeTrue())
+				})
+			})
+
+			Context("not matching kube-dns", func() {
+				BeforeEach(func() {
+					disruption.Spec.DNS[0].Hostname = "bar.svc.cluster.local"
+				})
+
+				It("should not catch it", func() {
+					caught, _, err := sm.Evaluate(nil)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeFalse())
+				})
+			})
+		})
+	})
+
+	Describe("Node", func() {
+		var (
+			sm      safemode.Node
+			targets []string
+		)
+
+		BeforeEach(func() {
+			disruption.Namespace = "default"
+			disruption.Spec.Level = chaostypes.DisruptionLevelNode
+			disruption.Spec.NodeFailure = &v1beta1.NodeFailureSpec{}
+			targets = []string{"worker"}
+		})
+
+		JustBeforeEach(func() {
+			k8sClient := fake.NewClientBuilder().WithObjects(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "control-plane",
+						Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+					},
+				},
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "worker",
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "control-plane-pod"},
+					Spec:       corev1.PodSpec{NodeName: "control-plane"},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "worker-pod"},
+					Spec:       corev1.PodSpec{NodeName: "worker"},
+				},
+			).Build()
+
+			sm = safemode.Node{}
+			sm.Init(disruption, k8sClient)
+		})
+
+		It("should not catch a worker node", func() {
+			caught, _, err := sm.Evaluate(targets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caught).To(BeFalse())
+		})
+
+		Context("with a control plane node", func() {
+			BeforeEach(func() {
+				targets = []string{"worker", "control-plane"}
+			})
+
+			It("should catch it", func() {
+				caught, response, err := sm.Evaluate(targets)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeTrue())
+				Expect(response).To(ContainSubstring("disableControlPlaneNodeFailure"))
+			})
+
+			Context("with the safety net disabled", func() {
+				BeforeEach(func() {
+					disruption.Spec.Unsafemode = &v1beta1.UnsafemodeSpec{DisableControlPlaneNodeFailure: true}
+				})
+
+				It("should not catch it", func() {
+					caught, _, err := sm.Evaluate(targets)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeFalse())
+				})
+			})
+		})
+
+		Context("with a pod level disruption", func() {
+			BeforeEach(func() {
+				disruption.Spec.Level = chaostypes.DisruptionLevelPod
+				targets = []string{"worker-pod"}
+			})
+
+			It("should not catch a pod running on a worker node", func() {
+				caught, _, err := sm.Evaluate(targets)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(caught).To(BeFalse())
+			})
+
+			Context("targeting a pod running on a control plane node", func() {
+				BeforeEach(func() {
+					targets = []string{"worker-pod", "control-plane-pod"}
+				})
+
+				It("should catch it", func() {
+					caught, response, err := sm.Evaluate(targets)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(caught).To(BeTrue())
+					Expect(response).To(ContainSubstring("control-plane"))
+				})
+			})
+		})
+	})
+
+	Describe("EvaluateAll", func() {
+		BeforeEach(func() {
+			disruption.Spec.DiskPressure = &v1beta1.DiskPressureSpec{Path: "/"}
+			disruption.Spec.DNS = v1beta1.DNSDisruptionSpec{
+				{
+					Hostname: "kube-dns",
+					Record:   v1beta1.DNSRecord{Type: "A", Value: "10.0.0.1"},
+				},
+			}
+		})
+
+		It("should return the responses of all caught safety nets", func() {
+			responses, err := safemode.EvaluateAll(safemode.AddAllSafemodeObjects(disruption, nil), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(responses).To(HaveLen(2))
+		})
+	})
+})
@@ -5,20 +5,34 @@
 
 package stress
 
-import "runtime"
+import (
+	"runtime"
+	"time"
+)
+
+// cpuDutyCyclePeriod is the period of a stress cycle, made of a busy part and an idle part
+const cpuDutyCyclePeriod = 100 * time.Millisecond
 
 type cpu struct {
-	dryRun bool
+	dryRun     bool
+	percentage int
 }
 
-// NewCPU creates a CPU stresser
-func NewCPU(dryRun bool) Stresser {
+// NewCPU creates a CPU stresser loading the CPU at the given percentage (between 1 and 100)
+func NewCPU(dryRun bool, percentage int) Stresser {
+	if percentage <= 0 || percentage > 100 {
+		percentage = 100
+	}
+
 	return cpu{
-		dryRun: dryRun,
+		dryRun:     dryRun,
+		percentage: percentage,
 	}
 }
 
-// Stress starts X goroutines loading CPU
+// Stress loads the CPU until an exit signal is received
+// the load is achieved through a duty cycle: the routine eats cpu for percentage% of each period
+// and sleeps for the remaining time of the period
 func (c cpu) Stress(exit <-chan struct{}) {
 	// early exit if dry-run mode is enabled
 	if c.dryRun {
@@ -27,17 +41,33 @@ func (c cpu) Stress(exit <-chan struct{}) {
 		return
 	}
 
+	busy := cpuDutyCyclePeriod * time.Duration(c.percentage) / 100
+	idle := cpuDutyCyclePeriod - busy
+
 	// start eating cpu
 	for {
+		// eat cpu for the busy part of the cycle
+		start := time.Now()
+		for time.Since(start) < busy {
+			// noop
+		}
+
+		// sleep for the idle part of the cycle if any
+		if idle > 0 {
+			select {
+			case <-exit:
+				return
+			case <-time.After(idle):
+			}
+
+			continue
+		}
+
 		select {
 		case <-exit:
 			// exit
 			return
 		default:
-			// eat cpu
-			for i := uint64(0); i < 18446744073709551615; i++ {
-				// noop
-			}
 		}
 
 		// useful to let other goroutines be scheduled after a loop
@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package stress
+
+import (
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// memoryRampStep is the interval between two allocations when ramping up memory usage
+const memoryRampStep = time.Second
+
+type memory struct {
+	dryRun bool
+	bytes  int64
+	ramp   time.Duration
+	charge func(allocate func())
+}
+
+// NewMemory creates a memory stresser allocating the given amount of bytes,
+// linearly over the given ramp duration (or at once if the ramp duration is zero)
+// each allocation is run by the given charge function, letting the caller join the memory cgroup it must be charged to first
+func NewMemory(dryRun bool, bytes int64, ramp time.Duration, charge func(allocate func())) Stresser {
+	if charge == nil {
+		charge = func(allocate func()) { allocate() }
+	}
+
+	return memory{
+		dryRun: dryRun,
+		bytes:  bytes,
+		ramp:   ramp,
+		charge: charge,
+	}
+}
+
+// Stress allocates memory until the target amount is reached and holds it until an exit signal is received
+// each allocated page is touched so it is actually backed by physical memory and charged to the current memory cgroup
+func (m memory) Stress(exit <-chan struct{}) {
+	// early exit if dry-run mode is enabled
+	if m.dryRun {
+		<-exit
+
+		return
+	}
+
+	steps := int64(m.ramp / memoryRampStep)
+	if steps < 1 {
+		steps = 1
+	}
+
+	chunkSize := m.bytes / steps
+	pageSize := os.Getpagesize()
+	chunks := make([][]byte, 0, steps)
+
+	// release allocated memory on exit
+	defer func() {
+		chunks = nil
+
+		debug.FreeOSMemory()
+	}()
+
+	for step := int64(0); step < steps; step++ {
+		size := chunkSize
+
+		// the last chunk holds the remainder of the division
+		if step == steps-1 {
+			size = m.bytes - chunkSize*(steps-1)
+		}
+
+		// allocate and touch pages
+		m.charge(func() {
+			chunk := make([]byte, size)
+			for i := 0; i < len(chunk); i += pageSize {
+				chunk[i] = 1
+			}
+
+			chunks = append(chunks, chunk)
+		})
+
+		// wait before the next allocation if needed
+		if step < steps-1 {
+			select {
+			case <-exit:
+				return
+			case <-time.After(memoryRampStep):
+			}
+		}
+	}
+
+	<-exit
+}
@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package targetselector
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/DataDog/chaos-controller/utils"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetTargetsWithinDisruptionBudgets returns up to count random pods from the given target pods living in the given namespace
+// so that the disruptions allowed by the pod disruption budgets of the namespace are never exceeded, the current targets
+// of the disruption still ready already taking their share of the budgets matching them
+func GetTargetsWithinDisruptionBudgets(c client.Client, namespace string, currentTargets []string, targets []string, count int) ([]string, error) {
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := c.List(context.Background(), pdbs, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("error listing pod disruption budgets: %w", err)
+	}
+
+	currentPods, err := getPods(c, namespace, currentTargets)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := getPods(c, namespace, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return selectPodsWithinDisruptionBudgets(currentPods, pods, pdbs.Items, count)
+}
+
+// getPods returns the given pods living in the given namespace, ignoring the ones which do not exist anymore
+func getPods(c client.Client, namespace string, names []string) ([]corev1.Pod, error) {
+	pods := []corev1.Pod{}
+
+	for _, name := range names {
+		pod := corev1.Pod{}
+
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, &pod); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+
+			return nil, fmt.Errorf("error getting target pod %s: %w", name, err)
+		}
+
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+// selectPodsWithinDisruptionBudgets randomly selects up to count pods from the given pods without exceeding
+// the allowed disruptions of any of the given pod disruption budgets matching them, minus the given ready current pods they match
+func selectPodsWithinDisruptionBudgets(currentPods []corev1.Pod, pods []corev1.Pod, pdbs []policyv1.PodDisruptionBudget, count int) ([]string, error) {
+	selectors := make([]labels.Selector, len(pdbs))
+	allowedDisruptions := make([]int32, len(pdbs))
+
+	for i, pdb := range pdbs {
+		// a nil selector matches no pod while an empty selector matches every pod
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing pod disruption budget %s selector: %w", pdb.Name, err)
+		}
+
+		selectors[i] = selector
+		allowedDisruptions[i] = pdb.Status.DisruptionsAllowed
+
+		// the pods already disrupted but still ready (e.g. by a network or cpu pressure disruption) are counted as healthy by the budget,
+		// while the not ready ones (e.g. by a container or node failure) are already excluded from its allowed disruptions
+		for _, pod := range currentPods {
+			if utils.IsPodReady(pod) && selector.Matches(labels.Set(pod.Labels)) {
+				allowedDisruptions[i]--
+			}
+		}
+	}
+
+	selected := []string{}
+
+	for _, index := range rand.Perm(len(pods)) { //nolint:gosec
+		if len(selected) >= count {
+			break
+		}
+
+		pod := pods[index]
+		matchingBudgets := []int{}
+		withinBudgets := true
+
+		for i, selector := range selectors {
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+
+			if allowedDisruptions[i] <= 0 {
+				withinBudgets = false
+
+				break
+			}
+
+			matchingBudgets = append(matchingBudgets, i)
+		}
+
+		if !withinBudgets {
+			continue
+		}
+
+		for _, i := range matchingBudgets {
+			allowedDisruptions[i]--
+		}
+
+		selected = append(selected, pod.Name)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022 Datadog, Inc.
+
+package targetselector
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("selectPodsWithinDisruptionBudgets", func() {
+	var (
+		pods []corev1.Pod
+		pdbs []policyv1.PodDisruptionBudget
+	)
+
+	newPod := func(name, app string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"app": app},
+			},
+		}
+	}
+
+	newPDB := func(selector *metav1.LabelSelector, allowed int32) policyv1.PodDisruptionBudget {
+		return policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: selector,
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{
+				DisruptionsAllowed: allowed,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		pods = []corev1.Pod{
+			newPod("foo-1", "foo"),
+			newPod("foo-2", "foo"),
+			newPod("foo-3", "foo"),
+			newPod("bar-1", "bar"),
+		}
+		pdbs = []policyv1.PodDisruptionBudget{
+			newPDB(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}, 1),
+		}
+	})
+
+	It("should not exceed the allowed disruptions of matching budgets", func() {
+		selected, err := selectPodsWithinDisruptionBudgets(nil, pods, pdbs, 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selected).To(HaveLen(2))
+		Expect(selected).To(ContainElement("bar-1"))
+	})
+
+	It("should not select more than the given count", func() {
+		selected, err := selectPodsWithinDisruptionBudgets(nil, pods, nil, 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selected).To(HaveLen(3))
+	})
+
+	Context("with a budget allowing no disruption", func() {
+		BeforeEach(func() {
+			pdbs[0].Status.DisruptionsAllowed = 0
+		})
+
+		It("should only select pods not matching the budget", func() {
+			selected, err := selectPodsWithinDisruptionBudgets(nil, pods, pdbs, 4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(Equal([]string{"bar-1"}))
+		})
+	})
+
+	Context("with overlapping budgets", func() {
+		BeforeEach(func() {
+			pdbs[0].Status.DisruptionsAllowed = 3
+			pdbs = append(pdbs, newPDB(&metav1.LabelSelector{}, 2))
+		})
+
+		It("should respect the most restrictive budget", func() {
+			selected, err := selectPodsWithinDisruptionBudgets(nil, pods, pdbs, 4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(HaveLen(2))
+		})
+	})
+
+	Context("with a budget without selector", func() {
+		BeforeEach(func() {
+			pdbs = []policyv1.PodDisruptionBudget{newPDB(nil, 0)}
+		})
+
+		It("should not match any pod", func() {
+			selected, err := selectPodsWithinDisruptionBudgets(nil, pods, pdbs, 4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(HaveLen(4))
+		})
+	})
+
+	Context("with a current target matching a budget", func() {
+		var currentPod corev1.Pod
+
+		BeforeEach(func() {
+			currentPod = newPod("foo-0", "foo")
+			currentPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		})
+
+		It("should not select any other pod matching the budget", func() {
+			selected, err := selectPodsWithinDisruptionBudgets([]corev1.Pod{currentPod}, pods, pdbs, 4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selected).To(Equal([]string{"bar-1"}))
+		})
+
+		Context("not ready anymore", func() {
+			BeforeEach(func() {
+				currentPod.Status.Conditions[0].Status = corev1.ConditionFalse
+			})
+
+			It("should not deduct it twice from the allowed disruptions of the budget", func() {
+				selected, err := selectPodsWithinDisruptionBudgets([]corev1.Pod{currentPod}, pods, pdbs, 4)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(selected).To(HaveLen(2))
+				Expect(selected).To(ContainElement("bar-1"))
+			})
+		})
+	})
+})
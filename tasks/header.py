@@ -26,8 +26,9 @@ auto_generated_headers = [
 
 files_to_skip = [
     "api/v1beta1/zz_generated.deepcopy.go",
-    "bin/injector/dns_disruption_resolver.py",
+    "chart/templates/crds/chaos.datadoghq.com_disruptioncrons.yaml",
     "chart/templates/crds/chaos.datadoghq.com_disruptions.yaml",
+    "chart/templates/crds/chaos.datadoghq.com_disruptionworkflows.yaml",
     "chart/templates/role.yaml",
     "chart/install.yaml",
     "cpuset/cpuset.go",
@@ -32,12 +32,16 @@ const (
 	DisruptionKindContainerFailure = "container-failure"
 	// DisruptionKindCPUPressure is a CPU pressure disruption
 	DisruptionKindCPUPressure = "cpu-pressure"
+	// DisruptionKindMemoryPressure is a memory pressure disruption
+	DisruptionKindMemoryPressure = "memory-pressure"
 	// DisruptionKindDiskPressure is a disk pressure disruption
 	DisruptionKindDiskPressure = "disk-pressure"
 	// DisruptionKindDNSDisruption is a dns disruption
 	DisruptionKindDNSDisruption = "dns-disruption"
 	// DisruptionKindGRPCDisruption is a grpc disruption
 	DisruptionKindGRPCDisruption = "grpc-disruption"
+	// DisruptionKindHTTPDisruption is an http disruption
+	DisruptionKindHTTPDisruption = "http-disruption"
 
 	// DisruptionLevelUnspecified is the value used when the level of injection is not specified
 	DisruptionLevelUnspecified = ""
@@ -59,6 +63,10 @@ const (
 	DisruptionNameLabel = "chaos.datadoghq.com/disruption-name"
 	// DisruptionNamespaceLabel is the label used to identify the disruption namespace for a chaos pod. This is used to determine pod ownership.
 	DisruptionNamespaceLabel = "chaos.datadoghq.com/disruption-namespace"
+	// DisruptionCronNameLabel is the label used to identify the disruption cron name for a disruption created by a disruption cron.
+	DisruptionCronNameLabel = "chaos.datadoghq.com/disruption-cron-name"
+	// DisruptionWorkflowNameLabel is the label used to identify the disruption workflow name for a disruption created by a disruption workflow.
+	DisruptionWorkflowNameLabel = "chaos.datadoghq.com/disruption-workflow-name"
 
 	finalizerPrefix     = "finalizer.chaos.datadoghq.com"
 	DisruptionFinalizer = finalizerPrefix
@@ -70,6 +78,9 @@ const (
 	// Also used in the DNS Disruption to allow combined Network + DNS Disruption
 	// This value should NEVER be changed without changing the Network Disruption TC tree.
 	InjectorCgroupClassID = "0x00020002"
+	// InjectorCgroupClass is the tc class matching the InjectorCgroupClassID.
+	// It is used to classify packets with iptables on cgroup v2 hosts where the net_cls cgroup does not exist.
+	InjectorCgroupClass = "2:2"
 
 	// DDMarkChaoslibPrefix allows to consistently name the chaos-imported API in ddmark.
 	// It's arbitrary but needs to be consistent across multiple files.
@@ -87,8 +98,10 @@ var (
 		DisruptionKindNodeFailure,
 		DisruptionKindContainerFailure,
 		DisruptionKindCPUPressure,
+		DisruptionKindMemoryPressure,
 		DisruptionKindDiskPressure,
 		DisruptionKindDNSDisruption,
 		DisruptionKindGRPCDisruption,
+		DisruptionKindHTTPDisruption,
 	}
 )
@@ -39,6 +39,17 @@ func Contains(s []string, str string) bool {
 	return false
 }
 
+// IsPodReady returns true if the given pod has the ready condition
+func IsPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
 type SetupWebhookWithManagerConfig struct {
 	Manager                ctrl.Manager
 	Logger                 *zap.SugaredLogger